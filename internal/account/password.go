@@ -0,0 +1,124 @@
+package account
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// パスワードのハッシュの形式（pbkdf2-sha256$反復回数$ソルト$ハッシュ、ソルトとハッシュはパディングなしのBase64）
+const (
+	hashScheme     = "pbkdf2-sha256"
+	hashIterations = 210000
+	saltLength     = 16
+	keyLength      = 32
+)
+
+// errInvalidHash ハッシュの形式が不正
+var errInvalidHash = errors.New("invalid password hash: must be generated with -hash-password")
+
+// HashPassword パスワードからランダムなソルトを付けたハッシュを生成
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, hashIterations, keyLength)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return strings.Join([]string{
+		hashScheme,
+		strconv.Itoa(hashIterations),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	}, "$"), nil
+}
+
+// ValidateHash ハッシュの形式を検証
+func ValidateHash(hash string) error {
+	_, _, _, err := parseHash(hash)
+	return err
+}
+
+// VerifyPassword パスワードがハッシュと一致するか
+func VerifyPassword(hash, password string) bool {
+	iterations, salt, expected, err := parseHash(hash)
+	if err != nil {
+		return false
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(expected))
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(key, expected) == 1
+}
+
+// parseHash ハッシュを反復回数・ソルト・ハッシュに分解
+func parseHash(hash string) (int, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != hashScheme {
+		return 0, nil, nil, errInvalidHash
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return 0, nil, nil, errInvalidHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil || len(salt) == 0 {
+		return 0, nil, nil, errInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return 0, nil, nil, errInvalidHash
+	}
+	return iterations, salt, key, nil
+}
+
+// verifyCacheTTL 一致を確認したパスワードを記憶する時間
+const verifyCacheTTL = 5 * time.Minute
+
+// VerifyCache 一致を確認したパスワードを一定時間記憶する（Basic認証のリクエストのたびにハッシュを計算しないため）
+type VerifyCache struct {
+	mutex    sync.Mutex
+	verified map[[32]byte]time.Time
+}
+
+// NewVerifyCache 空のVerifyCacheを作成
+func NewVerifyCache() *VerifyCache {
+	return &VerifyCache{verified: make(map[[32]byte]time.Time)}
+}
+
+// Verify パスワードがハッシュと一致するか（記憶している場合はハッシュを計算しない）
+func (c *VerifyCache) Verify(hash, password string) bool {
+	key := sha256.Sum256([]byte(hash + "\x00" + password))
+	now := time.Now()
+
+	c.mutex.Lock()
+	expires, ok := c.verified[key]
+	c.mutex.Unlock()
+	if ok && now.Before(expires) {
+		return true
+	}
+	if !VerifyPassword(hash, password) {
+		return false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for k, e := range c.verified {
+		if now.After(e) {
+			delete(c.verified, k)
+		}
+	}
+	c.verified[key] = now.Add(verifyCacheTTL)
+	return true
+}
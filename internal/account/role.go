@@ -0,0 +1,13 @@
+package account
+
+import "slices"
+
+// Roles ユーザーに指定できる役割（権限の弱い順）
+// viewer: ダッシュボードと履歴の閲覧のみ、editor: チェックの実行と対象・定期チェックの変更、admin: 設定の再読み込みと監査記録の閲覧
+var Roles = []string{"viewer", "editor", "admin"}
+
+// Allows 役割がrequiredの権限を持つか
+func Allows(role, required string) bool {
+	have := slices.Index(Roles, role)
+	return have >= 0 && have >= slices.Index(Roles, required)
+}
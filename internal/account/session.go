@@ -0,0 +1,70 @@
+package account
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Sessions ログイン中のセッション（メモリ上にのみ保持し、再起動するとログインし直す）
+type Sessions struct {
+	mutex    sync.Mutex
+	sessions map[string]*session
+}
+
+// session 1つのログイン
+type session struct {
+	user    string
+	expires time.Time
+}
+
+// NewSessions 空のセッションの一覧を作成
+func NewSessions() *Sessions {
+	return &Sessions{sessions: make(map[string]*session)}
+}
+
+// Create ユーザーのセッションを作成してトークンを返す
+func (s *Sessions) Create(user string, ttl time.Duration) string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	token := hex.EncodeToString(b)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sweep(time.Now())
+	s.sessions[token] = &session{user: user, expires: time.Now().Add(ttl)}
+	return token
+}
+
+// User トークンのセッションのユーザー名（存在しないか期限切れの場合はfalse）
+func (s *Sessions) User(token string) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sess, ok := s.sessions[token]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(sess.expires) {
+		delete(s.sessions, token)
+		return "", false
+	}
+	return sess.user, true
+}
+
+// Delete セッションを削除（ログアウト）
+func (s *Sessions) Delete(token string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.sessions, token)
+}
+
+// sweep 期限切れのセッションを削除
+func (s *Sessions) sweep(now time.Time) {
+	for token, sess := range s.sessions {
+		if now.After(sess.expires) {
+			delete(s.sessions, token)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package ack
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"healthcheck/internal/checker"
+)
+
+// File 失敗している対象の確認を保存するファイル
+var File = "acknowledgments.json"
+
+// store 対象のURLごとの確認（定期チェック・通知・Webで共有し、ファイルに保存する）
+type store struct {
+	mutex  sync.Mutex
+	loaded bool
+	acks   map[string]*checker.Acknowledgment
+}
+
+// acknowledgments 確認の保存先
+var acknowledgments = &store{}
+
+// Acknowledge 失敗している対象を確認済みにする（同じ対象の確認は置き換える）
+// 確認済みの対象は復旧するか期限を過ぎるまで、ダウンのアラートとエスカレーションを送信しない
+func Acknowledge(targetURL string, a checker.Acknowledgment) error {
+	s := acknowledgments
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.load()
+
+	s.acks[targetURL] = &a
+	return s.save()
+}
+
+// Clear 対象の確認を取り消す（確認済みでなかった場合はfalseを返す）
+func Clear(targetURL string) (bool, error) {
+	s := acknowledgments
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.load()
+
+	if _, ok := s.acks[targetURL]; !ok {
+		return false, nil
+	}
+	delete(s.acks, targetURL)
+	return true, s.save()
+}
+
+// Get 対象の有効な確認（確認済みでない場合と期限を過ぎた場合はnil）
+func Get(targetURL string, now time.Time) *checker.Acknowledgment {
+	s := acknowledgments
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.load()
+
+	a, ok := s.acks[targetURL]
+	if !ok || a.Expired(now) {
+		return nil
+	}
+	copied := *a
+	return &copied
+}
+
+// Active 有効な確認を対象のURLごとに返す
+func Active(now time.Time) map[string]*checker.Acknowledgment {
+	s := acknowledgments
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.load()
+
+	active := make(map[string]*checker.Acknowledgment, len(s.acks))
+	for u, a := range s.acks {
+		if !a.Expired(now) {
+			copied := *a
+			active[u] = &copied
+		}
+	}
+	return active
+}
+
+// Annotate 失敗した結果に対象の確認を付け、成功した対象（復旧した対象）と期限を過ぎた確認は削除する
+func Annotate(results []*checker.CheckResult, now time.Time) {
+	s := acknowledgments
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.load()
+	if len(s.acks) == 0 {
+		return
+	}
+
+	changed := false
+	for _, r := range results {
+		a, ok := s.acks[r.URL]
+		if !ok {
+			continue
+		}
+		if r.Success || a.Expired(now) {
+			delete(s.acks, r.URL)
+			changed = true
+			continue
+		}
+		copied := *a
+		r.Acknowledgment = &copied
+	}
+	if !changed {
+		return
+	}
+	if err := s.save(); err != nil {
+		slog.Warn("failed to save acknowledgments", "error", err)
+	}
+}
+
+// load ファイルから確認を読み込む（初回のみ、ロックを保持して呼び出す）
+func (s *store) load() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	s.acks = make(map[string]*checker.Acknowledgment)
+
+	data, err := os.ReadFile(File)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("failed to read acknowledgments", "error", err)
+		}
+		return
+	}
+	if err := json.Unmarshal(data, &s.acks); err != nil {
+		slog.Warn("failed to parse acknowledgments", "error", err)
+	}
+}
+
+// save 確認をファイルに保存（ロックを保持して呼び出す）
+func (s *store) save() error {
+	data, err := json.MarshalIndent(s.acks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if err := os.WriteFile(File, data, 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
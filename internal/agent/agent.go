@@ -0,0 +1,79 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+)
+
+// ResultsPath コーディネーターが結果を受け付けるパス
+const ResultsPath = "/api/agent/results"
+
+// Report エージェントからコーディネーターに送信する1回分の結果
+type Report struct {
+	Region    string                 `json:"region"`
+	RunID     string                 `json:"run_id,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Results   []*checker.CheckResult `json:"results"`
+}
+
+// Client コーディネーターに結果を送信するクライアント
+type Client struct {
+	url    string
+	token  string
+	region string
+	client *http.Client
+}
+
+// NewClient 設定からClientを作成（コーディネーターが設定されていない場合はnil）
+func NewClient(cfg *config.Config) *Client {
+	if cfg.Coordinator == "" {
+		return nil
+	}
+	return &Client{
+		url:    strings.TrimSuffix(cfg.Coordinator, "/") + ResultsPath,
+		token:  cfg.CoordinatorToken,
+		region: cfg.Region,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Push 1回分の結果をコーディネーターに送信
+func (c *Client) Push(ctx context.Context, runID string, timestamp time.Time, results []*checker.CheckResult) error {
+	body, err := json.Marshal(Report{
+		Region:    c.region,
+		RunID:     runID,
+		Timestamp: timestamp,
+		Results:   results,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send report: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("coordinator returned %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry 1回のAPI呼び出しの監査記録
+type Entry struct {
+	Timestamp    time.Time              `json:"timestamp"`
+	RequestID    string                 `json:"request_id,omitempty"`
+	RunID        string                 `json:"run_id,omitempty"`
+	Remote       string                 `json:"remote"`
+	ForwardedFor string                 `json:"forwarded_for,omitempty"`
+	User         string                 `json:"user,omitempty"`
+	Method       string                 `json:"method"`
+	Path         string                 `json:"path"`
+	Status       int                    `json:"status"`
+	Duration     time.Duration          `json:"duration_ms"`
+	Action       string                 `json:"action"` // check / har / import / benchmark / agent_results など
+	Targets      []string               `json:"targets,omitempty"`
+	Options      map[string]string      `json:"options,omitempty"`
+	Summary      map[string]interface{} `json:"summary,omitempty"`
+}
+
+// Query 監査記録の検索条件（ゼロ値の条件は無視する）
+type Query struct {
+	Since  time.Time
+	Until  time.Time
+	User   string
+	Action string
+	Target string // 対象のURLに含まれる文字列
+	Limit  int
+}
+
+// Log 監査記録をJSON Lines形式で追記するファイル
+type Log struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewLog 監査記録のファイルを指定してLogを作成
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Append 監査記録を追記
+func (l *Log) Append(e *Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Query 条件に一致する監査記録を新しい順に返す
+func (l *Log) Query(q Query) ([]*Entry, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	f, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Entry{}, nil
+		}
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []*Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if q.matches(&e) {
+			entries = append(entries, &e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	// 新しい順に並べ、件数を制限
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	if q.Limit > 0 && len(entries) > q.Limit {
+		entries = entries[:q.Limit]
+	}
+	return entries, nil
+}
+
+// matches 記録が条件に一致するか
+func (q Query) matches(e *Entry) bool {
+	if !q.Since.IsZero() && e.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.Timestamp.Before(q.Until) {
+		return false
+	}
+	if q.User != "" && e.User != q.User {
+		return false
+	}
+	if q.Action != "" && e.Action != q.Action {
+		return false
+	}
+	if q.Target != "" {
+		found := false
+		for _, t := range e.Targets {
+			if strings.Contains(t, q.Target) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+type entryKey struct{}
+
+// WithEntry 記録中の監査記録をコンテキストに設定
+func WithEntry(ctx context.Context, e *Entry) context.Context {
+	return context.WithValue(ctx, entryKey{}, e)
+}
+
+// FromContext コンテキストの監査記録（設定されていない場合はnil）
+func FromContext(ctx context.Context) *Entry {
+	e, _ := ctx.Value(entryKey{}).(*Entry)
+	return e
+}
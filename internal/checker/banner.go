@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// bannerConn 接続後にサーバーから話し始めるプロトコル（SSH・FTP）の接続
+type bannerConn struct {
+	net.Conn
+	reader *bufio.Reader
+	start  time.Time // 接続を始めた時刻
+}
+
+// finish 接続の開始からやり取りの完了までを応答時間として記録
+func (b *bannerConn) finish(result *CheckResult) {
+	result.ResponseTime = time.Since(b.start)
+	result.Latency = result.ResponseTime
+}
+
+// dialBanner 対象に接続し、接続までの時間を記録
+// 失敗した場合はresultに理由を記録してエラーを返す
+func (c *Checker) dialBanner(ctx context.Context, target config.Target, defaultPort string, result *CheckResult) (*bannerConn, error) {
+	parsedURL, err := url.Parse(target.URL)
+	if err != nil || parsedURL.Hostname() == "" {
+		result.Error = CategoryInvalidURL
+		result.ErrorMessage = fmt.Sprintf("Target must be scheme://host[:port]: %s", target.URL)
+		return nil, fmt.Errorf("invalid URL %q", target.URL)
+	}
+	port := parsedURL.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	c.waitForRateLimit(ctx, parsedURL.Hostname())
+
+	timeout := c.config.Timeout
+	if target.Timeout != "" {
+		if d, err := time.ParseDuration(target.Timeout); err == nil {
+			timeout = d
+		}
+	}
+
+	dialer := newDialer(c.config, 0)
+	dialCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	startTime := time.Now()
+	conn, err := dialer.DialContext(dialCtx, "tcp", net.JoinHostPort(parsedURL.Hostname(), port))
+	result.Phases = &Phases{Connect: time.Since(startTime)}
+	result.ResponseTime = result.Phases.Connect
+	result.Latency = result.ResponseTime
+	if err != nil {
+		result.Error = classifyNetError(err)
+		if dialCtx.Err() == context.DeadlineExceeded {
+			result.Error = CategoryTimeout
+		}
+		result.ErrorMessage = err.Error()
+		return nil, err
+	}
+	result.RemoteAddr = conn.RemoteAddr().String()
+	// 接続を含めてタイムアウトまでに応答を終えなければ打ち切る
+	conn.SetDeadline(startTime.Add(timeout))
+	return &bannerConn{Conn: conn, reader: bufio.NewReader(conn), start: startTime}, nil
+}
+
+// fail バナーやコマンドの応答を読み書きできなかった理由を記録
+func (b *bannerConn) fail(err error, result *CheckResult) {
+	b.finish(result)
+	var netErr net.Error
+	var protoErr textproto.ProtocolError
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		result.Error = CategoryTimeout
+		result.ErrorMessage = "Timed out waiting for the server"
+	case errors.As(err, &protoErr):
+		result.Error = CategoryProtocolError
+		result.ErrorMessage = err.Error()
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		result.Error = CategoryEmptyResponse
+		result.ErrorMessage = "Connection closed by the server"
+	default:
+		result.Error = CategoryRequestFailed
+		result.ErrorMessage = err.Error()
+	}
+}
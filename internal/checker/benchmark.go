@@ -0,0 +1,76 @@
+package checker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"healthcheck/internal/config"
+	"healthcheck/internal/runs"
+	"healthcheck/internal/tracing"
+)
+
+// NewBenchmarkChecker ベンチマーク用のCheckerを作成
+// 通常のレート制限の代わりにBenchmarkのrateで送信間隔を制御するため、レート制限は実質無効にする
+// concurrencyが0以下の場合は設定の並列度を使う
+func NewBenchmarkChecker(cfg *config.Config, concurrency int) *Checker {
+	bench := config.DefaultConfig()
+	bench.Timeout = cfg.Timeout
+	bench.MaxLatency = cfg.MaxLatency
+	bench.Concurrency = cfg.Concurrency
+	if concurrency > 0 {
+		bench.Concurrency = concurrency
+	}
+	bench.Insecure = cfg.Insecure
+	bench.DomainRate = 1 << 30
+	bench.GlobalRate = 1 << 30
+	bench.RootCauseHints = false
+	return NewChecker(bench)
+}
+
+// Benchmark 各URLをcount回ずつチェック（リトライは行わない）
+// rateが0より大きい場合は全体で1秒あたりrate回の一定間隔でリクエストを開始する
+func (c *Checker) Benchmark(ctx context.Context, urls []string, count int, rate float64) []*CheckResult {
+	var ticker *time.Ticker
+	if rate > 0 {
+		ticker = time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+	}
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	var results []*CheckResult
+	semaphore := make(chan struct{}, c.config.Concurrency)
+	runID := tracing.RunID(ctx)
+	runs.AddTotal(runID, len(urls)*count)
+
+	// URLを交互に並べ、特定のURLに負荷が偏らないようにする
+	for i := 0; i < count; i++ {
+		for _, u := range urls {
+			if ticker != nil {
+				select {
+				case <-ctx.Done():
+					wg.Wait()
+					return results
+				case <-ticker.C:
+				}
+			}
+			semaphore <- struct{}{}
+
+			wg.Add(1)
+			go func(u string) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+
+				result := c.CheckURL(ctx, u)
+				runs.Record(runID, result.Success)
+				mutex.Lock()
+				results = append(results, result)
+				mutex.Unlock()
+			}(u)
+		}
+	}
+
+	wg.Wait()
+	return results
+}
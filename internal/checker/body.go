@@ -0,0 +1,68 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"healthcheck/internal/tracing"
+)
+
+// readBody レスポンスの本文を最大サイズまで受信し、サイズと受信速度を結果に記録
+// 本文を最後まで読むことで接続がキープアライブで再利用される
+// keepを指定した場合は先頭のkeepバイトまでの内容を返す
+func (c *Checker) readBody(ctx context.Context, resp *http.Response, result *CheckResult, keep int64) ([]byte, error) {
+	if resp.ContentLength > 0 {
+		result.ContentLength = resp.ContentLength
+	}
+	limit := c.config.MaxBodyBytes
+	if limit < keep {
+		limit = keep
+	}
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	start := time.Now()
+	kept := &prefixBuffer{limit: keep}
+	n, err := io.Copy(io.Discard, io.TeeReader(io.LimitReader(resp.Body, limit), kept))
+	if err == nil && n == limit {
+		// 最大サイズを超える本文が残っているか
+		var one [1]byte
+		if m, _ := resp.Body.Read(one[:]); m > 0 {
+			result.Truncated = true
+		}
+	}
+	end := time.Now()
+
+	result.BytesDownloaded = n
+	transfer := end.Sub(start)
+	if result.Phases != nil {
+		result.Phases.Transfer = transfer
+	}
+	if transfer > 0 && n > 0 {
+		result.Throughput = float64(n) / transfer.Seconds()
+	}
+	tracing.Record(ctx, "transfer", start, end)
+	return kept.buf.Bytes(), err
+}
+
+// prefixBuffer 書き込まれた内容の先頭limitバイトのみを保持するWriter
+type prefixBuffer struct {
+	buf   bytes.Buffer
+	limit int64
+}
+
+// Write 上限までの内容を保持（上限を超えた分は破棄する）
+func (b *prefixBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - int64(b.buf.Len()); remaining > 0 {
+		if int64(len(p)) > remaining {
+			b.buf.Write(p[:remaining])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,438 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	cdplog "github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+
+	"healthcheck/internal/config"
+)
+
+const (
+	// browserIdleTime リクエストがこの時間なければネットワークが落ち着いたとみなす
+	browserIdleTime = 500 * time.Millisecond
+	// maxBrowserEntries 記録するコンソールのエラー・失敗したリソースの最大数
+	maxBrowserEntries = 20
+)
+
+// browserNames browser_pathを省略した場合にPATHから探すブラウザ
+var browserNames = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "chrome", "headless_shell"}
+
+// browserSlots 同時に起動するブラウザの数の上限（1つで数百MBのメモリを使うため）
+var browserSlots = make(chan struct{}, 2)
+
+// BrowserMetrics ブラウザでページを読み込んだ結果
+type BrowserMetrics struct {
+	DOMContentLoaded time.Duration    `json:"dom_content_loaded_ms"`     // DOMContentLoadedまでの時間
+	Load             time.Duration    `json:"load_ms"`                   // onloadまでの時間
+	NetworkIdle      time.Duration    `json:"network_idle_ms,omitempty"` // リクエストがなくなるまでの時間（期限までに落ち着かなかった場合は0）
+	Requests         int              `json:"requests"`                  // ページとサブリソースのリクエスト数
+	ConsoleErrors    []string         `json:"console_errors,omitempty"`  // コンソールのエラーと捕捉されなかった例外
+	FailedResources  []FailedResource `json:"failed_resources,omitempty"`
+}
+
+// FailedResource 読み込みに失敗した・安全でないサブリソース（スクリプト・スタイルシート・画像・XHRなど）
+type FailedResource struct {
+	URL    string `json:"url"`
+	Type   string `json:"type,omitempty"`   // Script / Stylesheet / Image / XHR / Fetchなど
+	Status int    `json:"status,omitempty"` // 4xx・5xxで応答した場合のステータスコード
+	Error  string `json:"error,omitempty"`  // 接続できなかった場合の理由（例: net::ERR_NAME_NOT_RESOLVED）
+
+	Insecure bool `json:"insecure,omitempty"` // HTTPSのページからHTTPで読み込む（混在コンテンツ）
+}
+
+// browserProvider ヘッドレスのChrome/Chromiumでページ全体を読み込むチェック
+type browserProvider struct {
+	checker *Checker
+}
+
+// Scheme 担当するスキーム
+func (p *browserProvider) Scheme() string { return "browser" }
+
+// Check ページを読み込む
+func (p *browserProvider) Check(ctx context.Context, target config.Target) *CheckResult {
+	return p.checker.CheckBrowser(ctx, target)
+}
+
+// CheckBrowser ヘッドレスブラウザでページを読み込み、ネットワークが落ち着くまで待つ
+// HTMLの取得だけでは分からないスクリプトのエラーやサブリソースの読み込みの失敗を検出する
+// DOMContentLoaded・onloadまでの時間、コンソールのエラー、失敗したサブリソースを記録する
+func (c *Checker) CheckBrowser(ctx context.Context, target config.Target) *CheckResult {
+	result := &CheckResult{
+		URL:       target.URL,
+		Timestamp: time.Now().UTC(),
+		Success:   false,
+	}
+
+	parsedURL, err := url.Parse(target.URL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		result.Error = CategoryInvalidURL
+		result.ErrorMessage = fmt.Sprintf("Browser target must be an http(s) URL: %s", target.URL)
+		return result
+	}
+	var ignore []*regexp.Regexp
+	for _, pattern := range target.IgnoreResources {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			result.Error = CategoryInvalidRule
+			result.ErrorMessage = fmt.Sprintf("Invalid ignore_resources %q: %v", pattern, err)
+			return result
+		}
+		ignore = append(ignore, re)
+	}
+
+	timeout := c.config.Timeout
+	if target.Timeout != "" {
+		if d, err := time.ParseDuration(target.Timeout); err == nil {
+			timeout = d
+		}
+	}
+	c.waitForRateLimit(ctx, parsedURL.Hostname())
+	loadCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	select {
+	case browserSlots <- struct{}{}:
+		defer func() { <-browserSlots }()
+	case <-loadCtx.Done():
+		result.Error = CategoryTimeout
+		result.ErrorMessage = fmt.Sprintf("No browser became available within %v", timeout)
+		return result
+	}
+
+	path, err := browserPath(c.config.BrowserPath)
+	if err != nil {
+		result.Error = CategoryBrowserError
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	// チェックごとに一時的なプロファイルで起動し、終了時にプロセスとプロファイルを削除する
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(loadCtx, browserOptions(path)...)
+	defer cancelAlloc()
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	defer cancelBrowser()
+	if err := chromedp.Run(browserCtx); err != nil {
+		result.Error = CategoryBrowserError
+		// 起動に失敗した場合、chromedpのエラーにはブラウザの出力が含まれる
+		result.ErrorMessage = fmt.Sprintf("failed to start browser %s: %s", path, strings.TrimSpace(err.Error()))
+		if loadCtx.Err() == context.DeadlineExceeded {
+			result.Error = CategoryTimeout
+			result.ErrorMessage = fmt.Sprintf("Browser did not start within %v", timeout)
+		}
+		return result
+	}
+
+	startTime := time.Now()
+	load, err := loadPage(browserCtx, target.URL, ignore)
+	result.ResponseTime = time.Since(startTime)
+	if load.metrics.Load > 0 {
+		result.ResponseTime = load.metrics.Load
+	}
+	result.Latency = result.ResponseTime
+	result.StatusCode = load.status
+	result.Browser = load.metrics
+
+	expected := target.ExpectedStatus
+	switch {
+	case load.navigationError != "":
+		result.Error = navigationErrorCategory(load.navigationError)
+		result.ErrorMessage = fmt.Sprintf("Navigation failed: %s", load.navigationError)
+	case err != nil && loadCtx.Err() == context.DeadlineExceeded:
+		result.Error = CategoryTimeout
+		result.ErrorMessage = fmt.Sprintf("Page did not finish loading within %v", timeout)
+	case err != nil:
+		result.Error = CategoryBrowserError
+		result.ErrorMessage = err.Error()
+	case load.status == 0:
+		result.Error = CategoryRequestFailed
+		result.ErrorMessage = "No response for the page"
+	case (expected == 0 && (load.status < 200 || load.status >= 300)) || (expected != 0 && load.status != expected):
+		result.Error = statusCategory(load.status)
+		result.ErrorMessage = fmt.Sprintf("HTTP %d", load.status)
+	case len(load.metrics.ConsoleErrors) > 0 || len(load.metrics.FailedResources) > 0:
+		result.Error = CategoryPageError
+		result.ErrorMessage = pageErrorMessage(load.metrics)
+	default:
+		result.Success = true
+	}
+
+	return result
+}
+
+// pageErrorMessage コンソールのエラーと失敗したサブリソースの件数と最初の1件
+func pageErrorMessage(m *BrowserMetrics) string {
+	var parts []string
+	if n := len(m.ConsoleErrors); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d console errors (first: %s)", n, m.ConsoleErrors[0]))
+	}
+	if n := len(m.FailedResources); n > 0 {
+		f := m.FailedResources[0]
+		reason := f.Error
+		if f.Status != 0 {
+			reason = fmt.Sprintf("HTTP %d", f.Status)
+		}
+		parts = append(parts, fmt.Sprintf("%d failed resources (first: %s %s)", n, f.URL, reason))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// navigationErrorCategory ブラウザのネットワークエラー（net::ERR_...）を失敗の種類に分類
+func navigationErrorCategory(errorText string) ErrorCategory {
+	switch {
+	case strings.Contains(errorText, "NAME_NOT_RESOLVED"), strings.Contains(errorText, "NAME_RESOLUTION_FAILED"):
+		return CategoryDNSFailure
+	case strings.Contains(errorText, "CONNECTION_REFUSED"):
+		return CategoryConnectRefused
+	case strings.Contains(errorText, "CERT_"), strings.Contains(errorText, "SSL_"):
+		return CategoryTLSError
+	case strings.Contains(errorText, "TIMED_OUT"):
+		return CategoryTimeout
+	case strings.Contains(errorText, "EMPTY_RESPONSE"):
+		return CategoryEmptyResponse
+	}
+	return CategoryRequestFailed
+}
+
+// browserPath 起動するブラウザのパス（指定がなければPATHから探す）
+func browserPath(configured string) (string, error) {
+	if configured != "" {
+		return configured, nil
+	}
+	for _, name := range browserNames {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.New("Chrome/Chromium not found: set browser_path")
+}
+
+// browserOptions ヘッドレスブラウザの起動オプション（chromedpの既定のオプションに加える）
+func browserOptions(path string) []chromedp.ExecAllocatorOption {
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.ExecPath(path),
+		chromedp.Flag("mute-audio", true),
+	)
+	// rootではサンドボックスを使えないため（コンテナでの実行など）
+	if os.Geteuid() == 0 {
+		opts = append(opts, chromedp.NoSandbox)
+	}
+	return opts
+}
+
+// pageLoad ページの読み込みの経過
+type pageLoad struct {
+	metrics         *BrowserMetrics
+	status          int    // ページの応答のステータスコード
+	navigationError string // ページ自体の読み込みに失敗した理由
+}
+
+// browserRequest 応答を待っているリクエスト
+type browserRequest struct {
+	url          string
+	resourceType string
+}
+
+// loadPage ページを開き、onloadの後にネットワークが落ち着くまでのイベントを記録
+// ignoreに一致するURL・メッセージは失敗として扱わない（広告・解析のスクリプトなど）
+func loadPage(ctx context.Context, pageURL string, ignore []*regexp.Regexp) (*pageLoad, error) {
+	load := &pageLoad{metrics: &BrowserMetrics{}}
+
+	// イベントはchromedpの受信処理から呼ばれるため、チャネルに渡してこの関数のループで処理する
+	events := make(chan interface{}, 256)
+	done := make(chan struct{})
+	defer close(done)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		select {
+		case events <- ev:
+		case <-done:
+		}
+	})
+	if err := chromedp.Run(ctx, network.Enable(), page.Enable(), runtime.Enable(), cdplog.Enable()); err != nil {
+		return load, err
+	}
+	ignored := func(s string) bool {
+		for _, re := range ignore {
+			if re.MatchString(s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	type navigateResult struct {
+		loaderID  cdp.LoaderID
+		errorText string
+		err       error
+	}
+	navigated := make(chan navigateResult, 1)
+	startTime := time.Now()
+	go func() {
+		var nav navigateResult
+		nav.err = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			_, nav.loaderID, nav.errorText, _, err = page.Navigate(pageURL).Do(ctx)
+			return err
+		}))
+		navigated <- nav
+	}()
+
+	inflight := make(map[network.RequestID]browserRequest)
+	statuses := make(map[network.RequestID]int) // ページ（Document）の応答のステータスコード
+	var failed []struct {
+		requestID network.RequestID
+		resource  FailedResource
+	}
+	addConsoleError := func(message string) {
+		if message != "" && !ignored(message) && len(load.metrics.ConsoleErrors) < maxBrowserEntries {
+			load.metrics.ConsoleErrors = append(load.metrics.ConsoleErrors, message)
+		}
+	}
+	addFailed := func(requestID network.RequestID, resource FailedResource) {
+		if !ignored(resource.URL) && len(failed) < maxBrowserEntries+1 {
+			failed = append(failed, struct {
+				requestID network.RequestID
+				resource  FailedResource
+			}{requestID, resource})
+		}
+	}
+
+	var loaded bool
+	var loaderID cdp.LoaderID // ページ自体のリクエストのID（Page.navigateの応答で分かる）
+	var idleSince time.Time
+	idle := time.NewTimer(time.Hour)
+	idle.Stop()
+	defer idle.Stop()
+
+	finish := func() {
+		// ページ自体の失敗はサブリソースの失敗として数えない
+		for _, f := range failed {
+			if string(f.requestID) != string(loaderID) && len(load.metrics.FailedResources) < maxBrowserEntries {
+				load.metrics.FailedResources = append(load.metrics.FailedResources, f.resource)
+			}
+		}
+		load.status = statuses[network.RequestID(loaderID)]
+	}
+
+	navigating := true
+	for {
+		if loaded && len(inflight) == 0 && idleSince.IsZero() {
+			idleSince = time.Now()
+			idle.Reset(browserIdleTime)
+		}
+
+		select {
+		case nav := <-navigated:
+			navigating = false
+			if nav.err != nil {
+				return load, nav.err
+			}
+			if nav.errorText != "" {
+				load.navigationError = nav.errorText
+				return load, nil
+			}
+			loaderID = nav.loaderID
+		case <-idle.C:
+			load.metrics.NetworkIdle = idleSince.Sub(startTime)
+			finish()
+			return load, nil
+		case <-ctx.Done():
+			finish()
+			// onloadの後にネットワークが落ち着かないページ（長いポーリングなど）は読み込めたものとする
+			if loaded && !navigating {
+				return load, nil
+			}
+			return load, ctx.Err()
+		case ev := <-events:
+			switch ev := ev.(type) {
+			case *page.EventDomContentEventFired:
+				if load.metrics.DOMContentLoaded == 0 {
+					load.metrics.DOMContentLoaded = time.Since(startTime)
+				}
+			case *page.EventLoadEventFired:
+				if !loaded {
+					loaded = true
+					load.metrics.Load = time.Since(startTime)
+				}
+			case *network.EventRequestWillBeSent:
+				// リダイレクトは同じrequestIdで届くため、URLだけを更新する
+				if _, ok := inflight[ev.RequestID]; !ok {
+					load.metrics.Requests++
+				}
+				inflight[ev.RequestID] = browserRequest{url: ev.Request.URL, resourceType: string(ev.Type)}
+				if !idleSince.IsZero() {
+					idleSince = time.Time{}
+					idle.Stop()
+				}
+			case *network.EventResponseReceived:
+				status := int(ev.Response.Status)
+				if ev.Type == network.ResourceTypeDocument {
+					statuses[ev.RequestID] = status
+				}
+				if status >= 400 {
+					addFailed(ev.RequestID, FailedResource{URL: ev.Response.URL, Type: string(ev.Type), Status: status})
+				}
+			case *network.EventLoadingFinished:
+				delete(inflight, ev.RequestID)
+			case *network.EventLoadingFailed:
+				req := inflight[ev.RequestID]
+				delete(inflight, ev.RequestID)
+				// ページの遷移などで取り消されたリクエストは失敗として扱わない
+				if !ev.Canceled {
+					reason := ev.ErrorText
+					if ev.BlockedReason != "" {
+						reason += " (blocked: " + string(ev.BlockedReason) + ")"
+					}
+					addFailed(ev.RequestID, FailedResource{URL: req.url, Type: string(ev.Type), Error: reason})
+				}
+			case *runtime.EventConsoleAPICalled:
+				if ev.Type != runtime.APITypeError && ev.Type != runtime.APITypeAssert {
+					continue
+				}
+				var parts []string
+				for _, arg := range ev.Args {
+					var s string
+					switch {
+					case json.Unmarshal(arg.Value, &s) == nil:
+						parts = append(parts, s)
+					case arg.Description != "":
+						parts = append(parts, arg.Description)
+					case len(arg.Value) > 0:
+						parts = append(parts, string(arg.Value))
+					}
+				}
+				addConsoleError(strings.Join(parts, " "))
+			case *runtime.EventExceptionThrown:
+				if ev.ExceptionDetails == nil {
+					continue
+				}
+				message := ev.ExceptionDetails.Text
+				if ev.ExceptionDetails.Exception != nil && ev.ExceptionDetails.Exception.Description != "" {
+					message = ev.ExceptionDetails.Exception.Description
+				}
+				// スタックトレースは先頭の行だけを残す
+				message, _, _ = strings.Cut(message, "\n")
+				addConsoleError(message)
+			case *cdplog.EventEntryAdded:
+				entry := ev.Entry
+				// ネットワークとスクリプトのエラーは上のイベントで記録済み（混在コンテンツ・CSPの違反などを拾う）
+				if entry != nil && entry.Level == cdplog.LevelError && entry.Source != cdplog.SourceNetwork && entry.Source != cdplog.SourceJavascript && entry.Source != "console-api" {
+					addConsoleError(entry.Text)
+				}
+			}
+		}
+	}
+}
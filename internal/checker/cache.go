@@ -0,0 +1,122 @@
+package checker
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"healthcheck/internal/config"
+)
+
+// CacheStatus CDN・キャッシュサーバーが応答に付けたキャッシュの状態
+type CacheStatus struct {
+	Status  string            `json:"status"`            // hit / miss / stale / expired / bypass / revalidated / dynamic など（判定できない場合はunknown）
+	Age     *int              `json:"age,omitempty"`     // Ageヘッダーの秒数（キャッシュに保存されてからの経過時間）
+	Headers map[string]string `json:"headers,omitempty"` // 判定に使ったヘッダーの値
+}
+
+// Hit キャッシュから応答したか
+func (s *CacheStatus) Hit() bool {
+	return s.Status == "hit"
+}
+
+// cacheStatusHeaders キャッシュの状態を示すヘッダー（先にあるものほど優先して判定に使う）
+// CF-Cache-Status: Cloudflare、Cache-Status: RFC 9211、X-Cache-Status: nginx、X-Cache: CloudFront・Fastly・Varnishなど
+var cacheStatusHeaders = []string{"CF-Cache-Status", "Cache-Status", "X-Cache-Status", "X-Cache"}
+
+// recordCache 応答のキャッシュに関するヘッダーを記録（いずれもない場合は記録しない）
+func recordCache(resp *http.Response, result *CheckResult) {
+	headers := make(map[string]string)
+	for _, name := range append(cacheStatusHeaders, "Age") {
+		if v := strings.TrimSpace(resp.Header.Get(name)); v != "" {
+			headers[name] = v
+		}
+	}
+	if len(headers) == 0 {
+		return
+	}
+
+	status := &CacheStatus{Status: "unknown", Headers: headers}
+	if v, ok := headers["Age"]; ok {
+		if age, err := strconv.Atoi(v); err == nil && age >= 0 {
+			status.Age = &age
+		}
+	}
+	for _, name := range cacheStatusHeaders {
+		if v, ok := headers[name]; ok {
+			status.Status = parseCacheStatus(name, v)
+			break
+		}
+	}
+	// 状態のヘッダーがなくAgeが正の場合はキャッシュから応答したとみなす
+	if status.Status == "unknown" && status.Age != nil {
+		status.Status = "miss"
+		if *status.Age > 0 {
+			status.Status = "hit"
+		}
+	}
+	result.Cache = status
+}
+
+// parseCacheStatus ヘッダーの値をキャッシュの状態に変換
+func parseCacheStatus(name, value string) string {
+	value = strings.ToLower(value)
+	switch name {
+	case "Cache-Status":
+		// 複数のキャッシュを経由した場合は最後（クライアントに最も近いキャッシュ）の値を使う
+		// 例: "ExampleCache; hit, CDN; fwd=uri-miss"
+		entries := strings.Split(value, ",")
+		params := strings.Split(entries[len(entries)-1], ";")
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if p == "hit" {
+				return "hit"
+			}
+			if fwd, ok := strings.CutPrefix(p, "fwd="); ok {
+				switch fwd {
+				case "stale", "bypass":
+					return fwd
+				}
+				return "miss"
+			}
+		}
+		return "unknown"
+	case "X-Cache":
+		// Fastlyは経由したキャッシュごとに"MISS, HIT"のように追加するため最後の値を使う
+		entries := strings.Split(value, ",")
+		value = strings.TrimSpace(entries[len(entries)-1])
+	}
+
+	// CloudFrontの"Hit from cloudfront"・"RefreshHit from cloudfront"などの形式を含める
+	for _, s := range []string{"hit", "miss", "stale", "expired", "bypass", "revalidated", "updating", "dynamic"} {
+		if strings.Contains(value, s) {
+			if s == "updating" {
+				return "stale"
+			}
+			return s
+		}
+	}
+	if value == "pass" {
+		return "bypass"
+	}
+	return "unknown"
+}
+
+// checkCache キャッシュの状態を期待する値と比較（hitはキャッシュから応答したこと、missはキャッシュから応答していないこと）
+func checkCache(target config.Target, result *CheckResult) {
+	if target.ExpectedCache == "" {
+		return
+	}
+	status := "none"
+	hit := false
+	if result.Cache != nil {
+		status, hit = result.Cache.Status, result.Cache.Hit()
+	}
+	if (target.ExpectedCache == "hit") == hit {
+		return
+	}
+	result.Success = false
+	result.Error = CategoryCacheMismatch
+	result.ErrorMessage = fmt.Sprintf("expected cache %s, got %s", target.ExpectedCache, status)
+}
@@ -0,0 +1,110 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// ErrorCategory 失敗の種類（結果のerrorに記録し、集計・通知・ダッシュボードで分類に使う）
+type ErrorCategory string
+
+// 失敗の種類
+const (
+	// ネットワーク・接続
+	CategoryDNSFailure     ErrorCategory = "dns_failure"     // 名前解決に失敗した
+	CategoryConnectRefused ErrorCategory = "connect_refused" // 接続を拒否された
+	CategoryTLSError       ErrorCategory = "tls_error"       // TLSハンドシェイク・証明書の検証に失敗した
+	CategoryTimeout        ErrorCategory = "timeout"         // リクエストを送信し終える前にタイムアウトした
+	CategoryHeaderTimeout  ErrorCategory = "header_timeout"  // 接続後、期限までに応答ヘッダーが返らなかった
+	CategoryBodyTimeout    ErrorCategory = "body_timeout"    // 応答ヘッダーの後、期限までに本文を受信し終えなかった
+	CategoryEmptyResponse  ErrorCategory = "empty_response"  // 接続後、何も返さずに接続を閉じられた
+	CategoryRequestFailed  ErrorCategory = "request_failed"  // その他のネットワークエラー（接続のリセットなど）
+	CategoryCanceled       ErrorCategory = "canceled"        // 実行が中止された
+
+	// HTTPの応答
+	CategoryHTTP4xx     ErrorCategory = "http_4xx"     // 4xxの応答（429を除く）
+	CategoryHTTP5xx     ErrorCategory = "http_5xx"     // 5xxの応答
+	CategoryRateLimited ErrorCategory = "rate_limited" // 429 Too Many Requests
+	CategoryHTTPError   ErrorCategory = "http_error"   // 期待したステータスコードと異なる（4xx・5xx以外）
+
+	// 成功条件
+	CategoryAssertionFailed ErrorCategory = "assertion_failed" // 成功条件の式・リダイレクト先・Content-Typeなどの検証に失敗した
+	CategoryContentChanged  ErrorCategory = "content_changed"  // 内容のハッシュが基準から変化した
+	CategoryCacheMismatch   ErrorCategory = "cache_mismatch"   // CDNのキャッシュの状態が期待と異なる
+
+	// 設定・実行
+	CategoryInvalidURL        ErrorCategory = "invalid_url"
+	CategoryRequestError      ErrorCategory = "request_error"
+	CategoryInvalidRule       ErrorCategory = "invalid_rule"
+	CategoryAuthFailed        ErrorCategory = "auth_failed"
+	CategoryUnsupportedScheme ErrorCategory = "unsupported_scheme"
+	CategoryExecFailed        ErrorCategory = "exec_failed"    // コマンドが0以外の終了コードで終了した
+	CategoryExecError         ErrorCategory = "exec_error"     // コマンドを起動できなかった
+	CategoryPageError         ErrorCategory = "page_error"     // ページでスクリプトのエラー・サブリソースの失敗・混在コンテンツがあった
+	CategoryBrowserError      ErrorCategory = "browser_error"  // ブラウザを起動・操作できなかった
+	CategoryProtocolError     ErrorCategory = "protocol_error" // SSHのバナー・FTPの応答などが想定と異なる
+
+	// ドメイン
+	CategoryDomainExpiring     ErrorCategory = "domain_expiring"      // ドメインの有効期限が近い・切れている
+	CategoryDomainLookupFailed ErrorCategory = "domain_lookup_failed" // RDAP・WHOISで有効期限を調べられなかった
+
+	// ハートビート
+	CategoryHeartbeatMissed ErrorCategory = "heartbeat_missed"
+	CategoryHeartbeatError  ErrorCategory = "heartbeat_error"
+)
+
+// Retryable リトライで回復する可能性がある失敗か
+func (c ErrorCategory) Retryable() bool {
+	switch c {
+	case CategoryDNSFailure, CategoryConnectRefused, CategoryTLSError, CategoryTimeout,
+		CategoryHeaderTimeout, CategoryBodyTimeout, CategoryEmptyResponse, CategoryRequestFailed:
+		return true
+	}
+	return false
+}
+
+// classifyNetError リクエストのエラーを失敗の種類に分類
+func classifyNetError(err error) ErrorCategory {
+	var (
+		dnsErr       *net.DNSError
+		verifyErr    *tls.CertificateVerificationError
+		recordErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+		netErr       net.Error
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return CategoryCanceled
+	case errors.As(err, &dnsErr):
+		return CategoryDNSFailure
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return CategoryConnectRefused
+	case errors.As(err, &verifyErr), errors.As(err, &recordErr), errors.As(err, &alertErr),
+		errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return CategoryTLSError
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return CategoryTimeout
+	}
+	return CategoryRequestFailed
+}
+
+// statusCategory 期待と異なるステータスコードを失敗の種類に分類
+func statusCategory(code int) ErrorCategory {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return CategoryRateLimited
+	case code >= 400 && code < 500:
+		return CategoryHTTP4xx
+	case code >= 500 && code < 600:
+		return CategoryHTTP5xx
+	}
+	return CategoryHTTPError
+}
@@ -3,43 +3,50 @@ package checker
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"healthcheck/internal/config"
+	"healthcheck/internal/events"
+	"healthcheck/internal/ipinfo"
+	"healthcheck/internal/runs"
+	"healthcheck/internal/tracing"
 )
 
 // Checker HTTPチェックを実行する構造体
 type Checker struct {
-	config     *config.Config
-	httpClient *http.Client
-	domainRate map[string]*rateLimiter
-	globalRate *rateLimiter
-	rateMutex  sync.Mutex
-}
-
-// rateLimiter レート制限を管理する構造体
-type rateLimiter struct {
-	ticker *time.Ticker
-	limit  int
-	count  int
-	mutex  sync.Mutex
+	config         *config.Config
+	providers      map[string]CheckProvider
+	httpClient     *http.Client
+	coldClient     *http.Client // 接続を再利用しないクライアント（connection: cold）
+	http3Client    *http.Client // HTTP/3での接続を試すクライアント（http3）
+	callbackClient *http.Client // 結果をコールバックのURLへ送信するクライアント
+
+	rdapMutex     sync.Mutex
+	rdapBootstrap map[string][]string // TLDごとのRDAPサーバー（domain://のチェックで取得）
+	rdapFetched   time.Time
 }
 
 // NewChecker 新しいCheckerインスタンスを作成
 func NewChecker(cfg *config.Config) *Checker {
 	transport := &http.Transport{
-		DialContext: (&net.Dialer{
-			Timeout: 5 * time.Second,
-		}).DialContext,
-		TLSHandshakeTimeout: 10 * time.Second,
+		DialContext:         dialContext(newDialer(cfg, cfg.ConnectTimeout)),
+		TLSHandshakeTimeout: cfg.TLSHandshakeTimeout,
 		MaxIdleConns:        100,
 		IdleConnTimeout:     90 * time.Second,
+		// DialContextやTLS設定を指定した場合もALPNでHTTP/2を使用する
+		ForceAttemptHTTP2: true,
 	}
 
 	// TLS設定
@@ -53,6 +60,9 @@ func NewChecker(cfg *config.Config) *Checker {
 		Transport: transport,
 		Timeout:   cfg.Timeout,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.Context().Value(noFollowKey{}) != nil {
+				return http.ErrUseLastResponse
+			}
 			if len(via) >= 3 {
 				return fmt.Errorf("stopped after 3 redirects")
 			}
@@ -60,78 +70,68 @@ func NewChecker(cfg *config.Config) *Checker {
 		},
 	}
 
-	return &Checker{
-		config:     cfg,
-		httpClient: client,
-		domainRate: make(map[string]*rateLimiter),
-		globalRate: newRateLimiter(cfg.GlobalRate),
-	}
-}
-
-// newRateLimiter 新しいレート制限器を作成
-func newRateLimiter(limit int) *rateLimiter {
-	rl := &rateLimiter{
-		ticker: time.NewTicker(time.Second),
-		limit:  limit,
-		count:  0,
+	// 毎回TCP接続とTLSハンドシェイクから計測するためのクライアント
+	coldTransport := transport.Clone()
+	coldTransport.DisableKeepAlives = true
+	coldClient := *client
+	coldClient.Transport = coldTransport
+
+	c := &Checker{
+		config:         cfg,
+		httpClient:     client,
+		coldClient:     &coldClient,
+		http3Client:    newHTTP3Client(cfg),
+		callbackClient: newCallbackClient(cfg),
 	}
-	go rl.resetCounter()
-	return rl
+	c.providers = c.newProviders()
+	return c
 }
 
-// resetCounter カウンターをリセット
-func (rl *rateLimiter) resetCounter() {
-	for range rl.ticker.C {
-		rl.mutex.Lock()
-		rl.count = 0
-		rl.mutex.Unlock()
-	}
+// CheckURL 単一URLのチェックを実行
+func (c *Checker) CheckURL(ctx context.Context, targetURL string) *CheckResult {
+	return c.CheckHTTP(ctx, config.Target{URL: targetURL})
 }
 
-// allow リクエストが許可されるかチェック
-func (rl *rateLimiter) allow() bool {
-	rl.mutex.Lock()
-	defer rl.mutex.Unlock()
-	if rl.count < rl.limit {
-		rl.count++
-		return true
+// CheckHTTP 対象のメソッド・ヘッダー・ボディでHTTPリクエストを送りチェック
+func (c *Checker) CheckHTTP(ctx context.Context, target config.Target) *CheckResult {
+	targetURL := target.URL
+	result := &CheckResult{
+		URL:       targetURL,
+		Timestamp: time.Now().UTC(),
+		Success:   false,
 	}
-	return false
-}
 
-// waitForRateLimit レート制限を待機
-func (rl *rateLimiter) waitForRateLimit() {
-	for !rl.allow() {
-		time.Sleep(100 * time.Millisecond)
+	method := target.Method
+	if method == "" {
+		method = http.MethodGet
 	}
-}
-
-// getDomainRateLimiter ドメインごとのレート制限器を取得
-func (c *Checker) getDomainRateLimiter(domain string) *rateLimiter {
-	c.rateMutex.Lock()
-	defer c.rateMutex.Unlock()
-
-	if rl, exists := c.domainRate[domain]; exists {
-		return rl
+	maxLatency := c.config.MaxLatency
+	var targetTimeout time.Duration
+	if target.Timeout != "" {
+		if d, err := time.ParseDuration(target.Timeout); err == nil && d > 0 {
+			maxLatency = d
+			targetTimeout = d
+		}
 	}
 
-	rl := newRateLimiter(c.config.DomainRate)
-	c.domainRate[domain] = rl
-	return rl
-}
-
-// CheckURL 単一URLのチェックを実行
-func (c *Checker) CheckURL(ctx context.Context, targetURL string) *CheckResult {
-	result := &CheckResult{
-		URL:       targetURL,
-		Timestamp: time.Now(),
-		Success:   false,
-	}
+	ctx, span := tracing.Start(ctx, method)
+	span.Client = true
+	span.SetAttribute("url.full", targetURL)
+	defer func() {
+		if result.StatusCode != 0 {
+			span.SetAttribute("http.response.status_code", result.StatusCode)
+		}
+		if !result.Success {
+			span.SetAttribute("error.type", string(result.Error))
+			span.SetError(result.ErrorMessage)
+		}
+		span.Finish()
+	}()
 
 	// URLのパース
 	parsedURL, err := url.Parse(targetURL)
 	if err != nil {
-		result.Error = "invalid_url"
+		result.Error = CategoryInvalidURL
 		result.ErrorMessage = fmt.Sprintf("URL parse error: %v", err)
 		return result
 	}
@@ -139,68 +139,211 @@ func (c *Checker) CheckURL(ctx context.Context, targetURL string) *CheckResult {
 	domain := parsedURL.Hostname()
 
 	// レート制限のチェック
-	c.globalRate.waitForRateLimit()
-	domainRL := c.getDomainRateLimiter(domain)
-	domainRL.waitForRateLimit()
+	c.waitForRateLimit(ctx, domain)
 
-	// DNS解決時間の計測
-	dnsStart := time.Now()
-	_, err = net.LookupHost(domain)
-	dnsDuration := time.Since(dnsStart)
+	// 接続先のアドレスファミリー・IPアドレスを指定した場合は他の接続と共有しない
+	dial, pinned := dialTargetFor(ctx, target)
+	if pinned {
+		ctx = withDialTarget(ctx, dial)
+	}
+
+	// DNS解決時間の計測（接続先のIPアドレスを指定した場合は名前解決しない）
+	// 名前解決の期限を指定した場合は、期限を過ぎた時点で失敗とする
+	var dnsDuration time.Duration
+	if dial.address == "" {
+		dnsCtx, dnsCancel := ctx, context.CancelFunc(func() {})
+		if c.config.DNSTimeout > 0 {
+			dnsCtx, dnsCancel = context.WithTimeout(ctx, c.config.DNSTimeout)
+		}
+		dnsStart := time.Now()
+		result.ResolvedIPs, err = net.DefaultResolver.LookupHost(dnsCtx, domain)
+		dnsDuration = time.Since(dnsStart)
+		dnsCancel()
+		var dnsErr *net.DNSError
+		if c.config.DNSTimeout > 0 && errors.As(err, &dnsErr) && dnsErr.IsTimeout {
+			result.Latency = dnsDuration
+			result.Error = CategoryDNSFailure
+			result.ErrorMessage = fmt.Sprintf("DNS lookup for %s exceeded %v", domain, c.config.DNSTimeout)
+			return result
+		}
+	}
 
 	// HTTPリクエストの開始時間
 	startTime := time.Now()
 
-	// タイムアウト付きコンテキスト
-	reqCtx, cancel := context.WithTimeout(ctx, c.config.MaxLatency)
+	// リダイレクト先を検証する場合はリダイレクトをたどらない
+	if target.ExpectsRedirect() {
+		ctx = withNoFollow(ctx)
+	}
+
+	// タイムアウト付きコンテキスト（ヘッダー・本文の受信期限を過ぎた場合は理由を付けて打ち切る）
+	reqCtx, cancel := context.WithTimeout(ctx, maxLatency)
 	defer cancel()
+	reqCtx, cancelCause := context.WithCancelCause(reqCtx)
+	defer cancelCause(nil)
+	headerTimeout, bodyTimeout := c.readTimeouts(target)
+	reqCtx, stopHeaderDeadline := headerDeadline(reqCtx, headerTimeout, cancelCause)
 
 	// HTTPリクエストの作成
-	req, err := http.NewRequestWithContext(reqCtx, "GET", targetURL, nil)
+	var body io.Reader
+	if target.Body != "" {
+		body = strings.NewReader(target.Body)
+	}
+	req, err := http.NewRequestWithContext(reqCtx, method, targetURL, body)
 	if err != nil {
-		result.Error = "request_error"
+		result.Error = CategoryRequestError
 		result.ErrorMessage = fmt.Sprintf("Request creation error: %v", err)
 		return result
 	}
 
 	req.Header.Set("User-Agent", "HealthCheck/1.0")
+	for name, value := range target.Headers {
+		req.Header.Set(name, value)
+	}
+	negotiateEncoding(target, req)
+	if target.Auth != "" {
+		if err := c.authorize(ctx, target, req); err != nil {
+			result.Error = CategoryAuthFailed
+			result.ErrorMessage = fmt.Sprintf("Failed to obtain access token: %v", err)
+			return result
+		}
+	}
+
+	span.Inject(req.Header)
+
+	// フェーズごとの所要時間を記録
+	result.Phases = &Phases{}
+	phases := newPhaseTracer(result.Phases)
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), phases.clientTrace()))
 
 	// HTTPリクエストの実行
-	resp, err := c.httpClient.Do(req)
+	client := c.httpClient
+	result.Connection = "reuse"
+	if target.Connection == "cold" || pinned {
+		client = c.coldClient
+		result.Connection = "cold"
+	}
+	// 対象のtimeoutが全体のtimeoutより長い場合は、クライアントのタイムアウトで先に打ち切らない
+	if client.Timeout > 0 && targetTimeout > client.Timeout {
+		longer := *client
+		longer.Timeout = targetTimeout
+		client = &longer
+	}
+	resp, err := client.Do(req)
+	stopHeaderDeadline()
+	result.ConnectionReused, result.RemoteAddr = phases.conn()
+	if addr, err := net.ResolveTCPAddr("tcp", result.RemoteAddr); err == nil && result.RemoteAddr != "" {
+		result.IPFamily = ipFamily(addr.IP)
+	} else if pinned && dial.network != "" {
+		// 接続できなかった場合は指定したアドレスファミリー（tcp4 / tcp6）を記録
+		result.IPFamily = "ipv" + strings.TrimPrefix(dial.network, "tcp")
+	}
 	responseTime := time.Since(startTime)
+	phases.record(ctx)
 
 	// レイテンシの計算（DNS解決 + 応答時間）
 	result.Latency = dnsDuration + responseTime
 
 	// エラーチェック
 	if err != nil {
-		result.Error = "request_failed"
-		result.ErrorMessage = err.Error()
-		if responseTime >= c.config.MaxLatency {
-			result.Error = "timeout"
-			result.ErrorMessage = fmt.Sprintf("Response time exceeded %v: %v", c.config.MaxLatency, err)
-		}
+		classifyRequestError(reqCtx, err, phases.sent(), responseTime, maxLatency, headerTimeout, result)
 		return result
 	}
 	defer resp.Body.Close()
 
 	// 応答時間が30秒を超えた場合
-	if responseTime > c.config.MaxLatency {
+	if responseTime > maxLatency {
 		result.StatusCode = resp.StatusCode
 		result.ResponseTime = responseTime
-		result.Error = "timeout"
-		result.ErrorMessage = fmt.Sprintf("Response time %v exceeded maximum %v", responseTime, c.config.MaxLatency)
+		result.Error = CategoryTimeout
+		result.ErrorMessage = fmt.Sprintf("Response time %v exceeded maximum %v", responseTime, maxLatency)
 		return result
 	}
 
 	// ステータスコードのチェック
 	result.StatusCode = resp.StatusCode
 	result.ResponseTime = responseTime
-	result.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	recordProtocol(resp, result)
+	if target.HTTP3 && parsedURL.Scheme == "https" {
+		// 本文の受信速度の計測に影響しないよう、チェックを終えてから試す
+		defer c.probeHTTP3(ctx, target, method, result)
+	}
+	recordCache(resp, result)
+	if err := recordMedia(resp, result); err != nil {
+		result.Error = CategoryRequestFailed
+		result.ErrorMessage = err.Error()
+		return result
+	}
+
+	// 本文を受信してサイズと受信速度を記録（失敗時の記録と成功条件の式のために先頭を保持する）
+	keep := int64(c.config.SnippetBytes)
+	if target.Success != "" {
+		if rule, err := compiledRule(target.Success); err == nil && rule.UsesBody() {
+			keep = max(keep, maxRuleBodySize)
+		}
+	}
+	if target.WatchContent || target.ScanResources {
+		keep = max(keep, c.config.MaxBodyBytes)
+	}
+	if bodyTimeout > 0 {
+		timer := time.AfterFunc(bodyTimeout, func() { cancelCause(errBodyTimeout) })
+		defer timer.Stop()
+	}
+	content, err := c.readBody(ctx, resp, result, keep)
+	if err != nil {
+		classifyBodyError(reqCtx, err, bodyTimeout, result)
+		return result
+	}
+	defer func() {
+		if !result.Success {
+			c.captureSnippet(resp, content, result)
+		}
+	}()
+	// 他の条件をすべて満たした場合に内容の変化を調べる
+	if target.WatchContent {
+		defer func() {
+			if result.Success {
+				c.checkContent(target, content, result)
+			}
+		}()
+	}
+	// ページのHTMLが読み込むサブリソースを確認する
+	if target.ScanResources {
+		defer func() {
+			if result.Success && isHTML(result.ContentType) {
+				c.scanResources(ctx, resp, content, result)
+			}
+		}()
+	}
+	defer func() {
+		if result.Success {
+			checkMedia(target, result)
+		}
+		if result.Success {
+			checkCache(target, result)
+		}
+	}()
+
+	if target.Success != "" {
+		c.evalSuccess(target, resp, content, result)
+		return result
+	}
+	if target.ExpectsRedirect() {
+		checkRedirect(target, resp, result)
+		return result
+	}
+	if target.ExpectedStatus > 0 {
+		result.Success = resp.StatusCode == target.ExpectedStatus
+	} else {
+		result.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+	}
 
 	if !result.Success {
-		result.Error = "http_error"
+		result.Error = statusCategory(resp.StatusCode)
 		result.ErrorMessage = fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		if target.ExpectedStatus > 0 {
+			result.ErrorMessage = fmt.Sprintf("expected HTTP %d, got %s", target.ExpectedStatus, resp.Status)
+		}
 	}
 
 	return result
@@ -208,57 +351,184 @@ func (c *Checker) CheckURL(ctx context.Context, targetURL string) *CheckResult {
 
 // CheckURLWithRetry リトライ機能付きでURLをチェック
 func (c *Checker) CheckURLWithRetry(ctx context.Context, targetURL string) *CheckResult {
+	return c.CheckHTTPWithRetry(ctx, config.Target{URL: targetURL})
+}
+
+// CheckHTTPWithRetry リトライ機能付きで対象をHTTPでチェック
+func (c *Checker) CheckHTTPWithRetry(ctx context.Context, target config.Target) *CheckResult {
+	targetURL := target.URL
 	var result *CheckResult
 	backoff := 1 * time.Second
 
+	ctx, span := tracing.Start(ctx, "check")
+	defer span.Finish()
+	span.SetAttribute("url.full", targetURL)
+
 	for attempt := 0; attempt <= c.config.Retries; attempt++ {
+		span.SetAttribute("healthcheck.attempts", attempt+1)
 		if attempt > 0 {
 			// 指数バックオフ
 			time.Sleep(backoff)
 			backoff *= 2
 		}
 
-		result = c.CheckURL(ctx, targetURL)
+		result = c.CheckHTTP(ctx, target)
+		// トークンが失効していた場合は取り直して1回だけ再試行
+		if result.StatusCode == http.StatusUnauthorized && target.Auth != "" && c.invalidateToken(target.Auth) {
+			result = c.CheckHTTP(ctx, target)
+		}
+		// キャッシュのヒットを期待する対象は、最初のリクエストでキャッシュが温まったとみなして1回だけ再試行
+		if result.Error == CategoryCacheMismatch && target.ExpectedCache == "hit" {
+			result = c.CheckHTTP(ctx, target)
+		}
 
 		// 成功した場合、またはリトライ不可能なエラーの場合は終了
-		if result.Success || (result.Error != "timeout" && result.Error != "request_failed") {
+		if result.Success || !result.Error.Retryable() {
 			break
 		}
 	}
 
+	// 失敗した場合は原因のヒントを付与
+	if !result.Success && c.config.RootCauseHints {
+		result.Hint = c.Diagnose(ctx, target, result)
+	}
+	// 応答を受け取れなかった場合は宛先までの経路を調べる
+	if !result.Success && result.StatusCode == 0 && c.config.Traceroute &&
+		(result.Error == CategoryTimeout || result.Error == CategoryRequestFailed) {
+		c.traceFailure(ctx, target, result)
+	}
+	// 接続先のIPアドレスを逆引きし、地域とAS番号を調べる（応答したCDNの拠点やオリジンの確認用）
+	opts := ipinfo.Options{ReverseDNS: c.config.ReverseDNS, Databases: c.config.GeoIPDatabases}
+	if host, _, err := net.SplitHostPort(result.RemoteAddr); err == nil && opts.Enabled() {
+		result.IPInfo = ipinfo.Lookup(ctx, host, opts)
+	}
+
+	span.SetAttribute("healthcheck.success", result.Success)
+	if !result.Success {
+		span.SetError(result.ErrorMessage)
+		if result.Hint != "" {
+			span.SetAttribute("healthcheck.hint", result.Hint)
+		}
+	}
+
 	return result
 }
 
+// Progress 並列チェックの進捗（対象のチェックの開始時と完了時に送信する）
+type Progress struct {
+	Total     int          `json:"total"`            // 対象の数
+	Completed int          `json:"completed"`        // 完了した数
+	Running   []string     `json:"running"`          // チェック中の対象のURL
+	Result    *CheckResult `json:"result,omitempty"` // 完了した対象の結果（開始時はnil）
+}
+
 // CheckURLs 複数のURLを並列でチェック
-func (c *Checker) CheckURLs(ctx context.Context, urls []string, resultChan chan<- *CheckResult, progressChan chan<- int) {
+func (c *Checker) CheckURLs(ctx context.Context, urls []string, resultChan chan<- *CheckResult, progressChan chan<- Progress) {
+	targets := make([]config.Target, len(urls))
+	for i, u := range urls {
+		targets[i] = config.Target{Name: u, URL: u}
+	}
+	c.CheckTargets(ctx, targets, resultChan, progressChan)
+}
+
+// CheckTargets 複数の対象をスキームに応じたチェック方法で並列にチェック
+// 優先度がhighの対象から順に並列度の枠を割り当てる（同じ優先度の中では指定した順）
+// 同じ対象が複数回含まれる場合、2回目以降の結果はDuplicateとする。設定のduplicatesがdedupeの場合は
+// 1回だけチェックし、その結果の複製を残りの出現の結果として送信する
+// progressChanには対象ごとに開始と完了の2回送信するため、対象数の2倍のバッファを持たせるか読み続けること
+// WithStaggerで開始の遅延を指定した場合は、対象ごとに開始からその時間だけ待ってから並列度の枠を割り当てる
+func (c *Checker) CheckTargets(ctx context.Context, targets []config.Target, resultChan chan<- *CheckResult, progressChan chan<- Progress) {
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, c.config.Concurrency)
 	completed := 0
+	var running []string
 	var completedMutex sync.Mutex
 
-	for _, targetURL := range urls {
+	runID := tracing.RunID(ctx)
+	project := events.ProjectFromContext(ctx)
+	runs.AddTotal(runID, len(targets))
+
+	dedupe := c.config.Duplicates == "dedupe"
+	keys := make([]string, len(targets))
+	occurrences := make(map[string]int, len(targets))
+	for i, target := range targets {
+		keys[i] = duplicateKey(target)
+		occurrences[keys[i]]++
+	}
+
+	// 定期チェックで開始時刻を分散する場合は、開始の遅延の短い順に開始する
+	start := time.Now()
+	delays, order := staggerDelays(ctx, targets, priorityOrder(targets))
+
+	seen := make(map[string]bool, len(targets))
+	for _, i := range order {
+		target, key := targets[i], keys[i]
+		duplicate := seen[key]
+		seen[key] = true
+		if duplicate && dedupe {
+			continue
+		}
+		if delays != nil {
+			waitUntil(ctx, start.Add(delays[i]))
+		}
+
+		// セマフォで並列度を制御（枠を確保してから開始し、優先度の順を守る）
+		semaphore <- struct{}{}
 		wg.Add(1)
-		go func(url string) {
+		go func(target config.Target, key string, duplicate bool) {
 			defer wg.Done()
-
-			// セマフォで並列度を制御
-			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			// URLチェックの実行
-			result := c.CheckURLWithRetry(ctx, url)
+			if progressChan != nil {
+				completedMutex.Lock()
+				running = append(running, target.URL)
+				progressChan <- Progress{Total: len(targets), Completed: completed, Running: slices.Clone(running)}
+				completedMutex.Unlock()
+			}
+
+			// チェックの実行
+			result := c.Check(ctx, target)
+			if result.Tags == nil {
+				result.Tags = target.Tags
+			}
+			result.Severity = target.Severity
+			result.Region = c.config.Region
+			result.Duplicate = duplicate
+			results := []*CheckResult{result}
+			if dedupe {
+				for range occurrences[key] - 1 {
+					copied := *result
+					copied.Duplicate = true
+					results = append(results, &copied)
+				}
+			}
+			for _, r := range results {
+				events.Publish(events.Event{Type: "result", RunID: runID, Project: project, Data: r})
+				runs.Record(runID, r.Success)
+			}
+			slog.DebugContext(ctx, "checked", "url", result.URL, "success", result.Success, "status", result.StatusCode,
+				"response_time", result.ResponseTime, "error", result.Error, "occurrences", len(results))
 
 			// 結果を送信
-			resultChan <- result
+			for _, r := range results {
+				resultChan <- r
+			}
 
 			// 進捗を更新
 			completedMutex.Lock()
-			completed++
 			if progressChan != nil {
-				progressChan <- completed
+				if i := slices.Index(running, target.URL); i >= 0 {
+					running = slices.Delete(running, i, i+1)
+				}
+				for _, r := range results {
+					completed++
+					progressChan <- Progress{Total: len(targets), Completed: completed, Running: slices.Clone(running), Result: r}
+				}
+			} else {
+				completed += len(results)
 			}
 			completedMutex.Unlock()
-		}(targetURL)
+		}(target, key, duplicate)
 	}
 
 	wg.Wait()
@@ -268,6 +538,34 @@ func (c *Checker) CheckURLs(ctx context.Context, urls []string, resultChan chan<
 	}
 }
 
+// priorityOrder 対象をチェックする順（優先度がhighの対象を先にした添字の並び）
+func priorityOrder(targets []config.Target) []int {
+	order := make([]int, len(targets))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		switch {
+		case targets[a].HighPriority() && !targets[b].HighPriority():
+			return -1
+		case !targets[a].HighPriority() && targets[b].HighPriority():
+			return 1
+		}
+		return 0
+	})
+	return order
+}
+
+// duplicateKey 同じ対象かを判定するキー（名前以外の設定がすべて同じ場合に同じ対象とする）
+func duplicateKey(target config.Target) string {
+	target.Name = ""
+	data, err := json.Marshal(target)
+	if err != nil {
+		return target.URL
+	}
+	return string(data)
+}
+
 // ExtractDomain URLからドメインを抽出
 func ExtractDomain(targetURL string) string {
 	parsedURL, err := url.Parse(targetURL)
@@ -280,3 +578,27 @@ func ExtractDomain(targetURL string) string {
 	}
 	return host
 }
+
+// 2階層のセカンドレベルドメインとして扱うラベル（example.co.jpなど）
+var secondLevelLabels = map[string]bool{
+	"co": true, "ne": true, "or": true, "ac": true, "go": true, "ed": true, "lg": true,
+	"com": true, "net": true, "org": true, "gov": true, "edu": true,
+}
+
+// BaseDomain URLから登録ドメイン（例: api.example.com → example.com）を簡易的に抽出
+func BaseDomain(targetURL string) string {
+	host := ExtractDomain(targetURL)
+	if net.ParseIP(host) != nil {
+		return host
+	}
+
+	labels := strings.Split(host, ".")
+	if len(labels) <= 2 {
+		return host
+	}
+	n := 2
+	if len(labels[len(labels)-1]) == 2 && secondLevelLabels[labels[len(labels)-2]] {
+		n = 3
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
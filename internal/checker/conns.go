@@ -0,0 +1,33 @@
+package checker
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// openConns チェックのHTTPクライアントが開いている接続の数（全てのチェッカーで共有）
+var openConns atomic.Int64
+
+// countedConn 閉じたときに開いている接続の数を減らす接続
+type countedConn struct {
+	net.Conn
+	once sync.Once
+}
+
+// trackConn 接続を開いている接続の数に含める
+func trackConn(conn net.Conn) net.Conn {
+	openConns.Add(1)
+	return &countedConn{Conn: conn}
+}
+
+// Close 接続を閉じる（複数回呼ばれても1回だけ数える）
+func (c *countedConn) Close() error {
+	c.once.Do(func() { openConns.Add(-1) })
+	return c.Conn.Close()
+}
+
+// OpenConnections チェックのHTTPクライアントが現在開いている接続の数
+func OpenConnections() int64 {
+	return openConns.Load()
+}
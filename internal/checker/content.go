@@ -0,0 +1,217 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// ContentBaselinesFile 内容の変化の検出で基準とするハッシュを保存するファイル
+var ContentBaselinesFile = "content_baselines.json"
+
+// ErrNoContentBaseline 指定した対象の内容の基準がない
+var ErrNoContentBaseline = errors.New("no content baseline")
+
+// ContentBaseline 対象の内容の基準
+type ContentBaseline struct {
+	Hash     string    `json:"hash"`               // 基準とする内容のハッシュ（SHA-256）
+	Selector string    `json:"selector,omitempty"` // ハッシュを計算した範囲のセレクター
+	Since    time.Time `json:"since"`              // 基準とした日時
+	Latest   string    `json:"latest,omitempty"`   // 基準と異なる最新の内容のハッシュ
+	Changed  time.Time `json:"changed,omitzero"`   // 基準と異なる内容を最初に検出した日時
+}
+
+// baselineStore 対象のURLごとの内容の基準（チェッカー間で共有し、ファイルに保存する）
+type baselineStore struct {
+	mutex     sync.Mutex
+	loaded    bool
+	baselines map[string]*ContentBaseline
+}
+
+// contentBaselines 内容の基準の保存先
+var contentBaselines = &baselineStore{}
+
+// selectorCache コンパイル済みのセレクター
+var selectorCache sync.Map
+
+// compiledSelector コンパイル済みのセレクターを返す
+func compiledSelector(source string) (*Selector, error) {
+	if s, ok := selectorCache.Load(source); ok {
+		return s.(*Selector), nil
+	}
+	s, err := CompileSelector(source)
+	if err != nil {
+		return nil, err
+	}
+	selectorCache.Store(source, s)
+	return s, nil
+}
+
+// ValidateSelectors 対象の内容のセレクターがすべて解析できるか検証
+func ValidateSelectors(targets []config.Target) error {
+	for i, t := range targets {
+		if t.ContentSelector == "" {
+			continue
+		}
+		if _, err := CompileSelector(t.ContentSelector); err != nil {
+			return fmt.Errorf("target %d: invalid content_selector %q: %w", i+1, t.ContentSelector, err)
+		}
+	}
+	return nil
+}
+
+// ContentHash 本文（セレクターを指定した場合は一致した要素）のハッシュを計算
+func ContentHash(body []byte, selector string) (string, error) {
+	data := string(body)
+	if selector != "" {
+		s, err := compiledSelector(selector)
+		if err != nil {
+			return "", err
+		}
+		parts := s.Select(data)
+		if len(parts) == 0 {
+			return "", fmt.Errorf("content selector %q matched no elements", selector)
+		}
+		data = strings.Join(parts, "\n")
+	}
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// checkContent 内容のハッシュを基準と比較し、変化していれば失敗とする
+// 基準がない場合（セレクターを変更した場合を含む）は今回の内容を基準にする
+func (c *Checker) checkContent(target config.Target, body []byte, result *CheckResult) {
+	hash, err := ContentHash(body, target.ContentSelector)
+	if err != nil {
+		result.Success = false
+		result.Error = CategoryAssertionFailed
+		result.ErrorMessage = err.Error()
+		return
+	}
+	result.ContentHash = hash
+
+	baseline, changed := contentBaselines.observe(target.URL, target.ContentSelector, hash, result.Timestamp)
+	if changed {
+		result.Success = false
+		result.Error = CategoryContentChanged
+		result.ErrorMessage = fmt.Sprintf("content changed since %s (baseline %s, got %s)",
+			baseline.Since.Format(time.RFC3339), shortHash(baseline.Hash), shortHash(hash))
+	}
+}
+
+// shortHash 表示用に短縮したハッシュ
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// ContentBaselines 保存されている内容の基準を対象のURLごとに返す
+func ContentBaselines() map[string]ContentBaseline {
+	s := contentBaselines
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.load()
+
+	baselines := make(map[string]ContentBaseline, len(s.baselines))
+	for u, b := range s.baselines {
+		baselines[u] = *b
+	}
+	return baselines
+}
+
+// AcceptContent 検出した内容の変化を承認し、最新の内容を新しい基準にする
+func AcceptContent(targetURL string) (ContentBaseline, error) {
+	s := contentBaselines
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.load()
+
+	b, ok := s.baselines[targetURL]
+	if !ok {
+		return ContentBaseline{}, fmt.Errorf("%w for %s", ErrNoContentBaseline, targetURL)
+	}
+	if b.Latest == "" {
+		return *b, nil
+	}
+	b.Hash, b.Since = b.Latest, time.Now()
+	b.Latest, b.Changed = "", time.Time{}
+	if err := s.save(); err != nil {
+		return *b, err
+	}
+	return *b, nil
+}
+
+// observe 今回の内容のハッシュを記録し、基準と異なる場合はtrueを返す
+func (s *baselineStore) observe(targetURL, selector, hash string, at time.Time) (ContentBaseline, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.load()
+
+	b, ok := s.baselines[targetURL]
+	if !ok || b.Selector != selector {
+		b = &ContentBaseline{Hash: hash, Selector: selector, Since: at}
+		s.baselines[targetURL] = b
+	} else if b.Hash == hash {
+		if b.Latest == "" {
+			return *b, false
+		}
+		// 元の内容に戻った
+		b.Latest, b.Changed = "", time.Time{}
+	} else {
+		if b.Latest == hash {
+			return *b, true
+		}
+		b.Latest = hash
+		if b.Changed.IsZero() {
+			b.Changed = at
+		}
+	}
+
+	if err := s.save(); err != nil {
+		slog.Warn("failed to save content baselines", "error", err)
+	}
+	return *b, b.Latest != ""
+}
+
+// load ファイルから基準を読み込む（初回のみ、ロックを保持して呼び出す）
+func (s *baselineStore) load() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	s.baselines = make(map[string]*ContentBaseline)
+
+	data, err := os.ReadFile(ContentBaselinesFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("failed to read content baselines", "error", err)
+		}
+		return
+	}
+	if err := json.Unmarshal(data, &s.baselines); err != nil {
+		slog.Warn("failed to parse content baselines", "error", err)
+	}
+}
+
+// save 基準をファイルに保存（ロックを保持して呼び出す）
+func (s *baselineStore) save() error {
+	data, err := json.MarshalIndent(s.baselines, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if err := os.WriteFile(ContentBaselinesFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package checker
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// CorrelationKeys 同時に失敗した場合にまとめる単位（同じドメイン・同じAS・同じタグ）
+// ドメイン・AS・タグ（キーの順）の順に返す。ASは接続先のAS番号が分かる場合のみ含める
+func CorrelationKeys(targetURL string, asn uint64, tags map[string]string) []string {
+	keys := []string{"domain:" + BaseDomain(targetURL)}
+	if asn != 0 {
+		keys = append(keys, fmt.Sprintf("asn:AS%d", asn))
+	}
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		keys = append(keys, "tag:"+k+"="+tags[k])
+	}
+	return keys
+}
+
+// CorrelationGroup 相関の単位と、その単位に含まれる要素の番号（昇順）
+type CorrelationGroup struct {
+	Key     string
+	Members []int
+}
+
+// SelectCorrelated 候補のグループのうち要素の多いものから順に要素を割り当て、相関イベントとするグループを返す
+// 1つの要素は1つのグループにのみ含め、割り当て済みの要素を除いてminTargets以上残ったグループだけを選ぶ
+// 要素の数が同じ場合は候補の順を優先し、返すグループは最初の要素の番号の順に並べる
+func SelectCorrelated(candidates []CorrelationGroup, minTargets int) []CorrelationGroup {
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(len(candidates[b].Members), len(candidates[a].Members))
+	})
+
+	assigned := make(map[int]bool)
+	var selected []CorrelationGroup
+	for _, i := range order {
+		var members []int
+		for _, m := range candidates[i].Members {
+			if !assigned[m] {
+				members = append(members, m)
+			}
+		}
+		if len(members) == 0 || len(members) < minTargets {
+			continue
+		}
+		for _, m := range members {
+			assigned[m] = true
+		}
+		selected = append(selected, CorrelationGroup{Key: candidates[i].Key, Members: members})
+	}
+	slices.SortFunc(selected, func(a, b CorrelationGroup) int {
+		return cmp.Compare(a.Members[0], b.Members[0])
+	})
+	return selected
+}
@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// deniedAddress 接続先のアドレス（IPアドレス:ポート）が接続しないネットワーク（denied_networks）に含まれる場合はエラー
+func deniedAddress(networks []netip.Prefix, address string) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	ip := addrPort.Addr().WithZone("").Unmap()
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return fmt.Errorf("connection to %s is denied by denied_networks (%s)", ip, n)
+		}
+	}
+	return nil
+}
+
+// deniedControl 名前解決した後の接続先をdenied_networksと照合するnet.DialerのControl（指定がない場合はnil）
+// リダイレクト先やDNSの応答が変わった場合も、実際に接続するアドレスで判定する
+func deniedControl(networks []netip.Prefix) func(network, address string, c syscall.RawConn) error {
+	if len(networks) == 0 {
+		return nil
+	}
+	return func(network, address string, c syscall.RawConn) error {
+		return deniedAddress(networks, address)
+	}
+}
+
+// newDialer 設定の接続の期限とdenied_networksを適用したnet.Dialer
+func newDialer(cfg *config.Config, timeout time.Duration) *net.Dialer {
+	return &net.Dialer{Timeout: timeout, Control: deniedControl(cfg.DeniedNetworks)}
+}
+
+// CallbackClient 結果をコールバックのURLへ送信するクライアント
+// 対象と同じくdenied_networksのアドレスには接続せず、リダイレクトはたどらない（プロキシも使わない）
+func (c *Checker) CallbackClient() *http.Client {
+	return c.callbackClient
+}
+
+// newCallbackClient CallbackClientで返すクライアントを作成
+func newCallbackClient(cfg *config.Config) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         newDialer(cfg, cfg.ConnectTimeout).DialContext,
+			TLSHandshakeTimeout: cfg.TLSHandshakeTimeout,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,161 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"syscall"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// probeTimeout 原因調査の各プローブのタイムアウト
+const probeTimeout = 3 * time.Second
+
+// maxProbeAddrs TCP接続を試すアドレス数の上限
+const maxProbeAddrs = 4
+
+// Diagnose 失敗した結果に対してDNS・TCP・TLSの補助プローブを実行し、原因のヒントを1行で返す
+// 対象にoriginを指定した場合は、オリジンを直接チェックした結果をCDN経由の結果と比べて付け加える
+func (c *Checker) Diagnose(ctx context.Context, target config.Target, result *CheckResult) string {
+	hint := c.probeNetwork(ctx, result)
+	if target.Origin != "" {
+		hint += "; " + c.probeOrigin(ctx, target)
+	}
+	return hint
+}
+
+// probeNetwork URLのホストへのDNS・TCP・TLSの補助プローブを実行し、失敗した段階を1行で返す
+func (c *Checker) probeNetwork(ctx context.Context, result *CheckResult) string {
+	parsedURL, err := url.Parse(result.URL)
+	if err != nil || parsedURL.Hostname() == "" {
+		return "URL is invalid"
+	}
+	host := parsedURL.Hostname()
+	port := parsedURL.Port()
+	if port == "" {
+		port = "80"
+		if parsedURL.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	// DNS
+	dnsCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	addrs, err := net.DefaultResolver.LookupIPAddr(dnsCtx, host)
+	cancel()
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return fmt.Sprintf("DNS: %s does not resolve (NXDOMAIN)", host)
+		}
+		return fmt.Sprintf("DNS lookup for %s failed: %v", host, err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Sprintf("DNS: %s has no addresses", host)
+	}
+
+	// TCP（解決されたアドレスごと）
+	var reachable []string
+	var lastErr error
+	for i, addr := range addrs {
+		if i >= maxProbeAddrs {
+			break
+		}
+		target := net.JoinHostPort(addr.IP.String(), port)
+		conn, err := (&net.Dialer{Timeout: probeTimeout}).DialContext(ctx, "tcp", target)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		conn.Close()
+		reachable = append(reachable, addr.IP.String())
+	}
+	probed := len(addrs)
+	if probed > maxProbeAddrs {
+		probed = maxProbeAddrs
+	}
+	if len(reachable) == 0 {
+		return fmt.Sprintf("DNS resolves but TCP connect to port %s %s", port, describeDialError(lastErr))
+	}
+	partial := ""
+	if len(reachable) < probed {
+		partial = fmt.Sprintf(" (TCP connect failed on %d of %d addresses: %s)", probed-len(reachable), probed, describeDialError(lastErr))
+	}
+
+	// TLS（ハンドシェイクのみ）
+	if parsedURL.Scheme == "https" {
+		dialer := &tls.Dialer{
+			NetDialer: &net.Dialer{Timeout: probeTimeout},
+			Config: &tls.Config{
+				ServerName:         host,
+				InsecureSkipVerify: c.config.Insecure,
+			},
+		}
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(reachable[0], port))
+		if err != nil {
+			return fmt.Sprintf("TCP connects but TLS handshake fails: %v%s", err, partial)
+		}
+		conn.Close()
+	}
+
+	// ネットワーク経路は正常
+	switch {
+	case result.StatusCode >= 500:
+		return fmt.Sprintf("Network path is healthy; server responded with HTTP %d%s", result.StatusCode, partial)
+	case result.StatusCode >= 400:
+		return fmt.Sprintf("Network path is healthy; request was rejected with HTTP %d%s", result.StatusCode, partial)
+	case result.Error == CategoryTimeout, result.Error == CategoryHeaderTimeout:
+		return "Connection succeeds but the server did not respond in time" + partial
+	case result.Error == CategoryBodyTimeout:
+		return "Connection succeeds and headers arrive but the response body stalls" + partial
+	case result.Error == CategoryEmptyResponse:
+		return "Connection succeeds but the server closes it without a response" + partial
+	}
+	if partial != "" {
+		return "Connection succeeds on some addresses only" + partial
+	}
+	return "DNS, TCP and TLS probes succeed; failure is likely in the HTTP layer"
+}
+
+// probeOrigin CDNを経由せずオリジンへ同じリクエスト（Host・SNIはURLのまま）を送り、CDN経由の失敗と比べた結果を返す
+// オリジンが応答する場合はCDNまたはCDNからオリジンまでの経路、オリジンも失敗する場合はオリジンの障害とみなす
+func (c *Checker) probeOrigin(ctx context.Context, target config.Target) string {
+	origin := target.Origin
+	probe := target
+	probe.Resolve = origin
+	probe.IPFamily = ""
+	probe.WatchContent = false
+	probe.ScanResources = false
+	ctx = withDialTarget(ctx, dialTarget{address: origin, label: origin})
+	result := c.CheckHTTP(ctx, probe)
+
+	switch {
+	case result.Success:
+		return fmt.Sprintf("origin %s responds directly (HTTP %d), so the fault is likely in the CDN", origin, result.StatusCode)
+	case result.StatusCode != 0:
+		return fmt.Sprintf("origin %s also fails directly (HTTP %d), so the fault is likely at the origin", origin, result.StatusCode)
+	}
+	return fmt.Sprintf("origin %s also fails directly (%s), so the fault is likely at the origin", origin, result.Error)
+}
+
+// describeDialError 接続エラーを短い説明に変換
+func describeDialError(err error) string {
+	if err == nil {
+		return "failed"
+	}
+	var netErr net.Error
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return "is refused"
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return "is unreachable (no route)"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timed out"
+	}
+	return fmt.Sprintf("failed: %v", err)
+}
@@ -0,0 +1,261 @@
+package checker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+const (
+	// rdapBootstrapURL TLDごとのRDAPサーバーの一覧（IANA、RFC 9224）
+	rdapBootstrapURL = "https://data.iana.org/rdap/dns.json"
+	// rdapBootstrapTTL RDAPサーバーの一覧を取り直す間隔
+	rdapBootstrapTTL = 24 * time.Hour
+	// whoisIANA TLDのWHOISサーバーを調べるサーバー
+	whoisIANA = "whois.iana.org"
+	// maxDomainResponseBytes RDAP・WHOISの応答を読み込む最大サイズ
+	maxDomainResponseBytes = 1 << 20
+)
+
+// whoisExpiryPattern WHOISの応答から有効期限の行を探すパターン（レジストリごとに表記が異なる）
+var whoisExpiryPattern = regexp.MustCompile(`(?im)^\s*(?:Registry Expiry Date|Registrar Registration Expiration Date|Expiration Date|Expiry Date|expires|paid-till|\[Expires on\]|\[有効期限\]|\[State\][^(\n]*\()\s*:?\s*([0-9][^\s)]*)`)
+
+// whoisDateLayouts WHOISの有効期限の日付の形式
+var whoisDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006/01/02",
+	"2006.01.02",
+	"02-Jan-2006",
+	"02.01.2006",
+}
+
+// domainProvider ドメインの有効期限のチェック（domain://example.com）
+// RDAPで登録の有効期限を調べ、TLDにRDAPサーバーがない場合はWHOISで調べる
+// 残り日数が対象のexpiry_warn_days（デフォルト: 30日）以下の場合は失敗とする
+type domainProvider struct {
+	checker *Checker
+}
+
+// Scheme 担当するスキーム
+func (p *domainProvider) Scheme() string { return "domain" }
+
+// Check ドメインの有効期限を確かめる
+func (p *domainProvider) Check(ctx context.Context, target config.Target) *CheckResult {
+	result := &CheckResult{
+		URL:       target.URL,
+		Timestamp: time.Now().UTC(),
+		Success:   false,
+	}
+
+	parsedURL, err := url.Parse(target.URL)
+	domain := ""
+	if err == nil {
+		domain = strings.TrimSuffix(strings.ToLower(parsedURL.Hostname()), ".")
+	}
+	if domain == "" || !strings.Contains(domain, ".") || net.ParseIP(domain) != nil {
+		result.Error = CategoryInvalidURL
+		result.ErrorMessage = fmt.Sprintf("Domain target must be domain://example.com: %s", target.URL)
+		return result
+	}
+
+	timeout := p.checker.config.Timeout
+	if target.Timeout != "" {
+		if d, err := time.ParseDuration(target.Timeout); err == nil {
+			timeout = d
+		}
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startTime := time.Now()
+	expiry, source, err := p.checker.domainExpiry(lookupCtx, domain)
+	result.ResponseTime = time.Since(startTime)
+	result.Latency = result.ResponseTime
+	if err != nil {
+		result.Error = CategoryDomainLookupFailed
+		if lookupCtx.Err() == context.DeadlineExceeded {
+			result.Error = CategoryTimeout
+		}
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	result.ExpiresAt = &expiry
+	result.ExpirySource = source
+
+	warnDays := target.ExpiryWarnDays
+	if warnDays == 0 {
+		warnDays = config.DefaultExpiryWarnDays
+	}
+	days := int(time.Until(expiry).Hours() / 24)
+	switch {
+	case days < 0:
+		result.Error = CategoryDomainExpiring
+		result.ErrorMessage = fmt.Sprintf("Domain %s expired on %s", domain, expiry.Format("2006-01-02"))
+	case days <= warnDays:
+		result.Error = CategoryDomainExpiring
+		result.ErrorMessage = fmt.Sprintf("Domain %s expires in %d days (%s)", domain, days, expiry.Format("2006-01-02"))
+	default:
+		result.Success = true
+	}
+	return result
+}
+
+// domainExpiry ドメインの有効期限と調べた方法（rdap / whois）を返す
+func (c *Checker) domainExpiry(ctx context.Context, domain string) (time.Time, string, error) {
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+	servers, err := c.rdapServers(ctx)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if bases := servers[tld]; len(bases) > 0 {
+		expiry, err := c.rdapExpiry(ctx, bases[0], domain)
+		return expiry, "rdap", err
+	}
+	expiry, err := whoisExpiry(ctx, tld, domain)
+	return expiry, "whois", err
+}
+
+// rdapServers TLDごとのRDAPサーバーのURLを返す（一覧は取得後rdapBootstrapTTLの間キャッシュする）
+func (c *Checker) rdapServers(ctx context.Context) (map[string][]string, error) {
+	c.rdapMutex.Lock()
+	defer c.rdapMutex.Unlock()
+	if c.rdapBootstrap != nil && time.Since(c.rdapFetched) < rdapBootstrapTTL {
+		return c.rdapBootstrap, nil
+	}
+
+	var bootstrap struct {
+		Services [][][]string `json:"services"`
+	}
+	if err := c.getJSON(ctx, rdapBootstrapURL, &bootstrap); err != nil {
+		// 取り直しに失敗した場合は古い一覧で続ける
+		if c.rdapBootstrap != nil {
+			return c.rdapBootstrap, nil
+		}
+		return nil, fmt.Errorf("failed to load RDAP bootstrap: %w", err)
+	}
+	servers := make(map[string][]string)
+	for _, service := range bootstrap.Services {
+		if len(service) != 2 {
+			continue
+		}
+		for _, tld := range service[0] {
+			servers[strings.ToLower(tld)] = service[1]
+		}
+	}
+	c.rdapBootstrap = servers
+	c.rdapFetched = time.Now()
+	return servers, nil
+}
+
+// rdapExpiry RDAPのドメインの情報から有効期限（eventActionがexpiration）を返す
+func (c *Checker) rdapExpiry(ctx context.Context, base, domain string) (time.Time, error) {
+	var info struct {
+		Events []struct {
+			Action string    `json:"eventAction"`
+			Date   time.Time `json:"eventDate"`
+		} `json:"events"`
+	}
+	if err := c.getJSON(ctx, strings.TrimSuffix(base, "/")+"/domain/"+domain, &info); err != nil {
+		return time.Time{}, fmt.Errorf("RDAP lookup failed: %w", err)
+	}
+	for _, event := range info.Events {
+		if event.Action == "expiration" {
+			return event.Date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("RDAP response for %s has no expiration date", domain)
+}
+
+// getJSON URLから取得したJSONをvに読み込む
+func (c *Checker) getJSON(ctx context.Context, rawURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+	req.Header.Set("User-Agent", "HealthCheck/1.0")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("not found (%s)", rawURL)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, rawURL)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxDomainResponseBytes)).Decode(v)
+}
+
+// whoisExpiry WHOISで有効期限を調べる（TLDのWHOISサーバーはwhois.iana.orgに問い合わせる）
+func whoisExpiry(ctx context.Context, tld, domain string) (time.Time, error) {
+	referral, err := whoisQuery(ctx, whoisIANA, tld)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("WHOIS lookup failed: %w", err)
+	}
+	server := ""
+	for _, line := range strings.Split(referral, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "whois:"); ok {
+			server = strings.TrimSpace(value)
+			break
+		}
+	}
+	if server == "" {
+		return time.Time{}, fmt.Errorf("no RDAP or WHOIS server for .%s", tld)
+	}
+
+	query := domain
+	if server == "whois.jprs.jp" {
+		// JPRSは/eを付けると英語で応答する
+		query += "/e"
+	}
+	response, err := whoisQuery(ctx, server, query)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("WHOIS lookup failed: %w", err)
+	}
+	for _, match := range whoisExpiryPattern.FindAllStringSubmatch(response, -1) {
+		for _, layout := range whoisDateLayouts {
+			if t, err := time.Parse(layout, match[1]); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("WHOIS response from %s has no expiration date", server)
+}
+
+// whoisQuery WHOISサーバー（ポート43）に問い合わせて応答を返す
+func whoisQuery(ctx context.Context, server, query string) (string, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(server, "43"))
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := fmt.Fprintf(conn, "%s\r\n", query); err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	scanner := bufio.NewScanner(io.LimitReader(conn, maxDomainResponseBytes))
+	for scanner.Scan() {
+		sb.WriteString(scanner.Text())
+		sb.WriteByte('\n')
+	}
+	return sb.String(), scanner.Err()
+}
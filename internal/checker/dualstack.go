@@ -0,0 +1,149 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// dialTarget 接続先の指定（networkはtcp4/tcp6、addressを指定した場合は名前解決せずにそのIPアドレスへ接続）
+// addressにポートを含めた場合はURLのポートの代わりにそのポートへ接続する
+type dialTarget struct {
+	network string
+	address string
+	label   string // 結果に表示する接続先（例: IPv6, 192.0.2.1）
+}
+
+// dialKey コンテキストに接続先の指定を保持するキー
+type dialKey struct{}
+
+// withDialTarget 接続先の指定をコンテキストに設定
+func withDialTarget(ctx context.Context, d dialTarget) context.Context {
+	return context.WithValue(ctx, dialKey{}, d)
+}
+
+// dialTargetFor 対象のresolve・ip_familyに応じた接続先の指定（指定がない場合はfalse）
+func dialTargetFor(ctx context.Context, target config.Target) (dialTarget, bool) {
+	if d, ok := ctx.Value(dialKey{}).(dialTarget); ok {
+		return d, true
+	}
+	if target.Resolve != "" {
+		return dialTarget{address: target.Resolve, label: target.Resolve}, true
+	}
+	switch target.IPFamily {
+	case "ipv4":
+		return dialTarget{network: "tcp4", label: "IPv4"}, true
+	case "ipv6":
+		return dialTarget{network: "tcp6", label: "IPv6"}, true
+	}
+	return dialTarget{}, false
+}
+
+// dialAddress 接続先のIPアドレスを指定した場合に接続するアドレス（ポートを省略した場合はURLのポート）
+func (d dialTarget) dialAddress(addr string) (string, error) {
+	if _, _, err := net.SplitHostPort(d.address); err == nil {
+		return d.address, nil
+	}
+	if d.address == "" {
+		return addr, nil
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(d.address, port), nil
+}
+
+// dialContext コンテキストの接続先の指定に従って接続するDialContext（開いた接続は数える）
+func dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		d, ok := ctx.Value(dialKey{}).(dialTarget)
+		if !ok {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		if d.network != "" {
+			network = d.network
+		}
+		addr, err := d.dialAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return trackConn(conn), nil
+	}
+}
+
+// ipFamily IPアドレスのアドレスファミリー（ipv4 / ipv6）
+func ipFamily(ip net.IP) string {
+	if ip.To4() != nil {
+		return "ipv4"
+	}
+	return "ipv6"
+}
+
+// checkAddresses IPv4とIPv6（dual）、または解決したIPアドレスごと（each）に個別にチェック
+// 各接続先の結果をステップとして1つの結果にまとめ、いずれかが失敗した場合は失敗とする
+func (c *Checker) checkAddresses(ctx context.Context, target config.Target) *CheckResult {
+	result := &CheckResult{
+		URL:       target.URL,
+		Timestamp: time.Now().UTC(),
+	}
+
+	var dials []dialTarget
+	switch target.IPFamily {
+	case "dual":
+		dials = []dialTarget{{network: "tcp4", label: "IPv4"}, {network: "tcp6", label: "IPv6"}}
+	case "each":
+		parsedURL, err := url.Parse(target.URL)
+		if err != nil {
+			result.Error = CategoryInvalidURL
+			result.ErrorMessage = fmt.Sprintf("URL parse error: %v", err)
+			return result
+		}
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, parsedURL.Hostname())
+		if err != nil {
+			result.Error = CategoryDNSFailure
+			result.ErrorMessage = fmt.Sprintf("Failed to resolve %s: %v", parsedURL.Hostname(), err)
+			return result
+		}
+		for _, addr := range addrs {
+			network := "tcp6"
+			if ipFamily(addr.IP) == "ipv4" {
+				network = "tcp4"
+			}
+			dials = append(dials, dialTarget{network: network, address: addr.IP.String(), label: addr.IP.String()})
+		}
+	}
+
+	result.Success = true
+	for _, d := range dials {
+		addStep(result, c.CheckHTTPWithRetry(withDialTarget(ctx, d), target), d.label)
+	}
+	return result
+}
+
+// addStep 個別の接続先の結果をステップとして加える（応答時間は最も遅いもの、最初の失敗を全体の失敗とする）
+func addStep(result, step *CheckResult, label string) {
+	result.Steps = append(result.Steps, step)
+	result.ResponseTime = max(result.ResponseTime, step.ResponseTime)
+	result.Latency = max(result.Latency, step.Latency)
+	if result.StatusCode == 0 || (!step.Success && result.Success) {
+		result.StatusCode = step.StatusCode
+	}
+	if !step.Success && result.Success {
+		result.Success = false
+		result.Error = step.Error
+		result.ErrorMessage = fmt.Sprintf("%s: %s", label, step.ErrorMessage)
+		result.Hint = step.Hint
+	}
+}
@@ -0,0 +1,76 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// maxExecOutput 結果に含めるコマンドの出力の最大バイト数
+const maxExecOutput = 4096
+
+// CheckExec ローカルのコマンドを実行し、終了コード0を成功とする
+// 失敗した場合は標準出力と標準エラー出力をErrorMessageに含める
+func (c *Checker) CheckExec(ctx context.Context, target config.Target) *CheckResult {
+	result := &CheckResult{
+		URL:       target.URL,
+		Timestamp: time.Now().UTC(),
+		Success:   false,
+	}
+
+	timeout := c.config.Timeout
+	if target.Timeout != "" {
+		if d, err := time.ParseDuration(target.Timeout); err == nil {
+			timeout = d
+		}
+	}
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(execCtx, target.Command[0], target.Command[1:]...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// タイムアウト後に子プロセスが出力を握ったままでも待ち続けない
+	cmd.WaitDelay = time.Second
+
+	startTime := time.Now()
+	err := cmd.Run()
+	result.ResponseTime = time.Since(startTime)
+	result.Latency = result.ResponseTime
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.Success = true
+	case execCtx.Err() == context.DeadlineExceeded:
+		result.Error = CategoryTimeout
+		result.ErrorMessage = fmt.Sprintf("Command exceeded %v%s", timeout, formatOutput(output.String()))
+	case errors.As(err, &exitErr):
+		result.Error = CategoryExecFailed
+		result.ErrorMessage = fmt.Sprintf("Exit code %d%s", exitErr.ExitCode(), formatOutput(output.String()))
+	default:
+		result.Error = CategoryExecError
+		result.ErrorMessage = fmt.Sprintf("Command start error: %v", err)
+	}
+
+	return result
+}
+
+// formatOutput コマンドの出力をエラーメッセージ用に整形（長い場合は末尾を残す）
+func formatOutput(output string) string {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return ""
+	}
+	if len(output) > maxExecOutput {
+		output = "..." + output[len(output)-maxExecOutput:]
+	}
+	return ": " + output
+}
@@ -0,0 +1,105 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// ftpProvider FTPサーバーのチェック（ftp://[user@]host:port、ポートの省略時は21）
+// ウェルカムメッセージ（220）を受信し、ログインできることを確かめる
+// ユーザー名を省略した場合はanonymousでログインし、パスワードは対象のpasswordに指定する
+type ftpProvider struct {
+	checker *Checker
+}
+
+// Scheme 担当するスキーム
+func (p *ftpProvider) Scheme() string { return "ftp" }
+
+// Check ウェルカムメッセージとログインを確かめる
+func (p *ftpProvider) Check(ctx context.Context, target config.Target) *CheckResult {
+	result := &CheckResult{
+		URL:       target.URL,
+		Timestamp: time.Now().UTC(),
+		Success:   false,
+	}
+
+	conn, err := p.checker.dialBanner(ctx, target, "21", result)
+	if err != nil {
+		return result
+	}
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+
+	bannerStart := time.Now()
+	code, message, err := tp.Reader.ReadResponse(0)
+	result.Phases.Processing = time.Since(bannerStart)
+	if err != nil {
+		conn.fail(err, result)
+		return result
+	}
+	result.Banner, _, _ = strings.Cut(message, "\n")
+	if code != 220 {
+		conn.finish(result)
+		result.Error = CategoryProtocolError
+		result.ErrorMessage = fmt.Sprintf("Unexpected FTP greeting: %d %s", code, result.Banner)
+		return result
+	}
+
+	user, password := "anonymous", "anonymous@"
+	if parsedURL, err := url.Parse(target.URL); err == nil && parsedURL.User != nil && parsedURL.User.Username() != "" {
+		user, password = parsedURL.User.Username(), target.Password
+	} else if target.Password != "" {
+		password = target.Password
+	}
+	if err := ftpLogin(tp, user, password); err != nil {
+		conn.finish(result)
+		var ftpErr *textproto.Error
+		switch {
+		case errors.As(err, &ftpErr) && ftpErr.Code == 530:
+			result.Error = CategoryAuthFailed
+			result.ErrorMessage = fmt.Sprintf("FTP login failed for %s: %d %s", user, ftpErr.Code, ftpErr.Msg)
+		case errors.As(err, &ftpErr):
+			result.Error = CategoryProtocolError
+			result.ErrorMessage = fmt.Sprintf("FTP login failed for %s: %d %s", user, ftpErr.Code, ftpErr.Msg)
+		default:
+			conn.fail(err, result)
+		}
+		return result
+	}
+	conn.finish(result)
+
+	// 終了の応答は待たない
+	tp.Cmd("QUIT")
+	result.Success = true
+	return result
+}
+
+// ftpLogin USERとPASSでログインする（パスワードが不要なサーバーはUSERの応答で完了する）
+func ftpLogin(tp *textproto.Conn, user, password string) error {
+	if _, err := tp.Cmd("USER %s", user); err != nil {
+		return err
+	}
+	code, message, err := tp.ReadResponse(0)
+	if err != nil {
+		return err
+	}
+	switch {
+	case code == 230:
+		return nil
+	case code != 331:
+		return &textproto.Error{Code: code, Msg: message}
+	}
+
+	if _, err := tp.Cmd("PASS %s", password); err != nil {
+		return err
+	}
+	_, _, err = tp.ReadResponse(2)
+	return err
+}
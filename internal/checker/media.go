@@ -0,0 +1,86 @@
+package checker
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"healthcheck/internal/config"
+)
+
+// negotiateEncoding 期待する圧縮形式を指定した対象ではAccept-Encodingを明示する
+// 明示した場合はTransportが自動で展開しないため、gzipのみ自前で展開する
+func negotiateEncoding(target config.Target, req *http.Request) {
+	if len(target.ExpectedEncoding) > 0 && req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", strings.Join(target.ExpectedEncoding, ", "))
+	}
+}
+
+// recordMedia 応答のContent-TypeとContent-Encodingを記録（gzipは本文を展開して読めるようにする）
+func recordMedia(resp *http.Response, result *CheckResult) error {
+	result.ContentType = resp.Header.Get("Content-Type")
+	result.ContentEncoding = strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if resp.Uncompressed {
+		// Transportが展開した（Content-Encodingヘッダーは削除されている）
+		result.ContentEncoding = "gzip"
+	} else if result.ContentEncoding == "gzip" {
+		body, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to decompress gzip body: %w", err)
+		}
+		resp.Body = &gzipBody{Reader: body, raw: resp.Body}
+	}
+	return nil
+}
+
+// gzipBody gzipを展開しながら読む本文
+type gzipBody struct {
+	*gzip.Reader
+	raw io.ReadCloser
+}
+
+// Close 展開前の本文を閉じる
+func (b *gzipBody) Close() error {
+	return b.raw.Close()
+}
+
+// checkMedia Content-Type・charset・Content-Encodingを期待する値と比較
+func checkMedia(target config.Target, result *CheckResult) {
+	mediaType, params, _ := mime.ParseMediaType(result.ContentType)
+	if expected := target.ExpectedContentType; expected != "" && !matchMediaType(expected, mediaType) {
+		result.Success = false
+		result.Error = CategoryAssertionFailed
+		result.ErrorMessage = fmt.Sprintf("expected Content-Type %s, got %q", expected, result.ContentType)
+		return
+	}
+	if expected := target.ExpectedCharset; expected != "" && !strings.EqualFold(expected, params["charset"]) {
+		result.Success = false
+		result.Error = CategoryAssertionFailed
+		result.ErrorMessage = fmt.Sprintf("expected charset %s, got %q", expected, params["charset"])
+		return
+	}
+	if len(target.ExpectedEncoding) > 0 && !slices.ContainsFunc(target.ExpectedEncoding, func(e string) bool {
+		return strings.EqualFold(e, result.ContentEncoding)
+	}) {
+		result.Success = false
+		result.Error = CategoryAssertionFailed
+		encoding := result.ContentEncoding
+		if encoding == "" {
+			encoding = "none"
+		}
+		result.ErrorMessage = fmt.Sprintf("expected Content-Encoding %s, got %s", strings.Join(target.ExpectedEncoding, " or "), encoding)
+	}
+}
+
+// matchMediaType メディアタイプが期待する値（text/*のようなワイルドカードを含む）に一致するか
+func matchMediaType(expected, mediaType string) bool {
+	expected = strings.ToLower(expected)
+	if prefix, ok := strings.CutSuffix(expected, "/*"); ok {
+		return strings.HasPrefix(mediaType, prefix+"/")
+	}
+	return expected == mediaType
+}
@@ -0,0 +1,171 @@
+package checker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// tokenExpiryMargin 有効期限の直前に失効しないよう早めにトークンを取り直す時間
+const tokenExpiryMargin = 30 * time.Second
+
+// maxTokenResponseSize トークンエンドポイントの応答の最大サイズ
+const maxTokenResponseSize = 1 << 20
+
+// oauthToken 取得したアクセストークン
+type oauthToken struct {
+	mutex        sync.Mutex
+	accessToken  string
+	refreshToken string
+	expiry       time.Time // ゼロ値の場合は期限なし
+}
+
+// tokenCache 認証の設定ごとのトークン（チェッカー間で共有する）
+var tokenCache sync.Map
+
+// tokenResponse トークンエンドポイントの応答（RFC 6749）
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// authConfig 名前で指定された認証の設定
+func (c *Checker) authConfig(name string) (config.AuthConfig, bool) {
+	for _, a := range c.config.Auth {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return config.AuthConfig{}, false
+}
+
+// cachedToken 認証の設定に対応するトークンのキャッシュ
+func cachedToken(auth config.AuthConfig) *oauthToken {
+	key := auth.Name + "\x00" + auth.TokenURL + "\x00" + auth.ClientID
+	t, _ := tokenCache.LoadOrStore(key, &oauthToken{refreshToken: auth.RefreshToken})
+	return t.(*oauthToken)
+}
+
+// authorize 対象の認証の設定に従ってリクエストにBearerトークンを付与
+func (c *Checker) authorize(ctx context.Context, target config.Target, req *http.Request) error {
+	auth, ok := c.authConfig(target.Auth)
+	if !ok {
+		return fmt.Errorf("unknown auth %q", target.Auth)
+	}
+	token, err := c.accessToken(ctx, auth)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// invalidateToken 保持しているトークンを破棄（破棄した場合はtrue）
+func (c *Checker) invalidateToken(name string) bool {
+	auth, ok := c.authConfig(name)
+	if !ok {
+		return false
+	}
+	t := cachedToken(auth)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.accessToken == "" {
+		return false
+	}
+	t.accessToken = ""
+	return true
+}
+
+// accessToken 有効なアクセストークンを返す（期限切れの場合は取り直す）
+func (c *Checker) accessToken(ctx context.Context, auth config.AuthConfig) (string, error) {
+	t := cachedToken(auth)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.accessToken != "" && (t.expiry.IsZero() || time.Until(t.expiry) > tokenExpiryMargin) {
+		return t.accessToken, nil
+	}
+
+	resp, err := c.fetchToken(ctx, auth, t.refreshToken)
+	if err != nil {
+		return "", err
+	}
+	t.accessToken = resp.AccessToken
+	t.expiry = time.Time{}
+	if resp.ExpiresIn > 0 {
+		t.expiry = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	if resp.RefreshToken != "" {
+		t.refreshToken = resp.RefreshToken
+	}
+	return t.accessToken, nil
+}
+
+// fetchToken トークンエンドポイントからアクセストークンを取得
+// リフレッシュトークンがある場合はrefresh_token、ない場合はclient_credentialsのグラントを使う
+func (c *Checker) fetchToken(ctx context.Context, auth config.AuthConfig, refreshToken string) (*tokenResponse, error) {
+	form := url.Values{}
+	if refreshToken != "" {
+		form.Set("grant_type", "refresh_token")
+		form.Set("refresh_token", refreshToken)
+	} else {
+		form.Set("grant_type", "client_credentials")
+	}
+	if len(auth.Scopes) > 0 {
+		form.Set("scope", strings.Join(auth.Scopes, " "))
+	}
+	if auth.Audience != "" {
+		form.Set("audience", auth.Audience)
+	}
+
+	// 対象の接続先やリダイレクトの指定はトークンの取得には適用しない
+	ctx = context.WithValue(context.WithValue(ctx, dialKey{}, nil), noFollowKey{}, nil)
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, auth.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "HealthCheck/1.0")
+	req.SetBasicAuth(url.QueryEscape(auth.ClientID), url.QueryEscape(auth.ClientSecret))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var token tokenResponse
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if err := json.Unmarshal(data, &token); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || token.AccessToken == "" {
+		if token.Error != "" {
+			return nil, fmt.Errorf("token endpoint returned HTTP %d: %s %s", resp.StatusCode, token.Error, token.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token endpoint returned HTTP %d without access_token", resp.StatusCode)
+	}
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return nil, fmt.Errorf("unsupported token_type %q", token.TokenType)
+	}
+	return &token, nil
+}
@@ -0,0 +1,112 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"healthcheck/internal/tracing"
+)
+
+// Phases リクエストのフェーズごとの所要時間
+type Phases struct {
+	DNS        time.Duration `json:"dns_ms"`        // 名前解決
+	Connect    time.Duration `json:"connect_ms"`    // TCP接続
+	TLS        time.Duration `json:"tls_ms"`        // TLSハンドシェイク
+	Processing time.Duration `json:"processing_ms"` // リクエスト送信完了から最初のバイトまで
+	Transfer   time.Duration `json:"transfer_ms"`   // 本文の受信
+}
+
+// phaseTracer httptraceのフックからフェーズごとの時刻を記録
+// デュアルスタックの接続ではフックが並行して呼ばれるためロックする
+type phaseTracer struct {
+	mutex        sync.Mutex
+	phases       *Phases
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	wroteRequest time.Time
+	connReused   bool
+	remoteAddr   string
+	intervals    []phaseInterval
+}
+
+// phaseInterval 記録したフェーズの開始・終了時刻（トレースの区間に使用）
+type phaseInterval struct {
+	name       string
+	start, end time.Time
+}
+
+// newPhaseTracer フェーズごとの所要時間を記録するトレーサーを作成
+func newPhaseTracer(phases *Phases) *phaseTracer {
+	return &phaseTracer{phases: phases}
+}
+
+// clientTrace httptraceのフックを作成
+func (t *phaseTracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.add("dns", &t.phases.DNS, &t.dnsStart) },
+		ConnectStart: func(string, string) {
+			t.mark(&t.connectStart)
+		},
+		ConnectDone: func(string, string, error) {
+			t.add("connect", &t.phases.Connect, &t.connectStart)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.connReused = info.Reused
+			t.remoteAddr = info.Conn.RemoteAddr().String()
+		},
+		TLSHandshakeStart:    func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.add("tls", &t.phases.TLS, &t.tlsStart) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.mark(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.add("processing", &t.phases.Processing, &t.wroteRequest) },
+	}
+}
+
+// record 記録したフェーズをトレースの子区間として記録
+func (t *phaseTracer) record(ctx context.Context) {
+	t.mutex.Lock()
+	intervals := append([]phaseInterval(nil), t.intervals...)
+	t.mutex.Unlock()
+
+	for _, i := range intervals {
+		tracing.Record(ctx, i.name, i.start, i.end)
+	}
+}
+
+// conn リクエストに既存の接続を再利用したかと接続先のアドレス
+func (t *phaseTracer) conn() (bool, string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.connReused, t.remoteAddr
+}
+
+// sent リクエストを送信し終えたか（接続後に応答がなかった失敗の判別に使う）
+func (t *phaseTracer) sent() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return !t.wroteRequest.IsZero()
+}
+
+// mark 開始時刻を記録
+func (t *phaseTracer) mark(at *time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	*at = time.Now()
+}
+
+// add 開始時刻からの経過時間をフェーズに加算
+func (t *phaseTracer) add(name string, phase *time.Duration, start *time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if !start.IsZero() {
+		end := time.Now()
+		*phase += end.Sub(*start)
+		t.intervals = append(t.intervals, phaseInterval{name: name, start: *start, end: end})
+	}
+}
@@ -0,0 +1,138 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+
+	"healthcheck/internal/config"
+)
+
+// HTTP3Probe HTTP/3（QUIC）で同じ対象に接続を試した結果（http3を指定した場合）
+type HTTP3Probe struct {
+	Success      bool          `json:"success"`               // HTTP/3で応答を受け取れた（ステータスコードは問わない）
+	StatusCode   int           `json:"status_code,omitempty"` // HTTP/3での応答のステータスコード
+	ResponseTime time.Duration `json:"response_time_ms"`      // QUICの接続から応答のヘッダーを受け取るまでの時間
+	Error        string        `json:"error,omitempty"`       // 接続・リクエストに失敗した理由
+}
+
+// recordProtocol 応答に使われたHTTPのバージョンとALPNのネゴシエーション結果を記録
+func recordProtocol(resp *http.Response, result *CheckResult) {
+	result.Protocol = resp.Proto
+	if resp.TLS != nil {
+		result.ALPN = resp.TLS.NegotiatedProtocol
+		if result.ALPN == "" {
+			// ALPNに対応していないサーバー（HTTP/1.1で通信）
+			result.ALPN = "none"
+		}
+	}
+	result.HTTP3Advertised = advertisesHTTP3(resp.Header.Values("Alt-Svc"))
+}
+
+// advertisesHTTP3 Alt-SvcヘッダーでHTTP/3（h3、ドラフト版のh3-29なども含む）が提供されているか
+func advertisesHTTP3(values []string) bool {
+	for _, value := range values {
+		for _, service := range strings.Split(value, ",") {
+			id, _, _ := strings.Cut(strings.TrimSpace(service), "=")
+			if id == "h3" || strings.HasPrefix(id, "h3-") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newHTTP3Client HTTP/3での接続を試すクライアント（リダイレクトはたどらない）
+func newHTTP3Client(cfg *config.Config) *http.Client {
+	transport := &http3.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.Insecure},
+		QUICConfig:      &quic.Config{HandshakeIdleTimeout: cfg.TLSHandshakeTimeout},
+		Dial: func(ctx context.Context, addr string, tlsConfig *tls.Config, quicConfig *quic.Config) (*quic.Conn, error) {
+			return dialQUIC(ctx, addr, tlsConfig, quicConfig, cfg.DeniedNetworks)
+		},
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// dialQUIC コンテキストの接続先の指定（resolve・ip_family）に従ってQUICで接続する
+// deniedを指定した場合は、名前解決した接続先をTCPと同じくdenied_networksと照合する
+func dialQUIC(ctx context.Context, addr string, tlsConfig *tls.Config, quicConfig *quic.Config, denied []netip.Prefix) (*quic.Conn, error) {
+	network := "ip"
+	if d, ok := ctx.Value(dialKey{}).(dialTarget); ok {
+		var err error
+		if addr, err = d.dialAddress(addr); err != nil {
+			return nil, err
+		}
+		network += strings.TrimPrefix(d.network, "tcp")
+	}
+	if network != "ip" || len(denied) > 0 {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if net.ParseIP(host) == nil {
+			ips, err := net.DefaultResolver.LookupIP(ctx, network, host)
+			if err != nil {
+				return nil, err
+			}
+			addr = net.JoinHostPort(ips[0].String(), port)
+		}
+		if err := deniedAddress(denied, addr); err != nil {
+			return nil, err
+		}
+	}
+	return quic.DialAddrEarly(ctx, addr, tlsConfig, quicConfig)
+}
+
+// probeHTTP3 HTTPSの対象にHTTP/3で同じリクエストを送り、接続できたかを記録する
+// HTTP/3の結果はチェックの成否に影響しない（本番環境がHTTP/3を提供しているかの確認用）
+func (c *Checker) probeHTTP3(ctx context.Context, target config.Target, method string, result *CheckResult) {
+	probe := &HTTP3Probe{}
+	result.HTTP3 = probe
+
+	var body io.Reader
+	if target.Body != "" {
+		body = strings.NewReader(target.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.URL, body)
+	if err != nil {
+		probe.Error = err.Error()
+		return
+	}
+	req.Header.Set("User-Agent", "HealthCheck/1.0")
+	for name, value := range target.Headers {
+		req.Header.Set(name, value)
+	}
+	if target.Auth != "" {
+		if err := c.authorize(ctx, target, req); err != nil {
+			probe.Error = fmt.Sprintf("Failed to obtain access token: %v", err)
+			return
+		}
+	}
+
+	start := time.Now()
+	resp, err := c.http3Client.Do(req)
+	probe.ResponseTime = time.Since(start)
+	if err != nil {
+		probe.Error = fmt.Sprintf("HTTP/3 request failed: %v", err)
+		return
+	}
+	resp.Body.Close()
+	probe.Success = true
+	probe.StatusCode = resp.StatusCode
+}
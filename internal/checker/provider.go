@@ -0,0 +1,133 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// CheckProvider 対象のスキームごとのチェック方法
+// 独自のプロトコルに対応する場合は実装をRegisterで登録する
+type CheckProvider interface {
+	// Scheme 担当するスキーム（例: "http", "tcp"）
+	Scheme() string
+	// Check 対象をチェックして結果を返す（結果のURLには対象のURLを設定する）
+	Check(ctx context.Context, target config.Target) *CheckResult
+}
+
+// 登録されたチェック方法（組み込みのチェック方法より優先する）
+var (
+	registryMutex sync.RWMutex
+	registry      = make(map[string]CheckProvider)
+)
+
+// Register チェック方法を登録（同じスキームは後から登録したものに置き換わる）
+// Checkerの作成前に呼び出す
+func Register(provider CheckProvider) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry[strings.ToLower(provider.Scheme())] = provider
+}
+
+// builtinProviders 組み込みのチェック方法
+func (c *Checker) builtinProviders() []CheckProvider {
+	return []CheckProvider{
+		&httpProvider{checker: c, scheme: "http"},
+		&httpProvider{checker: c, scheme: "https"},
+		&tcpProvider{checker: c},
+		&sshProvider{checker: c},
+		&ftpProvider{checker: c},
+		&domainProvider{checker: c},
+		&execProvider{checker: c},
+		&browserProvider{checker: c},
+	}
+}
+
+// newProviders 組み込みと登録済みのチェック方法をスキームごとにまとめる
+func (c *Checker) newProviders() map[string]CheckProvider {
+	providers := make(map[string]CheckProvider)
+	for _, p := range c.builtinProviders() {
+		providers[p.Scheme()] = p
+	}
+
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	for scheme, p := range registry {
+		providers[scheme] = p
+	}
+	return providers
+}
+
+// Scheme 対象のスキーム（typeの指定がなければURLから判定）
+func Scheme(target config.Target) string {
+	if target.Type != "" && target.Type != "http" {
+		return strings.ToLower(target.Type)
+	}
+	if scheme, _, ok := strings.Cut(target.URL, "://"); ok {
+		return strings.ToLower(scheme)
+	}
+	if scheme, _, ok := strings.Cut(target.URL, ":"); ok {
+		return strings.ToLower(scheme)
+	}
+	return "http"
+}
+
+// Check 対象のスキームに対応するチェック方法でチェック
+func (c *Checker) Check(ctx context.Context, target config.Target) *CheckResult {
+	scheme := Scheme(target)
+	provider, ok := c.providers[scheme]
+	if !ok {
+		return &CheckResult{
+			URL:          target.URL,
+			Timestamp:    time.Now().UTC(),
+			Error:        CategoryUnsupportedScheme,
+			ErrorMessage: fmt.Sprintf("No check provider for scheme %q", scheme),
+		}
+	}
+	return provider.Check(ctx, target)
+}
+
+// httpProvider HTTP/HTTPSのチェック（リトライと原因のヒントを含む）
+type httpProvider struct {
+	checker *Checker
+	scheme  string
+}
+
+// Scheme 担当するスキーム
+func (p *httpProvider) Scheme() string { return p.scheme }
+
+// Check URLをチェック
+func (p *httpProvider) Check(ctx context.Context, target config.Target) *CheckResult {
+	if len(target.SNIHosts) > 0 {
+		return p.checker.checkVirtualHosts(ctx, target)
+	}
+	if target.Resolve == "" && (target.IPFamily == "dual" || target.IPFamily == "each") {
+		return p.checker.checkAddresses(ctx, target)
+	}
+	return p.checker.CheckHTTPWithRetry(ctx, target)
+}
+
+// execProvider ローカルのコマンドによるチェック
+type execProvider struct {
+	checker *Checker
+}
+
+// Scheme 担当するスキーム
+func (p *execProvider) Scheme() string { return "exec" }
+
+// Check コマンドを実行
+func (p *execProvider) Check(ctx context.Context, target config.Target) *CheckResult {
+	if len(target.Command) == 0 {
+		return &CheckResult{
+			URL:          target.URL,
+			Timestamp:    time.Now().UTC(),
+			Error:        CategoryExecError,
+			ErrorMessage: "No command configured",
+		}
+	}
+	return p.checker.CheckExec(ctx, target)
+}
@@ -0,0 +1,136 @@
+package checker
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"healthcheck/internal/tracing"
+)
+
+// rateLimiter 1秒ごとのリクエスト数を数えるレート制限器
+type rateLimiter struct {
+	window time.Time // 現在数えている1秒間の開始時刻（最後に使われた時刻の目安にもなる）
+	count  int
+	mutex  sync.Mutex
+}
+
+// allow 上限をlimitとしてリクエストが許可されるかチェック
+func (rl *rateLimiter) allow(limit int) bool {
+	now := time.Now()
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	if now.Sub(rl.window) >= time.Second {
+		rl.window = now
+		rl.count = 0
+	}
+	if rl.count < limit {
+		rl.count++
+		return true
+	}
+	return false
+}
+
+// waitForRateLimit レート制限を待機
+func (rl *rateLimiter) waitForRateLimit(limit int) {
+	for !rl.allow(limit) {
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+const (
+	// rateLimitShards ドメインごとのレート制限器を分けて持つシャードの数（ロックの競合を減らす）
+	rateLimitShards = 64
+	// domainLimiterIdleTimeout この時間リクエストのないドメインのレート制限器を破棄する
+	domainLimiterIdleTimeout = 10 * time.Minute
+)
+
+// rateLimits プロセス内のすべてのCheckerで共有するレート制限
+// スケジューラーとWebからの実行が重なっても、同じドメインへのリクエストを合わせて数える
+// 上限は呼び出し元のCheckerの設定（domain_rate・global_rate）で判定する
+type rateLimits struct {
+	global rateLimiter
+	shards [rateLimitShards]rateShard
+}
+
+// rateShard ドメインのハッシュで振り分けたレート制限器
+type rateShard struct {
+	mutex   sync.Mutex
+	domains map[string]*rateLimiter
+	swept   time.Time
+}
+
+// sharedRateLimits すべてのCheckerが使うレート制限
+var sharedRateLimits = &rateLimits{}
+
+// domain ドメインごとのレート制限器を取得
+func (r *rateLimits) domain(domain string) *rateLimiter {
+	h := fnv.New32a()
+	h.Write([]byte(domain))
+	shard := &r.shards[h.Sum32()%rateLimitShards]
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	now := time.Now()
+	shard.sweep(now)
+	if rl, exists := shard.domains[domain]; exists {
+		return rl
+	}
+	if shard.domains == nil {
+		shard.domains = make(map[string]*rateLimiter)
+	}
+	rl := &rateLimiter{window: now}
+	shard.domains[domain] = rl
+	return rl
+}
+
+// sweep しばらくリクエストのないドメインのレート制限器を破棄（mutexを保持して呼び出す）
+func (s *rateShard) sweep(now time.Time) {
+	if now.Sub(s.swept) < domainLimiterIdleTimeout {
+		return
+	}
+	for domain, rl := range s.domains {
+		rl.mutex.Lock()
+		idle := now.Sub(rl.window) >= domainLimiterIdleTimeout
+		rl.mutex.Unlock()
+		if idle {
+			delete(s.domains, domain)
+		}
+	}
+	s.swept = now
+}
+
+// RateLimiterStats 共有しているレート制限器の状態（デバッグ用）
+type RateLimiterStats struct {
+	Domains     int   `json:"domains"`      // 保持しているドメインごとのレート制限器の数
+	Shards      []int `json:"shards"`       // シャードごとのレート制限器の数
+	IdleTimeout int64 `json:"idle_timeout"` // 使われていないレート制限器を破棄するまでの時間（秒）
+}
+
+// RateLimiters 共有しているレート制限器の数を返す
+func RateLimiters() RateLimiterStats {
+	stats := RateLimiterStats{
+		Shards:      make([]int, rateLimitShards),
+		IdleTimeout: int64(domainLimiterIdleTimeout / time.Second),
+	}
+	for i := range sharedRateLimits.shards {
+		shard := &sharedRateLimits.shards[i]
+		shard.mutex.Lock()
+		stats.Shards[i] = len(shard.domains)
+		shard.mutex.Unlock()
+		stats.Domains += stats.Shards[i]
+	}
+	return stats
+}
+
+// waitForRateLimit 全体とドメインごとのレート制限を待機
+func (c *Checker) waitForRateLimit(ctx context.Context, domain string) {
+	_, span := tracing.Start(ctx, "rate_limit")
+	defer span.Finish()
+	span.SetAttribute("server.address", domain)
+
+	sharedRateLimits.global.waitForRateLimit(c.config.GlobalRate)
+	sharedRateLimits.domain(domain).waitForRateLimit(c.config.DomainRate)
+}
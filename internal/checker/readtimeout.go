@@ -0,0 +1,102 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// 受信の期限を過ぎてリクエストを打ち切った理由（context.Causeで判別する）
+var (
+	errHeaderTimeout = errors.New("response header timeout")
+	errBodyTimeout   = errors.New("response body read timeout")
+)
+
+// readTimeouts 対象のヘッダーと本文の受信期限（対象の指定を全体の設定より優先する、0の場合は期限なし）
+func (c *Checker) readTimeouts(target config.Target) (header, body time.Duration) {
+	header, body = c.config.HeaderTimeout, c.config.BodyTimeout
+	if d, err := time.ParseDuration(target.HeaderTimeout); err == nil && d > 0 {
+		header = d
+	}
+	if d, err := time.ParseDuration(target.BodyTimeout); err == nil && d > 0 {
+		body = d
+	}
+	return header, body
+}
+
+// headerDeadline リクエストの送信後、期限までにヘッダーを受信しなければcancelで打ち切る
+// 返した関数でタイマーを止める（応答を受け取ったらすぐに呼び出す）
+func headerDeadline(ctx context.Context, timeout time.Duration, cancel context.CancelCauseFunc) (context.Context, func()) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	var mutex sync.Mutex
+	var timer *time.Timer
+	stopped := false
+	trace := &httptrace.ClientTrace{
+		// 接続が再利用できずに送り直した場合は期限もやり直す
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if stopped {
+				return
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(timeout, func() { cancel(errHeaderTimeout) })
+		},
+	}
+	stop := func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		stopped = true
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+	return httptrace.WithClientTrace(ctx, trace), stop
+}
+
+// classifyRequestError ヘッダーを受信する前の失敗を、接続後に応答がなかったものと区別して記録
+func classifyRequestError(reqCtx context.Context, err error, sent bool, elapsed, maxLatency, headerTimeout time.Duration, result *CheckResult) {
+	switch {
+	case errors.Is(context.Cause(reqCtx), errHeaderTimeout):
+		result.Error = CategoryHeaderTimeout
+		result.ErrorMessage = fmt.Sprintf("Connected but no response headers within %v", headerTimeout)
+	case sent && elapsed >= maxLatency:
+		result.Error = CategoryHeaderTimeout
+		result.ErrorMessage = fmt.Sprintf("Connected but no response headers before the timeout %v", maxLatency)
+	case elapsed >= maxLatency:
+		result.Error = CategoryTimeout
+		result.ErrorMessage = fmt.Sprintf("Response time exceeded %v: %v", maxLatency, err)
+	case sent && (errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)):
+		result.Error = CategoryEmptyResponse
+		result.ErrorMessage = fmt.Sprintf("Server closed the connection without sending a response: %v", err)
+	default:
+		result.Error = classifyNetError(err)
+		result.ErrorMessage = err.Error()
+	}
+}
+
+// classifyBodyError 本文の受信中の失敗を記録（期限を過ぎた場合は本文の停滞として区別する）
+func classifyBodyError(reqCtx context.Context, err error, bodyTimeout time.Duration, result *CheckResult) {
+	cause := context.Cause(reqCtx)
+	switch {
+	case errors.Is(cause, errBodyTimeout):
+		result.Error = CategoryBodyTimeout
+		result.ErrorMessage = fmt.Sprintf("Response headers received but the body stalled after %d bytes (body timeout %v)", result.BytesDownloaded, bodyTimeout)
+	case errors.Is(cause, context.DeadlineExceeded):
+		result.Error = CategoryBodyTimeout
+		result.ErrorMessage = fmt.Sprintf("Response headers received but the body stalled after %d bytes (overall timeout)", result.BytesDownloaded)
+	default:
+		result.Error = classifyNetError(err)
+		result.ErrorMessage = fmt.Sprintf("Failed to read response body: %v", err)
+	}
+}
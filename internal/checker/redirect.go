@@ -0,0 +1,52 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"healthcheck/internal/config"
+)
+
+// noFollowKey リダイレクトをたどらないリクエストであることをコンテキストに保持するキー
+type noFollowKey struct{}
+
+// checkRedirect リダイレクトをたどらずに受け取った応答のLocationを期待するリダイレクト先と比較
+// ステータスコードはexpected_statusを指定した場合はその値、省略した場合は3xxを成功とみなす
+func checkRedirect(target config.Target, resp *http.Response, result *CheckResult) {
+	if location, err := resp.Location(); err == nil {
+		result.Location = location.String()
+	}
+
+	switch {
+	case target.ExpectedStatus > 0 && resp.StatusCode != target.ExpectedStatus:
+		result.Error = statusCategory(resp.StatusCode)
+		result.ErrorMessage = fmt.Sprintf("expected HTTP %d, got %s", target.ExpectedStatus, resp.Status)
+	case target.ExpectedStatus == 0 && (resp.StatusCode < 300 || resp.StatusCode >= 400):
+		result.Error = statusCategory(resp.StatusCode)
+		result.ErrorMessage = fmt.Sprintf("expected a redirect, got %s", resp.Status)
+	case result.Location == "":
+		result.Error = CategoryAssertionFailed
+		result.ErrorMessage = "redirect has no Location header"
+	case target.ExpectedLocation != "" && result.Location != target.ExpectedLocation:
+		result.Error = CategoryAssertionFailed
+		result.ErrorMessage = fmt.Sprintf("expected redirect to %s, got %s", target.ExpectedLocation, result.Location)
+	case target.ExpectedLocationPattern != "" && !matchLocation(target.ExpectedLocationPattern, result.Location):
+		result.Error = CategoryAssertionFailed
+		result.ErrorMessage = fmt.Sprintf("redirect to %s does not match %q", result.Location, target.ExpectedLocationPattern)
+	default:
+		result.Success = true
+	}
+}
+
+// matchLocation リダイレクト先が正規表現に一致するか（不正な正規表現は一致しないとみなす）
+func matchLocation(pattern, location string) bool {
+	re, err := regexp.Compile(pattern)
+	return err == nil && re.MatchString(location)
+}
+
+// withNoFollow リダイレクトをたどらないリクエストのコンテキスト
+func withNoFollow(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noFollowKey{}, true)
+}
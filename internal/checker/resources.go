@@ -0,0 +1,240 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxScanResources 1ページで確認するサブリソースの最大数
+	maxScanResources = 100
+	// resourceScanConcurrency サブリソースを同時に取得する数
+	resourceScanConcurrency = 4
+)
+
+// ResourceScan ページのHTMLから取り出したサブリソースを確認した結果（scan_resources）
+type ResourceScan struct {
+	Checked int              `json:"checked"`          // 確認したサブリソースの数
+	Issues  []FailedResource `json:"issues,omitempty"` // 読み込めない・HTTPで読み込むサブリソース
+}
+
+// pageResource ページが読み込むサブリソース
+type pageResource struct {
+	url          string
+	resourceType string
+}
+
+// resourceAttrs サブリソースを読み込む要素と、URLを指定する属性・種類
+var resourceAttrs = map[string]struct {
+	attrs        []string
+	resourceType string
+}{
+	"script": {[]string{"src"}, "Script"},
+	"img":    {[]string{"src", "srcset"}, "Image"},
+	"source": {[]string{"src", "srcset"}, "Media"},
+	"video":  {[]string{"src", "poster"}, "Media"},
+	"audio":  {[]string{"src"}, "Media"},
+	"track":  {[]string{"src"}, "Media"},
+	"iframe": {[]string{"src"}, "Document"},
+	"embed":  {[]string{"src"}, "Other"},
+	"object": {[]string{"data"}, "Other"},
+}
+
+// linkTypes 読み込みの対象とするlink要素のrel
+var linkTypes = map[string]string{
+	"stylesheet":    "Stylesheet",
+	"icon":          "Image",
+	"preload":       "Other",
+	"modulepreload": "Script",
+}
+
+// extractResources HTMLからスクリプト・スタイルシート・画像などのURLを取り出す（baseからの相対URLを解決し、重複は除く）
+func extractResources(doc string, base *url.URL) []pageResource {
+	var resources []pageResource
+	seen := make(map[string]bool)
+	add := func(raw, resourceType string) {
+		raw = strings.TrimSpace(html.UnescapeString(raw))
+		if raw == "" || len(resources) >= maxScanResources {
+			return
+		}
+		u, err := base.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			// data:・blob:・javascript:などは取得しない
+			return
+		}
+		u.Fragment = ""
+		if s := u.String(); !seen[s] {
+			seen[s] = true
+			resources = append(resources, pageResource{url: s, resourceType: resourceType})
+		}
+	}
+
+	scanStartTags(doc, func(el htmlElement) {
+		switch el.tag {
+		case "base":
+			// <base href>は以降の相対URLの基準になる
+			if href, ok := el.attrs["href"]; ok {
+				if u, err := base.Parse(strings.TrimSpace(html.UnescapeString(href))); err == nil {
+					base = u
+				}
+			}
+		case "link":
+			for _, rel := range strings.Fields(strings.ToLower(el.attrs["rel"])) {
+				if resourceType, ok := linkTypes[rel]; ok {
+					add(el.attrs["href"], resourceType)
+					break
+				}
+			}
+		default:
+			spec, ok := resourceAttrs[el.tag]
+			if !ok {
+				return
+			}
+			for _, attr := range spec.attrs {
+				value := el.attrs[attr]
+				if attr != "srcset" {
+					add(value, spec.resourceType)
+					continue
+				}
+				// srcsetは「URL 記述子」をカンマで区切った一覧
+				for _, candidate := range strings.Split(value, ",") {
+					if fields := strings.Fields(candidate); len(fields) > 0 {
+						add(fields[0], spec.resourceType)
+					}
+				}
+			}
+		}
+	})
+	return resources
+}
+
+// isHTML Content-TypeがHTMLか
+func isHTML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+// scanStartTags HTMLの開始タグを順に渡す（コメントとscript・styleなどの内容は読み飛ばす）
+func scanStartTags(doc string, fn func(el htmlElement)) {
+	i := 0
+	for {
+		lt := strings.IndexByte(doc[i:], '<')
+		if lt < 0 {
+			return
+		}
+		i += lt
+		rest := doc[i:]
+
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				return
+			}
+			i += 4 + end + 3
+		case len(rest) > 1 && isLetter(rest[1]):
+			el, end, _ := parseStartTag(rest)
+			i += end
+			fn(el)
+			if rawTextElements[el.tag] {
+				i += indexEndTag(doc[i:], el.tag)
+			}
+		default:
+			i++
+		}
+	}
+}
+
+// scanResources ページのサブリソースを取得し、読み込めないもの・HTTPSのページからHTTPで読み込むもの（混在コンテンツ）を記録
+// 他の条件をすべて満たした場合のみ呼び出し、問題があれば失敗とする
+func (c *Checker) scanResources(ctx context.Context, resp *http.Response, body []byte, result *CheckResult) {
+	page := resp.Request.URL
+	// Content-Security-Policyのupgrade-insecure-requestsがあればブラウザはHTTPSで読み込む
+	upgrade := strings.Contains(strings.ToLower(resp.Header.Get("Content-Security-Policy")), "upgrade-insecure-requests")
+
+	resources := extractResources(string(body), page)
+	scan := &ResourceScan{Checked: len(resources)}
+	issues := make([]*FailedResource, len(resources))
+
+	sem := make(chan struct{}, resourceScanConcurrency)
+	var wg sync.WaitGroup
+	for i, res := range resources {
+		if page.Scheme == "https" && strings.HasPrefix(res.url, "http://") {
+			if !upgrade {
+				issues[i] = &FailedResource{URL: res.url, Type: res.resourceType, Error: "insecure: loaded over HTTP from an HTTPS page", Insecure: true}
+				continue
+			}
+			res.url = "https://" + strings.TrimPrefix(res.url, "http://")
+		}
+		wg.Add(1)
+		go func(i int, res pageResource) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			issues[i] = c.fetchResource(ctx, res)
+		}(i, res)
+	}
+	wg.Wait()
+
+	for _, issue := range issues {
+		if issue != nil {
+			scan.Issues = append(scan.Issues, *issue)
+		}
+	}
+	result.Resources = scan
+	if len(scan.Issues) == 0 {
+		return
+	}
+
+	insecure := 0
+	for _, issue := range scan.Issues {
+		if issue.Insecure {
+			insecure++
+		}
+	}
+	result.Success = false
+	result.Error = CategoryPageError
+	first := scan.Issues[0]
+	reason := first.Error
+	if first.Status != 0 {
+		reason = fmt.Sprintf("HTTP %d", first.Status)
+	}
+	result.ErrorMessage = fmt.Sprintf("%d broken and %d insecure of %d resources (first: %s %s)",
+		len(scan.Issues)-insecure, insecure, scan.Checked, first.URL, reason)
+}
+
+// fetchResource サブリソースを取得し、読み込めなければその理由を返す（問題がなければnil）
+func (c *Checker) fetchResource(ctx context.Context, res pageResource) *FailedResource {
+	u, err := url.Parse(res.url)
+	if err != nil {
+		return &FailedResource{URL: res.url, Type: res.resourceType, Error: err.Error()}
+	}
+	c.waitForRateLimit(ctx, u.Hostname())
+
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, res.url, nil)
+	if err != nil {
+		return &FailedResource{URL: res.url, Type: res.resourceType, Error: err.Error()}
+	}
+	req.Header.Set("User-Agent", "HealthCheck/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &FailedResource{URL: res.url, Type: res.resourceType, Error: string(classifyNetError(err))}
+	}
+	defer resp.Body.Close()
+	// 接続を再利用できるよう、小さな本文は読み切る
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 400 {
+		return &FailedResource{URL: res.url, Type: res.resourceType, Status: resp.StatusCode}
+	}
+	return nil
+}
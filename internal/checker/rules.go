@@ -0,0 +1,626 @@
+package checker
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// 成功条件の式（例: status in 200..299 && latency < 800ms && body contains "ok"）
+//
+//	式     := 論理和
+//	論理和 := 論理積 ("||" 論理積)*
+//	論理積 := 単項 ("&&" 単項)*
+//	単項   := "!" 単項 | "(" 式 ")" | 比較
+//	比較   := 項目 演算子 値 | 項目（真偽値の項目のみ）
+//
+// 項目と使用できる演算子:
+//
+//	status     ステータスコード      == != < <= > >= in（200..299 または [200, 204]）
+//	latency    レイテンシ（DNS+応答） == != < <= > >=（値は 800ms などの期間）
+//	body       レスポンスボディ       == != contains matches（値は "文字列"、matchesは正規表現）
+//	cert_valid 証明書が有効か         == !=（値は true / false）、または単独で使用
+//	cert_days  証明書の残り日数       == != < <= > >=
+
+// maxRuleBodySize 成功条件の判定に読み込むレスポンスボディの最大サイズ
+const maxRuleBodySize = 1 << 20
+
+// RuleEnv 成功条件の判定に使う値
+type RuleEnv struct {
+	Status    int
+	Latency   time.Duration
+	Body      string
+	CertValid bool
+	CertDays  int
+}
+
+// Rule コンパイル済みの成功条件
+type Rule struct {
+	expr     string
+	root     ruleNode
+	usesBody bool
+	usesCert bool
+}
+
+// ruleNode 式の構文木のノード（falseの場合は満たさなかった条件の説明を返す）
+type ruleNode interface {
+	eval(env *RuleEnv) (bool, string)
+}
+
+// CompileRule 成功条件の式をコンパイル
+func CompileRule(expr string) (*Rule, error) {
+	tokens, err := lexRule(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &ruleParser{tokens: tokens, rule: &Rule{expr: expr}}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at %d", tok.text, tok.pos)
+	}
+	p.rule.root = root
+	return p.rule, nil
+}
+
+// String 元の式
+func (r *Rule) String() string { return r.expr }
+
+// UsesBody レスポンスボディを参照するか
+func (r *Rule) UsesBody() bool { return r.usesBody }
+
+// UsesCert 証明書を参照するか
+func (r *Rule) UsesCert() bool { return r.usesCert }
+
+// Eval 条件を満たすか判定（満たさない場合は理由も返す）
+func (r *Rule) Eval(env RuleEnv) (bool, string) {
+	return r.root.eval(&env)
+}
+
+// ruleCache コンパイル済みの成功条件（対象ごとにコンパイルし直さないため）
+var ruleCache sync.Map
+
+// compiledRule 式をコンパイル（一度コンパイルした式は再利用）
+func compiledRule(expr string) (*Rule, error) {
+	if r, ok := ruleCache.Load(expr); ok {
+		return r.(*Rule), nil
+	}
+	r, err := CompileRule(expr)
+	if err != nil {
+		return nil, err
+	}
+	ruleCache.Store(expr, r)
+	return r, nil
+}
+
+// ValidateRules 対象の成功条件の式がすべてコンパイルできるか検証
+func ValidateRules(targets []config.Target) error {
+	for i, t := range targets {
+		if t.Success == "" {
+			continue
+		}
+		if _, err := CompileRule(t.Success); err != nil {
+			return fmt.Errorf("target %d: invalid success %q: %w", i+1, t.Success, err)
+		}
+	}
+	return nil
+}
+
+// 字句の種類
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokInt
+	tokDuration
+	tokString
+	tokOp
+)
+
+// ruleToken 式の字句
+type ruleToken struct {
+	kind  tokenKind
+	text  string
+	pos   int
+	int   int
+	dur   time.Duration
+	value string
+}
+
+// lexRule 式を字句に分割
+func lexRule(expr string) ([]ruleToken, error) {
+	var tokens []ruleToken
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(expr) && expr[end] != '"' {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			value, err := strconv.Unquote(expr[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at %d: %w", i, err)
+			}
+			tokens = append(tokens, ruleToken{kind: tokString, text: expr[i : end+1], pos: i, value: value})
+			i = end + 1
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+				i++
+			}
+			// 200..299 の範囲と 1.5s の小数を区別する
+			if i+1 < len(expr) && expr[i] == '.' && expr[i+1] >= '0' && expr[i+1] <= '9' {
+				i++
+				for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+					i++
+				}
+			}
+			unitStart := i
+			for i < len(expr) && isLetter(expr[i]) {
+				i++
+			}
+			text := expr[start:i]
+			if i > unitStart {
+				d, err := time.ParseDuration(text)
+				if err != nil {
+					return nil, fmt.Errorf("invalid duration %q at %d", text, start)
+				}
+				tokens = append(tokens, ruleToken{kind: tokDuration, text: text, pos: start, dur: d})
+				continue
+			}
+			n, err := strconv.Atoi(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at %d", text, start)
+			}
+			tokens = append(tokens, ruleToken{kind: tokInt, text: text, pos: start, int: n})
+		case isLetter(c) || c == '_':
+			start := i
+			for i < len(expr) && (isLetter(expr[i]) || expr[i] >= '0' && expr[i] <= '9' || expr[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, ruleToken{kind: tokIdent, text: expr[start:i], pos: start})
+		default:
+			op := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "<=", ">=", "..", "<", ">", "!", "(", ")", "[", "]", ","} {
+				if strings.HasPrefix(expr[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+			}
+			tokens = append(tokens, ruleToken{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, ruleToken{kind: tokEOF, text: "end of expression", pos: len(expr)}), nil
+}
+
+// isLetter ASCIIの英字か
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// ruleParser 字句から構文木を作る再帰下降パーサー
+type ruleParser struct {
+	tokens []ruleToken
+	pos    int
+	rule   *Rule
+}
+
+func (p *ruleParser) peek() ruleToken { return p.tokens[p.pos] }
+
+func (p *ruleParser) next() ruleToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept 次の字句が指定した演算子またはキーワードなら読み進める
+func (p *ruleParser) accept(text string) bool {
+	tok := p.peek()
+	if (tok.kind == tokOp || tok.kind == tokIdent) && tok.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *ruleParser) expect(text string) error {
+	if !p.accept(text) {
+		tok := p.peek()
+		return fmt.Errorf("expected %q but got %q at %d", text, tok.text, tok.pos)
+	}
+	return nil
+}
+
+func (p *ruleParser) parseOr() (ruleNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseAnd() (ruleNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *ruleParser) parseUnary() (ruleNode, error) {
+	if p.accept("!") {
+		start := p.pos
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand, text: p.text(start)}, nil
+	}
+	if p.accept("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+	return p.parseComparison()
+}
+
+// text startからの字句を元の式の表記で返す（条件を満たさなかった理由に使う）
+func (p *ruleParser) text(start int) string {
+	var parts []string
+	for _, tok := range p.tokens[start:p.pos] {
+		parts = append(parts, tok.text)
+	}
+	return strings.Join(parts, " ")
+}
+
+// 比較演算子
+var compareOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func (p *ruleParser) parseComparison() (ruleNode, error) {
+	start := p.pos
+	field := p.next()
+	if field.kind != tokIdent {
+		return nil, fmt.Errorf("expected field but got %q at %d", field.text, field.pos)
+	}
+
+	switch field.text {
+	case "status", "cert_days":
+		if field.text == "cert_days" {
+			p.rule.usesCert = true
+		}
+		if p.accept("in") {
+			return p.parseIn(field.text, start)
+		}
+		op, err := p.compareOp()
+		if err != nil {
+			return nil, err
+		}
+		value := p.next()
+		if value.kind != tokInt {
+			return nil, fmt.Errorf("%s requires a number but got %q at %d", field.text, value.text, value.pos)
+		}
+		return &intNode{field: field.text, op: op, value: value.int, text: p.text(start)}, nil
+
+	case "latency":
+		op, err := p.compareOp()
+		if err != nil {
+			return nil, err
+		}
+		value := p.next()
+		if value.kind != tokDuration {
+			return nil, fmt.Errorf("latency requires a duration such as 800ms but got %q at %d", value.text, value.pos)
+		}
+		return &durationNode{op: op, value: value.dur, text: p.text(start)}, nil
+
+	case "body":
+		p.rule.usesBody = true
+		op := p.next()
+		if op.text != "contains" && op.text != "matches" && op.text != "==" && op.text != "!=" {
+			return nil, fmt.Errorf("body requires contains, matches, == or != but got %q at %d", op.text, op.pos)
+		}
+		value := p.next()
+		if value.kind != tokString {
+			return nil, fmt.Errorf("body requires a quoted string but got %q at %d", value.text, value.pos)
+		}
+		node := &bodyNode{op: op.text, value: value.value, text: p.text(start)}
+		if op.text == "matches" {
+			re, err := regexp.Compile(value.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression %q: %w", value.value, err)
+			}
+			node.re = re
+		}
+		return node, nil
+
+	case "cert_valid":
+		p.rule.usesCert = true
+		want := true
+		if tok := p.peek(); tok.text == "==" || tok.text == "!=" {
+			p.next()
+			value := p.next()
+			if value.text != "true" && value.text != "false" {
+				return nil, fmt.Errorf("cert_valid requires true or false but got %q at %d", value.text, value.pos)
+			}
+			want = (value.text == "true") == (tok.text == "==")
+		}
+		return &certNode{want: want, text: p.text(start)}, nil
+	}
+	return nil, fmt.Errorf("unknown field %q at %d", field.text, field.pos)
+}
+
+func (p *ruleParser) compareOp() (string, error) {
+	tok := p.next()
+	for _, op := range compareOps {
+		if tok.kind == tokOp && tok.text == op {
+			return op, nil
+		}
+	}
+	return "", fmt.Errorf("expected comparison operator but got %q at %d", tok.text, tok.pos)
+}
+
+// parseIn 範囲（200..299）または一覧（[200, 204]）
+func (p *ruleParser) parseIn(field string, start int) (ruleNode, error) {
+	node := &inNode{field: field}
+	if p.accept("[") {
+		for {
+			value := p.next()
+			if value.kind != tokInt {
+				return nil, fmt.Errorf("expected number but got %q at %d", value.text, value.pos)
+			}
+			node.values = append(node.values, value.int)
+			if p.accept("]") {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		low := p.next()
+		if low.kind != tokInt {
+			return nil, fmt.Errorf("expected range such as 200..299 but got %q at %d", low.text, low.pos)
+		}
+		if err := p.expect(".."); err != nil {
+			return nil, err
+		}
+		high := p.next()
+		if high.kind != tokInt || high.int < low.int {
+			return nil, fmt.Errorf("invalid range end %q at %d", high.text, high.pos)
+		}
+		node.low, node.high, node.isRange = low.int, high.int, true
+	}
+	node.text = p.text(start)
+	return node, nil
+}
+
+// orNode 論理和
+type orNode struct{ left, right ruleNode }
+
+func (n *orNode) eval(env *RuleEnv) (bool, string) {
+	if ok, _ := n.left.eval(env); ok {
+		return true, ""
+	}
+	return n.right.eval(env)
+}
+
+// andNode 論理積（最初に満たさなかった条件を理由にする）
+type andNode struct{ left, right ruleNode }
+
+func (n *andNode) eval(env *RuleEnv) (bool, string) {
+	if ok, reason := n.left.eval(env); !ok {
+		return false, reason
+	}
+	return n.right.eval(env)
+}
+
+// notNode 否定
+type notNode struct {
+	operand ruleNode
+	text    string
+}
+
+func (n *notNode) eval(env *RuleEnv) (bool, string) {
+	if ok, _ := n.operand.eval(env); ok {
+		return false, n.text
+	}
+	return true, ""
+}
+
+// intNode 整数の項目の比較
+type intNode struct {
+	field string
+	op    string
+	value int
+	text  string
+}
+
+func (n *intNode) eval(env *RuleEnv) (bool, string) {
+	actual := env.intField(n.field)
+	if compare(float64(actual), float64(n.value), n.op) {
+		return true, ""
+	}
+	return false, fmt.Sprintf("%s (actual %d)", n.text, actual)
+}
+
+// inNode 整数の項目が範囲または一覧に含まれるか
+type inNode struct {
+	field     string
+	isRange   bool
+	low, high int
+	values    []int
+	text      string
+}
+
+func (n *inNode) eval(env *RuleEnv) (bool, string) {
+	actual := env.intField(n.field)
+	if n.isRange && actual >= n.low && actual <= n.high {
+		return true, ""
+	}
+	for _, v := range n.values {
+		if actual == v {
+			return true, ""
+		}
+	}
+	return false, fmt.Sprintf("%s (actual %d)", n.text, actual)
+}
+
+// durationNode レイテンシの比較
+type durationNode struct {
+	op    string
+	value time.Duration
+	text  string
+}
+
+func (n *durationNode) eval(env *RuleEnv) (bool, string) {
+	if compare(float64(env.Latency), float64(n.value), n.op) {
+		return true, ""
+	}
+	return false, fmt.Sprintf("%s (actual %v)", n.text, env.Latency.Round(time.Millisecond))
+}
+
+// bodyNode レスポンスボディの照合
+type bodyNode struct {
+	op    string
+	value string
+	re    *regexp.Regexp
+	text  string
+}
+
+func (n *bodyNode) eval(env *RuleEnv) (bool, string) {
+	var ok bool
+	switch n.op {
+	case "contains":
+		ok = strings.Contains(env.Body, n.value)
+	case "matches":
+		ok = n.re.MatchString(env.Body)
+	case "==":
+		ok = env.Body == n.value
+	case "!=":
+		ok = env.Body != n.value
+	}
+	if ok {
+		return true, ""
+	}
+	return false, n.text
+}
+
+// certNode 証明書の有効性
+type certNode struct {
+	want bool
+	text string
+}
+
+func (n *certNode) eval(env *RuleEnv) (bool, string) {
+	if env.CertValid == n.want {
+		return true, ""
+	}
+	return false, fmt.Sprintf("%s (actual %v)", n.text, env.CertValid)
+}
+
+// intField 整数の項目の値
+func (env *RuleEnv) intField(field string) int {
+	if field == "cert_days" {
+		return env.CertDays
+	}
+	return env.Status
+}
+
+// compare 比較演算子で2つの値を比較
+func compare(actual, value float64, op string) bool {
+	switch op {
+	case "==":
+		return actual == value
+	case "!=":
+		return actual != value
+	case "<":
+		return actual < value
+	case "<=":
+		return actual <= value
+	case ">":
+		return actual > value
+	case ">=":
+		return actual >= value
+	}
+	return false
+}
+
+// evalSuccess 対象の成功条件の式でレスポンスを判定して結果に反映
+func (c *Checker) evalSuccess(target config.Target, resp *http.Response, body []byte, result *CheckResult) {
+	rule, err := compiledRule(target.Success)
+	if err != nil {
+		result.Error = CategoryInvalidRule
+		result.ErrorMessage = fmt.Sprintf("Invalid success expression: %v", err)
+		return
+	}
+
+	env := RuleEnv{Status: resp.StatusCode, Latency: result.Latency, Body: string(body)}
+	if rule.UsesCert() {
+		env.CertValid, env.CertDays = certStatus(resp.TLS, resp.Request.URL.Hostname(), time.Now())
+	}
+
+	ok, reason := rule.Eval(env)
+	result.Success = ok
+	if !ok {
+		result.Error = CategoryAssertionFailed
+		result.ErrorMessage = "Success condition not met: " + reason
+	}
+}
+
+// certStatus サーバー証明書がホスト名に対して有効か（検証を省略する設定でも検証する）と残り日数
+func certStatus(state *tls.ConnectionState, host string, now time.Time) (bool, int) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return false, 0
+	}
+	leaf := state.PeerCertificates[0]
+	days := int(leaf.NotAfter.Sub(now).Hours() / 24)
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Intermediates: intermediates, CurrentTime: now})
+	return err == nil, days
+}
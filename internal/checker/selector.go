@@ -0,0 +1,299 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Selector 本文の一部を選択するCSSセレクター
+// タグ名・#id・.class（例: div#main .price）と子孫の組み合わせのみに対応する
+type Selector struct {
+	source string
+	steps  []selectorStep
+}
+
+// selectorStep セレクターの1つの要素（空白区切りの1語）
+type selectorStep struct {
+	tag     string
+	id      string
+	classes []string
+}
+
+// htmlElement 開始タグから読み取った要素
+type htmlElement struct {
+	tag     string
+	id      string
+	classes []string
+	attrs   map[string]string // すべての属性（名前は小文字）
+	start   int               // 開始タグの位置
+}
+
+// voidElements 終了タグを持たない要素
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawTextElements 内容をタグとして解釈しない要素
+var rawTextElements = map[string]bool{"script": true, "style": true, "textarea": true, "title": true}
+
+// CompileSelector CSSセレクターを解析
+func CompileSelector(source string) (*Selector, error) {
+	fields := strings.Fields(source)
+	if len(fields) == 0 {
+		return nil, errors.New("empty selector")
+	}
+	s := &Selector{source: source}
+	for _, f := range fields {
+		step, err := parseSelectorStep(f)
+		if err != nil {
+			return nil, err
+		}
+		s.steps = append(s.steps, step)
+	}
+	return s, nil
+}
+
+// String セレクターの文字列
+func (s *Selector) String() string {
+	return s.source
+}
+
+// parseSelectorStep タグ名・#id・.classからなる1語を解析
+func parseSelectorStep(word string) (selectorStep, error) {
+	var step selectorStep
+	i := 0
+	readName := func() string {
+		start := i
+		for i < len(word) && isNameChar(word[i]) {
+			i++
+		}
+		return word[start:i]
+	}
+
+	if word[0] == '*' {
+		i++
+	} else {
+		step.tag = strings.ToLower(readName())
+	}
+	for i < len(word) {
+		c := word[i]
+		i++
+		name := readName()
+		switch {
+		case c == '#' && name != "":
+			step.id = name
+		case c == '.' && name != "":
+			step.classes = append(step.classes, name)
+		default:
+			return step, fmt.Errorf("unsupported selector %q (only tag, #id, .class and descendants are supported)", word)
+		}
+	}
+	return step, nil
+}
+
+// isNameChar タグ名・ID・クラス名に使える文字か（ASCII以外は許可）
+func isNameChar(c byte) bool {
+	return isLetter(c) || c >= '0' && c <= '9' || c == '-' || c == '_' || c >= 0x80
+}
+
+// matches 要素がセレクターの1語に一致するか
+func (st selectorStep) matches(el htmlElement) bool {
+	if st.tag != "" && st.tag != el.tag {
+		return false
+	}
+	if st.id != "" && st.id != el.id {
+		return false
+	}
+	for _, c := range st.classes {
+		found := false
+		for _, ec := range el.classes {
+			if c == ec {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesStack 開いている要素の並び（最後が対象の要素）がセレクターに一致するか
+func (s *Selector) matchesStack(stack []htmlElement) bool {
+	last := len(s.steps) - 1
+	if !s.steps[last].matches(stack[len(stack)-1]) {
+		return false
+	}
+	step := last - 1
+	for i := len(stack) - 2; i >= 0 && step >= 0; i-- {
+		if s.steps[step].matches(stack[i]) {
+			step--
+		}
+	}
+	return step < 0
+}
+
+// Select HTMLからセレクターに一致する要素（開始タグから終了タグまで）を文書内の順に返す
+// 一致した要素の内側でさらに一致した要素は、外側の要素に含まれるため個別には返さない
+func (s *Selector) Select(doc string) []string {
+	var stack []htmlElement
+	var parts []string
+	matched := -1 // 一致して閉じていない要素のstack上の位置
+	i := 0
+	for {
+		lt := strings.IndexByte(doc[i:], '<')
+		if lt < 0 {
+			break
+		}
+		i += lt
+		rest := doc[i:]
+
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				i = len(doc)
+			} else {
+				i += 4 + end + 3
+			}
+
+		case strings.HasPrefix(rest, "</"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				i = len(doc)
+				break
+			}
+			name := strings.ToLower(strings.TrimSpace(rest[2:end]))
+			i += end + 1
+			// 同じ名前で最も内側の要素まで閉じる（閉じ忘れの要素も合わせて閉じる）
+			for j := len(stack) - 1; j >= 0; j-- {
+				if stack[j].tag == name {
+					if matched >= j {
+						parts = append(parts, doc[stack[matched].start:i])
+						matched = -1
+					}
+					stack = stack[:j]
+					break
+				}
+			}
+
+		case len(rest) > 1 && isLetter(rest[1]):
+			el, end, selfClosing := parseStartTag(rest)
+			el.start = i
+			i += end
+			if voidElements[el.tag] || selfClosing {
+				if matched < 0 && s.matchesStack(append(stack, el)) {
+					parts = append(parts, doc[el.start:i])
+				}
+				break
+			}
+			stack = append(stack, el)
+			if matched < 0 && s.matchesStack(stack) {
+				matched = len(stack) - 1
+			}
+			if rawTextElements[el.tag] {
+				i += indexEndTag(doc[i:], el.tag)
+			}
+
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				i = len(doc)
+			} else {
+				i += end + 1
+			}
+
+		default:
+			i++
+		}
+	}
+	if matched >= 0 {
+		parts = append(parts, doc[stack[matched].start:])
+	}
+	return parts
+}
+
+// parseStartTag 開始タグからタグ名・id・class・属性を読み取る（endは開始タグの直後の位置）
+func parseStartTag(tag string) (el htmlElement, end int, selfClosing bool) {
+	i := 1
+	for i < len(tag) && isNameChar(tag[i]) {
+		i++
+	}
+	el.tag = strings.ToLower(tag[1:i])
+
+	for i < len(tag) {
+		switch c := tag[i]; {
+		case c == '>':
+			return el, i + 1, selfClosing
+		case c == '/':
+			selfClosing = true
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+			continue
+		}
+		selfClosing = false
+
+		// 属性名
+		start := i
+		for i < len(tag) && !strings.ContainsRune(" \t\n\r\f=>/", rune(tag[i])) {
+			i++
+		}
+		name := strings.ToLower(tag[start:i])
+		if i >= len(tag) || tag[i] != '=' {
+			continue
+		}
+		i++
+
+		// 属性値（引用符あり・なし）
+		var value string
+		if i < len(tag) && (tag[i] == '"' || tag[i] == '\'') {
+			quote := tag[i]
+			closing := strings.IndexByte(tag[i+1:], quote)
+			if closing < 0 {
+				return el, len(tag), false
+			}
+			value = tag[i+1 : i+1+closing]
+			i += closing + 2
+		} else {
+			start := i
+			for i < len(tag) && !strings.ContainsRune(" \t\n\r\f>", rune(tag[i])) {
+				i++
+			}
+			value = tag[start:i]
+		}
+		switch name {
+		case "id":
+			el.id = value
+		case "class":
+			el.classes = strings.Fields(value)
+		}
+		if el.attrs == nil {
+			el.attrs = make(map[string]string)
+		}
+		if _, ok := el.attrs[name]; !ok {
+			el.attrs[name] = value
+		}
+	}
+	return el, len(tag), selfClosing
+}
+
+// indexEndTag 指定した要素の終了タグの位置（大文字小文字を区別しない、見つからない場合は末尾）
+func indexEndTag(doc, tag string) int {
+	offset := 0
+	for {
+		idx := strings.Index(doc[offset:], "</")
+		if idx < 0 {
+			return len(doc)
+		}
+		pos := offset + idx
+		if name := doc[pos+2:]; len(name) >= len(tag) && strings.EqualFold(name[:len(tag)], tag) {
+			return pos
+		}
+		offset = pos + 2
+	}
+}
@@ -0,0 +1,243 @@
+package checker
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"healthcheck/internal/config"
+)
+
+// 展開対象として指定する際のURLの接頭辞
+const (
+	SitemapPrefix = "sitemap:"
+	RobotsPrefix  = "robots:"
+)
+
+// maxSitemapDepth サイトマップインデックスをたどる最大の深さ
+const maxSitemapDepth = 3
+
+// maxSitemapSize 読み込むサイトマップの最大サイズ（50MB）
+const maxSitemapSize = 50 << 20
+
+// sitemapDocument urlsetとsitemapindexの両方に対応するサイトマップ
+type sitemapDocument struct {
+	URLs []struct {
+		Loc string `xml:"loc"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// IsExpandable sitemap:またはrobots:で指定された対象か
+func IsExpandable(target string) bool {
+	return strings.HasPrefix(target, SitemapPrefix) || strings.HasPrefix(target, RobotsPrefix)
+}
+
+// ExpandURLs sitemap:/robots:で指定された対象を個別のページのURLに展開
+// 展開できなかった場合はサイトマップ自体のURLをチェック対象として残す
+func (c *Checker) ExpandURLs(ctx context.Context, urls []string) []string {
+	var expanded []string
+	seen := make(map[string]bool)
+	add := func(u string) {
+		if !seen[u] {
+			seen[u] = true
+			expanded = append(expanded, u)
+		}
+	}
+
+	for _, u := range urls {
+		if !IsExpandable(u) {
+			add(u)
+			continue
+		}
+
+		pages, source, err := c.expand(ctx, u)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to expand URL", "url", u, "error", err)
+			add(source)
+			continue
+		}
+		for _, page := range pages {
+			add(page)
+		}
+	}
+	return expanded
+}
+
+// ExpandTargets sitemap:/robots:の対象を個別のページの対象に展開
+// 展開したページには元の対象の名前・タグ・リクエストの設定を引き継ぐ
+func (c *Checker) ExpandTargets(ctx context.Context, targets []config.Target) []config.Target {
+	var expanded []config.Target
+	for _, t := range targets {
+		if !IsExpandable(t.URL) {
+			expanded = append(expanded, t)
+			continue
+		}
+		for _, u := range c.ExpandURLs(ctx, []string{t.URL}) {
+			page := t
+			page.URL = u
+			expanded = append(expanded, page)
+		}
+	}
+	return expanded
+}
+
+// expand 1つの対象をページのURLに展開（sourceは取得元のURL）
+func (c *Checker) expand(ctx context.Context, target string) ([]string, string, error) {
+	var sitemaps []string
+	source := strings.TrimPrefix(target, SitemapPrefix)
+	if strings.HasPrefix(target, RobotsPrefix) {
+		source = strings.TrimPrefix(target, RobotsPrefix)
+		found, err := c.robotsSitemaps(ctx, source)
+		if err != nil {
+			return nil, source, err
+		}
+		if len(found) == 0 {
+			return nil, source, fmt.Errorf("no Sitemap entries in robots.txt")
+		}
+		sitemaps = found
+	} else {
+		sitemaps = []string{source}
+	}
+
+	include, exclude := compilePatterns(c.config.SitemapInclude), compilePatterns(c.config.SitemapExclude)
+	var pages []string
+	for _, sitemap := range sitemaps {
+		if err := c.collectSitemap(ctx, sitemap, 0, include, exclude, &pages); err != nil {
+			return nil, source, err
+		}
+		if len(pages) >= c.config.SitemapMaxURLs {
+			break
+		}
+	}
+	if len(pages) == 0 {
+		return nil, source, fmt.Errorf("no URLs matched in sitemap")
+	}
+	return pages, source, nil
+}
+
+// collectSitemap サイトマップのURLを上限まで集める（インデックスは再帰的にたどる）
+func (c *Checker) collectSitemap(ctx context.Context, sitemapURL string, depth int, include, exclude []*regexp.Regexp, pages *[]string) error {
+	if depth > maxSitemapDepth {
+		return nil
+	}
+
+	body, err := c.fetch(ctx, sitemapURL)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	var reader io.Reader = body
+	if strings.HasSuffix(sitemapURL, ".gz") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return fmt.Errorf("failed to decompress sitemap: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var doc sitemapDocument
+	if err := xml.NewDecoder(io.LimitReader(reader, maxSitemapSize)).Decode(&doc); err != nil {
+		return fmt.Errorf("failed to parse sitemap %s: %w", sitemapURL, err)
+	}
+
+	for _, u := range doc.URLs {
+		if len(*pages) >= c.config.SitemapMaxURLs {
+			return nil
+		}
+		loc := strings.TrimSpace(u.Loc)
+		if loc != "" && matchPatterns(loc, include, exclude) {
+			*pages = append(*pages, loc)
+		}
+	}
+	for _, s := range doc.Sitemaps {
+		if len(*pages) >= c.config.SitemapMaxURLs {
+			return nil
+		}
+		if err := c.collectSitemap(ctx, strings.TrimSpace(s.Loc), depth+1, include, exclude, pages); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// robotsSitemaps robots.txtのSitemap行のURLを返す
+func (c *Checker) robotsSitemaps(ctx context.Context, robotsURL string) ([]string, error) {
+	body, err := c.fetch(ctx, robotsURL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var sitemaps []string
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "sitemap") {
+			sitemaps = append(sitemaps, strings.TrimSpace(value))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read robots.txt: %w", err)
+	}
+	return sitemaps, nil
+}
+
+// fetch URLを取得して本文を返す（2xx以外はエラー）
+func (c *Checker) fetch(ctx context.Context, targetURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "HealthCheck/1.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", targetURL, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: HTTP %d", targetURL, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// compilePatterns 正規表現のパターンをコンパイル（不正なパターンは無視）
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, p := range patterns {
+		if re, err := regexp.Compile(p); err == nil {
+			compiled = append(compiled, re)
+		}
+	}
+	return compiled
+}
+
+// matchPatterns URLが対象パターンのいずれかに一致し、除外パターンのどれにも一致しないか
+func matchPatterns(u string, include, exclude []*regexp.Regexp) bool {
+	for _, re := range exclude {
+		if re.MatchString(u) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, re := range include {
+		if re.MatchString(u) {
+			return true
+		}
+	}
+	return false
+}
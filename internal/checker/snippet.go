@@ -0,0 +1,66 @@
+package checker
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// redacted 秘匿情報を伏せた箇所に入れる文字列
+const redacted = "[REDACTED]"
+
+// secretHeaders 値を伏せるレスポンスヘッダー（小文字）
+var secretHeaders = map[string]bool{
+	"set-cookie":          true,
+	"cookie":              true,
+	"authorization":       true,
+	"proxy-authorization": true,
+	"x-api-key":           true,
+	"x-auth-token":        true,
+}
+
+// secretPatterns 本文から伏せる秘匿情報のパターン（1番目のグループは残す）
+var secretPatterns = []*regexp.Regexp{
+	// password=... / "token": "..." などのキーと値
+	regexp.MustCompile(`(?i)("?\b(?:password|passwd|secret|token|api[_-]?key|access[_-]?key|client[_-]?secret|session(?:[_-]?id)?|authorization)"?\s*[:=]\s*"?)((?:bearer\s+|basic\s+)?[^"\s&,;<]+)`),
+	// Bearerトークン
+	regexp.MustCompile(`(?i)(\bbearer\s+)[a-z0-9._~+/=-]+`),
+	// JWT（末尾が切り詰められたものを含む）
+	regexp.MustCompile(`()\beyJ[a-zA-Z0-9_-]+\.[a-zA-Z0-9_-]*(?:\.[a-zA-Z0-9_-]*)?`),
+}
+
+// captureSnippet 失敗した応答の本文の先頭と指定したヘッダーを秘匿情報を伏せて記録
+func (c *Checker) captureSnippet(resp *http.Response, body []byte, result *CheckResult) {
+	if n := c.config.SnippetBytes; n > 0 && len(body) > 0 {
+		if len(body) > n {
+			body = body[:n]
+		}
+		// 途中で切れた文字は除く
+		result.Snippet = redactSecrets(strings.ToValidUTF8(string(body), ""))
+	}
+
+	for _, name := range c.config.SnippetHeaders {
+		values := resp.Header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		if result.ResponseHeaders == nil {
+			result.ResponseHeaders = make(map[string]string)
+		}
+		value := strings.Join(values, ", ")
+		if secretHeaders[strings.ToLower(name)] {
+			value = redacted
+		} else {
+			value = redactSecrets(value)
+		}
+		result.ResponseHeaders[http.CanonicalHeaderKey(name)] = value
+	}
+}
+
+// redactSecrets パスワードやトークンと思われる値を伏せる
+func redactSecrets(s string) string {
+	for _, re := range secretPatterns {
+		s = re.ReplaceAllString(s, "${1}"+redacted)
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package checker
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+const (
+	// sshClientVersion 鍵交換の開始を確認するために送るクライアントのバージョン
+	sshClientVersion = "SSH-2.0-HealthCheck_1.0\r\n"
+	// sshMsgKexInit 鍵交換の開始を表すメッセージの番号（RFC 4253）
+	sshMsgKexInit = 20
+	// maxBannerLines バージョンの行より前に送られる行を読み飛ばす最大数
+	maxBannerLines = 20
+)
+
+// sshProvider SSHサーバーのチェック（ssh://host:port、ポートの省略時は22）
+// バナー（バージョン）を受信し、鍵交換の開始（KEXINIT）まで進むことを確かめる。認証は行わない
+type sshProvider struct {
+	checker *Checker
+}
+
+// Scheme 担当するスキーム
+func (p *sshProvider) Scheme() string { return "ssh" }
+
+// Check バナーと鍵交換の開始を確かめる
+func (p *sshProvider) Check(ctx context.Context, target config.Target) *CheckResult {
+	result := &CheckResult{
+		URL:       target.URL,
+		Timestamp: time.Now().UTC(),
+		Success:   false,
+	}
+
+	conn, err := p.checker.dialBanner(ctx, target, "22", result)
+	if err != nil {
+		return result
+	}
+	defer conn.Close()
+
+	// バージョンの行（SSH-protoversion-softwareversion）の前に他の行が送られることがある
+	bannerStart := time.Now()
+	var banner, firstLine string
+	for i := 0; i < maxBannerLines && banner == ""; i++ {
+		line, err := conn.reader.ReadString('\n')
+		if err != nil && firstLine == "" {
+			conn.fail(err, result)
+			return result
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "SSH-") {
+			banner = line
+		} else if firstLine == "" {
+			firstLine = line
+		}
+		if err != nil {
+			break
+		}
+	}
+	result.Phases.Processing = time.Since(bannerStart)
+	result.Banner = banner
+	if banner == "" {
+		// SSH以外のサービスが応答している
+		conn.finish(result)
+		result.Error = CategoryProtocolError
+		result.ErrorMessage = fmt.Sprintf("No SSH version banner received (got %q)", firstLine)
+		return result
+	}
+	if !strings.HasPrefix(banner, "SSH-2.0-") && !strings.HasPrefix(banner, "SSH-1.99-") {
+		conn.finish(result)
+		result.Error = CategoryProtocolError
+		result.ErrorMessage = fmt.Sprintf("Unsupported SSH protocol: %s", banner)
+		return result
+	}
+
+	// こちらのバージョンを送り、サーバーが鍵交換を始めることを確かめる
+	if _, err := io.WriteString(conn, sshClientVersion); err != nil {
+		conn.fail(err, result)
+		return result
+	}
+	var header [6]byte
+	if _, err := io.ReadFull(conn.reader, header[:]); err != nil {
+		conn.fail(err, result)
+		return result
+	}
+	conn.finish(result)
+	if length := binary.BigEndian.Uint32(header[:4]); length > 35000 || header[5] != sshMsgKexInit {
+		result.Error = CategoryProtocolError
+		result.ErrorMessage = fmt.Sprintf("Unexpected SSH message %d after version exchange", header[5])
+		return result
+	}
+
+	result.Success = true
+	return result
+}
@@ -0,0 +1,52 @@
+package checker
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// Stagger 対象ごとのチェックを開始するまでの遅延（実行の開始からの時間）
+type Stagger func(target config.Target) time.Duration
+
+// staggerKey コンテキストに対象ごとの開始の遅延を保持するキー
+type staggerKey struct{}
+
+// WithStagger 対象ごとに開始を遅らせて実行するコンテキスト（定期チェックで開始時刻を分散するため）
+func WithStagger(ctx context.Context, stagger Stagger) context.Context {
+	return context.WithValue(ctx, staggerKey{}, stagger)
+}
+
+// staggerDelays 対象ごとの開始の遅延と、遅延の短い順に並べ替えたチェックする順
+// 遅延が同じ対象はorderの順（優先度の高い対象が先）を保つ。遅延の指定がない場合はnilとorderをそのまま返す
+func staggerDelays(ctx context.Context, targets []config.Target, order []int) ([]time.Duration, []int) {
+	stagger, ok := ctx.Value(staggerKey{}).(Stagger)
+	if !ok || stagger == nil {
+		return nil, order
+	}
+	delays := make([]time.Duration, len(targets))
+	for i, t := range targets {
+		delays[i] = stagger(t)
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(delays[a], delays[b])
+	})
+	return delays, order
+}
+
+// waitUntil 指定した日時まで待つ（コンテキストが終了した場合はすぐに戻る）
+func waitUntil(ctx context.Context, at time.Time) {
+	d := time.Until(at)
+	if d <= 0 {
+		return
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
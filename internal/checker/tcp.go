@@ -0,0 +1,56 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// tcpProvider TCP接続のチェック（tcp://host:port）
+type tcpProvider struct {
+	checker *Checker
+}
+
+// Scheme 担当するスキーム
+func (p *tcpProvider) Scheme() string { return "tcp" }
+
+// Check 接続できれば成功とする
+func (p *tcpProvider) Check(ctx context.Context, target config.Target) *CheckResult {
+	result := &CheckResult{
+		URL:       target.URL,
+		Timestamp: time.Now().UTC(),
+		Success:   false,
+	}
+
+	parsedURL, err := url.Parse(target.URL)
+	if err != nil || parsedURL.Port() == "" {
+		result.Error = CategoryInvalidURL
+		result.ErrorMessage = fmt.Sprintf("TCP target must be tcp://host:port: %s", target.URL)
+		return result
+	}
+	p.checker.waitForRateLimit(ctx, parsedURL.Hostname())
+
+	dialCtx, cancel := context.WithTimeout(ctx, p.checker.config.Timeout)
+	defer cancel()
+
+	dialer := newDialer(p.checker.config, 0)
+	startTime := time.Now()
+	conn, err := dialer.DialContext(dialCtx, "tcp", parsedURL.Host)
+	result.ResponseTime = time.Since(startTime)
+	result.Latency = result.ResponseTime
+	if err != nil {
+		result.Error = classifyNetError(err)
+		if dialCtx.Err() == context.DeadlineExceeded {
+			result.Error = CategoryTimeout
+		}
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	conn.Close()
+
+	result.Success = true
+	return result
+}
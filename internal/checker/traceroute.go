@@ -0,0 +1,120 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// hopTimeout 各ホップの応答を待つ時間
+const hopTimeout = time.Second
+
+// tracePortBase 経路の調査に使うUDPの宛先ポート（TTLを加えて使用）
+const tracePortBase = 33434
+
+// Hop 経路の1ホップ
+type Hop struct {
+	TTL         int           `json:"ttl"`
+	Addr        string        `json:"addr,omitempty"`        // 応答したルーターのアドレス（応答がない場合は空）
+	RTT         time.Duration `json:"rtt_ms,omitempty"`      // 応答までの時間
+	Reached     bool          `json:"reached,omitempty"`     // 宛先に到達した
+	Unreachable bool          `json:"unreachable,omitempty"` // ルーターが宛先に到達できないと応答した
+}
+
+// traceFailure ネットワークレベルで失敗した結果に宛先までの経路を付与し、原因のヒントに要約を追加
+func (c *Checker) traceFailure(ctx context.Context, target config.Target, result *CheckResult) {
+	ip := c.traceAddress(ctx, target, result)
+	if ip == nil {
+		return
+	}
+
+	hops, err := c.traceroute(ctx, ip)
+	if err != nil {
+		slog.WarnContext(ctx, "traceroute failed", "url", result.URL, "address", ip.String(), "error", err)
+	}
+	result.Hops = hops
+	if summary := describeHops(ip, hops); summary != "" {
+		if result.Hint != "" {
+			result.Hint += "; "
+		}
+		result.Hint += summary
+	}
+}
+
+// traceAddress 経路を調べる宛先のIPアドレス（接続できた場合はその接続先、resolveの指定、名前解決の結果の順）
+func (c *Checker) traceAddress(ctx context.Context, target config.Target, result *CheckResult) net.IP {
+	if host, _, err := net.SplitHostPort(result.RemoteAddr); err == nil {
+		return net.ParseIP(host)
+	}
+	dial, _ := dialTargetFor(ctx, target)
+	if dial.address != "" {
+		host := dial.address
+		if h, _, err := net.SplitHostPort(dial.address); err == nil {
+			host = h
+		}
+		return net.ParseIP(host)
+	}
+
+	parsedURL, err := url.Parse(result.URL)
+	if err != nil {
+		return nil
+	}
+	network := "ip"
+	switch dial.network {
+	case "tcp4":
+		network = "ip4"
+	case "tcp6":
+		network = "ip6"
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	ips, err := net.DefaultResolver.LookupIP(lookupCtx, network, parsedURL.Hostname())
+	if err != nil || len(ips) == 0 {
+		return nil
+	}
+	return ips[0]
+}
+
+// traceroute TTLを1ずつ増やしながら宛先までの経路を調べる（宛先に到達するか最大ホップ数で終了）
+func (c *Checker) traceroute(ctx context.Context, ip net.IP) ([]Hop, error) {
+	var hops []Hop
+	for ttl := 1; ttl <= c.config.TracerouteMaxHops; ttl++ {
+		if ctx.Err() != nil {
+			return hops, ctx.Err()
+		}
+		hop, err := probeHop(ip, ttl, hopTimeout)
+		if err != nil {
+			return hops, err
+		}
+		hops = append(hops, hop)
+		if hop.Reached || hop.Unreachable {
+			break
+		}
+	}
+	return hops, nil
+}
+
+// describeHops 経路の調査結果を1行に要約
+func describeHops(ip net.IP, hops []Hop) string {
+	if len(hops) == 0 {
+		return ""
+	}
+	last := hops[len(hops)-1]
+	switch {
+	case last.Reached:
+		return fmt.Sprintf("traceroute: reached %s at hop %d", ip, last.TTL)
+	case last.Unreachable:
+		return fmt.Sprintf("traceroute: hop %d (%s) reports %s unreachable", last.TTL, last.Addr, ip)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if hops[i].Addr != "" {
+			return fmt.Sprintf("traceroute: no response from %s after hop %d (%s)", ip, hops[i].TTL, hops[i].Addr)
+		}
+	}
+	return fmt.Sprintf("traceroute: no router responded on the path to %s", ip)
+}
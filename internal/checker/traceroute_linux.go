@@ -0,0 +1,108 @@
+//go:build linux
+
+package checker
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+)
+
+// ICMPのエラーの発生元（sock_extended_errのee_origin）
+const (
+	eeOriginICMP  = 2
+	eeOriginICMP6 = 3
+)
+
+// probeHop 指定したTTLでUDPパケットを送り、ICMPエラーを返したルーターを調べる
+// IP_RECVERRでソケットのエラーキューからICMPの送信元を受け取るため、特権を必要としない
+func probeHop(ip net.IP, ttl int, timeout time.Duration) (Hop, error) {
+	hop := Hop{TTL: ttl}
+	family, level, ttlOption, recvErrOption := syscall.AF_INET, syscall.IPPROTO_IP, syscall.IP_TTL, syscall.IP_RECVERR
+	var sa syscall.Sockaddr
+	if ip4 := ip.To4(); ip4 != nil {
+		sa4 := &syscall.SockaddrInet4{Port: tracePortBase + ttl}
+		copy(sa4.Addr[:], ip4)
+		sa = sa4
+	} else {
+		family, level, ttlOption, recvErrOption = syscall.AF_INET6, syscall.IPPROTO_IPV6, syscall.IPV6_UNICAST_HOPS, syscall.IPV6_RECVERR
+		sa6 := &syscall.SockaddrInet6{Port: tracePortBase + ttl}
+		copy(sa6.Addr[:], ip.To16())
+		sa = sa6
+	}
+
+	fd, err := syscall.Socket(family, syscall.SOCK_DGRAM|syscall.SOCK_NONBLOCK|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return hop, fmt.Errorf("failed to create socket: %w", err)
+	}
+	defer syscall.Close(fd)
+	if err := syscall.SetsockoptInt(fd, level, ttlOption, ttl); err != nil {
+		return hop, fmt.Errorf("failed to set TTL: %w", err)
+	}
+	if err := syscall.SetsockoptInt(fd, level, recvErrOption, 1); err != nil {
+		return hop, fmt.Errorf("failed to enable error queue: %w", err)
+	}
+
+	start := time.Now()
+	if err := syscall.Sendto(fd, []byte("healthcheck"), 0, sa); err != nil {
+		return hop, fmt.Errorf("failed to send probe: %w", err)
+	}
+
+	buf := make([]byte, 512)
+	oob := make([]byte, 512)
+	for time.Since(start) < timeout {
+		_, oobn, _, _, err := syscall.Recvmsg(fd, buf, oob, syscall.MSG_ERRQUEUE)
+		if err == syscall.EAGAIN {
+			time.Sleep(5 * time.Millisecond)
+			continue
+		}
+		if err != nil {
+			return hop, fmt.Errorf("failed to receive ICMP error: %w", err)
+		}
+		if addr, reached, unreachable, ok := parseRecvErr(oob[:oobn]); ok {
+			hop.RTT = time.Since(start)
+			hop.Addr = addr
+			hop.Reached = reached
+			hop.Unreachable = unreachable && !reached
+			return hop, nil
+		}
+	}
+	return hop, nil
+}
+
+// parseRecvErr エラーキューの制御メッセージ（sock_extended_errと送信元のアドレス）を解析
+// 宛先のポートが閉じている応答（Port Unreachable）は宛先に到達したとみなす
+func parseRecvErr(oob []byte) (addr string, reached, unreachable, ok bool) {
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return "", false, false, false
+	}
+	for _, m := range msgs {
+		isIPv4 := m.Header.Level == syscall.IPPROTO_IP && m.Header.Type == syscall.IP_RECVERR
+		isIPv6 := m.Header.Level == syscall.IPPROTO_IPV6 && m.Header.Type == syscall.IPV6_RECVERR
+		if (!isIPv4 && !isIPv6) || len(m.Data) < 16 {
+			continue
+		}
+
+		// struct sock_extended_err { u32 errno; u8 origin, type, code, pad; u32 info, data; }
+		origin, icmpType, icmpCode := m.Data[4], m.Data[5], m.Data[6]
+		offender := m.Data[16:]
+		if len(offender) >= 8 && binary.NativeEndian.Uint16(offender) == syscall.AF_INET {
+			addr = net.IP(offender[4:8]).String()
+		} else if len(offender) >= 24 && binary.NativeEndian.Uint16(offender) == syscall.AF_INET6 {
+			addr = net.IP(offender[8:24]).String()
+		}
+
+		switch origin {
+		case eeOriginICMP:
+			// 3: Destination Unreachable（コード3はPort Unreachable）、11: Time Exceeded
+			return addr, icmpType == 3 && icmpCode == 3, icmpType == 3, true
+		case eeOriginICMP6:
+			// 1: Destination Unreachable（コード4はPort Unreachable）、3: Time Exceeded
+			return addr, icmpType == 1 && icmpCode == 4, icmpType == 1, true
+		}
+	}
+	return "", false, false, false
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package checker
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// probeHop 経路の調査はLinuxのみ対応
+func probeHop(ip net.IP, ttl int, timeout time.Duration) (Hop, error) {
+	return Hop{TTL: ttl}, errors.New("traceroute is only supported on linux")
+}
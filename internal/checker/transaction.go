@@ -0,0 +1,157 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"time"
+
+	"healthcheck/internal/events"
+	"healthcheck/internal/tracing"
+)
+
+// Step トランザクションを構成する1リクエスト
+type Step struct {
+	Method         string            `json:"method"`
+	URL            string            `json:"url"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Body           string            `json:"body,omitempty"`
+	ExpectedStatus int               `json:"expected_status,omitempty"` // 0の場合は2xx/3xxを成功とみなす
+}
+
+// Transaction 順番に実行する複数ステップのチェック
+type Transaction struct {
+	Name  string `json:"name"`
+	Steps []Step `json:"steps"`
+}
+
+// CheckTransaction トランザクションの各ステップを順に実行
+// クッキーはステップ間で引き継ぎ、リダイレクトは記録どおり個別のステップとして扱う
+func (c *Checker) CheckTransaction(ctx context.Context, tx *Transaction) *CheckResult {
+	result := &CheckResult{
+		URL:       tx.Name,
+		Timestamp: time.Now().UTC(),
+		Success:   false,
+		Region:    c.config.Region,
+	}
+
+	ctx, span := tracing.Start(ctx, "transaction")
+	defer func() {
+		span.SetAttribute("healthcheck.transaction", tx.Name)
+		span.SetAttribute("healthcheck.success", result.Success)
+		if !result.Success {
+			span.SetError(result.ErrorMessage)
+		}
+		span.Finish()
+		events.Publish(events.Event{Type: "result", RunID: span.TraceID, Project: events.ProjectFromContext(ctx), Data: result})
+	}()
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{
+		Transport: c.httpClient.Transport,
+		Timeout:   c.config.Timeout,
+		Jar:       jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	for i, step := range tx.Steps {
+		stepResult := c.checkStep(ctx, client, step)
+		result.Steps = append(result.Steps, stepResult)
+		result.ResponseTime += stepResult.ResponseTime
+		result.Latency += stepResult.Latency
+		result.StatusCode = stepResult.StatusCode
+
+		if !stepResult.Success {
+			result.Error = stepResult.Error
+			result.ErrorMessage = fmt.Sprintf("step %d (%s %s): %s", i+1, step.Method, step.URL, stepResult.ErrorMessage)
+			return result
+		}
+	}
+
+	result.Success = true
+	return result
+}
+
+// checkStep トランザクションの1ステップを実行
+func (c *Checker) checkStep(ctx context.Context, client *http.Client, step Step) *CheckResult {
+	result := &CheckResult{
+		URL:       step.URL,
+		Timestamp: time.Now().UTC(),
+		Success:   false,
+	}
+
+	parsedURL, err := url.Parse(step.URL)
+	if err != nil {
+		result.Error = CategoryInvalidURL
+		result.ErrorMessage = fmt.Sprintf("URL parse error: %v", err)
+		return result
+	}
+	ctx, span := tracing.Start(ctx, step.httpMethod())
+	span.Client = true
+	span.SetAttribute("url.full", step.URL)
+	defer func() {
+		if result.StatusCode != 0 {
+			span.SetAttribute("http.response.status_code", result.StatusCode)
+		}
+		if !result.Success {
+			span.SetError(result.ErrorMessage)
+		}
+		span.Finish()
+	}()
+
+	c.waitForRateLimit(ctx, parsedURL.Hostname())
+
+	reqCtx, cancel := context.WithTimeout(ctx, c.config.MaxLatency)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, step.httpMethod(), step.URL, strings.NewReader(step.Body))
+	if err != nil {
+		result.Error = CategoryRequestError
+		result.ErrorMessage = fmt.Sprintf("Request creation error: %v", err)
+		return result
+	}
+	req.Header.Set("User-Agent", "HealthCheck/1.0")
+	for name, value := range step.Headers {
+		req.Header.Set(name, value)
+	}
+	span.Inject(req.Header)
+
+	startTime := time.Now()
+	resp, err := client.Do(req)
+	result.ResponseTime = time.Since(startTime)
+	result.Latency = result.ResponseTime
+	if err != nil {
+		result.Error = classifyNetError(err)
+		result.ErrorMessage = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	if step.ExpectedStatus > 0 {
+		result.Success = resp.StatusCode == step.ExpectedStatus
+	} else {
+		result.Success = resp.StatusCode >= 200 && resp.StatusCode < 400
+	}
+	if !result.Success {
+		result.Error = statusCategory(resp.StatusCode)
+		result.ErrorMessage = fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		if step.ExpectedStatus > 0 {
+			result.ErrorMessage = fmt.Sprintf("expected HTTP %d, got %s", step.ExpectedStatus, resp.Status)
+		}
+	}
+	return result
+}
+
+// httpMethod ステップのHTTPメソッド（未指定の場合はGET）
+func (s Step) httpMethod() string {
+	if s.Method == "" {
+		return http.MethodGet
+	}
+	return s.Method
+}
@@ -1,17 +1,80 @@
 package checker
 
-import "time"
+import (
+	"time"
+
+	"healthcheck/internal/ipinfo"
+)
 
 // CheckResult 単一URLのチェック結果
 type CheckResult struct {
-	URL          string        `json:"url"`
-	StatusCode   int           `json:"status_code"`
-	ResponseTime time.Duration `json:"response_time_ms"`
-	Latency      time.Duration `json:"latency_ms"` // DNS解決から応答までの時間
-	Error        string        `json:"error,omitempty"`
-	ErrorMessage string        `json:"error_message,omitempty"`
-	Timestamp    time.Time     `json:"timestamp"`
-	Success      bool          `json:"success"`
+	URL          string         `json:"url"`
+	StatusCode   int            `json:"status_code"`
+	ResponseTime time.Duration  `json:"response_time_ms"`
+	Latency      time.Duration  `json:"latency_ms"` // DNS解決から応答までの時間
+	Error        ErrorCategory  `json:"error,omitempty"`
+	ErrorMessage string         `json:"error_message,omitempty"`
+	Timestamp    time.Time      `json:"timestamp"`
+	Success      bool           `json:"success"`
+	Duplicate    bool           `json:"duplicate,omitempty"` // 同じ対象の2回目以降の出現（duplicates: dedupeの場合は最初の出現の結果を割り当てたもの）
+	Steps        []*CheckResult `json:"steps,omitempty"`     // トランザクションチェックの各ステップの結果
+
+	Degraded        bool            `json:"degraded,omitempty"`         // 成功したが過去の基準値より統計的に遅い
+	DegradedMessage string          `json:"degraded_message,omitempty"` // 劣化と判定した理由
+	Hint            string          `json:"hint,omitempty"`             // 失敗時の補助プローブによる原因のヒント
+	Hops            []Hop           `json:"hops,omitempty"`             // ネットワークレベルの失敗時に調べた宛先までの経路
+	Phases          *Phases         `json:"phases,omitempty"`           // フェーズごとの所要時間
+	Acknowledgment  *Acknowledgment `json:"acknowledgment,omitempty"`   // 失敗を確認済みの場合の確認の内容
+	Flapping        bool            `json:"flapping,omitempty"`         // 失敗と成功を短い間隔で繰り返している（不安定）
+	DependencyDown  string          `json:"dependency_down,omitempty"`  // 依存先がダウンしているため個別に通知しなかった場合の依存先のURL
+
+	Browser   *BrowserMetrics `json:"browser,omitempty"`   // ブラウザでページを読み込んだ結果（type: browser）
+	Resources *ResourceScan   `json:"resources,omitempty"` // ページのサブリソースを確認した結果（scan_resources）
+
+	Connection       string       `json:"connection,omitempty"`        // 接続の方式（reuse / cold）
+	ConnectionReused bool         `json:"connection_reused,omitempty"` // 既存の接続を再利用した
+	RemoteAddr       string       `json:"remote_addr,omitempty"`       // 接続先のIPアドレスとポート
+	IPFamily         string       `json:"ip_family,omitempty"`         // 接続先のアドレスファミリー（ipv4 / ipv6）
+	ResolvedIPs      []string     `json:"resolved_ips,omitempty"`      // 名前解決で得たIPアドレス
+	IPInfo           *ipinfo.Info `json:"ip_info,omitempty"`           // 接続先のIPアドレスの逆引きと地域・AS番号（reverse_dns / geoip_databasesを指定した場合）
+	Protocol         string       `json:"protocol,omitempty"`          // 応答のHTTPバージョン（例: HTTP/2.0）
+	ALPN             string       `json:"alpn,omitempty"`              // TLSのALPNで合意したプロトコル（h2 / http/1.1 / none）
+	HTTP3Advertised  bool         `json:"http3_advertised,omitempty"`  // Alt-SvcヘッダーでHTTP/3が提供されている
+	HTTP3            *HTTP3Probe  `json:"http3,omitempty"`             // HTTP/3での接続を試した結果（http3を指定した場合）
+	Banner           string       `json:"banner,omitempty"`            // SSHのバージョン・FTPのウェルカムメッセージ（1行目）
+	ExpiresAt        *time.Time   `json:"expires_at,omitempty"`        // ドメインの有効期限（domain://）
+	ExpirySource     string       `json:"expiry_source,omitempty"`     // 有効期限を調べた方法（rdap / whois）
+
+	ContentLength   int64   `json:"content_length,omitempty"`   // Content-Lengthヘッダーの値（不明な場合は0）
+	BytesDownloaded int64   `json:"bytes_downloaded,omitempty"` // 受信した本文のバイト数
+	Throughput      float64 `json:"throughput_bps,omitempty"`   // 本文の受信速度（バイト/秒）
+	Truncated       bool    `json:"truncated,omitempty"`        // 本文が最大サイズを超えたため受信を打ち切った
+	ContentHash     string  `json:"content_hash,omitempty"`     // 内容の変化の検出で計算したハッシュ
+	Location        string  `json:"location,omitempty"`         // リダイレクト先を検証した場合の応答のLocation
+	ContentType     string  `json:"content_type,omitempty"`     // 応答のContent-Type
+	ContentEncoding string  `json:"content_encoding,omitempty"` // 応答のContent-Encoding（圧縮されていない場合は空）
+
+	Cache *CacheStatus `json:"cache,omitempty"` // CDN・キャッシュサーバーのキャッシュの状態（X-Cache・CF-Cache-Status・Ageなどを返した場合）
+
+	Snippet         string            `json:"snippet,omitempty"`          // 失敗時の本文の先頭（秘匿情報は伏せる）
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"` // 失敗時に記録したレスポンスヘッダー
+
+	Tags     map[string]string `json:"tags,omitempty"`     // 対象のタグ
+	Severity string            `json:"severity,omitempty"` // 対象の重大度（指定した場合のみ）
+	Region   string            `json:"region,omitempty"`   // チェックを実行した地域
+}
+
+// Acknowledgment 失敗している対象の確認（対応中であることを示し、復旧するまで繰り返しのアラートを止める）
+type Acknowledgment struct {
+	Note      string    `json:"note,omitempty"`   // 対応状況などのメモ
+	By        string    `json:"by,omitempty"`     // 確認したユーザーまたは接続元
+	Timestamp time.Time `json:"timestamp"`        // 確認した日時
+	Expires   time.Time `json:"expires,omitzero"` // 確認の期限（ゼロ値の場合は復旧するまで）
+}
+
+// Expired 確認の期限を過ぎたか
+func (a *Acknowledgment) Expired(now time.Time) bool {
+	return !a.Expires.IsZero() && !now.Before(a.Expires)
 }
 
 // ResponseTimeMs 応答時間をミリ秒で返す
@@ -0,0 +1,53 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// checkVirtualHosts sni_hostsのホスト名ごとに、同じ接続先へHost・SNIを変えてチェック
+// 接続先はresolve（省略時はURLのホスト名を解決した最初のアドレス）で、1台のサーバーが提供する各ドメインの証明書と応答を確かめる
+// 各ホスト名の結果をステップとして1つの結果にまとめ、いずれかが失敗した場合は失敗とする
+func (c *Checker) checkVirtualHosts(ctx context.Context, target config.Target) *CheckResult {
+	result := &CheckResult{
+		URL:       target.URL,
+		Timestamp: time.Now().UTC(),
+	}
+
+	parsedURL, err := url.Parse(target.URL)
+	if err != nil {
+		result.Error = CategoryInvalidURL
+		result.ErrorMessage = fmt.Sprintf("URL parse error: %v", err)
+		return result
+	}
+	address := target.Resolve
+	if address == "" {
+		addrs, err := net.DefaultResolver.LookupHost(ctx, parsedURL.Hostname())
+		if err != nil || len(addrs) == 0 {
+			result.Error = CategoryDNSFailure
+			result.ErrorMessage = fmt.Sprintf("Failed to resolve %s: %v", parsedURL.Hostname(), err)
+			return result
+		}
+		address = addrs[0]
+	}
+
+	result.Success = true
+	for _, host := range target.SNIHosts {
+		vhost := target
+		vhost.SNIHosts = nil
+		vhost.Resolve = address
+		u := *parsedURL
+		u.Host = host
+		if port := parsedURL.Port(); port != "" {
+			u.Host = net.JoinHostPort(host, port)
+		}
+		vhost.URL = u.String()
+		addStep(result, c.CheckHTTPWithRetry(ctx, vhost), host)
+	}
+	return result
+}
@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/discovery"
+	"healthcheck/internal/i18n"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/tracing"
+	"healthcheck/internal/urllist"
+)
+
+// 終了コード（CIで結果を判別できるよう、基準ごとに異なる値にする）
+const (
+	ExitOK          = 0 // すべての基準を満たした
+	ExitError       = 1 // 対象の指定の誤りなどでチェックできなかった
+	ExitSuccessRate = 3 // 成功率が基準を下回った
+	ExitP95         = 4 // 応答時間のp95が基準を超えた
+)
+
+// Options CLIでの実行の設定
+type Options struct {
+	URLs           []string          // チェックするURL（空の場合は設定ファイルの対象）
+	MinSuccessRate float64           // 成功率（%）がこれを下回ったら失敗（0の場合は判定しない）
+	MaxP95         time.Duration     // 応答時間のp95がこれを超えたら失敗（0の場合は判定しない）
+	Metadata       map[string]string // JSON形式の結果に含める実行の情報（triggered_by・deploy_sha・environmentなど）
+}
+
+// Run 対象を1回チェックして結果を表示し、基準の判定結果を終了コードとして返す
+// 複数の基準を満たさない場合は成功率の終了コードを優先する
+// JSON形式の場合は標準出力をJSONだけにするため、エラーと判定結果は標準エラー出力に表示する
+func Run(ctx context.Context, cfg *config.Config, opts Options, out io.Writer) int {
+	lang := Language(cfg)
+	messages := out
+	if cfg.OutputFormat == "json" {
+		messages = os.Stderr
+	}
+	targets, err := Targets(ctx, cfg, opts.URLs)
+	if err != nil {
+		fmt.Fprintln(messages, i18n.T(lang, "cli_error", err))
+		return ExitError
+	}
+
+	results, statistics := Check(ctx, cfg, targets)
+	if cfg.OutputFormat == "json" {
+		if err := PrintJSON(out, opts.Metadata, results, statistics); err != nil {
+			fmt.Fprintln(messages, i18n.T(lang, "cli_error", err))
+			return ExitError
+		}
+	} else {
+		PrintResults(out, lang, results, statistics, nil, useColor(cfg, out))
+	}
+	return Evaluate(messages, lang, statistics, opts)
+}
+
+// Language 端末に表示するメッセージの言語（設定ファイルのlanguage > 環境変数LANGなど > デフォルト言語）
+func Language(cfg *config.Config) string {
+	if cfg.Language != "" {
+		return cfg.Language
+	}
+	if lang := i18n.FromEnv(); lang != "" {
+		return lang
+	}
+	return i18n.DefaultLanguage
+}
+
+// Targets 引数のURLまたは設定ファイル（自動検出を含む）からチェックする対象を作成
+// ハートビートは受信を待つ対象のためCLIではチェックしない
+func Targets(ctx context.Context, cfg *config.Config, urls []string) ([]config.Target, error) {
+	var targets []config.Target
+	if len(urls) > 0 {
+		for _, raw := range urls {
+			u, err := urllist.Normalize(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", raw, err)
+			}
+			targets = append(targets, config.Target{Name: u, URL: u})
+		}
+		return targets, nil
+	}
+
+	discovery.NewManager(cfg).Refresh(ctx)
+	for _, t := range cfg.AllTargets() {
+		if t.Type == "heartbeat" || cfg.InMaintenance(t.URL, time.Now()) {
+			continue
+		}
+		targets = append(targets, t)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no targets: specify URLs as arguments or targets in the config file")
+	}
+	return targets, nil
+}
+
+// Check 対象をチェックして結果と統計情報を返す（結果は対象の順に並べる）
+func Check(ctx context.Context, cfg *config.Config, targets []config.Target) ([]*checker.CheckResult, *stats.Statistics) {
+	c := checker.NewChecker(cfg)
+
+	ctx, span := tracing.Start(ctx, "run")
+	defer span.Finish()
+	span.SetAttribute("healthcheck.trigger", "cli")
+
+	// sitemap:/robots:の対象を個別のページに展開
+	targets = c.ExpandTargets(ctx, targets)
+	resultChan := make(chan *checker.CheckResult, len(targets))
+	startTime := time.Now()
+	go c.CheckTargets(ctx, targets, resultChan, nil)
+
+	byURL := make(map[string][]*checker.CheckResult, len(targets))
+	for result := range resultChan {
+		byURL[result.URL] = append(byURL[result.URL], result)
+	}
+	results := make([]*checker.CheckResult, 0, len(targets))
+	for _, t := range targets {
+		if pending := byURL[t.URL]; len(pending) > 0 {
+			results = append(results, pending[0])
+			byURL[t.URL] = pending[1:]
+		}
+	}
+	return results, stats.CalculateStatistics(results, time.Since(startTime))
+}
+
+// PrintResults 結果を表形式で表示
+// previousを指定した場合は前回の結果からの応答時間の傾向（↑遅化 / ↓改善 / →横ばい）も表示する
+func PrintResults(out io.Writer, lang string, results []*checker.CheckResult, statistics *stats.Statistics, previous map[string]*checker.CheckResult, color bool) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	header := "STATUS\tCODE\tTIME\t\tURL\tERROR"
+	if color {
+		// 各行の色の指定と同じ長さにして列を揃える
+		header = colorDefault + header + colorReset
+	}
+	fmt.Fprintln(w, header)
+	for _, r := range results {
+		status, rowColor := "OK", colorGreen
+		if !r.Success {
+			status, rowColor = "NG", colorRed
+		}
+		code := "-"
+		if r.StatusCode != 0 {
+			code = fmt.Sprint(r.StatusCode)
+		}
+		line := fmt.Sprintf("%s\t%s\t%.0fms\t%s\t%s\t%s", status, code, r.ResponseTimeMs(), trend(r, previous[r.URL]), r.URL, r.ErrorMessage)
+		if color {
+			line = rowColor + line + colorReset
+		}
+		fmt.Fprintln(w, line)
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\n%s\n", i18n.T(lang, "cli_summary",
+		statistics.SuccessCount, statistics.TotalRequests, statistics.SuccessRate,
+		statistics.AvgResponseTimeMs(), float64(statistics.P95ResponseTime)/float64(time.Millisecond),
+		statistics.TotalDuration.Round(time.Millisecond)))
+	if h := statistics.ResponseTimeHistogram; h != nil && h.Count > 0 {
+		fmt.Fprintln(out, i18n.T(lang, "cli_histogram", formatHistogram(h)))
+	}
+}
+
+// formatHistogram ヒストグラムを「≤50ms:3 ≤100ms:5 ... >10s:0」の形式で表記
+func formatHistogram(h *stats.Histogram) string {
+	parts := make([]string, 0, len(h.Counts))
+	for i, bound := range h.Bounds {
+		parts = append(parts, fmt.Sprintf("≤%v:%d", bound, h.Counts[i]))
+	}
+	if len(h.Bounds) > 0 {
+		parts = append(parts, fmt.Sprintf(">%v:%d", h.Bounds[len(h.Bounds)-1], h.Counts[len(h.Counts)-1]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// PrintJSON 結果と統計情報を1行のJSONとして出力（履歴のファイルと同じ形式）
+// metadataを指定した場合は実行の情報として含める
+func PrintJSON(out io.Writer, metadata map[string]string, results []*checker.CheckResult, statistics *stats.Statistics) error {
+	data := map[string]interface{}{
+		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+		"results":    results,
+		"statistics": statistics,
+	}
+	if len(metadata) > 0 {
+		data["metadata"] = metadata
+	}
+	if err := json.NewEncoder(out).Encode(data); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
+
+// Evaluate 統計情報を基準と比較し、満たさない基準を表示して終了コードを返す
+func Evaluate(out io.Writer, lang string, statistics *stats.Statistics, opts Options) int {
+	code := ExitOK
+	if opts.MaxP95 > 0 && statistics.P95ResponseTime > opts.MaxP95 {
+		fmt.Fprintln(out, i18n.T(lang, "cli_p95_exceeded",
+			statistics.P95ResponseTime.Round(time.Millisecond), opts.MaxP95))
+		code = ExitP95
+	}
+	if opts.MinSuccessRate > 0 && statistics.SuccessRate < opts.MinSuccessRate {
+		fmt.Fprintln(out, i18n.T(lang, "cli_success_rate_below",
+			statistics.SuccessRate, opts.MinSuccessRate))
+		code = ExitSuccessRate
+	}
+	return code
+}
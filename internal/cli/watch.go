@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/i18n"
+)
+
+// 端末の表示の制御シーケンス
+const (
+	colorRed     = "\033[31m"
+	colorGreen   = "\033[32m"
+	colorDefault = "\033[39m"
+	colorReset   = "\033[0m"
+	clearScreen  = "\033[H\033[2J"
+)
+
+// trendThreshold 応答時間の変化を傾向として示す割合
+const trendThreshold = 0.1
+
+// Watch 間隔ごとに対象をチェックして端末の表を更新し続ける（ctxがキャンセルされるまで）
+// metadataはJSON形式の各行に含める実行の情報
+func Watch(ctx context.Context, cfg *config.Config, urls []string, metadata map[string]string, interval time.Duration, out io.Writer) int {
+	lang := Language(cfg)
+	color := useColor(cfg, out)
+	tty := isTerminal(out)
+	previous := make(map[string]*checker.CheckResult)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for run := 1; ; run++ {
+		// 自動検出の対象が変わることがあるため毎回対象を作り直す
+		targets, err := Targets(ctx, cfg, urls)
+		if err != nil {
+			fmt.Fprintln(out, i18n.T(lang, "cli_error", err))
+			return ExitError
+		}
+		results, statistics := Check(ctx, cfg, targets)
+		if ctx.Err() != nil {
+			return ExitOK
+		}
+
+		if cfg.OutputFormat == "json" {
+			// JSON形式では画面を書き換えず、1回ごとに1行を追記する
+			if err := PrintJSON(out, metadata, results, statistics); err != nil {
+				fmt.Fprintln(os.Stderr, i18n.T(lang, "cli_error", err))
+				return ExitError
+			}
+		} else {
+			var b strings.Builder
+			if tty {
+				b.WriteString(clearScreen)
+			}
+			fmt.Fprintf(&b, "%s\n\n", i18n.T(lang, "cli_watch_header", interval, run, i18n.FormatTime(time.Now())))
+			PrintResults(&b, lang, results, statistics, previous, color)
+			if !tty {
+				b.WriteString("\n")
+			}
+			io.WriteString(out, b.String())
+		}
+
+		previous = make(map[string]*checker.CheckResult, len(results))
+		for _, r := range results {
+			previous[r.URL] = r
+		}
+
+		select {
+		case <-ctx.Done():
+			return ExitOK
+		case <-ticker.C:
+		}
+	}
+}
+
+// trend 前回の結果からの応答時間の傾向（比較できない場合は空）
+func trend(current, previous *checker.CheckResult) string {
+	if previous == nil || !current.Success || !previous.Success || previous.ResponseTime <= 0 {
+		return ""
+	}
+	change := float64(current.ResponseTime-previous.ResponseTime) / float64(previous.ResponseTime)
+	switch {
+	case change > trendThreshold:
+		return "↑"
+	case change < -trendThreshold:
+		return "↓"
+	}
+	return "→"
+}
+
+// useColor 色付きで表示するか（設定のNoColor・環境変数NO_COLOR・出力先が端末かで判定）
+func useColor(cfg *config.Config, out io.Writer) bool {
+	if cfg.NoColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(out)
+}
+
+// isTerminal 出力先が端末か
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
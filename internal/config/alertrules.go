@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// validateAlertRules アラートのルールの条件・期間と、参照している通知先の名前を検証
+func validateAlertRules(rules []AlertRule, notifiers []NotifierConfig) error {
+	names := make(map[string]bool)
+	for _, n := range notifiers {
+		names[n.Name] = true
+	}
+
+	for i, rule := range rules {
+		label := fmt.Sprintf("alert rule %d", i+1)
+		if rule.Name != "" {
+			label = fmt.Sprintf("alert rule %q", rule.Name)
+		}
+
+		if len(rule.Notifiers) == 0 {
+			return fmt.Errorf("%s: notifiers is required", label)
+		}
+		for _, name := range append(append([]string(nil), rule.Notifiers...), rule.EscalateTo...) {
+			if !names[name] {
+				return fmt.Errorf("%s: unknown notifier %q", label, name)
+			}
+		}
+		for _, s := range rule.Severity {
+			if !slices.Contains(Severities, s) {
+				return fmt.Errorf("%s: invalid severity %q: must be one of %s", label, s, strings.Join(Severities, ", "))
+			}
+		}
+		for _, k := range rule.Kinds {
+			if !slices.Contains(AlertKinds, k) {
+				return fmt.Errorf("%s: invalid kind %q: must be one of %s", label, k, strings.Join(AlertKinds, ", "))
+			}
+		}
+
+		if rule.DedupeWindow != "" {
+			if d, err := time.ParseDuration(rule.DedupeWindow); err != nil || d < 0 {
+				return fmt.Errorf("%s: invalid dedupe_window %q: must be a non-negative duration", label, rule.DedupeWindow)
+			}
+		}
+		if rule.EscalateAfter != "" {
+			if d, err := time.ParseDuration(rule.EscalateAfter); err != nil || d <= 0 {
+				return fmt.Errorf("%s: invalid escalate_after %q: must be a positive duration", label, rule.EscalateAfter)
+			}
+		}
+		if (rule.EscalateAfter == "") != (len(rule.EscalateTo) == 0) {
+			return fmt.Errorf("%s: escalate_after and escalate_to must be specified together", label)
+		}
+	}
+	return nil
+}
+
+// Matches アラートの種類・重大度・タグがルールの条件に一致するか
+func (r AlertRule) Matches(kind, severity string, tags map[string]string) bool {
+	if len(r.Kinds) > 0 && !slices.Contains(r.Kinds, kind) {
+		return false
+	}
+	if len(r.Severity) > 0 && !slices.Contains(r.Severity, severity) {
+		return false
+	}
+	if len(r.Tags) > 0 && (len(tags) == 0 || !MatchTags(tags, r.Tags)) {
+		return false
+	}
+	return true
+}
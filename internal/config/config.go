@@ -1,31 +1,444 @@
 package config
 
-import "time"
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Config アプリケーションの設定を保持する構造体
 type Config struct {
-	Timeout      time.Duration // タイムアウト時間（デフォルト: 30秒）
-	Concurrency  int           // 並列度（デフォルト: 10）
-	Retries      int           // リトライ回数（デフォルト: 3）
-	MaxLatency   time.Duration // 最大レイテンシ（30秒）
-	DomainRate   int           // 同一ドメインごとのレート制限（リクエスト/秒）
-	GlobalRate   int           // 全体的なレート制限（リクエスト/秒）
-	NoColor      bool          // カラー出力を無効化
-	Verbose      bool          // 詳細ログを出力
-	Insecure     bool          // SSL証明書の検証をスキップ
+	Timeout     time.Duration // タイムアウト時間（デフォルト: 30秒）
+	Concurrency int           // 並列度（デフォルト: 10）
+	Retries     int           // リトライ回数（デフォルト: 3）
+	MaxLatency  time.Duration // 最大レイテンシ（30秒）
+	DomainRate  int           // 同一ドメインごとのレート制限（リクエスト/秒）
+	GlobalRate  int           // 全体的なレート制限（リクエスト/秒）
+	NoColor     bool          // カラー出力を無効化
+	Verbose     bool          // 詳細ログを出力
+	LogFormat   string        // ログの形式（text / json、デフォルト: text）
+	AuditLog    string        // API呼び出しの監査記録のファイル（空の場合は記録しない、デフォルト: audit.log）
+	Insecure    bool          // SSL証明書の検証をスキップ
+
+	DeniedNetworks []netip.Prefix // 接続しないネットワーク（チェックの対象とコールバックの送信先に適用、デフォルト: DefaultDeniedNetworks）
+
+	ResultsDir   string // 履歴を保存するディレクトリ（デフォルト: results）
+	OutputFormat string // コマンドラインでの結果の表示形式（OutputFormatsのいずれか、デフォルト: table）
+	Duplicates   string // 同じ対象が複数回指定された場合の扱い（DuplicateModesのいずれか、デフォルト: each）
+	Language     string // Web UIとコマンドラインの表示言語（ja / en、空の場合はブラウザ・環境変数から判定）
+
+	DisplayLocation *time.Location // 画面・通知・レポートで日時を表示するタイムゾーン（保存する日時はUTC、デフォルト: サーバーのローカルタイムゾーン）
+
+	DNSTimeout          time.Duration // 名前解決の期限（0の場合は接続の期限に含める）
+	ConnectTimeout      time.Duration // 名前解決を含むTCP接続の期限（デフォルト: 5秒）
+	TLSHandshakeTimeout time.Duration // TLSハンドシェイクの期限（デフォルト: 10秒）
+	HeaderTimeout       time.Duration // リクエストの送信からヘッダーの受信までの期限（0の場合は全体のタイムアウトのみ）
+	BodyTimeout         time.Duration // ヘッダーの受信から本文の受信完了までの期限（0の場合は全体のタイムアウトのみ）
+
+	MaxBodyBytes   int64    // レスポンスの本文を受信する最大サイズ（0の場合は本文を受信しない、デフォルト: 10MB）
+	SnippetBytes   int      // 失敗時に記録する本文の先頭のバイト数（0の場合は記録しない、デフォルト: 2048）
+	SnippetHeaders []string // 失敗時に記録するレスポンスヘッダー
+
+	MaxConcurrentRuns  int  // Webから同時に実行できるチェックの数（0の場合は無制限、デフォルト: 4）
+	MaxRunURLs         int  // Webからの1回のチェックで受け付ける最大URL数（0の場合は無制限、デフォルト: 1000）
+	ClientRate         int  // 接続元ごとのAPIリクエスト数の上限（リクエスト/分、0の場合は無制限、デフォルト: 60）
+	MaxBufferedResults int  // /api/checkで結果をメモリに保持する最大件数（超えた場合は一時ファイルに書き出す、0の場合は無制限、デフォルト: 10000）
+	DebugEndpoints     bool // /debug/pprof と /debug/vars で診断情報を公開する（adminの役割が必要、デフォルト: false）
+
+	Interval           time.Duration       // 定期チェックの間隔（0の場合は定期チェックを行わない）
+	PriorityInterval   time.Duration       // 優先度がhighの対象だけを定期チェックの間にチェックする間隔（0の場合は行わない）
+	Spread             bool                // 定期チェックの対象ごとの開始時刻を間隔の中に分散する（デフォルト: false）
+	Jitter             time.Duration       // 定期チェックの対象ごとに開始時刻を遅らせる最大のランダムな時間（0の場合は遅らせない）
+	Targets            []Target            // 定期チェックの対象
+	Templates          map[string]Target   // 対象のtemplateで名前を指定するひな形（組み込みのBuiltinTemplatesに追加・上書きする）
+	MaintenanceWindows []MaintenanceWindow // メンテナンス期間
+	HistoryLimit       int                 // 保持する履歴ファイル数（デフォルト: 10）
+	HistogramBuckets   []time.Duration     // 応答時間・レイテンシのヒストグラムの区間の上限（昇順、デフォルト: DefaultHistogramBuckets）
+	SLOTarget          float64             // 稼働率の目標値（%、デフォルト: 99.9）
+	AnomalySigma       float64             // 応答時間が基準値から何σ遅いと劣化とみなすか（デフォルト: 3）
+	AnomalyMinSamples  int                 // 劣化判定に必要な過去のサンプル数（デフォルト: 10）
+	Notifiers          []NotifierConfig    // アラートの通知先
+	AlertRules         []AlertRule         // アラートの振り分けのルール（空の場合はすべての通知先に送信）
+	AlertDedupeWindow  time.Duration       // 同じアラートを同じ通知先に再送しない時間（0の場合は抑止しない）
+	Digests            []DigestConfig      // 定期的に通知先へ送信する稼働レポート
+	Auth               []AuthConfig        // 対象のauthで名前を指定する認証の設定
+	Projects           []Project           // 対象・履歴・ダッシュボードを分けるプロジェクト
+	Users              []User              // Web UIとAPIを使えるユーザー（空の場合はログインせずにすべての操作ができる）
+	SessionTTL         time.Duration       // ログインの有効期間（デフォルト: 12時間）
+	ShareSecret        string              // 実行の共有リンクの署名に使う鍵（空の場合は起動ごとにランダムな鍵を使い、再起動すると共有リンクは無効になる）
+	Theme              Theme               // Web UIの表示モードとブランドの設定
+	Hooks              []Hook              // CIのデプロイフックなどから/api/hooks/{name}で実行するチェック
+
+	CorrelationWindow     time.Duration // 同時に失敗したとみなす時間幅（デフォルト: 2分）
+	CorrelationMinTargets int           // 相関イベントとしてまとめる最小の対象数（デフォルト: 2）
+	RegressionThreshold   float64       // 前回からの応答時間の変化として報告する閾値（%、デフォルト: 50）
+	FlapWindow            int           // 不安定（フラッピング）の判定に使う直近のチェック回数（0の場合は判定しない、デフォルト: 0）
+	FlapHighThreshold     float64       // 状態の変化率がこの値以上になったら不安定とみなす（%、デフォルト: 50）
+	FlapLowThreshold      float64       // 不安定な対象の状態の変化率がこの値を下回ったら安定したとみなす（%、デフォルト: 25）
+	RootCauseHints        bool          // 失敗時にDNS・TCP・TLSの補助プローブで原因を調べる（デフォルト: true）
+	Traceroute            bool          // ネットワークレベルの失敗時に宛先までの経路を調べる（デフォルト: false）
+	TracerouteMaxHops     int           // 経路を調べる最大ホップ数（デフォルト: 20）
+	ReverseDNS            bool          // 接続先のIPアドレスを逆引きする（デフォルト: false）
+	GeoIPDatabases        []string      // 接続先の地域・AS番号を調べるMaxMind DB形式のファイル（空の場合は調べない）
+	BrowserPath           string        // type: browserの対象で起動するChrome/Chromiumのパス（空の場合はPATHから探す）
+
+	SitemapMaxURLs int      // sitemap:/robots:の対象から展開する最大URL数（デフォルト: 100）
+	SitemapInclude []string // 展開したURLのうち対象にするパターン（正規表現、空の場合はすべて）
+	SitemapExclude []string // 展開したURLのうち除外するパターン（正規表現）
+
+	OTLPEndpoint string            // トレースを送信するOTLP/HTTPのURL（空の場合は送信しない）
+	OTLPHeaders  map[string]string // OTLPの送信時に付与するヘッダー（認証など）
+	ServiceName  string            // トレースのサービス名（デフォルト: healthcheck）
+
+	Region           string            // このインスタンスの地域名（結果に記録する）
+	Coordinator      string            // 結果を送信するコーディネーターのURL（エージェントとして動作する場合）
+	CoordinatorToken string            // コーディネーターへの送信に使うトークン
+	Agents           map[string]string // コーディネーターとして受け付けるエージェントの地域名とトークン
+
+	Discovery         []DiscoveryConfig // 対象の自動検出の設定
+	DiscoveryInterval time.Duration     // 自動検出の更新間隔（デフォルト: 1分）
+
+	discovered *discoveredTargets // 検出済みの対象（Cloneで複製した設定と共有する）
+}
+
+// DiscoveryConfig 対象の自動検出の設定
+type DiscoveryConfig struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`                 // consul / dns_srv / kubernetes
+	Address    string `json:"address,omitempty"`    // ConsulまたはKubernetesのAPIのアドレス
+	Service    string `json:"service,omitempty"`    // Consulのサービス名
+	Tag        string `json:"tag,omitempty"`        // Consulのタグで絞り込み
+	Datacenter string `json:"datacenter,omitempty"` // Consulのデータセンター
+	Token      string `json:"token,omitempty"`      // ConsulのACLトークンまたはKubernetesのBearerトークン
+	Record     string `json:"record,omitempty"`     // SRVレコード名（例: _http._tcp.example.com）
+	Scheme     string `json:"scheme,omitempty"`     // 生成するURLのスキーム（デフォルト: http）
+	Path       string `json:"path,omitempty"`       // 生成するURLのパス（デフォルト: /）
+	Group      string `json:"group,omitempty"`      // ステータスページでのグループ名
+
+	Tags map[string]string `json:"tags,omitempty"` // 検出した対象に付与するタグ
+
+	Resource      string `json:"resource,omitempty"`       // Kubernetesの検出するリソース（ingress / service、デフォルト: ingress）
+	Namespace     string `json:"namespace,omitempty"`      // Kubernetesの名前空間（空の場合は全名前空間）
+	LabelSelector string `json:"label_selector,omitempty"` // Kubernetesのラベルセレクター
+	Annotation    string `json:"annotation,omitempty"`     // 値が"true"のリソースのみ対象にするアノテーション
+	CAFile        string `json:"ca_file,omitempty"`        // KubernetesのAPIサーバーのCA証明書
+}
+
+// NotifierConfig アラートの通知先の設定
+type NotifierConfig struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`               // webhook / slack / email / pagerduty / opsgenie / alertmanager
+	Language string   `json:"language,omitempty"` // 通知文の言語（ja / en、デフォルト: ja）
+	URL      string   `json:"url,omitempty"`
+	SMTPAddr string   `json:"smtp_addr,omitempty"` // host:port
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+
+	RoutingKey string `json:"routing_key,omitempty"` // PagerDutyのEvents API v2のインテグレーションキー
+	APIKey     string `json:"api_key,omitempty"`     // OpsgenieのAPIキー
+
+	Tags map[string]string `json:"tags,omitempty"` // 指定した場合はタグがすべて一致する対象のアラートのみ通知
+}
+
+// AlertRule アラートの通知先を決めるルール（上から順に評価し、最初に一致したルールの通知先に送信する）
+// 条件を省略した項目はすべてに一致する
+type AlertRule struct {
+	Name     string            `json:"name"`
+	Tags     map[string]string `json:"tags,omitempty"`     // 対象のタグの条件（すべて一致する場合）
+	Severity []string          `json:"severity,omitempty"` // 重大度の条件（Severitiesのいずれか）
+	Kinds    []string          `json:"kinds,omitempty"`    // アラートの種類の条件（AlertKindsのいずれか）
+
+	Notifiers     []string `json:"notifiers"`                // 送信する通知先の名前
+	DedupeWindow  string   `json:"dedupe_window,omitempty"`  // 同じアラートを再送しない時間（省略時はalert_dedupe_window）
+	EscalateAfter string   `json:"escalate_after,omitempty"` // ダウンが解決しないまま経過したらエスカレーションする時間
+	EscalateTo    []string `json:"escalate_to,omitempty"`    // エスカレーション先の通知先の名前
+}
+
+// DigestConfig 定期的に送信する稼働レポートの設定
+type DigestConfig struct {
+	Name      string   `json:"name"`
+	Period    string   `json:"period"`              // daily / weekly
+	At        string   `json:"at,omitempty"`        // 送信する時刻（HH:MM、ローカル時刻、デフォルト: 09:00）
+	Weekday   string   `json:"weekday,omitempty"`   // weeklyで送信する曜日（例: monday、デフォルト: monday）
+	Notifiers []string `json:"notifiers,omitempty"` // 送信する通知先の名前（空の場合はすべての通知先）
+	Slowest   int      `json:"slowest,omitempty"`   // 応答時間の遅い対象を何件含めるか（デフォルト: 5）
+}
+
+// Project 対象・履歴・APIのトークン・ダッシュボードを他のチームと分けて運用する単位
+// プロジェクトのページとAPIは/p/{name}/以下で提供し、設定ファイルの対象とは別に定期チェックする
+type Project struct {
+	Name      string   `json:"name"`                // URLと履歴のディレクトリに使う識別子
+	Title     string   `json:"title,omitempty"`     // ダッシュボードに表示する名前（省略時はname）
+	Targets   []Target `json:"targets"`             // プロジェクトの定期チェックの対象
+	Tokens    []string `json:"tokens,omitempty"`    // ページとAPIへのアクセスに必要なトークン（空の場合は認証しない）
+	Notifiers []string `json:"notifiers,omitempty"` // アラートを送信する通知先の名前（空の場合は通知しない）
+}
+
+// Hook CIのデプロイフックなどから/api/hooks/{name}で実行する、あらかじめ決めた対象のチェック
+type Hook struct {
+	Name        string            `json:"name"`                   // URLに使う識別子
+	Secret      string            `json:"secret"`                 // 呼び出しに必要な共有シークレット
+	Targets     []Target          `json:"targets,omitempty"`      // チェックする対象
+	Tags        map[string]string `json:"tags,omitempty"`         // 指定した場合はタグがすべて一致する設定ファイルの対象もチェックする
+	CallbackURL string            `json:"callback_url,omitempty"` // 指定した場合は応答を待たずに受け付け、結果をこのURLにPOSTする
+}
+
+// User Web UIとAPIを使えるユーザー
+type User struct {
+	Name         string `json:"name"`
+	PasswordHash string `json:"password_hash"` // -hash-passwordで生成したパスワードのハッシュ
+	Role         string `json:"role"`          // viewer / editor / admin
+}
+
+// Theme Web UIの表示モードとブランドの設定
+type Theme struct {
+	Mode        string `json:"mode,omitempty"`         // 表示モード（ThemeModesのいずれか、空の場合はOSの設定に合わせる）
+	AccentColor string `json:"accent_color,omitempty"` // ヘッダーとボタンの色（#rgb / #rrggbb、空の場合は標準の紫）
+	LogoURL     string `json:"logo_url,omitempty"`     // ヘッダーに表示するロゴ画像のURL
+	Title       string `json:"title,omitempty"`        // ヘッダーに表示するタイトル
+}
+
+// ThemeModes テーマのmodeに指定できる表示モード
+var ThemeModes = []string{"light", "dark"}
+
+// Priorities 対象のpriorityに指定できる優先度（high / normal（デフォルト））
+// highの対象は実行の中で先にチェックし、priority_intervalを設定した場合は定期チェックの間にもチェックする
+var Priorities = []string{"high", "normal"}
+
+// Severities 対象のseverityに指定できる重大度（critical（デフォルト）/ warning / info）
+var Severities = []string{"critical", "warning", "info"}
+
+// AlertKinds アラートのルールのkindsに指定できるアラートの種類（復旧は元のアラートの通知先に送信する）
+var AlertKinds = []string{"down", "degraded", "regression", "flapping"}
+
+// AuthConfig 保護された対象のアクセストークンを取得する認証の設定（OAuth2）
+type AuthConfig struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"` // oauth2
+	TokenURL     string   `json:"token_url"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	Audience     string   `json:"audience,omitempty"`      // audienceパラメーター（一部のプロバイダーで必要）
+	RefreshToken string   `json:"refresh_token,omitempty"` // 指定した場合はrefresh_tokenのグラントで取得する
+}
+
+// Target 定期チェックの対象
+type Target struct {
+	Name    string            `json:"name"`
+	URL     string            `json:"url"`
+	Service string            `json:"service,omitempty"` // ステータスページでのグループ名
+	Tags    map[string]string `json:"tags,omitempty"`    // 任意のタグ（例: team=payments, env=prod）
+
+	Severity string `json:"severity,omitempty"` // ダウンのアラートの重大度（Severitiesのいずれか、デフォルト: critical）
+	Priority string `json:"priority,omitempty"` // チェックの優先度（Prioritiesのいずれか、デフォルト: normal）
+
+	DependsOn []string `json:"depends_on,omitempty"` // 依存する対象の名前またはURL（依存先がダウンしている間はこの対象のダウンを個別に通知しない）
+	Template  string   `json:"template,omitempty"`   // 指定していない項目を補うひな形の名前（設定ファイルのtemplatesまたはBuiltinTemplates）
+
+	Type    string   `json:"type,omitempty"`    // http（デフォルト）/ exec / heartbeat / browser
+	Command []string `json:"command,omitempty"` // execで実行するコマンドと引数
+
+	Password string `json:"password,omitempty"` // ftp://user@hostでログインするパスワード（URLには含めない）
+	Timeout  string `json:"timeout,omitempty"`  // http・exec・browserのタイムアウト（省略時は全体のタイムアウト）
+
+	ExpiryWarnDays int `json:"expiry_warn_days,omitempty"` // domain://で失敗とする有効期限までの残り日数（デフォルト: DefaultExpiryWarnDays）
+
+	IgnoreResources []string `json:"ignore_resources,omitempty"` // browserで失敗として扱わないサブリソースのURL・コンソールのメッセージ（正規表現）
+
+	HeaderTimeout string `json:"header_timeout,omitempty"` // リクエストの送信からヘッダーの受信までの期限（省略時は全体の設定）
+	BodyTimeout   string `json:"body_timeout,omitempty"`   // ヘッダーの受信から本文の受信完了までの期限（省略時は全体の設定）
+
+	Method         string            `json:"method,omitempty"`          // httpのメソッド（デフォルト: GET）
+	Headers        map[string]string `json:"headers,omitempty"`         // httpのリクエストヘッダー
+	Auth           string            `json:"auth,omitempty"`            // 使用する認証の設定の名前（Bearerトークンを付与する）
+	Body           string            `json:"body,omitempty"`            // httpのリクエストボディ
+	ExpectedStatus int               `json:"expected_status,omitempty"` // 成功とみなすステータスコード（0の場合は2xx）
+	Success        string            `json:"success,omitempty"`         // 成功条件の式（指定した場合はexpected_statusより優先）
+	Connection     string            `json:"connection,omitempty"`      // reuse（デフォルト、接続を再利用）/ cold（毎回新しい接続）
+	IPFamily       string            `json:"ip_family,omitempty"`       // 接続に使うアドレスファミリー（IPFamiliesのいずれか）
+	Resolve        string            `json:"resolve,omitempty"`         // 名前解決の代わりに接続するIPアドレス（ポートも指定可、Host・SNIはURLのまま）
+	Origin         string            `json:"origin,omitempty"`          // CDNの背後のオリジンのIPアドレス（ポートも指定可、失敗時に直接チェックしてCDN経由の結果と比べる）
+	SNIHosts       []string          `json:"sni_hosts,omitempty"`       // 同じ接続先に対してHost・SNIを変えてチェックするホスト名（バーチャルホストごとの証明書と応答）
+
+	ExpectedLocation        string `json:"expected_location,omitempty"`         // リダイレクト先として期待するURL（指定した場合はリダイレクトをたどらない）
+	ExpectedLocationPattern string `json:"expected_location_pattern,omitempty"` // リダイレクト先として期待するURLの正規表現
+
+	ExpectedContentType string   `json:"expected_content_type,omitempty"` // 期待するメディアタイプ（例: application/json、text/*）
+	ExpectedCharset     string   `json:"expected_charset,omitempty"`      // 期待するContent-Typeのcharset（例: utf-8）
+	ExpectedEncoding    []string `json:"expected_encoding,omitempty"`     // 許容するContent-Encoding（例: ["gzip", "br"]）
+	ExpectedCache       string   `json:"expected_cache,omitempty"`        // 期待するCDNのキャッシュの状態（CacheExpectationsのいずれか）
+
+	WatchContent    bool   `json:"watch_content,omitempty"`    // 内容のハッシュが基準から変化した場合に失敗とする
+	ContentSelector string `json:"content_selector,omitempty"` // ハッシュを計算する範囲のCSSセレクター（省略時は本文全体）
+
+	ScanResources bool `json:"scan_resources,omitempty"` // HTMLが読み込むスクリプト・CSS・画像を取得し、読み込めないもの・HTTPで読み込むものがあれば失敗とする
+
+	HTTP3 bool `json:"http3,omitempty"` // HTTPSの対象でHTTP/3（QUIC）での接続も試して結果を記録する（成否はチェックの結果に影響しない）
+
+	Period string `json:"period,omitempty"` // heartbeatの受信を期待する間隔
+	Grace  string `json:"grace,omitempty"`  // heartbeatの遅延を許容する時間（デフォルト: 0）
+	Token  string `json:"token,omitempty"`  // heartbeatの受信URLのトークン（省略時は自動生成）
+}
+
+// HighPriority 優先度の高い対象か
+func (t Target) HighPriority() bool {
+	return t.Priority == "high"
+}
+
+// ExpectsRedirect リダイレクト先を検証する対象か
+func (t Target) ExpectsRedirect() bool {
+	return t.ExpectedLocation != "" || t.ExpectedLocationPattern != ""
+}
+
+// DefaultExpiryWarnDays domain://の対象で有効期限が近いとみなす残り日数の既定値
+const DefaultExpiryWarnDays = 30
+
+// DefaultHistogramBuckets 応答時間・レイテンシのヒストグラムの区間の上限の既定値
+var DefaultHistogramBuckets = []time.Duration{
+	50 * time.Millisecond, 100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+// DefaultDeniedNetworks 接続しないネットワークの既定値（クラウドのメタデータサービスなどがあるリンクローカルアドレス）
+var DefaultDeniedNetworks = []netip.Prefix{
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("fe80::/10"),
+}
+
+// maxSnippetBytes 失敗時に記録する本文の上限（結果の保存サイズを抑えるため）
+const maxSnippetBytes = 64 << 10
+
+// IPFamilies 対象のip_familyに指定できる値
+// any（デフォルト）/ ipv4 / ipv6 / dual（IPv4とIPv6を個別にチェック）/ each（解決したIPアドレスごとにチェック）
+var IPFamilies = []string{"any", "ipv4", "ipv6", "dual", "each"}
+
+// CacheExpectations 対象のexpected_cacheに指定できる値
+// hit（キャッシュから応答する、外れた場合は1回だけ再試行してキャッシュが温まった後の応答で判定）/ miss（キャッシュから応答しない）
+var CacheExpectations = []string{"hit", "miss"}
+
+// DuplicateModes 同じ対象が複数回指定された場合の扱い
+// each（出現ごとにチェック）/ dedupe（1回だけチェックし、同じ結果をすべての出現に割り当てる）
+var DuplicateModes = []string{"each", "dedupe"}
+
+// OutputFormats コマンドラインでの結果の表示形式
+// table（表形式）/ json（1回のチェックごとに1行のJSON）
+var OutputFormats = []string{"table", "json"}
+
+// ValidateResolve 対象のresolveの形式（IPアドレス、またはIPアドレスとポート）を検証
+func ValidateResolve(resolve string) error {
+	host, port, err := net.SplitHostPort(resolve)
+	if err != nil {
+		host, port = resolve, ""
+	}
+	if net.ParseIP(host) == nil {
+		return fmt.Errorf("%q is not an IP address", host)
+	}
+	if port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("invalid port %q", port)
+		}
+	}
+	return nil
+}
+
+// ValidateSNIHosts 対象のsni_hosts（ポートを含まないホスト名）を検証
+// ホスト名ごとのチェックは1つの接続先に対して行うため、ip_familyのdual・eachとは併用できない
+func ValidateSNIHosts(hosts []string, ipFamily string) error {
+	if len(hosts) > 0 && (ipFamily == "dual" || ipFamily == "each") {
+		return fmt.Errorf("cannot be combined with ip_family %q", ipFamily)
+	}
+	for _, host := range hosts {
+		if host == "" || strings.ContainsAny(host, ":/@?# \t") {
+			return fmt.Errorf("%q is not a host name", host)
+		}
+	}
+	return nil
+}
+
+// MaintenanceWindow メンテナンス期間（期間中の対象はチェックしない）
+type MaintenanceWindow struct {
+	Name    string    `json:"name"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Targets []string  `json:"targets,omitempty"` // 対象のURL（空の場合は全対象）
+}
+
+// Covers 指定時刻に対象URLがメンテナンス期間に含まれるか
+func (m MaintenanceWindow) Covers(targetURL string, at time.Time) bool {
+	if at.Before(m.Start) || !at.Before(m.End) {
+		return false
+	}
+	if len(m.Targets) == 0 {
+		return true
+	}
+	for _, t := range m.Targets {
+		if t == targetURL {
+			return true
+		}
+	}
+	return false
 }
 
 // DefaultConfig デフォルト設定を返す
 func DefaultConfig() *Config {
 	return &Config{
-		Timeout:     30 * time.Second,
-		Concurrency: 10,
-		Retries:     3,
-		MaxLatency:  30 * time.Second,
-		DomainRate:  5,  // 1秒間に最大5リクエスト
-		GlobalRate:  50, // 1秒間に最大50リクエスト
-		NoColor:     false,
-		Verbose:     false,
-		Insecure:    false,
+		Timeout:               30 * time.Second,
+		Concurrency:           10,
+		Retries:               3,
+		MaxLatency:            30 * time.Second,
+		ConnectTimeout:        5 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		DomainRate:            5,  // 1秒間に最大5リクエスト
+		GlobalRate:            50, // 1秒間に最大50リクエスト
+		NoColor:               false,
+		Verbose:               false,
+		Insecure:              false,
+		ResultsDir:            "results",
+		OutputFormat:          "table",
+		Duplicates:            "each",
+		DisplayLocation:       time.Local,
+		AuditLog:              "audit.log",
+		MaxBodyBytes:          10 << 20,
+		SnippetBytes:          2048,
+		SnippetHeaders:        []string{"Content-Type", "Server", "Location", "Retry-After", "Cache-Control", "Via", "X-Cache", "X-Request-Id", "WWW-Authenticate"},
+		MaxConcurrentRuns:     4,
+		MaxRunURLs:            1000,
+		MaxBufferedResults:    10000,
+		ClientRate:            60,
+		HistoryLimit:          10,
+		HistogramBuckets:      slices.Clone(DefaultHistogramBuckets),
+		DeniedNetworks:        slices.Clone(DefaultDeniedNetworks),
+		SLOTarget:             99.9,
+		AnomalySigma:          3,
+		AnomalyMinSamples:     10,
+		CorrelationWindow:     2 * time.Minute,
+		CorrelationMinTargets: 2,
+		RegressionThreshold:   50,
+		FlapHighThreshold:     50,
+		FlapLowThreshold:      25,
+		RootCauseHints:        true,
+		TracerouteMaxHops:     20,
+		SitemapMaxURLs:        100,
+		ServiceName:           "healthcheck",
+		DiscoveryInterval:     time.Minute,
+		SessionTTL:            12 * time.Hour,
+		discovered:            &discoveredTargets{sources: make(map[string][]Target)},
+	}
+}
+
+// InMaintenance 指定時刻に対象URLがいずれかのメンテナンス期間中か
+func (c *Config) InMaintenance(targetURL string, at time.Time) bool {
+	for _, m := range c.MaintenanceWindows {
+		if m.Covers(targetURL, at) {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// weekdays digestsのweekdayに指定できる曜日
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// validateDigests 稼働レポートの設定を検証し、省略された項目にデフォルト値を設定
+func validateDigests(digests []DigestConfig, notifiers []NotifierConfig) error {
+	names := make(map[string]bool)
+	for _, n := range notifiers {
+		names[n.Name] = true
+	}
+
+	for i := range digests {
+		d := &digests[i]
+		if d.Period != "daily" && d.Period != "weekly" {
+			return fmt.Errorf("digest %d: invalid period %q: must be daily or weekly", i+1, d.Period)
+		}
+		if d.Name == "" {
+			d.Name = d.Period
+		}
+		if d.At == "" {
+			d.At = "09:00"
+		}
+		if _, err := time.Parse("15:04", d.At); err != nil {
+			return fmt.Errorf("digest %q: invalid at %q: must be HH:MM", d.Name, d.At)
+		}
+		if d.Weekday == "" {
+			d.Weekday = "monday"
+		}
+		d.Weekday = strings.ToLower(d.Weekday)
+		if _, ok := weekdays[d.Weekday]; !ok {
+			return fmt.Errorf("digest %q: invalid weekday %q", d.Name, d.Weekday)
+		}
+		if d.Slowest < 0 {
+			return fmt.Errorf("digest %q: invalid slowest %d: must not be negative", d.Name, d.Slowest)
+		}
+		if d.Slowest == 0 {
+			d.Slowest = 5
+		}
+		for _, name := range d.Notifiers {
+			if !names[name] {
+				return fmt.Errorf("digest %q: unknown notifier %q", d.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// Window レポートの集計期間（dailyは1日、weeklyは7日）
+func (d DigestConfig) Window() time.Duration {
+	if d.Period == "weekly" {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// LastDue 指定時刻以前で最後に送信する予定だった日時
+func (d DigestConfig) LastDue(now time.Time) time.Time {
+	at, err := time.Parse("15:04", d.At)
+	if err != nil {
+		at, _ = time.Parse("15:04", "09:00")
+	}
+	due := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, now.Location())
+	if due.After(now) {
+		due = due.AddDate(0, 0, -1)
+	}
+	if d.Period == "weekly" {
+		weekday, ok := weekdays[d.Weekday]
+		if !ok {
+			weekday = time.Monday
+		}
+		for due.Weekday() != weekday {
+			due = due.AddDate(0, 0, -1)
+		}
+	}
+	return due
+}
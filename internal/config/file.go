@@ -0,0 +1,721 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/netip"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"healthcheck/internal/i18n"
+	"healthcheck/internal/ipinfo"
+)
+
+// fileConfig 設定ファイル（JSON）の構造
+// 時間はtime.ParseDurationの形式（例: "30s", "5m"）で指定する
+type fileConfig struct {
+	Timeout               string              `json:"timeout"`
+	DNSTimeout            string              `json:"dns_timeout"`
+	ConnectTimeout        string              `json:"connect_timeout"`
+	TLSHandshakeTimeout   string              `json:"tls_handshake_timeout"`
+	HeaderTimeout         string              `json:"header_timeout"`
+	BodyTimeout           string              `json:"body_timeout"`
+	Concurrency           int                 `json:"concurrency"`
+	Retries               *int                `json:"retries"`
+	DomainRate            int                 `json:"domain_rate"`
+	GlobalRate            int                 `json:"global_rate"`
+	Insecure              bool                `json:"insecure"`
+	DeniedNetworks        []string            `json:"denied_networks"`
+	ResultsDir            string              `json:"results_dir"`
+	OutputFormat          string              `json:"output_format"`
+	Duplicates            string              `json:"duplicates"`
+	Language              string              `json:"language"`
+	DisplayTimezone       string              `json:"display_timezone"`
+	Verbose               bool                `json:"verbose"`
+	NoColor               bool                `json:"no_color"`
+	LogFormat             string              `json:"log_format"`
+	AuditLog              *string             `json:"audit_log"`
+	MaxConcurrentRuns     *int                `json:"max_concurrent_runs"`
+	MaxRunURLs            *int                `json:"max_run_urls"`
+	ClientRate            *int                `json:"client_rate"`
+	MaxBufferedResults    *int                `json:"max_buffered_results"`
+	DebugEndpoints        bool                `json:"debug_endpoints"`
+	MaxBodyBytes          *int64              `json:"max_body_bytes"`
+	SnippetBytes          *int                `json:"snippet_bytes"`
+	SnippetHeaders        []string            `json:"snippet_headers"`
+	Interval              string              `json:"interval"`
+	PriorityInterval      string              `json:"priority_interval"`
+	Spread                bool                `json:"spread"`
+	Jitter                string              `json:"jitter"`
+	Targets               []Target            `json:"targets"`
+	Templates             map[string]Target   `json:"templates"`
+	MaintenanceWindows    []MaintenanceWindow `json:"maintenance_windows"`
+	HistoryLimit          int                 `json:"history_limit"`
+	HistogramBuckets      []string            `json:"histogram_buckets"`
+	SLOTarget             float64             `json:"slo_target"`
+	AnomalySigma          float64             `json:"anomaly_sigma"`
+	AnomalyMinSamples     int                 `json:"anomaly_min_samples"`
+	Notifiers             []NotifierConfig    `json:"notifiers"`
+	AlertRules            []AlertRule         `json:"alert_rules"`
+	AlertDedupeWindow     string              `json:"alert_dedupe_window"`
+	Digests               []DigestConfig      `json:"digests"`
+	Auth                  []AuthConfig        `json:"auth"`
+	Projects              []Project           `json:"projects"`
+	Users                 []User              `json:"users"`
+	SessionTTL            string              `json:"session_ttl"`
+	ShareSecret           string              `json:"share_secret"`
+	Theme                 Theme               `json:"theme"`
+	Hooks                 []Hook              `json:"hooks"`
+	CorrelationWindow     string              `json:"correlation_window"`
+	CorrelationMinTargets int                 `json:"correlation_min_targets"`
+	RegressionThreshold   float64             `json:"regression_threshold"`
+	FlapWindow            *int                `json:"flap_window"`
+	FlapHighThreshold     float64             `json:"flap_high_threshold"`
+	FlapLowThreshold      float64             `json:"flap_low_threshold"`
+	RootCauseHints        *bool               `json:"root_cause_hints"`
+	Traceroute            bool                `json:"traceroute"`
+	TracerouteMaxHops     int                 `json:"traceroute_max_hops"`
+	ReverseDNS            bool                `json:"reverse_dns"`
+	GeoIPDatabases        []string            `json:"geoip_databases"`
+	BrowserPath           string              `json:"browser_path"`
+	OTLPEndpoint          string              `json:"otlp_endpoint"`
+	OTLPHeaders           map[string]string   `json:"otlp_headers"`
+	ServiceName           string              `json:"service_name"`
+	SitemapMaxURLs        int                 `json:"sitemap_max_urls"`
+	SitemapInclude        []string            `json:"sitemap_include"`
+	SitemapExclude        []string            `json:"sitemap_exclude"`
+	Discovery             []DiscoveryConfig   `json:"discovery"`
+	DiscoveryInterval     string              `json:"discovery_interval"`
+	Region                string              `json:"region"`
+	Coordinator           string              `json:"coordinator"`
+	CoordinatorToken      string              `json:"coordinator_token"`
+	Agents                map[string]string   `json:"agents"`
+}
+
+// regionPattern 地域名に使用できる文字（履歴のファイル名にも使う）
+var regionPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Load 設定ファイルを読み込み、デフォルト設定に上書きして返す
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var fc fileConfig
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	cfg := DefaultConfig()
+	if fc.Timeout != "" {
+		d, err := time.ParseDuration(fc.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout %q: %w", fc.Timeout, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q: must be positive", fc.Timeout)
+		}
+		cfg.Timeout = d
+		cfg.MaxLatency = d
+	}
+	for _, t := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"dns_timeout", fc.DNSTimeout, &cfg.DNSTimeout},
+		{"connect_timeout", fc.ConnectTimeout, &cfg.ConnectTimeout},
+		{"tls_handshake_timeout", fc.TLSHandshakeTimeout, &cfg.TLSHandshakeTimeout},
+		{"header_timeout", fc.HeaderTimeout, &cfg.HeaderTimeout},
+		{"body_timeout", fc.BodyTimeout, &cfg.BodyTimeout},
+		{"alert_dedupe_window", fc.AlertDedupeWindow, &cfg.AlertDedupeWindow},
+		{"session_ttl", fc.SessionTTL, &cfg.SessionTTL},
+		{"jitter", fc.Jitter, &cfg.Jitter},
+	} {
+		if t.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(t.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", t.name, t.value, err)
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("invalid %s %q: must not be negative", t.name, t.value)
+		}
+		*t.dest = d
+	}
+	if fc.Interval != "" {
+		d, err := time.ParseDuration(fc.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval %q: %w", fc.Interval, err)
+		}
+		cfg.Interval = d
+	}
+	if fc.PriorityInterval != "" {
+		d, err := time.ParseDuration(fc.PriorityInterval)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid priority_interval %q: must be a positive duration", fc.PriorityInterval)
+		}
+		cfg.PriorityInterval = d
+	}
+	cfg.Spread = fc.Spread
+	if cfg.Interval > 0 && cfg.Jitter >= cfg.Interval {
+		return nil, fmt.Errorf("invalid jitter %q: must be shorter than interval", fc.Jitter)
+	}
+	if fc.CorrelationWindow != "" {
+		d, err := time.ParseDuration(fc.CorrelationWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid correlation_window %q: %w", fc.CorrelationWindow, err)
+		}
+		cfg.CorrelationWindow = d
+	}
+	if fc.DiscoveryInterval != "" {
+		d, err := time.ParseDuration(fc.DiscoveryInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid discovery_interval %q: %w", fc.DiscoveryInterval, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid discovery_interval %q: must be a positive duration", fc.DiscoveryInterval)
+		}
+		cfg.DiscoveryInterval = d
+	}
+	if fc.CorrelationMinTargets > 0 {
+		cfg.CorrelationMinTargets = fc.CorrelationMinTargets
+	}
+	if fc.RegressionThreshold > 0 {
+		cfg.RegressionThreshold = fc.RegressionThreshold
+	}
+	if fc.FlapHighThreshold != 0 {
+		cfg.FlapHighThreshold = fc.FlapHighThreshold
+	}
+	if fc.FlapLowThreshold != 0 {
+		cfg.FlapLowThreshold = fc.FlapLowThreshold
+	}
+	if cfg.FlapLowThreshold <= 0 || cfg.FlapHighThreshold > 100 || cfg.FlapLowThreshold > cfg.FlapHighThreshold {
+		return nil, fmt.Errorf("invalid flap thresholds %g/%g: must satisfy 0 < flap_low_threshold <= flap_high_threshold <= 100", cfg.FlapLowThreshold, cfg.FlapHighThreshold)
+	}
+	if fc.Concurrency < 0 {
+		return nil, fmt.Errorf("invalid concurrency %d: must be at least 1", fc.Concurrency)
+	}
+	if fc.Concurrency > 0 {
+		cfg.Concurrency = fc.Concurrency
+	}
+	if fc.Retries != nil {
+		if *fc.Retries < 0 {
+			return nil, fmt.Errorf("invalid retries %d: must not be negative", *fc.Retries)
+		}
+		cfg.Retries = *fc.Retries
+	}
+	if fc.DomainRate < 0 || fc.GlobalRate < 0 {
+		return nil, fmt.Errorf("invalid rate limit: domain_rate and global_rate must be at least 1")
+	}
+	if fc.DomainRate > 0 {
+		cfg.DomainRate = fc.DomainRate
+	}
+	if fc.GlobalRate > 0 {
+		cfg.GlobalRate = fc.GlobalRate
+	}
+	if fc.ResultsDir != "" {
+		cfg.ResultsDir = fc.ResultsDir
+	}
+	if fc.OutputFormat != "" {
+		if !slices.Contains(OutputFormats, fc.OutputFormat) {
+			return nil, fmt.Errorf("invalid output_format %q: must be one of %s", fc.OutputFormat, strings.Join(OutputFormats, ", "))
+		}
+		cfg.OutputFormat = fc.OutputFormat
+	}
+	if fc.Duplicates != "" {
+		if !slices.Contains(DuplicateModes, fc.Duplicates) {
+			return nil, fmt.Errorf("invalid duplicates %q: must be one of %s", fc.Duplicates, strings.Join(DuplicateModes, ", "))
+		}
+		cfg.Duplicates = fc.Duplicates
+	}
+	if fc.Language != "" && !i18n.Supported(fc.Language) {
+		return nil, fmt.Errorf("invalid language %q: must be one of %s", fc.Language, strings.Join(i18n.Languages, ", "))
+	}
+	cfg.Language = fc.Language
+	if fc.DisplayTimezone != "" {
+		loc, err := time.LoadLocation(fc.DisplayTimezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid display_timezone %q: %w", fc.DisplayTimezone, err)
+		}
+		cfg.DisplayLocation = loc
+	}
+	if fc.HistoryLimit > 0 {
+		cfg.HistoryLimit = fc.HistoryLimit
+	}
+	if fc.HistogramBuckets != nil {
+		buckets := make([]time.Duration, 0, len(fc.HistogramBuckets))
+		for _, b := range fc.HistogramBuckets {
+			d, err := time.ParseDuration(b)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("invalid histogram_buckets %q: must be a positive duration", b)
+			}
+			if len(buckets) > 0 && d <= buckets[len(buckets)-1] {
+				return nil, fmt.Errorf("invalid histogram_buckets %q: must be in ascending order", b)
+			}
+			buckets = append(buckets, d)
+		}
+		if len(buckets) == 0 {
+			return nil, fmt.Errorf("invalid histogram_buckets: must not be empty")
+		}
+		cfg.HistogramBuckets = buckets
+	}
+	if fc.SLOTarget > 0 {
+		if fc.SLOTarget >= 100 {
+			return nil, fmt.Errorf("invalid slo_target %v: must be below 100", fc.SLOTarget)
+		}
+		cfg.SLOTarget = fc.SLOTarget
+	}
+	if fc.AnomalySigma > 0 {
+		cfg.AnomalySigma = fc.AnomalySigma
+	}
+	if fc.AnomalyMinSamples > 0 {
+		cfg.AnomalyMinSamples = fc.AnomalyMinSamples
+	}
+	if fc.RootCauseHints != nil {
+		cfg.RootCauseHints = *fc.RootCauseHints
+	}
+	cfg.Traceroute = fc.Traceroute
+	if fc.TracerouteMaxHops > 0 {
+		cfg.TracerouteMaxHops = min(fc.TracerouteMaxHops, 64)
+	}
+	cfg.ReverseDNS = fc.ReverseDNS
+	for _, path := range fc.GeoIPDatabases {
+		if _, err := ipinfo.Load(path); err != nil {
+			return nil, fmt.Errorf("invalid geoip_databases %q: %w", path, err)
+		}
+	}
+	cfg.GeoIPDatabases = fc.GeoIPDatabases
+	cfg.BrowserPath = fc.BrowserPath
+	if fc.SitemapMaxURLs > 0 {
+		cfg.SitemapMaxURLs = fc.SitemapMaxURLs
+	}
+	for _, pattern := range append(append([]string(nil), fc.SitemapInclude...), fc.SitemapExclude...) {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("invalid sitemap pattern %q: %w", pattern, err)
+		}
+	}
+	cfg.SitemapInclude = fc.SitemapInclude
+	cfg.SitemapExclude = fc.SitemapExclude
+	if fc.ServiceName != "" {
+		cfg.ServiceName = fc.ServiceName
+	}
+	cfg.OTLPEndpoint = fc.OTLPEndpoint
+	cfg.OTLPHeaders = fc.OTLPHeaders
+	cfg.Insecure = fc.Insecure
+	if fc.DeniedNetworks != nil {
+		networks := make([]netip.Prefix, 0, len(fc.DeniedNetworks))
+		for _, n := range fc.DeniedNetworks {
+			prefix, err := netip.ParsePrefix(n)
+			if err != nil {
+				return nil, fmt.Errorf("invalid denied_networks %q: %w", n, err)
+			}
+			networks = append(networks, prefix.Masked())
+		}
+		cfg.DeniedNetworks = networks
+	}
+	cfg.Verbose = fc.Verbose
+	cfg.NoColor = fc.NoColor
+	if fc.LogFormat != "" && fc.LogFormat != "text" && fc.LogFormat != "json" {
+		return nil, fmt.Errorf("invalid log_format %q: must be text or json", fc.LogFormat)
+	}
+	cfg.LogFormat = fc.LogFormat
+	if fc.AuditLog != nil {
+		cfg.AuditLog = *fc.AuditLog
+	}
+	cfg.DebugEndpoints = fc.DebugEndpoints
+	limits := []struct {
+		name  string
+		value *int
+		dest  *int
+	}{
+		{"max_concurrent_runs", fc.MaxConcurrentRuns, &cfg.MaxConcurrentRuns},
+		{"max_run_urls", fc.MaxRunURLs, &cfg.MaxRunURLs},
+		{"client_rate", fc.ClientRate, &cfg.ClientRate},
+		{"max_buffered_results", fc.MaxBufferedResults, &cfg.MaxBufferedResults},
+		{"flap_window", fc.FlapWindow, &cfg.FlapWindow},
+	}
+	for _, l := range limits {
+		if l.value == nil {
+			continue
+		}
+		if *l.value < 0 {
+			return nil, fmt.Errorf("invalid %s %d: must not be negative", l.name, *l.value)
+		}
+		*l.dest = *l.value
+	}
+	if cfg.FlapWindow > 0 && cfg.FlapWindow < 3 {
+		return nil, fmt.Errorf("invalid flap_window %d: must be 0 or at least 3", cfg.FlapWindow)
+	}
+	if fc.MaxBodyBytes != nil {
+		if *fc.MaxBodyBytes < 0 {
+			return nil, fmt.Errorf("invalid max_body_bytes %d: must not be negative", *fc.MaxBodyBytes)
+		}
+		cfg.MaxBodyBytes = *fc.MaxBodyBytes
+	}
+	if fc.SnippetBytes != nil {
+		if *fc.SnippetBytes < 0 || *fc.SnippetBytes > maxSnippetBytes {
+			return nil, fmt.Errorf("invalid snippet_bytes %d: must be between 0 and %d", *fc.SnippetBytes, maxSnippetBytes)
+		}
+		cfg.SnippetBytes = *fc.SnippetBytes
+	}
+	if fc.SnippetHeaders != nil {
+		cfg.SnippetHeaders = fc.SnippetHeaders
+	}
+	cfg.Targets = fc.Targets
+	cfg.Templates = fc.Templates
+	cfg.MaintenanceWindows = fc.MaintenanceWindows
+	cfg.Notifiers = fc.Notifiers
+	cfg.AlertRules = fc.AlertRules
+	cfg.Digests = fc.Digests
+	cfg.Auth = fc.Auth
+	cfg.Projects = fc.Projects
+	cfg.Users = fc.Users
+	cfg.Theme = fc.Theme
+	cfg.Hooks = fc.Hooks
+	cfg.Discovery = fc.Discovery
+
+	if fc.Region != "" && !regionPattern.MatchString(fc.Region) {
+		return nil, fmt.Errorf("invalid region %q: use letters, digits, '-' and '_'", fc.Region)
+	}
+	if fc.Coordinator != "" && (fc.Region == "" || fc.CoordinatorToken == "") {
+		return nil, fmt.Errorf("region and coordinator_token are required with coordinator")
+	}
+	for region, token := range fc.Agents {
+		if !regionPattern.MatchString(region) {
+			return nil, fmt.Errorf("invalid agent region %q: use letters, digits, '-' and '_'", region)
+		}
+		if token == "" {
+			return nil, fmt.Errorf("agent %q: token is required", region)
+		}
+	}
+	cfg.Region = fc.Region
+	cfg.Coordinator = fc.Coordinator
+	cfg.CoordinatorToken = fc.CoordinatorToken
+	cfg.Agents = fc.Agents
+
+	for _, m := range cfg.MaintenanceWindows {
+		if !m.End.After(m.Start) {
+			return nil, fmt.Errorf("maintenance window %q: end must be after start", m.Name)
+		}
+	}
+
+	authNames := make(map[string]bool)
+	for i, a := range cfg.Auth {
+		if a.Name == "" {
+			return nil, fmt.Errorf("auth %d: name is required", i+1)
+		}
+		if authNames[a.Name] {
+			return nil, fmt.Errorf("auth %d: duplicate name %q", i+1, a.Name)
+		}
+		authNames[a.Name] = true
+		if a.Type != "oauth2" {
+			return nil, fmt.Errorf("auth %d: unknown type %q", i+1, a.Type)
+		}
+		if a.TokenURL == "" || a.ClientID == "" {
+			return nil, fmt.Errorf("auth %d: token_url and client_id are required for oauth2", i+1)
+		}
+	}
+
+	if err := validateTemplates(cfg.Templates); err != nil {
+		return nil, err
+	}
+	if err := expandTemplates(cfg); err != nil {
+		return nil, err
+	}
+	if err := validateTargets(cfg.Targets, authNames); err != nil {
+		return nil, err
+	}
+
+	for i, n := range cfg.Notifiers {
+		switch n.Type {
+		case "webhook", "slack":
+			if n.URL == "" {
+				return nil, fmt.Errorf("notifier %d: url is required for %s", i+1, n.Type)
+			}
+		case "email":
+			if n.SMTPAddr == "" || n.From == "" || len(n.To) == 0 {
+				return nil, fmt.Errorf("notifier %d: smtp_addr, from and to are required for email", i+1)
+			}
+		case "pagerduty":
+			if n.RoutingKey == "" {
+				return nil, fmt.Errorf("notifier %d: routing_key is required for pagerduty", i+1)
+			}
+		case "opsgenie":
+			if n.APIKey == "" {
+				return nil, fmt.Errorf("notifier %d: api_key is required for opsgenie", i+1)
+			}
+		case "alertmanager":
+			if n.URL == "" {
+				return nil, fmt.Errorf("notifier %d: url is required for alertmanager", i+1)
+			}
+		default:
+			return nil, fmt.Errorf("notifier %d: unknown type %q", i+1, n.Type)
+		}
+		if n.Language != "" && n.Language != "ja" && n.Language != "en" {
+			return nil, fmt.Errorf("notifier %d: unsupported language %q", i+1, n.Language)
+		}
+		if n.Name == "" {
+			cfg.Notifiers[i].Name = n.Type
+		}
+	}
+	if err := validateAlertRules(cfg.AlertRules, cfg.Notifiers); err != nil {
+		return nil, err
+	}
+	if err := validateDigests(cfg.Digests, cfg.Notifiers); err != nil {
+		return nil, err
+	}
+	if err := validateProjects(cfg.Projects, authNames, cfg.Notifiers); err != nil {
+		return nil, err
+	}
+	if err := validateUsers(cfg.Users); err != nil {
+		return nil, err
+	}
+	if err := validateHooks(cfg.Hooks, authNames); err != nil {
+		return nil, err
+	}
+	if cfg.SessionTTL == 0 {
+		return nil, fmt.Errorf("invalid session_ttl %q: must be positive", fc.SessionTTL)
+	}
+	if fc.ShareSecret != "" && len(fc.ShareSecret) < 16 {
+		return nil, fmt.Errorf("share_secret must be at least 16 characters")
+	}
+	cfg.ShareSecret = fc.ShareSecret
+	if err := validateTheme(cfg.Theme); err != nil {
+		return nil, err
+	}
+
+	for i, d := range cfg.Discovery {
+		switch d.Type {
+		case "consul":
+			if d.Service == "" {
+				return nil, fmt.Errorf("discovery %d: service is required for consul", i+1)
+			}
+		case "dns_srv":
+			if d.Record == "" {
+				return nil, fmt.Errorf("discovery %d: record is required for dns_srv", i+1)
+			}
+		case "kubernetes":
+			if d.Resource != "" && d.Resource != "ingress" && d.Resource != "service" {
+				return nil, fmt.Errorf("discovery %d: unknown kubernetes resource %q", i+1, d.Resource)
+			}
+		default:
+			return nil, fmt.Errorf("discovery %d: unknown type %q", i+1, d.Type)
+		}
+		if d.Name == "" {
+			cfg.Discovery[i].Name = fmt.Sprintf("%s-%d", d.Type, i+1)
+		}
+	}
+
+	return cfg, nil
+}
+
+// validateTargets 定期チェックの対象を検証し、省略されたURLと名前を補う
+func validateTargets(targets []Target, authNames map[string]bool) error {
+	for i := range targets {
+		if err := validateTarget(&targets[i], authNames); err != nil {
+			return fmt.Errorf("target %d: %w", i+1, err)
+		}
+	}
+	return validateDependencies(targets)
+}
+
+// validateDependencies 対象のdepends_onが他の対象を参照し、依存関係が循環していないか検証
+func validateDependencies(targets []Target) error {
+	parents := make(map[int][]int)
+	for i, t := range targets {
+		for _, ref := range t.DependsOn {
+			j := slices.IndexFunc(targets, func(p Target) bool { return p.Name == ref || p.URL == ref })
+			if j < 0 {
+				return fmt.Errorf("target %d: unknown depends_on %q", i+1, ref)
+			}
+			if j == i {
+				return fmt.Errorf("target %d: depends_on must not refer to itself", i+1)
+			}
+			parents[i] = append(parents[i], j)
+		}
+	}
+
+	// 0: 未訪問、1: 探索中、2: 循環なしを確認済み
+	visited := make([]int, len(targets))
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		switch visited[i] {
+		case 1:
+			return false
+		case 2:
+			return true
+		}
+		visited[i] = 1
+		for _, j := range parents[i] {
+			if !visit(j) {
+				return false
+			}
+		}
+		visited[i] = 2
+		return true
+	}
+	for i := range targets {
+		if !visit(i) {
+			return fmt.Errorf("target %d: depends_on must not form a cycle", i+1)
+		}
+	}
+	return nil
+}
+
+// ValidateTarget 設定ファイル以外から追加する対象を設定ファイルの対象と同じく検証し、省略されたURLと名前を補う
+func (c *Config) ValidateTarget(t *Target) error {
+	authNames := make(map[string]bool, len(c.Auth))
+	for _, a := range c.Auth {
+		authNames[a.Name] = true
+	}
+	if err := c.ApplyTemplate(t); err != nil {
+		return err
+	}
+	return validateTarget(t, authNames)
+}
+
+// validateTarget 1つの対象を検証し、省略されたURLと名前を補う
+func validateTarget(target *Target, authNames map[string]bool) error {
+	t := *target
+	switch t.Type {
+	case "", "http":
+		if t.URL == "" {
+			return fmt.Errorf("url is required")
+		}
+		if t.Timeout != "" {
+			if _, err := time.ParseDuration(t.Timeout); err != nil {
+				return fmt.Errorf("invalid timeout %q: %w", t.Timeout, err)
+			}
+		}
+		for _, rt := range [][2]string{{"header_timeout", t.HeaderTimeout}, {"body_timeout", t.BodyTimeout}} {
+			if rt[1] == "" {
+				continue
+			}
+			if d, err := time.ParseDuration(rt[1]); err != nil || d <= 0 {
+				return fmt.Errorf("invalid %s %q: must be a positive duration", rt[0], rt[1])
+			}
+		}
+		if t.ExpectedStatus != 0 && (t.ExpectedStatus < 100 || t.ExpectedStatus > 599) {
+			return fmt.Errorf("invalid expected_status %d", t.ExpectedStatus)
+		}
+		if t.Connection != "" && t.Connection != "reuse" && t.Connection != "cold" {
+			return fmt.Errorf("invalid connection %q: must be reuse or cold", t.Connection)
+		}
+		if t.IPFamily != "" && !slices.Contains(IPFamilies, t.IPFamily) {
+			return fmt.Errorf("invalid ip_family %q: must be one of %s", t.IPFamily, strings.Join(IPFamilies, ", "))
+		}
+		if t.ExpectedContentType != "" {
+			if _, _, err := mime.ParseMediaType(t.ExpectedContentType); err != nil {
+				return fmt.Errorf("invalid expected_content_type %q: %w", t.ExpectedContentType, err)
+			}
+		}
+		if t.ExpectedCache != "" && !slices.Contains(CacheExpectations, t.ExpectedCache) {
+			return fmt.Errorf("invalid expected_cache %q: must be one of %s", t.ExpectedCache, strings.Join(CacheExpectations, ", "))
+		}
+		if t.ExpectedLocationPattern != "" {
+			if _, err := regexp.Compile(t.ExpectedLocationPattern); err != nil {
+				return fmt.Errorf("invalid expected_location_pattern %q: %w", t.ExpectedLocationPattern, err)
+			}
+		}
+		if t.Resolve != "" {
+			if err := ValidateResolve(t.Resolve); err != nil {
+				return fmt.Errorf("invalid resolve: %w", err)
+			}
+		}
+		if t.Origin != "" {
+			if err := ValidateResolve(t.Origin); err != nil {
+				return fmt.Errorf("invalid origin: %w", err)
+			}
+		}
+		if err := ValidateSNIHosts(t.SNIHosts, t.IPFamily); err != nil {
+			return fmt.Errorf("invalid sni_hosts: %w", err)
+		}
+		if t.ExpiryWarnDays < 0 {
+			return fmt.Errorf("invalid expiry_warn_days %d: must not be negative", t.ExpiryWarnDays)
+		}
+		// URLは結果や履歴にそのまま残るため、パスワードはpasswordに分けて指定させる
+		if u, err := url.Parse(t.URL); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				return fmt.Errorf("url must not contain a password: use password")
+			}
+		}
+	case "exec":
+		if len(t.Command) == 0 {
+			return fmt.Errorf("command is required for exec")
+		}
+		if t.Timeout != "" {
+			if _, err := time.ParseDuration(t.Timeout); err != nil {
+				return fmt.Errorf("invalid timeout %q: %w", t.Timeout, err)
+			}
+		}
+		// 履歴やステータスページで対象を識別するためのURL
+		if t.URL == "" {
+			name := t.Name
+			if name == "" {
+				name = strings.Join(t.Command, " ")
+			}
+			target.URL = "exec:" + name
+		}
+	case "browser":
+		if u, err := url.Parse(t.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid url %q: browser requires an http(s) URL", t.URL)
+		}
+		if t.Timeout != "" {
+			if _, err := time.ParseDuration(t.Timeout); err != nil {
+				return fmt.Errorf("invalid timeout %q: %w", t.Timeout, err)
+			}
+		}
+		if t.ExpectedStatus != 0 && (t.ExpectedStatus < 100 || t.ExpectedStatus > 599) {
+			return fmt.Errorf("invalid expected_status %d", t.ExpectedStatus)
+		}
+		for _, pattern := range t.IgnoreResources {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid ignore_resources %q: %w", pattern, err)
+			}
+		}
+	case "heartbeat":
+		if t.Name == "" {
+			return fmt.Errorf("name is required for heartbeat")
+		}
+		if t.Period == "" {
+			return fmt.Errorf("period is required for heartbeat")
+		}
+		for _, v := range []string{t.Period, t.Grace} {
+			if v == "" {
+				continue
+			}
+			if _, err := time.ParseDuration(v); err != nil {
+				return fmt.Errorf("invalid duration %q: %w", v, err)
+			}
+		}
+		if t.URL == "" {
+			target.URL = "heartbeat:" + t.Name
+		}
+	default:
+		// 独自に登録されたチェック方法の対象
+		if t.URL == "" {
+			return fmt.Errorf("url is required for %s", t.Type)
+		}
+	}
+	if t.Severity != "" && !slices.Contains(Severities, t.Severity) {
+		return fmt.Errorf("invalid severity %q: must be one of %s", t.Severity, strings.Join(Severities, ", "))
+	}
+	if t.Priority != "" && !slices.Contains(Priorities, t.Priority) {
+		return fmt.Errorf("invalid priority %q: must be one of %s", t.Priority, strings.Join(Priorities, ", "))
+	}
+	if t.Name == "" {
+		target.Name = target.URL
+	}
+	if t.Auth != "" && !authNames[t.Auth] {
+		return fmt.Errorf("unknown auth %q", t.Auth)
+	}
+	return nil
+}
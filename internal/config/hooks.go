@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// validateHooks CIなどから呼び出すフックの名前・シークレット・対象・結果の送信先を検証
+func validateHooks(hooks []Hook, authNames map[string]bool) error {
+	names := make(map[string]bool)
+	for i, h := range hooks {
+		if !regionPattern.MatchString(h.Name) {
+			return fmt.Errorf("hook %d: invalid name %q: use letters, digits, '-' and '_'", i+1, h.Name)
+		}
+		if names[h.Name] {
+			return fmt.Errorf("hook %d: duplicate name %q", i+1, h.Name)
+		}
+		names[h.Name] = true
+		if h.Secret == "" {
+			return fmt.Errorf("hook %q: secret is required", h.Name)
+		}
+		if len(h.Targets) == 0 && len(h.Tags) == 0 {
+			return fmt.Errorf("hook %q: targets or tags is required", h.Name)
+		}
+		if h.CallbackURL != "" {
+			u, err := url.Parse(h.CallbackURL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("hook %q: invalid callback_url %q: must be an http(s) URL", h.Name, h.CallbackURL)
+			}
+		}
+		for j, t := range h.Targets {
+			if t.Type == "heartbeat" {
+				return fmt.Errorf("hook %q: target %d: heartbeat is not supported in hooks", h.Name, j+1)
+			}
+		}
+		if err := validateTargets(h.Targets, authNames); err != nil {
+			return fmt.Errorf("hook %q: %w", h.Name, err)
+		}
+	}
+	return nil
+}
+
+// FindHook 名前でフックを探す
+func (c *Config) FindHook(name string) (Hook, bool) {
+	for _, h := range c.Hooks {
+		if h.Name == name {
+			return h, true
+		}
+	}
+	return Hook{}, false
+}
+
+// HookTargets フックでチェックする対象（フックの対象と、タグが一致する設定ファイル・自動検出の対象）
+// ハートビートは受信を待つ対象のためチェックしない
+func (c *Config) HookTargets(h Hook) []Target {
+	targets := append([]Target(nil), h.Targets...)
+	if len(h.Tags) == 0 {
+		return targets
+	}
+	for _, t := range c.AllTargets() {
+		if t.Type != "heartbeat" && MatchTags(t.Tags, h.Tags) {
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
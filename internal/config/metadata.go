@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxMetadata 1回の実行に付けられるメタデータの最大数
+	MaxMetadata = 20
+	// MaxMetadataValue メタデータの値の最大文字数
+	MaxMetadataValue = 256
+)
+
+// ParseMetadata "key=value" 形式の文字列を実行のメタデータ（triggered_by・deploy_sha・environmentなど）に変換
+// 空の文字列は無視する
+func ParseMetadata(values []string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	for _, v := range values {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(v, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid metadata %q: must be key=value", v)
+		}
+		metadata[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := ValidateMetadata(metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// ValidateMetadata メタデータの数・キー・値の長さを検証
+// 履歴の一覧に表示するため、キーは空白と制御文字を含まない文字列に限る
+func ValidateMetadata(metadata map[string]string) error {
+	if len(metadata) > MaxMetadata {
+		return fmt.Errorf("too many metadata entries: %d (max %d)", len(metadata), MaxMetadata)
+	}
+	for key, value := range metadata {
+		if key == "" || strings.IndexFunc(key, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+			return fmt.Errorf("invalid metadata key %q: must be non-empty without spaces", key)
+		}
+		if len([]rune(value)) > MaxMetadataValue {
+			return fmt.Errorf("invalid metadata %q: value exceeds %d characters", key, MaxMetadataValue)
+		}
+		if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+			return fmt.Errorf("invalid metadata %q: value must not contain control characters", key)
+		}
+	}
+	return nil
+}
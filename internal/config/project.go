@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+)
+
+// validateProjects プロジェクトの設定と対象を検証
+func validateProjects(projects []Project, authNames map[string]bool, notifiers []NotifierConfig) error {
+	names := make(map[string]bool)
+	for i := range projects {
+		p := &projects[i]
+		if !regionPattern.MatchString(p.Name) {
+			return fmt.Errorf("project %d: invalid name %q: use letters, digits, '-' and '_'", i+1, p.Name)
+		}
+		if names[p.Name] {
+			return fmt.Errorf("project %d: duplicate name %q", i+1, p.Name)
+		}
+		names[p.Name] = true
+		if p.Title == "" {
+			p.Title = p.Name
+		}
+		for _, token := range p.Tokens {
+			if token == "" {
+				return fmt.Errorf("project %q: empty token", p.Name)
+			}
+		}
+		for _, name := range p.Notifiers {
+			if !slices.ContainsFunc(notifiers, func(n NotifierConfig) bool { return n.Name == name }) {
+				return fmt.Errorf("project %q: unknown notifier %q", p.Name, name)
+			}
+		}
+		// ハートビートの受信口はプロジェクトを区別しないため、プロジェクトでは使えない
+		for j, t := range p.Targets {
+			if t.Type == "heartbeat" {
+				return fmt.Errorf("project %q: target %d: heartbeat is not supported in projects", p.Name, j+1)
+			}
+		}
+		if err := validateTargets(p.Targets, authNames); err != nil {
+			return fmt.Errorf("project %q: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// FindProject 名前でプロジェクトを探す
+func (c *Config) FindProject(name string) (Project, bool) {
+	for _, p := range c.Projects {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Project{}, false
+}
+
+// ForProject プロジェクトの定期チェックに使う設定を作成
+// チェック方法などは全体の設定を引き継ぎ、対象・履歴の保存先・通知先をプロジェクトのものに置き換える
+// 自動検出・稼働レポート・エージェントの設定は引き継がない
+func (c *Config) ForProject(p Project) *Config {
+	pc := DefaultConfig()
+	pc.Apply(c)
+	pc.ResultsDir = filepath.Join(c.ResultsDir, "projects", p.Name)
+	pc.Targets = p.Targets
+	pc.Notifiers = nil
+	for _, n := range c.Notifiers {
+		if slices.Contains(p.Notifiers, n.Name) {
+			pc.Notifiers = append(pc.Notifiers, n)
+		}
+	}
+	pc.AlertRules = nil
+	pc.Digests = nil
+	pc.Coordinator = ""
+	pc.CoordinatorToken = ""
+	pc.Agents = nil
+	pc.Projects = nil
+	return pc
+}
@@ -0,0 +1,108 @@
+package config
+
+import "reflect"
+
+// Clone 設定の複製（検出済みの対象は複製元と共有する）
+// 実行中の設定は複数のゴルーチンから読むため変更せず、複製に変更を加えてから置き換える
+func (c *Config) Clone() *Config {
+	clone := *c
+	return &clone
+}
+
+// Apply 再読み込みした設定を反映する（実行中の設定ではなく、Cloneした設定に対して呼び出す）
+// 検出済みの対象は引き継ぐ。起動時にしか反映できない項目は変更せず、変更されていた項目の名前を返す
+func (c *Config) Apply(next *Config) []string {
+	c.Timeout = next.Timeout
+	c.Concurrency = next.Concurrency
+	c.Retries = next.Retries
+	c.MaxLatency = next.MaxLatency
+	c.DomainRate = next.DomainRate
+	c.GlobalRate = next.GlobalRate
+	c.Duplicates = next.Duplicates
+	c.Insecure = next.Insecure
+	c.DeniedNetworks = next.DeniedNetworks
+	c.DNSTimeout = next.DNSTimeout
+	c.ConnectTimeout = next.ConnectTimeout
+	c.TLSHandshakeTimeout = next.TLSHandshakeTimeout
+	c.HeaderTimeout = next.HeaderTimeout
+	c.BodyTimeout = next.BodyTimeout
+
+	c.MaxBodyBytes = next.MaxBodyBytes
+	c.SnippetBytes = next.SnippetBytes
+	c.SnippetHeaders = next.SnippetHeaders
+	c.MaxRunURLs = next.MaxRunURLs
+	c.MaxBufferedResults = next.MaxBufferedResults
+
+	c.Interval = next.Interval
+	c.PriorityInterval = next.PriorityInterval
+	c.Spread = next.Spread
+	c.Jitter = next.Jitter
+	c.Targets = next.Targets
+	c.Templates = next.Templates
+	c.MaintenanceWindows = next.MaintenanceWindows
+	c.HistoryLimit = next.HistoryLimit
+	c.HistogramBuckets = next.HistogramBuckets
+	c.SLOTarget = next.SLOTarget
+	c.AnomalySigma = next.AnomalySigma
+	c.AnomalyMinSamples = next.AnomalyMinSamples
+	c.Notifiers = next.Notifiers
+	c.AlertRules = next.AlertRules
+	c.AlertDedupeWindow = next.AlertDedupeWindow
+	c.Digests = next.Digests
+	c.Auth = next.Auth
+	c.Projects = next.Projects
+	c.Users = next.Users
+	c.Language = next.Language
+	c.DisplayLocation = next.DisplayLocation
+	c.SessionTTL = next.SessionTTL
+	c.ShareSecret = next.ShareSecret
+	c.Theme = next.Theme
+	c.Hooks = next.Hooks
+
+	c.CorrelationWindow = next.CorrelationWindow
+	c.CorrelationMinTargets = next.CorrelationMinTargets
+	c.RegressionThreshold = next.RegressionThreshold
+	c.FlapWindow = next.FlapWindow
+	c.FlapHighThreshold = next.FlapHighThreshold
+	c.FlapLowThreshold = next.FlapLowThreshold
+	c.RootCauseHints = next.RootCauseHints
+	c.Traceroute = next.Traceroute
+	c.TracerouteMaxHops = next.TracerouteMaxHops
+	c.ReverseDNS = next.ReverseDNS
+	c.GeoIPDatabases = next.GeoIPDatabases
+	c.BrowserPath = next.BrowserPath
+
+	c.SitemapMaxURLs = next.SitemapMaxURLs
+	c.SitemapInclude = next.SitemapInclude
+	c.SitemapExclude = next.SitemapExclude
+
+	c.Region = next.Region
+	c.Coordinator = next.Coordinator
+	c.CoordinatorToken = next.CoordinatorToken
+	c.Agents = next.Agents
+
+	// ログ・監査記録・履歴の保存先・APIの制限・トレースの送信先・自動検出は起動時に作成するため再起動が必要
+	var restart []string
+	for _, f := range []struct {
+		name      string
+		old, next interface{}
+	}{
+		{"log_format", c.LogFormat, next.LogFormat},
+		{"verbose", c.Verbose, next.Verbose},
+		{"audit_log", c.AuditLog, next.AuditLog},
+		{"results_dir", c.ResultsDir, next.ResultsDir},
+		{"max_concurrent_runs", c.MaxConcurrentRuns, next.MaxConcurrentRuns},
+		{"client_rate", c.ClientRate, next.ClientRate},
+		{"debug_endpoints", c.DebugEndpoints, next.DebugEndpoints},
+		{"otlp_endpoint", c.OTLPEndpoint, next.OTLPEndpoint},
+		{"otlp_headers", c.OTLPHeaders, next.OTLPHeaders},
+		{"service_name", c.ServiceName, next.ServiceName},
+		{"discovery", c.Discovery, next.Discovery},
+		{"discovery_interval", c.DiscoveryInterval, next.DiscoveryInterval},
+	} {
+		if !reflect.DeepEqual(f.old, f.next) {
+			restart = append(restart, f.name)
+		}
+	}
+	return restart
+}
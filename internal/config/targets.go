@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// discoveredTargets 検出元ごとの検出済みの対象
+// 再読み込みで設定を置き換えても自動検出の結果を引き継ぐよう、設定とは別に保持して共有する
+type discoveredTargets struct {
+	mutex   sync.RWMutex
+	sources map[string][]Target
+}
+
+// SetDiscoveredTargets 検出元ごとの検出済みの対象を置き換える
+func (c *Config) SetDiscoveredTargets(source string, targets []Target) {
+	c.discovered.mutex.Lock()
+	defer c.discovered.mutex.Unlock()
+	c.discovered.sources[source] = targets
+}
+
+// AddDiscoveredTargets 検出元の検出済みの対象に追加する
+func (c *Config) AddDiscoveredTargets(source string, targets []Target) {
+	c.discovered.mutex.Lock()
+	defer c.discovered.mutex.Unlock()
+	c.discovered.sources[source] = append(c.discovered.sources[source], targets...)
+}
+
+// AllTargets 設定ファイルの対象と検出済みの対象をまとめて返す
+// 同じURLが複数ある場合は設定ファイルの対象を優先する
+func (c *Config) AllTargets() []Target {
+	c.discovered.mutex.RLock()
+	defer c.discovered.mutex.RUnlock()
+
+	targets := append([]Target(nil), c.Targets...)
+	seen := make(map[string]bool)
+	for _, t := range targets {
+		seen[t.URL] = true
+	}
+
+	sources := make([]string, 0, len(c.discovered.sources))
+	for source := range c.discovered.sources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		for _, t := range c.discovered.sources[source] {
+			if seen[t.URL] {
+				continue
+			}
+			seen[t.URL] = true
+			targets = append(targets, t)
+		}
+	}
+	return targets
+}
+
+// Dependencies 対象のURLごとの依存先のURL（depends_onのうち対象が見つかるもの）
+func (c *Config) Dependencies() map[string][]string {
+	targets := c.AllTargets()
+	deps := make(map[string][]string)
+	for _, t := range targets {
+		for _, ref := range t.DependsOn {
+			for _, p := range targets {
+				if (p.Name == ref || p.URL == ref) && p.URL != t.URL {
+					deps[t.URL] = append(deps[t.URL], p.URL)
+					break
+				}
+			}
+		}
+	}
+	return deps
+}
+
+// ID APIで対象を指定するための識別子（名前、名前がない場合はURL）
+func (t Target) ID() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.URL
+}
+
+// FindTarget 識別子（名前またはURL）が一致する対象を探す
+func (c *Config) FindTarget(id string) (Target, bool) {
+	for _, t := range c.AllTargets() {
+		if t.Name == id || t.URL == id {
+			return t, true
+		}
+	}
+	return Target{}, false
+}
+
+// MatchTags タグが条件のタグをすべて含むか（条件が空の場合は常にtrue）
+func MatchTags(tags, filter map[string]string) bool {
+	for k, v := range filter {
+		if tags[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseTagFilter "key=value" 形式の文字列をタグの条件に変換
+func ParseTagFilter(values []string) (map[string]string, error) {
+	filter := make(map[string]string)
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag filter %q: must be key=value", v)
+		}
+		filter[key] = value
+	}
+	return filter, nil
+}
+
+// TagKeys 対象に付与されたタグのキーを名前順に返す
+func TagKeys(targets []Target) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, t := range targets {
+		for k := range t.Tags {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+)
+
+// BuiltinTemplates あらかじめ用意した対象のひな形（設定ファイルのtemplatesで同じ名前を指定した場合はそちらを使う）
+var BuiltinTemplates = map[string]Target{
+	// JSONを返すAPIのヘルスチェック
+	"api": {
+		Method:              "GET",
+		Headers:             map[string]string{"Accept": "application/json"},
+		ExpectedContentType: "application/json",
+		Timeout:             "10s",
+		HeaderTimeout:       "5s",
+	},
+	// 優先度の高いAPI（priority_intervalを設定した場合は定期チェックの間にもチェックする）
+	"critical-api": {
+		Method:              "GET",
+		Headers:             map[string]string{"Accept": "application/json"},
+		ExpectedContentType: "application/json",
+		Timeout:             "5s",
+		HeaderTimeout:       "3s",
+		Severity:            "critical",
+		Priority:            "high",
+	},
+	// CDNから配信する静的なサイト
+	"static-site": {
+		ExpectedContentType: "text/html",
+		ExpectedEncoding:    []string{"gzip", "br"},
+		ExpectedCache:       "hit",
+		ScanResources:       true,
+	},
+}
+
+// validateTemplates 設定ファイルのひな形を検証（対象ごとに決まる項目とひな形の入れ子は指定できない）
+func validateTemplates(templates map[string]Target) error {
+	for name, t := range templates {
+		if !regionPattern.MatchString(name) {
+			return fmt.Errorf("template %q: invalid name: use letters, digits, '-' and '_'", name)
+		}
+		if t.Name != "" || t.URL != "" || t.Template != "" || len(t.DependsOn) > 0 {
+			return fmt.Errorf("template %q: name, url, template and depends_on cannot be set", name)
+		}
+	}
+	return nil
+}
+
+// expandTemplates 対象・プロジェクトの対象・フックの対象のひな形を展開
+func expandTemplates(cfg *Config) error {
+	expand := func(targets []Target) error {
+		for i := range targets {
+			if err := applyTemplate(&targets[i], cfg.Templates); err != nil {
+				return fmt.Errorf("target %d: %w", i+1, err)
+			}
+		}
+		return nil
+	}
+	if err := expand(cfg.Targets); err != nil {
+		return err
+	}
+	for _, p := range cfg.Projects {
+		if err := expand(p.Targets); err != nil {
+			return fmt.Errorf("project %q: %w", p.Name, err)
+		}
+	}
+	for _, h := range cfg.Hooks {
+		if err := expand(h.Targets); err != nil {
+			return fmt.Errorf("hook %q: %w", h.Name, err)
+		}
+	}
+	return nil
+}
+
+// ApplyTemplate 対象のtemplateで指定したひな形を展開（実行時に追加・指定された対象用）
+func (c *Config) ApplyTemplate(t *Target) error {
+	return applyTemplate(t, c.Templates)
+}
+
+// applyTemplate 対象のtemplateで指定したひな形の項目のうち、対象で指定していないものを補う
+// headers・tagsのようなオブジェクトの項目はキーごとに結合し、同じキーは対象の値を優先する
+func applyTemplate(target *Target, templates map[string]Target) error {
+	if target.Template == "" {
+		return nil
+	}
+	template, ok := templates[target.Template]
+	if !ok {
+		template, ok = BuiltinTemplates[target.Template]
+	}
+	if !ok {
+		return fmt.Errorf("unknown template %q", target.Template)
+	}
+
+	merged, err := targetFields(template)
+	if err != nil {
+		return err
+	}
+	own, err := targetFields(*target)
+	if err != nil {
+		return err
+	}
+	for key, value := range own {
+		if base, ok := merged[key]; ok && isJSONObject(base) && isJSONObject(value) {
+			if value, err = mergeJSONObjects(base, value); err != nil {
+				return err
+			}
+		}
+		merged[key] = value
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	var expanded Target
+	if err := json.Unmarshal(data, &expanded); err != nil {
+		return fmt.Errorf("template %q: %w", target.Template, err)
+	}
+	*target = expanded
+	return nil
+}
+
+// targetFields 対象で指定した項目（JSONのキーと値）
+func targetFields(t Target) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// isJSONObject JSONの値がオブジェクトか
+func isJSONObject(v json.RawMessage) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(v), []byte("{"))
+}
+
+// mergeJSONObjects 2つのJSONのオブジェクトをキーごとに結合（同じキーはoverrideの値を使う）
+func mergeJSONObjects(base, override json.RawMessage) (json.RawMessage, error) {
+	var merged, values map[string]json.RawMessage
+	if err := json.Unmarshal(base, &merged); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(override, &values); err != nil {
+		return nil, err
+	}
+	maps.Copy(merged, values)
+	return json.Marshal(merged)
+}
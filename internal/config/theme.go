@@ -0,0 +1,29 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// accentColorPattern テーマのaccent_colorに指定できる色（#rgb / #rrggbb）
+var accentColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validateTheme テーマの表示モード・色・ロゴのURLを検証
+func validateTheme(t Theme) error {
+	if t.Mode != "" && !slices.Contains(ThemeModes, t.Mode) {
+		return fmt.Errorf("invalid theme mode %q: must be one of %s", t.Mode, strings.Join(ThemeModes, ", "))
+	}
+	if t.AccentColor != "" && !accentColorPattern.MatchString(t.AccentColor) {
+		return fmt.Errorf("invalid theme accent_color %q: use #rgb or #rrggbb", t.AccentColor)
+	}
+	if t.LogoURL != "" {
+		u, err := url.Parse(t.LogoURL)
+		if err != nil || (u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid theme logo_url %q: must be an http(s) URL or a path", t.LogoURL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"healthcheck/internal/account"
+)
+
+// validateUsers ユーザーの名前・役割・パスワードのハッシュを検証
+func validateUsers(users []User) error {
+	names := make(map[string]bool)
+	for i, u := range users {
+		if u.Name == "" || strings.ContainsAny(u.Name, ":") {
+			return fmt.Errorf("user %d: invalid name %q", i+1, u.Name)
+		}
+		if names[u.Name] {
+			return fmt.Errorf("user %d: duplicate name %q", i+1, u.Name)
+		}
+		names[u.Name] = true
+		if !slices.Contains(account.Roles, u.Role) {
+			return fmt.Errorf("user %q: invalid role %q: must be one of %s", u.Name, u.Role, strings.Join(account.Roles, ", "))
+		}
+		if err := account.ValidateHash(u.PasswordHash); err != nil {
+			return fmt.Errorf("user %q: %w", u.Name, err)
+		}
+	}
+	return nil
+}
+
+// FindUser 名前でユーザーを探す
+func (c *Config) FindUser(name string) (User, bool) {
+	for _, u := range c.Users {
+		if u.Name == name {
+			return u, true
+		}
+	}
+	return User{}, false
+}
@@ -0,0 +1,67 @@
+package dashboard
+
+import (
+	"fmt"
+	"html"
+	"unicode/utf8"
+)
+
+// バッジの色（shields.ioと同じ配色）
+const (
+	BadgeGreen       = "#4c1"
+	BadgeYellowGreen = "#a4a61d"
+	BadgeYellow      = "#dfb317"
+	BadgeRed         = "#e05d44"
+	BadgeGrey        = "#9f9f9f"
+)
+
+// GenerateBadge shields.io風のSVGバッジを生成
+func GenerateBadge(label, value, color string) string {
+	labelWidth := textWidth(label) + 10
+	valueWidth := textWidth(value) + 10
+	width := labelWidth + valueWidth
+
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">
+  <title>%s: %s</title>
+  <linearGradient id="s" x2="0" y2="100%%">
+    <stop offset="0" stop-color="#bbb" stop-opacity=".1"/>
+    <stop offset="1" stop-opacity=".1"/>
+  </linearGradient>
+  <clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>
+  <g clip-path="url(#r)">
+    <rect width="%d" height="20" fill="#555"/>
+    <rect x="%d" width="%d" height="20" fill="%s"/>
+    <rect width="%d" height="20" fill="url(#s)"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>
+    <text x="%d" y="14">%s</text>
+    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>
+    <text x="%d" y="14">%s</text>
+  </g>
+</svg>`,
+		width, html.EscapeString(label), html.EscapeString(value),
+		html.EscapeString(label), html.EscapeString(value),
+		width,
+		labelWidth,
+		labelWidth, valueWidth, color,
+		width,
+		labelWidth/2, html.EscapeString(label),
+		labelWidth/2, html.EscapeString(label),
+		labelWidth+valueWidth/2, html.EscapeString(value),
+		labelWidth+valueWidth/2, html.EscapeString(value),
+	)
+}
+
+// textWidth 文字列の表示幅を概算（全角文字は半角の約2倍とする）
+func textWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if utf8.RuneLen(r) > 1 {
+			width += 12
+		} else {
+			width += 7
+		}
+	}
+	return width
+}
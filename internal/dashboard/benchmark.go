@@ -0,0 +1,232 @@
+package dashboard
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// GenerateBenchmark 各URLを繰り返しチェックするベンチマークページを生成
+func GenerateBenchmark(count, maxCount, concurrency int, lang string, theme Theme) string {
+	lang = pageLanguage(lang)
+	tmpl := `<!DOCTYPE html>
+<html lang="{{.Lang}}" data-theme="{{themeMode}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{pageTitle "Health Check Benchmark"}}</title>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            background: #f5f5f5;
+            padding: 20px;
+        }
+        .container {
+            max-width: 1400px;
+            margin: 0 auto;
+        }
+        .header {
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            padding: 30px;
+            border-radius: 10px;
+            margin-bottom: 20px;
+            box-shadow: 0 5px 15px rgba(0,0,0,0.1);
+        }
+        .header h1 {
+            font-size: 2em;
+            margin-bottom: 10px;
+        }
+        .card {
+            background: white;
+            padding: 20px;
+            border-radius: 8px;
+            box-shadow: 0 2px 5px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+        .controls {
+            display: flex;
+            gap: 20px;
+            align-items: center;
+        }
+        .controls input,
+        .controls select {
+            padding: 8px;
+            border: 2px solid #e0e0e0;
+            border-radius: 5px;
+            font-size: 14px;
+        }
+        .results-table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+        .results-table th,
+        .results-table td {
+            padding: 12px;
+            text-align: left;
+            border-bottom: 1px solid #e5e5e5;
+        }
+        .results-table th {
+            background: #f9fafb;
+            font-weight: 600;
+            color: #666;
+        }
+        .actions {
+            text-align: center;
+            margin-top: 30px;
+        }
+        .btn {
+            display: inline-block;
+            padding: 12px 24px;
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            text-decoration: none;
+            border-radius: 5px;
+            font-weight: 600;
+            margin: 0 10px;
+        }
+        textarea {
+            width: 100%;
+            height: 120px;
+            padding: 10px;
+            border: 2px solid #e0e0e0;
+            border-radius: 5px;
+            font-family: monospace;
+            font-size: 14px;
+            margin-bottom: 15px;
+        }
+        .controls input {
+            width: 90px;
+        }
+        .controls button {
+            padding: 8px 20px;
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            border: none;
+            border-radius: 5px;
+            font-weight: 600;
+            cursor: pointer;
+        }
+        .controls button:disabled {
+            opacity: 0.5;
+            cursor: wait;
+        }
+    </style>
+    {{themeStyle}}
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            {{themeSwitch}}
+            {{langSwitch}}
+            {{brand}}
+            <h1>⏱️ Benchmark</h1>
+            <p>{{t "bench_description"}}</p>
+        </div>
+
+        <div class="card">
+            <textarea id="urls" placeholder="https://example.com&#10;https://example.org"></textarea>
+            <div class="controls">
+                <label>{{t "bench_count"}} <input type="number" id="count" value="{{.Count}}" min="1" max="{{.MaxCount}}"></label>
+                <label>{{t "bench_rate"}} <input type="number" id="rate" value="0" min="0" step="0.1"></label>
+                <label>{{t "bench_concurrency"}} <input type="number" id="concurrency" value="{{.Concurrency}}" min="1"></label>
+                <button id="run">{{t "run"}}</button>
+                <span id="summary"></span>
+            </div>
+        </div>
+
+        <div class="card">
+            <table class="results-table">
+                <thead>
+                    <tr>
+                        <th>URL</th>
+                        <th>{{t "count"}}</th>
+                        <th>{{t "failure"}}</th>
+                        <th>{{t "error_rate"}}</th>
+                        <th>{{t "min"}}</th>
+                        <th>{{t "avg"}}</th>
+                        <th>p50</th>
+                        <th>p95</th>
+                        <th>p99</th>
+                        <th>{{t "max"}}</th>
+                        <th>req/s</th>
+                    </tr>
+                </thead>
+                <tbody id="rows"></tbody>
+            </table>
+        </div>
+
+        <div class="actions">
+            <a href="/" class="btn">{{t "new_check"}}</a>
+        </div>
+    </div>
+
+    <script>
+        const ms = ns => Math.round(ns / 1e6) + 'ms';
+
+        async function run() {
+            const button = document.getElementById('run');
+            const params = new URLSearchParams({
+                urls: document.getElementById('urls').value,
+                count: document.getElementById('count').value,
+                rate: document.getElementById('rate').value,
+                concurrency: document.getElementById('concurrency').value
+            });
+            button.disabled = true;
+            document.getElementById('summary').textContent = {{t "running"}};
+            try {
+                const response = await fetch('/api/benchmark', { method: 'POST', body: params });
+                if (!response.ok) {
+                    alert({{t "error_prefix"}} + await response.text());
+                    document.getElementById('summary').textContent = '';
+                    return;
+                }
+                const data = await response.json();
+                document.getElementById('summary').textContent =
+                    data.total + {{t "requests_unit"}} + ' / ' + (data.total_duration_ms / 1e9).toFixed(1) + {{t "seconds_unit"}};
+
+                const rows = document.getElementById('rows');
+                rows.innerHTML = '';
+                (data.distributions || []).forEach(d => {
+                    const tr = document.createElement('tr');
+                    [d.url, d.count, d.failure_count, d.error_rate.toFixed(1) + '%',
+                     ms(d.min_response_time_ms), ms(d.avg_response_time_ms), ms(d.p50_response_time_ms),
+                     ms(d.p95_response_time_ms), ms(d.p99_response_time_ms), ms(d.max_response_time_ms),
+                     d.requests_per_sec.toFixed(1)
+                    ].forEach(v => {
+                        const td = document.createElement('td');
+                        td.textContent = v;
+                        tr.appendChild(td);
+                    });
+                    rows.appendChild(tr);
+                });
+            } finally {
+                button.disabled = false;
+            }
+        }
+
+        document.getElementById('run').addEventListener('click', run);
+    </script>
+</body>
+</html>`
+
+	t, err := template.New("benchmark").Funcs(PageFuncs(lang, theme)).Parse(tmpl)
+	if err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	data := struct {
+		Lang        string
+		Count       int
+		MaxCount    int
+		Concurrency int
+	}{lang, count, maxCount, concurrency}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, data); err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,234 @@
+package dashboard
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcheck/internal/i18n"
+)
+
+// CalendarEvent カレンダーに表示するイベント
+type CalendarEvent struct {
+	Type  string    `json:"type"` // scheduled / maintenance / incident
+	Title string    `json:"title"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end,omitempty"`
+}
+
+// calendarDay 1日分のイベント
+type calendarDay struct {
+	Date   string
+	Today  bool
+	Events []CalendarEvent
+}
+
+// GenerateCalendar 実行予定・メンテナンス期間・インシデントをまとめたカレンダーを生成
+func GenerateCalendar(events []CalendarEvent, from, to time.Time, lang string, theme Theme) string {
+	lang = pageLanguage(lang)
+	tmpl := `<!DOCTYPE html>
+<html lang="{{.Lang}}" data-theme="{{themeMode}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{pageTitle "Health Check Calendar"}}</title>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            background: #f5f5f5;
+            padding: 20px;
+        }
+        .container {
+            max-width: 1000px;
+            margin: 0 auto;
+        }
+        .header {
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            padding: 30px;
+            border-radius: 10px;
+            margin-bottom: 20px;
+            box-shadow: 0 5px 15px rgba(0,0,0,0.1);
+        }
+        .header h1 {
+            font-size: 2em;
+            margin-bottom: 10px;
+        }
+        .legend span {
+            display: inline-block;
+            margin-right: 15px;
+            font-size: 14px;
+        }
+        .day {
+            background: white;
+            padding: 20px;
+            border-radius: 8px;
+            box-shadow: 0 2px 5px rgba(0,0,0,0.1);
+            margin-bottom: 15px;
+        }
+        .day.today {
+            border-left: 5px solid #667eea;
+        }
+        .day h2 {
+            font-size: 1.1em;
+            color: #333;
+            margin-bottom: 10px;
+        }
+        .event {
+            display: flex;
+            gap: 15px;
+            padding: 8px 0;
+            border-bottom: 1px solid #e5e5e5;
+            font-size: 14px;
+        }
+        .event:last-child { border-bottom: none; }
+        .event .time {
+            color: #666;
+            min-width: 130px;
+            font-family: monospace;
+        }
+        .badge {
+            display: inline-block;
+            padding: 2px 10px;
+            border-radius: 12px;
+            font-size: 12px;
+            font-weight: 600;
+            min-width: 90px;
+            text-align: center;
+        }
+        .badge-scheduled { background: #dbeafe; color: #1e40af; }
+        .badge-maintenance { background: #fef3c7; color: #92400e; }
+        .badge-incident { background: #fee2e2; color: #991b1b; }
+        .empty {
+            color: #999;
+            font-size: 14px;
+        }
+        .actions {
+            text-align: center;
+            margin-top: 30px;
+        }
+        .btn {
+            display: inline-block;
+            padding: 12px 24px;
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            text-decoration: none;
+            border-radius: 5px;
+            font-weight: 600;
+            margin: 0 10px;
+        }
+    </style>
+    {{themeStyle}}
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            {{themeSwitch}}
+            {{langSwitch}}
+            {{brand}}
+            <h1>📅 Health Check Calendar</h1>
+            <p>{{.From}} 〜 {{.To}}</p>
+            <p class="legend">
+                <span>🔵 {{typeLabel "scheduled"}}</span>
+                <span>🟡 {{typeLabel "maintenance"}}</span>
+                <span>🔴 {{typeLabel "incident"}}</span>
+            </p>
+        </div>
+
+        {{range .Days}}
+        <div class="day{{if .Today}} today{{end}}">
+            <h2>{{.Date}}{{if .Today}}{{t "calendar_today"}}{{end}}</h2>
+            {{if .Events}}
+                {{range .Events}}
+                <div class="event">
+                    <span class="time">{{formatRange .Start .End}}</span>
+                    <span class="badge badge-{{.Type}}">{{typeLabel .Type}}</span>
+                    <span>{{.Title}}</span>
+                </div>
+                {{end}}
+            {{else}}
+                <p class="empty">{{t "calendar_empty"}}</p>
+            {{end}}
+        </div>
+        {{end}}
+
+        <div class="actions">
+            <a href="/" class="btn">{{t "new_check"}}</a>
+        </div>
+    </div>
+</body>
+</html>`
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+
+	// 日ごとにイベントをまとめる（複数日にまたがるイベントは各日に表示）
+	today := i18n.In(time.Now()).Format("2006-01-02")
+	var days []calendarDay
+	for day := truncateDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+		d := calendarDay{
+			Date:  day.Format("2006-01-02 (Mon)"),
+			Today: day.Format("2006-01-02") == today,
+		}
+		for _, e := range events {
+			end := e.End
+			if end.IsZero() {
+				end = e.Start
+			}
+			if e.Start.Before(next) && !end.Before(day) {
+				d.Events = append(d.Events, e)
+			}
+		}
+		days = append(days, d)
+	}
+
+	data := struct {
+		Lang string
+		From string
+		To   string
+		Days []calendarDay
+	}{
+		Lang: lang,
+		From: from.Format("2006-01-02"),
+		To:   to.Format("2006-01-02"),
+		Days: days,
+	}
+
+	funcs := template.FuncMap{
+		"formatRange": func(start, end time.Time) string {
+			if end.IsZero() || end.Equal(start) {
+				return i18n.In(start).Format("01/02 15:04")
+			}
+			return i18n.In(start).Format("01/02 15:04") + " - " + i18n.In(end).Format("01/02 15:04")
+		},
+		"typeLabel": func(t string) string {
+			switch t {
+			case "scheduled", "maintenance", "incident":
+				return i18n.T(lang, "event_"+t)
+			}
+			return t
+		},
+	}
+
+	t, err := template.New("calendar").Funcs(PageFuncs(lang, theme)).Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, data); err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	return buf.String()
+}
+
+// truncateDay 時刻をその日の0時に切り捨てる
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
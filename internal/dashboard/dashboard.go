@@ -8,17 +8,49 @@ import (
 	"time"
 
 	"healthcheck/internal/checker"
+	"healthcheck/internal/i18n"
 	"healthcheck/internal/stats"
+	"healthcheck/internal/urllist"
 )
 
+// Extras ダッシュボードに追加表示する履歴ベースの情報
+type Extras struct {
+	SLA       []*stats.TargetSLA // 対象ごとの稼働率
+	SLAWindow string             // 稼働率の集計期間（24h/7d/30d）
+	SLOTarget float64            // 稼働率の目標値（%）
+
+	Regression *stats.Regression  // 前回の実行との差分
+	Rejected   []urllist.Rejected // 入力のうち受け付けなかった行
+
+	Live bool // 最新の保存済み結果を表示中（定期チェックの完了時に自動で更新する）
+
+	Scheduler string            // 定期チェックの状態（running/paused/stopped/disabled、表示中の場合のみ）
+	TargetIDs map[string]string // 設定済みの対象のURLと識別子（すぐにチェックするボタンを表示する）
+
+	Acks map[string]*checker.Acknowledgment // 有効な確認（表示中の場合のみ、失敗している対象の確認・取り消しのボタンを表示する）
+
+	Project  string // プロジェクトのダッシュボードの場合はプロジェクトの表示名
+	BasePath string // 操作とイベントのURLの接頭辞（プロジェクトの場合は/p/{name}）
+
+	Shared bool      // 共有リンクで表示（アプリ内のページへのリンクと、ほかの対象を含む稼働率の表を表示しない）
+	RunAt  time.Time // 表示する実行の日時（ゼロ値の場合は表示した日時）
+
+	User     string // ログイン中のユーザー名（ユーザーを設定していない場合は空）
+	ReadOnly bool   // 閲覧のみの権限（定期チェックの操作とすぐにチェックするボタンを表示しない）
+
+	Language string // 表示言語（ja / en、空の場合はデフォルト言語）
+	Theme    Theme  // 表示モードとブランドの設定
+}
+
 // GenerateDashboard HTMLダッシュボードを生成
-func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statistics, historyPath string) string {
+func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statistics, historyPath string, extras Extras) string {
+	lang := pageLanguage(extras.Language)
 	tmpl := `<!DOCTYPE html>
-<html lang="ja">
+<html lang="{{.Lang}}" data-theme="{{themeMode}}">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>Health Check Dashboard</title>
+    <title>{{pageTitle "Health Check Dashboard"}}</title>
     <script src="https://cdn.jsdelivr.net/npm/chart.js@3.9.1/dist/chart.min.js"></script>
     <style>
         * { margin: 0; padding: 0; box-sizing: border-box; }
@@ -107,7 +139,11 @@ func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statist
         }
         .status-success { background: #d1fae5; color: #065f46; }
         .status-redirect { background: #fef3c7; color: #92400e; }
+        .status-degraded { background: #ffedd5; color: #9a3412; }
         .status-error { background: #fee2e2; color: #991b1b; }
+        .status-acknowledged { background: #e0e7ff; color: #3730a3; }
+        .status-flapping { background: #f3e8ff; color: #6b21a8; }
+        .results-table tr.acknowledged td { opacity: 0.65; }
         .charts-grid {
             display: grid;
             grid-template-columns: repeat(auto-fit, minmax(400px, 1fr));
@@ -147,81 +183,262 @@ func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statist
             font-size: 12px;
             margin-top: 5px;
         }
+        .hint {
+            color: #92400e;
+            font-size: 12px;
+            margin-top: 5px;
+        }
+        .snippet {
+            font-size: 12px;
+            margin-top: 5px;
+        }
+        .snippet summary {
+            cursor: pointer;
+            color: #666;
+        }
+        .snippet pre {
+            max-width: 480px;
+            max-height: 240px;
+            overflow: auto;
+            white-space: pre-wrap;
+            word-break: break-all;
+            background: #f8f8f8;
+            padding: 8px;
+            border-radius: 4px;
+        }
+        .section-header {
+            display: flex;
+            justify-content: space-between;
+            align-items: center;
+            margin-bottom: 15px;
+        }
+        .section-header h2 { margin-bottom: 0; }
+        .help {
+            font-size: 12px;
+            color: #999;
+            margin-bottom: 10px;
+        }
+        .section-header select {
+            padding: 6px;
+            border: 2px solid #e0e0e0;
+            border-radius: 5px;
+        }
+        .budget-bar {
+            width: 120px;
+            height: 8px;
+            background: #e5e5e5;
+            border-radius: 4px;
+            overflow: hidden;
+        }
+        .budget-bar div {
+            height: 100%;
+            background: #10b981;
+        }
+        .budget-bar div.over { background: #ef4444; }
+        .btn-small {
+            padding: 4px 10px;
+            border: 1px solid #667eea;
+            border-radius: 4px;
+            background: white;
+            color: #667eea;
+            font-size: 12px;
+            cursor: pointer;
+        }
+        .btn-small:disabled { opacity: 0.5; cursor: wait; }
+        .header .btn-small { margin-left: 10px; }
+        .btn-small + .status-badge { margin-left: 6px; }
     </style>
+    {{themeStyle}}
 </head>
 <body>
     <div class="container">
         <div class="header">
-            <h1>📊 Health Check Dashboard</h1>
-            <p>実行日時: {{.Timestamp}}</p>
+            {{themeSwitch}}
+            {{langSwitch}}
+            {{brand}}
+            <h1>📊 Health Check Dashboard{{if .Extras.Project}} - {{.Extras.Project}}{{end}}</h1>
+            <p>{{t "dash_run_at" .Timestamp}}</p>
+            {{if .Extras.User}}
+            <form method="post" action="/logout">
+                👤 {{.Extras.User}}
+                <button type="submit" class="btn-small">{{t "logout"}}</button>
+            </form>
+            {{end}}
+            {{if .Extras.Live}}<p id="liveNotice">{{t "dash_live_notice"}}</p>{{end}}
+            {{if and .Extras.Live (ne .Extras.Scheduler "disabled")}}
+            <p>
+                {{t "dash_scheduler"}} {{if eq .Extras.Scheduler "paused"}}{{t "scheduler_paused"}}{{else if eq .Extras.Scheduler "running"}}{{t "scheduler_running"}}{{else}}{{t "scheduler_stopped"}}{{end}}
+                {{if .Extras.ReadOnly}}
+                {{else if eq .Extras.Scheduler "paused"}}
+                <button type="button" class="btn-small" data-scheduler="resume">{{t "resume"}}</button>
+                {{else}}
+                <button type="button" class="btn-small" data-scheduler="pause">{{t "pause"}}</button>
+                {{end}}
+            </p>
+            {{end}}
         </div>
 
         <div class="stats-grid">
             <div class="stat-card">
-                <h3>総リクエスト数</h3>
+                <h3>{{t "total_requests"}}</h3>
                 <div class="value">{{.Statistics.TotalRequests}}</div>
             </div>
             <div class="stat-card success">
-                <h3>成功</h3>
+                <h3>{{t "success"}}</h3>
                 <div class="value">{{.Statistics.SuccessCount}}</div>
             </div>
             <div class="stat-card failure">
-                <h3>失敗</h3>
+                <h3>{{t "failure"}}</h3>
                 <div class="value">{{.Statistics.FailureCount}}</div>
             </div>
             <div class="stat-card info">
-                <h3>成功率</h3>
+                <h3>{{t "success_rate"}}</h3>
                 <div class="value">{{printf "%.1f" .Statistics.SuccessRate}}%</div>
             </div>
             <div class="stat-card">
-                <h3>平均応答時間</h3>
+                <h3>{{t "avg_response_time"}}</h3>
                 <div class="value">{{printf "%.0f" .Statistics.AvgResponseTimeMs}}ms</div>
             </div>
             <div class="stat-card">
-                <h3>平均レイテンシ</h3>
+                <h3>{{t "avg_latency"}}</h3>
                 <div class="value">{{printf "%.0f" .Statistics.AvgLatencyMs}}ms</div>
             </div>
+            {{if .Statistics.CacheCounts}}
+            <div class="stat-card info">
+                <h3>{{t "cache_hit_rate"}}</h3>
+                <div class="value">{{printf "%.1f" .Statistics.CacheHitRate}}%</div>
+            </div>
+            {{end}}
+        </div>
+
+        {{if .Extras.Rejected}}
+        <div class="results-section">
+            <h2>{{t "dash_rejected"}}</h2>
+            <table class="results-table">
+                <tbody>
+                    {{range .Extras.Rejected}}
+                    <tr><td>{{t "line_number" .Line}}</td><td>{{.Text}}</td><td>{{.Reason}}</td></tr>
+                    {{end}}
+                </tbody>
+            </table>
+        </div>
+        {{end}}
+
+        {{if .Extras.Regression}}
+        <div class="results-section">
+            <h2>{{t "dash_regression"}}</h2>
+            <p class="help">{{t "dash_compared_with" (datetime .Extras.Regression.PreviousTimestamp "2006-01-02 15:04:05 MST")}}</p>
+            {{if .Extras.Regression.Empty}}
+                <p>{{t "dash_no_regression"}}</p>
+            {{else}}
+            <table class="results-table">
+                <tbody>
+                    {{range .Extras.Regression.NewFailures}}
+                    <tr><td><span class="status-badge status-error">{{t "new_failure"}}</span></td><td>{{.}}</td><td></td></tr>
+                    {{end}}
+                    {{range .Extras.Regression.Recovered}}
+                    <tr><td><span class="status-badge status-success">{{t "recovered"}}</span></td><td>{{.}}</td><td></td></tr>
+                    {{end}}
+                    {{range .Extras.Regression.LatencyChanges}}
+                    <tr>
+                        <td>
+                            {{if gt .ChangePercent 0.0}}
+                                <span class="status-badge status-degraded">{{t "response_time_change" .ChangePercent}}</span>
+                            {{else}}
+                                <span class="status-badge status-success">{{t "response_time_change" .ChangePercent}}</span>
+                            {{end}}
+                        </td>
+                        <td>{{.URL}}</td>
+                        <td>{{printf "%.0f" .PreviousMs}}ms → {{printf "%.0f" .CurrentMs}}ms</td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+            {{end}}
         </div>
+        {{end}}
 
         <div class="charts-grid">
             <div class="chart-card">
-                <h3>ステータスコード分布</h3>
+                <h3>{{t "status_code_distribution"}}</h3>
                 <canvas id="statusChart"></canvas>
             </div>
             <div class="chart-card">
-                <h3>応答時間分布</h3>
+                <h3>{{t "response_time_distribution"}}</h3>
                 <canvas id="responseTimeChart"></canvas>
             </div>
             <div class="chart-card">
-                <h3>レイテンシ分布</h3>
+                <h3>{{t "latency_distribution"}}</h3>
                 <canvas id="latencyChart"></canvas>
             </div>
         </div>
 
         <div class="results-section">
-            <h2>詳細結果</h2>
+            <h2>{{t "dash_details"}}</h2>
             <table class="results-table">
                 <thead>
                     <tr>
                         <th>URL</th>
-                        <th>ステータス</th>
-                        <th>ステータスコード</th>
-                        <th>応答時間</th>
-                        <th>レイテンシ</th>
-                        <th>エラー</th>
+                        <th>{{t "status"}}</th>
+                        <th>{{t "status_code"}}</th>
+                        <th>{{t "response_time"}}</th>
+                        <th>{{t "latency"}}</th>
+                        <th>{{t "error"}}</th>
+                        {{if $.Extras.TargetIDs}}<th></th>{{end}}
                     </tr>
                 </thead>
                 <tbody>
                     {{range .Results}}
-                    <tr>
-                        <td>{{.URL}}</td>
+                    {{$ack := false}}{{if not .Success}}{{$ack = .Acknowledgment}}{{if $.Extras.Live}}{{$ack = index $.Extras.Acks .URL}}{{end}}{{end}}
+                    <tr{{if $ack}} class="acknowledged"{{end}}>
+                        <td>{{.URL}}
+                            {{if or .IPInfo .ResolvedIPs}}
+                                <details class="snippet">
+                                    <summary>{{t "ip_info"}}</summary>
+                                    <pre>{{with .IPInfo}}{{t "ip_address"}}: {{.IP}}
+{{if .Hostnames}}{{t "reverse_dns"}}: {{range $i, $name := .Hostnames}}{{if $i}}, {{end}}{{$name}}{{end}}
+{{end}}{{if or .Country .City}}{{t "location"}}: {{.Country}}{{if .City}} / {{.City}}{{end}}
+{{end}}{{if .ASN}}AS: AS{{.ASN}}{{if .ASOrg}} ({{.ASOrg}}){{end}}
+{{end}}{{end}}{{if .ResolvedIPs}}{{t "resolved_ips"}}: {{range $i, $ip := .ResolvedIPs}}{{if $i}}, {{end}}{{$ip}}{{end}}{{end}}</pre>
+                                </details>
+                            {{end}}
+                            {{with .Browser}}
+                                <details class="snippet">
+                                    <summary>{{t "browser_metrics"}}</summary>
+                                    <pre>DOMContentLoaded: {{printf "%.0f" (ms .DOMContentLoaded)}}ms / onload: {{printf "%.0f" (ms .Load)}}ms{{if .NetworkIdle}} / {{t "network_idle"}}: {{printf "%.0f" (ms .NetworkIdle)}}ms{{end}}
+{{t "page_requests"}}: {{.Requests}}{{if .ConsoleErrors}}
+{{t "console_errors"}}:{{range .ConsoleErrors}}
+  {{.}}{{end}}{{end}}{{if .FailedResources}}
+{{t "failed_resources"}}:{{range .FailedResources}}
+  {{.URL}} ({{if .Status}}HTTP {{.Status}}{{else}}{{.Error}}{{end}}){{end}}{{end}}</pre>
+                                </details>
+                            {{end}}
+                            {{with $ack}}{{if or .Note (not .Expires.IsZero)}}
+                                <div class="hint">📝 {{.Note}}{{if not .Expires.IsZero}} ({{t "acknowledged_until" (datetime .Expires "2006-01-02 15:04 MST")}}){{end}}</div>
+                            {{end}}{{end}}
+                            {{with .Resources}}{{if .Issues}}
+                                <details class="snippet">
+                                    <summary>{{t "resource_scan" .Checked}}</summary>
+                                    <pre>{{range .Issues}}{{.URL}} ({{if .Insecure}}{{t "insecure_resource"}}{{else if .Status}}HTTP {{.Status}}{{else}}{{.Error}}{{end}})
+{{end}}</pre>
+                                </details>
+                            {{end}}{{end}}
+                        </td>
                         <td>
-                            {{if .Success}}
-                                <span class="status-badge status-success">成功</span>
+                            {{if .Degraded}}
+                                <span class="status-badge status-degraded" title="{{.DegradedMessage}}">{{t "degraded"}}</span>
+                            {{else if .Success}}
+                                <span class="status-badge status-success">{{t "success"}}</span>
                             {{else if and (ge .StatusCode 300) (lt .StatusCode 400)}}
-                                <span class="status-badge status-redirect">リダイレクト</span>
+                                <span class="status-badge status-redirect">{{t "redirect"}}</span>
                             {{else}}
-                                <span class="status-badge status-error">失敗</span>
+                                <span class="status-badge status-error">{{t "failure"}}</span>
+                            {{end}}
+                            {{if .Flapping}}
+                                <span class="status-badge status-flapping">{{t "flapping"}}</span>
+                            {{end}}
+                            {{with $ack}}
+                                <span class="status-badge status-acknowledged" title="{{.Note}}{{if .By}} ({{.By}}){{end}}">{{t "acknowledged"}}</span>
                             {{end}}
                         </td>
                         <td>{{.StatusCode}}</td>
@@ -233,22 +450,107 @@ func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statist
                                 {{if .ErrorMessage}}
                                     <div class="error-message">{{.ErrorMessage}}</div>
                                 {{end}}
+                                {{if .Hint}}
+                                    <div class="hint">💡 {{.Hint}}</div>
+                                {{end}}
+                                {{if .DependencyDown}}
+                                    <div class="hint">🔗 {{t "dependency_down" .DependencyDown}}</div>
+                                {{end}}
+                                {{if or .Snippet .ResponseHeaders}}
+                                    <details class="snippet">
+                                        <summary>{{t "response_body"}}</summary>
+                                        <pre>{{range $name, $value := .ResponseHeaders}}{{$name}}: {{$value}}
+{{end}}{{if .Snippet}}
+{{.Snippet}}{{end}}</pre>
+                                    </details>
+                                {{end}}
+                            {{else if .Degraded}}
+                                <div class="error-message">{{.DegradedMessage}}</div>
                             {{else}}
                                 -
                             {{end}}
                         </td>
+                        {{if $.Extras.TargetIDs}}
+                        <td>
+                            {{with index $.Extras.TargetIDs .URL}}<button type="button" class="btn-small" data-check-now="{{.}}">{{t "check_now"}}</button>{{end}}
+                            {{if and (not .Success) (not $.Extras.Project)}}
+                                {{if $ack}}<button type="button" class="btn-small" data-unacknowledge="{{.URL}}">{{t "unacknowledge"}}</button>
+                                {{else}}<button type="button" class="btn-small" data-acknowledge="{{.URL}}">{{t "acknowledge"}}</button>{{end}}
+                            {{end}}
+                        </td>
+                        {{end}}
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+        </div>
+
+        {{if not .Extras.Shared}}
+        <div class="results-section">
+            <div class="section-header">
+                <h2>{{t "dash_uptime_slo" .Extras.SLOTarget}}</h2>
+                <select id="slaWindow">
+                    <option value="24h"{{if eq .Extras.SLAWindow "24h"}} selected{{end}}>{{t "window_24h"}}</option>
+                    <option value="7d"{{if eq .Extras.SLAWindow "7d"}} selected{{end}}>{{t "window_7d"}}</option>
+                    <option value="30d"{{if eq .Extras.SLAWindow "30d"}} selected{{end}}>{{t "window_30d"}}</option>
+                </select>
+            </div>
+            {{if .Extras.SLA}}
+            <table class="results-table">
+                <thead>
+                    <tr>
+                        <th>URL</th>
+                        <th>{{t "uptime"}}</th>
+                        <th>{{t "checks"}}</th>
+                        <th>{{t "failures"}}</th>
+                        <th>{{t "downtime"}}</th>
+                        <th>{{t "error_budget_burned"}}</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .Extras.SLA}}
+                    <tr>
+                        <td>{{.URL}}</td>
+                        <td>{{printf "%.3f" .UptimePercent}}%</td>
+                        <td>{{.Checks}}</td>
+                        <td>{{.Failures}}</td>
+                        <td>{{t "minutes" .DowntimeMinutes}}</td>
+                        <td>
+                            <div class="budget-bar"><div class="{{if ge .ErrorBudgetBurned 100.0}}over{{end}}" style="width: {{budgetWidth .ErrorBudgetBurned}}%"></div></div>
+                            {{printf "%.1f" .ErrorBudgetBurned}}%
+                        </td>
                     </tr>
                     {{end}}
                 </tbody>
             </table>
+            {{else}}
+            <p>{{t "no_history_in_window"}}</p>
+            {{end}}
         </div>
+        {{end}}
 
+        {{if not (or .Extras.Project .Extras.Shared)}}
         <div class="actions">
-            <a href="/" class="btn">新しいチェック</a>
+            <a href="/" class="btn">{{t "new_check"}}</a>
+            <a href="/calendar" class="btn">{{t "nav_calendar"}}</a>
+            <a href="/explorer" class="btn">{{t "nav_explorer"}}</a>
+            <a href="/patterns" class="btn">{{t "nav_patterns"}}</a>
+            <a href="/heatmap" class="btn">{{t "nav_heatmap"}}</a>
+            <a href="/history" class="btn">{{t "nav_history"}}</a>
         </div>
+        {{end}}
     </div>
 
     <script>
+        {{if not .Extras.Shared}}
+        // 稼働率の集計期間を切り替え
+        document.getElementById('slaWindow').addEventListener('change', function(e) {
+            const params = new URLSearchParams(window.location.search);
+            params.set('window', e.target.value);
+            window.location.search = params.toString();
+        });
+        {{end}}
+
         const results = {{.ResultsJSON}};
         const statistics = {{.StatisticsJSON}};
 
@@ -280,30 +582,19 @@ func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statist
             }
         });
 
-        // 応答時間分布
-        const responseTimes = results.filter(r => r.success).map(r => r.response_time_ms);
-        if (responseTimes.length > 0) {
-            const bins = 10;
-            const min = Math.min(...responseTimes);
-            const max = Math.max(...responseTimes);
-            const binSize = (max - min) / bins;
-            const histogram = new Array(bins).fill(0);
-            
-            responseTimes.forEach(rt => {
-                const bin = Math.min(Math.floor((rt - min) / binSize), bins - 1);
-                histogram[bin]++;
-            });
-
-            new Chart(document.getElementById('responseTimeChart'), {
+        // 応答時間・レイテンシの分布（区間と件数はサーバーで計算したヒストグラム）
+        function drawHistogram(id, histogram, label, color) {
+            if (!histogram || histogram.count === 0) {
+                return;
+            }
+            new Chart(document.getElementById(id), {
                 type: 'bar',
                 data: {
-                    labels: Array.from({length: bins}, (_, i) => 
-                        Math.round(min + i * binSize) + 'ms'
-                    ),
+                    labels: histogram.labels,
                     datasets: [{
-                        label: '応答時間',
-                        data: histogram,
-                        backgroundColor: '#3b82f6'
+                        label: label,
+                        data: histogram.counts,
+                        backgroundColor: color
                     }]
                 },
                 options: {
@@ -316,59 +607,114 @@ func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statist
                 }
             });
         }
+        drawHistogram('responseTimeChart', statistics.response_time_histogram, {{t "response_time"}}, '#3b82f6');
+        drawHistogram('latencyChart', statistics.latency_histogram, {{t "latency"}}, '#10b981');
+    </script>
+    {{if .Extras.Live}}
+    <script>
+        var liveBase = {{.Extras.BasePath}};` + LiveScript + `
+        // 定期チェックが完了したら最新の結果を再表示し、アラートは見出しに表示する
+        connectLive(function(event) {
+            if (event.type === 'run_finished') {
+                location.reload();
+            } else if (event.type === 'alert') {
+                document.getElementById('liveNotice').textContent =
+                    '🔔 ' + event.data.kind + ' ' + (event.data.url || '') + ' (' + formatTime(event.timestamp) + ')';
+            }
+        }, function(connected) {
+            if (!connected) {
+                document.getElementById('liveNotice').textContent = '⚪ ' + {{t "reconnecting"}};
+            }
+        });
 
-        // レイテンシ分布
-        const latencies = results.filter(r => r.success).map(r => r.latency_ms);
-        if (latencies.length > 0) {
-            const bins = 10;
-            const min = Math.min(...latencies);
-            const max = Math.max(...latencies);
-            const binSize = (max - min) / bins;
-            const histogram = new Array(bins).fill(0);
-            
-            latencies.forEach(lat => {
-                const bin = Math.min(Math.floor((lat - min) / binSize), bins - 1);
-                histogram[bin]++;
+        // 定期チェックの一時停止・再開（完了したら再表示）と、対象を指定したすぐのチェック（結果をボタンの横に表示）
+        function postAction(button, path, done, init) {
+            button.disabled = true;
+            fetch(path, init || {method: 'POST'}).then(function(res) {
+                if (!res.ok) {
+                    return res.text().then(function(text) { throw new Error(text); });
+                }
+                return res.json();
+            }).then(function(data) {
+                button.disabled = false;
+                done(data);
+            }).catch(function(err) {
+                button.disabled = false;
+                alert({{t "action_failed"}} + err.message);
             });
-
-            new Chart(document.getElementById('latencyChart'), {
-                type: 'bar',
-                data: {
-                    labels: Array.from({length: bins}, (_, i) => 
-                        Math.round(min + i * binSize) + 'ms'
-                    ),
-                    datasets: [{
-                        label: 'レイテンシ',
-                        data: histogram,
-                        backgroundColor: '#10b981'
-                    }]
-                },
-                options: {
-                    responsive: true,
-                    scales: {
-                        y: {
-                            beginAtZero: true
-                        }
+        }
+        document.querySelectorAll('[data-scheduler]').forEach(function(button) {
+            button.addEventListener('click', function() {
+                postAction(button, liveBase + '/api/scheduler/' + button.dataset.scheduler, function() {
+                    location.reload();
+                });
+            });
+        });
+        document.querySelectorAll('[data-check-now]').forEach(function(button) {
+            button.addEventListener('click', function() {
+                postAction(button, liveBase + '/api/targets/' + encodeURIComponent(button.dataset.checkNow) + '/check-now', function(data) {
+                    const r = (data.results || [])[0];
+                    let span = button.nextElementSibling;
+                    if (!span) {
+                        span = document.createElement('span');
+                        button.parentNode.appendChild(span);
                     }
+                    span.className = 'status-badge ' + (r && r.success ? 'status-success' : 'status-error');
+                    span.textContent = !r ? '-' : r.success
+                        ? '✓ ' + r.status_code + ' ' + Math.round(r.response_time_ms) + 'ms'
+                        : '✗ ' + (r.error || r.status_code);
+                    span.title = formatTime(r ? r.timestamp : Date.now());
+                });
+            });
+        });
+        // 失敗している対象の確認（メモと期限を入力）と取り消し（完了したら再表示）
+        document.querySelectorAll('[data-acknowledge]').forEach(function(button) {
+            button.addEventListener('click', function() {
+                const note = prompt({{t "acknowledge_note"}});
+                if (note === null) {
+                    return;
+                }
+                const expires = prompt({{t "acknowledge_expires"}}, '');
+                if (expires === null) {
+                    return;
                 }
+                const body = new URLSearchParams({target: button.dataset.acknowledge, note: note, expires: expires});
+                postAction(button, '/api/acks', function() {
+                    location.reload();
+                }, {method: 'POST', body: body});
             });
-        }
+        });
+        document.querySelectorAll('[data-unacknowledge]').forEach(function(button) {
+            button.addEventListener('click', function() {
+                postAction(button, '/api/acks?target=' + encodeURIComponent(button.dataset.unacknowledge), function() {
+                    location.reload();
+                }, {method: 'DELETE'});
+            });
+        });
     </script>
+    {{end}}
 </body>
 </html>`
 
 	data := struct {
+		Lang          string
 		Timestamp     string
 		Results       []*checker.CheckResult
 		ResultsJSON   template.JS
 		Statistics    *stats.Statistics
 		StatisticsJSON template.JS
 		HistoryPath   string
+		Extras        Extras
 	}{
-		Timestamp:  time.Now().Format("2006-01-02 15:04:05"),
+		Lang:       lang,
+		Timestamp:  i18n.FormatTime(time.Now()),
 		Results:    results,
 		Statistics: statistics,
 		HistoryPath: historyPath,
+		Extras:      extras,
+	}
+	if !extras.RunAt.IsZero() {
+		data.Timestamp = i18n.FormatTime(extras.RunAt)
 	}
 
 	// JSON形式でデータを埋め込む（ミリ秒単位に変換）
@@ -380,6 +726,7 @@ func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statist
 		Latency      float64 `json:"latency_ms"`
 		Error        string  `json:"error,omitempty"`
 		ErrorMessage string  `json:"error_message,omitempty"`
+		Degraded     bool    `json:"degraded,omitempty"`
 	}
 	
 	var resultsJSONData []ResultJSON
@@ -390,8 +737,9 @@ func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statist
 			Success:      r.Success,
 			ResponseTime: r.ResponseTimeMs(),
 			Latency:      r.LatencyMs(),
-			Error:        r.Error,
+			Error:        string(r.Error),
 			ErrorMessage: r.ErrorMessage,
+			Degraded:     r.Degraded,
 		})
 	}
 	
@@ -402,7 +750,12 @@ func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statist
 		SuccessRate     float64 `json:"success_rate"`
 		AvgResponseTime float64 `json:"avg_response_time_ms"`
 		AvgLatency      float64 `json:"avg_latency_ms"`
+
+		ResponseTimeHistogram histogramJSON `json:"response_time_histogram"`
+		LatencyHistogram      histogramJSON `json:"latency_histogram"`
 	}
+
+	responseTimes, latencies := stats.Histograms(statistics, results)
 	
 	statsJSONData := StatsJSON{
 		TotalRequests:   statistics.TotalRequests,
@@ -411,6 +764,9 @@ func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statist
 		SuccessRate:     statistics.SuccessRate,
 		AvgResponseTime: statistics.AvgResponseTimeMs(),
 		AvgLatency:      statistics.AvgLatencyMs(),
+
+		ResponseTimeHistogram: newHistogramJSON(responseTimes),
+		LatencyHistogram:      newHistogramJSON(latencies),
 	}
 	
 	resultsJSON, _ := json.Marshal(resultsJSONData)
@@ -418,7 +774,18 @@ func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statist
 	data.ResultsJSON = template.JS(resultsJSON)
 	data.StatisticsJSON = template.JS(statsJSON)
 
-	t, err := template.New("dashboard").Parse(tmpl)
+	funcs := template.FuncMap{
+		// エラーバジェットのバーの幅（0〜100%）
+		"budgetWidth": func(burned float64) float64 {
+			if burned > 100 {
+				return 100
+			}
+			return burned
+		},
+		"ms": func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) },
+	}
+
+	t, err := template.New("dashboard").Funcs(PageFuncs(lang, extras.Theme)).Funcs(funcs).Parse(tmpl)
 	if err != nil {
 		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
 	}
@@ -430,3 +797,22 @@ func GenerateDashboard(results []*checker.CheckResult, statistics *stats.Statist
 
 	return buf.String()
 }
+
+// histogramJSON グラフに表示するヒストグラム（区間のラベルと件数）
+type histogramJSON struct {
+	Labels []string `json:"labels"`
+	Counts []int    `json:"counts"`
+	Count  int      `json:"count"`
+}
+
+// newHistogramJSON 区間の上限から「≤50ms」「>10000ms」の形式のラベルを付ける
+func newHistogramJSON(h *stats.Histogram) histogramJSON {
+	labels := make([]string, 0, len(h.Counts))
+	for _, b := range h.Bounds {
+		labels = append(labels, fmt.Sprintf("≤%gms", float64(b)/float64(time.Millisecond)))
+	}
+	if len(h.Bounds) > 0 {
+		labels = append(labels, fmt.Sprintf(">%gms", float64(h.Bounds[len(h.Bounds)-1])/float64(time.Millisecond)))
+	}
+	return histogramJSON{Labels: labels, Counts: h.Counts, Count: h.Count}
+}
@@ -0,0 +1,182 @@
+package dashboard
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"healthcheck/internal/i18n"
+)
+
+// スパークラインの大きさ（px）
+const (
+	sparklineWidth  = 120
+	sparklineHeight = 24
+)
+
+// EmbedWidget 外部のページにiframeで埋め込む対象またはグループの状態
+type EmbedWidget struct {
+	Name        string        `json:"name"`
+	State       string        `json:"state"` // 対象の状態のうち最も悪いもの（up / down / degraded / flapping / unknown）
+	Targets     []EmbedTarget `json:"targets"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Language    string        `json:"-"` // 表示言語（ja / en、空の場合はデフォルト言語）
+	Theme       Theme         `json:"-"` // 表示モードとアクセントカラー
+}
+
+// EmbedTarget ウィジェットに表示する対象ごとの状態と直近の応答時間
+type EmbedTarget struct {
+	Name           string       `json:"name"`
+	State          string       `json:"state"`
+	ResponseTimeMs float64      `json:"response_time_ms,omitempty"` // 最新のチェック結果の応答時間
+	LastChecked    time.Time    `json:"last_checked,omitzero"`
+	History        []EmbedPoint `json:"history"` // 直近のチェック結果（古い順）
+}
+
+// EmbedPoint スパークラインの1点
+type EmbedPoint struct {
+	Timestamp      time.Time `json:"timestamp"`
+	ResponseTimeMs float64   `json:"response_time_ms"`
+	Success        bool      `json:"success"`
+}
+
+// GenerateEmbed 見出しやナビゲーションのない、iframeに埋め込むための状態のウィジェットを生成
+// 1分ごとに再読み込みして最新の状態を表示する
+func GenerateEmbed(widget EmbedWidget) string {
+	lang := pageLanguage(widget.Language)
+	widget.Language = lang
+	tmpl := `<!DOCTYPE html>
+<html lang="{{.Language}}" data-theme="{{themeMode}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <meta http-equiv="refresh" content="60">
+    <title>{{.Name}}</title>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            background: transparent;
+            color: #333;
+            font-size: 13px;
+            padding: 8px;
+        }
+        .target {
+            display: flex;
+            align-items: center;
+            gap: 8px;
+            padding: 4px 0;
+            border-bottom: 1px solid #e5e5e5;
+        }
+        .target:last-child { border-bottom: none; }
+        .dot {
+            width: 10px;
+            height: 10px;
+            border-radius: 50%;
+            flex-shrink: 0;
+        }
+        .dot.up { background: #10b981; }
+        .dot.down { background: #ef4444; }
+        .dot.degraded { background: #f59e0b; }
+        .dot.flapping { background: #9333ea; }
+        .dot.unknown { background: #999; }
+        .name {
+            flex: 1;
+            overflow: hidden;
+            text-overflow: ellipsis;
+            white-space: nowrap;
+        }
+        .state-up { color: #10b981; }
+        .state-down { color: #ef4444; }
+        .state-degraded { color: #f59e0b; }
+        .state-flapping { color: #9333ea; }
+        .state-unknown { color: #999; }
+        .latency {
+            color: #999;
+            min-width: 50px;
+            text-align: right;
+        }
+        .sparkline polyline { fill: none; stroke: #667eea; stroke-width: 1.5; }
+        .sparkline circle { fill: #ef4444; }
+        .footer {
+            color: #999;
+            font-size: 11px;
+            margin-top: 4px;
+        }
+    </style>
+    {{themeStyle}}
+</head>
+<body>
+    {{range .Targets}}
+    <div class="target">
+        <span class="dot {{.State}}"></span>
+        <span class="name" title="{{.Name}}">{{.Name}}</span>
+        <span class="state-{{.State}}">{{stateLabel .State}}</span>
+        <span class="latency">{{if .ResponseTimeMs}}{{printf "%.0f" .ResponseTimeMs}}ms{{else}}-{{end}}</span>
+        {{sparkline .History}}
+    </div>
+    {{end}}
+    <p class="footer">{{t "last_updated" (datetime .GeneratedAt "2006-01-02 15:04 MST")}}</p>
+</body>
+</html>`
+
+	funcs := template.FuncMap{
+		"stateLabel": func(state string) string {
+			switch state {
+			case "up", "down", "degraded", "flapping":
+				return i18n.T(lang, "state_"+state)
+			}
+			return i18n.T(lang, "state_unknown")
+		},
+		"sparkline": sparkline,
+	}
+
+	t, err := template.New("embed").Funcs(PageFuncs(lang, widget.Theme)).Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, widget); err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	return buf.String()
+}
+
+// sparkline 応答時間の推移を折れ線で描いたSVG（失敗したチェックは下端に赤い点で示す）
+func sparkline(points []EmbedPoint) template.HTML {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg class="sparkline" width="%d" height="%d" viewBox="0 0 %d %d">`, sparklineWidth, sparklineHeight, sparklineWidth, sparklineHeight)
+
+	var max float64
+	for _, p := range points {
+		if p.Success && p.ResponseTimeMs > max {
+			max = p.ResponseTimeMs
+		}
+	}
+	step := float64(sparklineWidth)
+	if len(points) > 1 {
+		step = float64(sparklineWidth-4) / float64(len(points)-1)
+	}
+
+	var line []string
+	for i, p := range points {
+		x := 2 + float64(i)*step
+		if !p.Success {
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%d" r="2"/>`, x, sparklineHeight-2)
+			continue
+		}
+		y := float64(sparklineHeight - 2)
+		if max > 0 {
+			y -= p.ResponseTimeMs / max * float64(sparklineHeight-4)
+		}
+		line = append(line, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	if len(line) > 0 {
+		fmt.Fprintf(&b, `<polyline points="%s"/>`, strings.Join(line, " "))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
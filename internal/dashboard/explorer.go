@@ -0,0 +1,247 @@
+package dashboard
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"healthcheck/internal/i18n"
+)
+
+// GenerateExplorer 保存された結果を集計する結果エクスプローラーを生成
+func GenerateExplorer(dimensions []string, lang string, theme Theme) string {
+	lang = pageLanguage(lang)
+	tmpl := `<!DOCTYPE html>
+<html lang="{{.Lang}}" data-theme="{{themeMode}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{pageTitle "Health Check Explorer"}}</title>
+    <script src="https://cdn.jsdelivr.net/npm/chart.js@3.9.1/dist/chart.min.js"></script>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            background: #f5f5f5;
+            padding: 20px;
+        }
+        .container {
+            max-width: 1400px;
+            margin: 0 auto;
+        }
+        .header {
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            padding: 30px;
+            border-radius: 10px;
+            margin-bottom: 20px;
+            box-shadow: 0 5px 15px rgba(0,0,0,0.1);
+        }
+        .header h1 {
+            font-size: 2em;
+            margin-bottom: 10px;
+        }
+        .card {
+            background: white;
+            padding: 20px;
+            border-radius: 8px;
+            box-shadow: 0 2px 5px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+        .controls {
+            display: flex;
+            gap: 20px;
+            align-items: center;
+        }
+        .controls input,
+        .controls select {
+            padding: 8px;
+            border: 2px solid #e0e0e0;
+            border-radius: 5px;
+            font-size: 14px;
+        }
+        .results-table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+        .results-table th,
+        .results-table td {
+            padding: 12px;
+            text-align: left;
+            border-bottom: 1px solid #e5e5e5;
+        }
+        .results-table th {
+            background: #f9fafb;
+            font-weight: 600;
+            color: #666;
+        }
+        .actions {
+            text-align: center;
+            margin-top: 30px;
+        }
+        .btn {
+            display: inline-block;
+            padding: 12px 24px;
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            text-decoration: none;
+            border-radius: 5px;
+            font-weight: 600;
+            margin: 0 10px;
+        }
+    </style>
+    {{themeStyle}}
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            {{themeSwitch}}
+            {{langSwitch}}
+            {{brand}}
+            <h1>🔎 Result Explorer</h1>
+            <p>{{t "explorer_description"}}</p>
+        </div>
+
+        <div class="card controls">
+            <label>{{t "explorer_group_by"}}
+                <select id="groupBy">
+                    {{range .Dimensions}}
+                    <option value="{{.}}">{{dimensionLabel .}}</option>
+                    {{end}}
+                </select>
+            </label>
+            <label>{{t "explorer_window"}}
+                <select id="window">
+                    <option value="all">{{t "window_all"}}</option>
+                    <option value="24h">{{t "window_24h"}}</option>
+                    <option value="7d">{{t "window_7d"}}</option>
+                    <option value="30d">{{t "window_30d"}}</option>
+                </select>
+            </label>
+            <label>{{t "explorer_tag"}}
+                <input type="text" id="tag" placeholder="team=payments">
+            </label>
+            <span id="total"></span>
+        </div>
+
+        <div class="card">
+            <canvas id="groupChart" height="100"></canvas>
+        </div>
+
+        <div class="card">
+            <table class="results-table">
+                <thead>
+                    <tr>
+                        <th>{{t "value"}}</th>
+                        <th>{{t "count"}}</th>
+                        <th>{{t "success"}}</th>
+                        <th>{{t "failure"}}</th>
+                        <th>{{t "success_rate"}}</th>
+                        <th>{{t "avg_response_time"}}</th>
+                        <th>{{t "p95_response_time"}}</th>
+                        <th>{{t "max_response_time"}}</th>
+                    </tr>
+                </thead>
+                <tbody id="groupRows"></tbody>
+            </table>
+        </div>
+
+        <div class="actions">
+            <a href="/" class="btn">{{t "new_check"}}</a>
+        </div>
+    </div>
+
+    <script>
+        let chart = null;
+        const ms = ns => Math.round(ns / 1e6);
+
+        async function explore() {
+            const params = new URLSearchParams({
+                group_by: document.getElementById('groupBy').value,
+                window: document.getElementById('window').value
+            });
+            document.getElementById('tag').value.split(',').map(t => t.trim()).filter(t => t).forEach(t => params.append('tag', t));
+            const response = await fetch('/api/explore?' + params.toString());
+            if (!response.ok) {
+                alert({{t "error_prefix"}} + await response.text());
+                return;
+            }
+            const data = await response.json();
+            const groups = data.groups || [];
+
+            document.getElementById('total').textContent = data.total + {{t "results_unit"}};
+
+            const rows = document.getElementById('groupRows');
+            rows.innerHTML = '';
+            groups.forEach(g => {
+                const tr = document.createElement('tr');
+                [g.key, g.count, g.success_count, g.failure_count, g.success_rate.toFixed(1) + '%',
+                 ms(g.avg_response_time_ms) + 'ms', ms(g.p95_response_time_ms) + 'ms', ms(g.max_response_time_ms) + 'ms'
+                ].forEach(v => {
+                    const td = document.createElement('td');
+                    td.textContent = v;
+                    tr.appendChild(td);
+                });
+                rows.appendChild(tr);
+            });
+
+            if (chart) {
+                chart.destroy();
+            }
+            chart = new Chart(document.getElementById('groupChart'), {
+                type: 'bar',
+                data: {
+                    labels: groups.map(g => g.key),
+                    datasets: [
+                        { label: {{t "success"}}, data: groups.map(g => g.success_count), backgroundColor: '#10b981' },
+                        { label: {{t "failure"}}, data: groups.map(g => g.failure_count), backgroundColor: '#ef4444' },
+                        { label: {{t "avg_response_time_ms"}}, data: groups.map(g => ms(g.avg_response_time_ms)), type: 'line', borderColor: '#3b82f6', yAxisID: 'ms' }
+                    ]
+                },
+                options: {
+                    responsive: true,
+                    scales: {
+                        x: { stacked: true },
+                        y: { stacked: true, beginAtZero: true },
+                        ms: { position: 'right', beginAtZero: true, grid: { drawOnChartArea: false } }
+                    }
+                }
+            });
+        }
+
+        document.getElementById('groupBy').addEventListener('change', explore);
+        document.getElementById('window').addEventListener('change', explore);
+        document.getElementById('tag').addEventListener('change', explore);
+        explore();
+    </script>
+</body>
+</html>`
+
+	funcs := template.FuncMap{
+		"dimensionLabel": func(d string) string {
+			switch d {
+			case "domain", "status_class", "url", "error", "hour", "region":
+				return i18n.T(lang, "dimension_"+d)
+			}
+			if key, ok := strings.CutPrefix(d, "tag:"); ok {
+				return i18n.T(lang, "dimension_tag", key)
+			}
+			return d
+		},
+	}
+
+	t, err := template.New("explorer").Funcs(PageFuncs(lang, theme)).Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, struct {
+		Lang       string
+		Dimensions []string
+	}{lang, dimensions}); err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	return buf.String()
+}
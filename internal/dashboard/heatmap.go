@@ -0,0 +1,304 @@
+package dashboard
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// GenerateHeatmap 対象×時間枠のレイテンシと失敗を色で表すヒートマップのページを生成
+func GenerateHeatmap(lang string, theme Theme) string {
+	lang = pageLanguage(lang)
+	tmpl := `<!DOCTYPE html>
+<html lang="{{.Lang}}" data-theme="{{themeMode}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{pageTitle "Health Check Heatmap"}}</title>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            background: #f5f5f5;
+            padding: 20px;
+        }
+        .container {
+            max-width: 1400px;
+            margin: 0 auto;
+        }
+        .header {
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            padding: 30px;
+            border-radius: 10px;
+            margin-bottom: 20px;
+            box-shadow: 0 5px 15px rgba(0,0,0,0.1);
+        }
+        .header h1 {
+            font-size: 2em;
+            margin-bottom: 10px;
+        }
+        .card {
+            background: white;
+            padding: 20px;
+            border-radius: 8px;
+            box-shadow: 0 2px 5px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+        .card h3 {
+            margin-bottom: 15px;
+            color: #333;
+        }
+        .card select {
+            padding: 8px;
+            border: 2px solid #e0e0e0;
+            border-radius: 5px;
+            font-size: 14px;
+        }
+        .controls {
+            display: flex;
+            gap: 20px;
+            align-items: center;
+        }
+        .heatmap {
+            overflow-x: auto;
+        }
+        .heatmap table {
+            border-collapse: separate;
+            border-spacing: 2px;
+            font-size: 12px;
+        }
+        .heatmap th {
+            font-weight: normal;
+            color: #666;
+            text-align: left;
+            white-space: nowrap;
+        }
+        .heatmap th.target {
+            position: sticky;
+            left: 0;
+            max-width: 320px;
+            overflow: hidden;
+            text-overflow: ellipsis;
+            padding-right: 10px;
+            background: white;
+        }
+        .heatmap td {
+            width: 12px;
+            min-width: 12px;
+            height: 20px;
+            border-radius: 2px;
+        }
+        .legend {
+            display: flex;
+            gap: 15px;
+            margin-top: 15px;
+            font-size: 13px;
+            color: #666;
+        }
+        .legend span::before {
+            content: "";
+            display: inline-block;
+            width: 12px;
+            height: 12px;
+            border-radius: 2px;
+            margin-right: 5px;
+            vertical-align: middle;
+            background: var(--swatch);
+        }
+        .empty {
+            color: #999;
+        }
+        .actions {
+            text-align: center;
+            margin-top: 30px;
+        }
+        .btn {
+            display: inline-block;
+            padding: 12px 24px;
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            text-decoration: none;
+            border-radius: 5px;
+            font-weight: 600;
+            margin: 0 10px;
+        }
+    </style>
+    {{themeStyle}}
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            {{themeSwitch}}
+            {{langSwitch}}
+            {{brand}}
+            <h1>{{t "heatmap_title"}}</h1>
+            <p>{{t "heatmap_description"}}</p>
+        </div>
+
+        <div class="card controls">
+            <label>{{t "explorer_window"}}
+                <select id="window">
+                    <option value="24h">{{t "window_24h"}}</option>
+                    <option value="7d" selected>{{t "window_7d"}}</option>
+                    <option value="30d">{{t "window_30d"}}</option>
+                </select>
+            </label>
+            <label>{{t "heatmap_bucket"}}
+                <select id="bucket">
+                    <option value="">{{t "heatmap_bucket_auto"}}</option>
+                    <option value="5m">5m</option>
+                    <option value="15m">15m</option>
+                    <option value="30m">30m</option>
+                    <option value="1h">1h</option>
+                    <option value="3h">3h</option>
+                    <option value="6h">6h</option>
+                    <option value="24h">24h</option>
+                </select>
+            </label>
+            <label>{{t "heatmap_color_by"}}
+                <select id="colorBy">
+                    <option value="latency">{{t "heatmap_by_latency"}}</option>
+                    <option value="status">{{t "heatmap_by_status"}}</option>
+                </select>
+            </label>
+        </div>
+
+        <div class="card">
+            <div class="heatmap" id="heatmap"></div>
+            <div class="legend" id="legend"></div>
+        </div>
+
+        <div class="actions">
+            <a href="/" class="btn">{{t "new_check"}}</a>
+        </div>
+    </div>
+
+    <script>
+        const colors = {
+            none: '#e5e5e5',
+            normal: '#10b981',
+            slow: '#facc15',
+            verySlow: '#f97316',
+            extreme: '#b91c1c',
+            partial: '#f59e0b',
+            failure: '#ef4444'
+        };
+        const legends = {
+            latency: [['normal', {{t "heatmap_legend_normal"}}], ['slow', {{t "heatmap_legend_slow"}}], ['verySlow', {{t "heatmap_legend_very_slow"}}], ['extreme', {{t "heatmap_legend_extreme"}}], ['failure', {{t "failure"}}], ['none', {{t "no_data"}}]],
+            status: [['normal', {{t "success"}}], ['partial', {{t "heatmap_legend_partial"}}], ['failure', {{t "failure"}}], ['none', {{t "no_data"}}]]
+        };
+        let data = null;
+
+        // 時間枠の色（レイテンシは対象の中央値との比、成功・失敗は失敗の割合で分ける）
+        function cellColor(cell, median, colorBy) {
+            if (cell.count === 0) {
+                return colors.none;
+            }
+            if (cell.failures === cell.count) {
+                return colors.failure;
+            }
+            if (colorBy === 'status') {
+                return cell.failures > 0 ? colors.partial : colors.normal;
+            }
+            const ratio = median > 0 ? cell.avg_latency_ms / median : 1;
+            if (ratio <= 1.25) {
+                return colors.normal;
+            }
+            if (ratio <= 2) {
+                return colors.slow;
+            }
+            return ratio <= 4 ? colors.verySlow : colors.extreme;
+        }
+
+        // 時間枠の見出し（日付が変わる枠と一定間隔の枠のみ表示）
+        function columnLabel(times, i) {
+            const at = new Date(times[i]);
+            const step = Math.max(1, Math.ceil(times.length / 24));
+            const newDay = i === 0 || new Date(times[i - 1]).getDate() !== at.getDate();
+            if (newDay) {
+                return (at.getMonth() + 1) + '/' + at.getDate();
+            }
+            return i % step === 0 ? at.getHours() + ':' + String(at.getMinutes()).padStart(2, '0') : '';
+        }
+
+        function render() {
+            const container = document.getElementById('heatmap');
+            const colorBy = document.getElementById('colorBy').value;
+            container.replaceChildren();
+            if (!data.targets || data.targets.length === 0) {
+                const p = document.createElement('p');
+                p.className = 'empty';
+                p.textContent = {{t "no_history_in_window"}};
+                container.appendChild(p);
+            } else {
+                const table = document.createElement('table');
+                const head = table.insertRow();
+                head.appendChild(document.createElement('th'));
+                data.times.forEach((_, i) => {
+                    const th = document.createElement('th');
+                    th.textContent = columnLabel(data.times, i);
+                    head.appendChild(th);
+                });
+                data.targets.forEach(target => {
+                    const row = table.insertRow();
+                    const th = document.createElement('th');
+                    th.className = 'target';
+                    th.textContent = target.url;
+                    th.title = target.url;
+                    row.appendChild(th);
+                    target.cells.forEach((cell, i) => {
+                        const td = row.insertCell();
+                        td.style.background = cellColor(cell, target.median_latency_ms, colorBy);
+                        td.title = new Date(data.times[i]).toLocaleString(undefined, {timeZone: {{timeZone}} || undefined}) + '\n' +
+                            {{t "checks"}} + ': ' + cell.count + '  ' + {{t "failures"}} + ': ' + cell.failures +
+                            (cell.count > cell.failures ? '\n' + {{t "avg_latency"}} + ': ' + Math.round(cell.avg_latency_ms / 1e6) + 'ms' : '');
+                    });
+                });
+                container.appendChild(table);
+            }
+
+            const legend = document.getElementById('legend');
+            legend.replaceChildren(...legends[colorBy].map(([key, label]) => {
+                const span = document.createElement('span');
+                span.style.setProperty('--swatch', colors[key]);
+                span.textContent = label;
+                return span;
+            }));
+        }
+
+        async function load() {
+            const params = new URLSearchParams({ window: document.getElementById('window').value });
+            const bucket = document.getElementById('bucket').value;
+            if (bucket) {
+                params.set('bucket', bucket);
+            }
+            const response = await fetch('/api/heatmap?' + params.toString());
+            if (!response.ok) {
+                alert({{t "error_prefix"}} + await response.text());
+                return;
+            }
+            data = await response.json();
+            render();
+        }
+
+        document.getElementById('window').addEventListener('change', load);
+        document.getElementById('bucket').addEventListener('change', load);
+        document.getElementById('colorBy').addEventListener('change', render);
+        load();
+    </script>
+</body>
+</html>`
+
+	t, err := template.New("heatmap").Funcs(PageFuncs(lang, theme)).Parse(tmpl)
+	if err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, struct{ Lang string }{lang}); err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,275 @@
+package dashboard
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HistoryRun 履歴の一覧に表示する1回分の実行
+type HistoryRun struct {
+	Timestamp       time.Time         `json:"timestamp"`
+	RunID           string            `json:"run_id,omitempty"`
+	Region          string            `json:"region,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	Total           int               `json:"total_requests"`
+	Failures        int               `json:"failure_count"`
+	SuccessRate     float64           `json:"success_rate"`
+	AvgResponseTime time.Duration     `json:"avg_response_time_ms"`
+}
+
+// historyFilter 絞り込み中のメタデータの条件と、その条件を外した一覧のURL
+type historyFilter struct {
+	Key    string
+	Value  string
+	Remove string
+}
+
+// GenerateHistory 実行の履歴の一覧（日時・実行ID・メタデータ・成功率）を生成
+// メタデータをクリックすると同じ値を持つ実行に絞り込み、デプロイとの対応を追えるようにする
+func GenerateHistory(runs []HistoryRun, filter map[string]string, lang string, theme Theme) string {
+	lang = pageLanguage(lang)
+	tmpl := `<!DOCTYPE html>
+<html lang="{{.Lang}}" data-theme="{{themeMode}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{pageTitle "Health Check History"}}</title>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            background: #f5f5f5;
+            padding: 20px;
+        }
+        .container {
+            max-width: 1200px;
+            margin: 0 auto;
+        }
+        .header {
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            padding: 30px;
+            border-radius: 10px;
+            margin-bottom: 20px;
+            box-shadow: 0 5px 15px rgba(0,0,0,0.1);
+        }
+        .header h1 {
+            font-size: 2em;
+            margin-bottom: 10px;
+        }
+        .card {
+            background: white;
+            padding: 20px;
+            border-radius: 8px;
+            box-shadow: 0 2px 5px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+        .card form {
+            display: flex;
+            gap: 10px;
+            align-items: center;
+            flex-wrap: wrap;
+        }
+        .card input {
+            padding: 8px;
+            border: 2px solid #e0e0e0;
+            border-radius: 5px;
+            font-size: 14px;
+            min-width: 260px;
+        }
+        .card button {
+            padding: 8px 16px;
+            border: none;
+            border-radius: 5px;
+            background: #667eea;
+            color: white;
+            cursor: pointer;
+        }
+        .results-table {
+            width: 100%;
+            border-collapse: collapse;
+            font-size: 14px;
+        }
+        .results-table th {
+            background: #f8f9fa;
+            padding: 10px;
+            text-align: left;
+            font-weight: 600;
+            color: #666;
+        }
+        .results-table td {
+            padding: 10px;
+            border-bottom: 1px solid #e5e5e5;
+            vertical-align: top;
+        }
+        .results-table tr:hover {
+            background: #f8f9fa;
+        }
+        .mono {
+            font-family: monospace;
+            font-size: 12px;
+        }
+        .meta-chip {
+            display: inline-block;
+            padding: 2px 8px;
+            margin: 0 4px 4px 0;
+            border-radius: 10px;
+            background: #eef2ff;
+            color: #3730a3;
+            font-size: 12px;
+            text-decoration: none;
+        }
+        .failed {
+            color: #dc2626;
+            font-weight: 600;
+        }
+        .empty {
+            color: #999;
+        }
+        .actions {
+            text-align: center;
+            margin-top: 30px;
+        }
+        .btn {
+            display: inline-block;
+            padding: 12px 24px;
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            text-decoration: none;
+            border-radius: 5px;
+            font-weight: 600;
+            margin: 0 10px;
+        }
+    </style>
+    {{themeStyle}}
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            {{themeSwitch}}
+            {{langSwitch}}
+            {{brand}}
+            <h1>{{t "history_title"}}</h1>
+            <p>{{t "history_description"}}</p>
+        </div>
+
+        <div class="card">
+            <form method="get" action="/history">
+                {{range .Filters}}<input type="hidden" name="meta" value="{{.Key}}={{.Value}}">{{end}}
+                <input type="text" name="meta" placeholder="deploy_sha=abc123">
+                <button type="submit">{{t "history_filter"}}</button>
+                {{range .Filters}}<a class="meta-chip" href="{{.Remove}}" title="{{t "history_remove_filter"}}">{{.Key}}={{.Value}} ✕</a>{{end}}
+            </form>
+        </div>
+
+        <div class="card">
+            {{if .Runs}}
+            <table class="results-table">
+                <thead>
+                    <tr>
+                        <th>{{t "history_time"}}</th>
+                        <th>{{t "history_run_id"}}</th>
+                        <th>{{t "history_metadata"}}</th>
+                        <th>{{t "history_targets"}}</th>
+                        <th>{{t "success_rate"}}</th>
+                        <th>{{t "avg_response_time"}}</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .Runs}}
+                    <tr>
+                        <td>{{datetime .Timestamp "2006-01-02 15:04:05 MST"}}{{if .Region}}<br><span class="mono">{{.Region}}</span>{{end}}</td>
+                        <td class="mono">{{.RunID}}</td>
+                        <td>{{range $key, $value := .Metadata}}<a class="meta-chip" href="{{filterURL $key $value}}">{{$key}}={{$value}}</a>{{end}}</td>
+                        <td>{{.Total}}{{if .Failures}} <span class="failed">({{t "history_failures" .Failures}})</span>{{end}}</td>
+                        <td>{{printf "%.1f" .SuccessRate}}%</td>
+                        <td>{{printf "%.0f" (ms .AvgResponseTime)}}ms</td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+            {{else}}
+            <p class="empty">{{t "history_empty"}}</p>
+            {{end}}
+        </div>
+
+        <div class="actions">
+            <a href="/" class="btn">{{t "new_check"}}</a>
+        </div>
+    </div>
+</body>
+</html>`
+
+	// 絞り込みの条件はキーの順に表示し、それぞれを外したURLを用意する
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	filters := make([]historyFilter, 0, len(keys))
+	for _, k := range keys {
+		rest := make(map[string]string, len(filter))
+		for key, value := range filter {
+			if key != k {
+				rest[key] = value
+			}
+		}
+		filters = append(filters, historyFilter{Key: k, Value: filter[k], Remove: historyURL(rest)})
+	}
+
+	data := struct {
+		Lang    string
+		Runs    []HistoryRun
+		Filters []historyFilter
+	}{
+		Lang:    lang,
+		Runs:    runs,
+		Filters: filters,
+	}
+
+	funcs := template.FuncMap{
+		"ms": func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) },
+		// 現在の条件にメタデータを加えて絞り込むURL
+		"filterURL": func(key, value string) string {
+			next := make(map[string]string, len(filter)+1)
+			for k, v := range filter {
+				next[k] = v
+			}
+			next[key] = value
+			return historyURL(next)
+		},
+	}
+
+	t, err := template.New("history").Funcs(PageFuncs(lang, theme)).Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, data); err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	return buf.String()
+}
+
+// historyURL メタデータの条件で絞り込んだ履歴の一覧のURL
+func historyURL(filter map[string]string) string {
+	if len(filter) == 0 {
+		return "/history"
+	}
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	q := url.Values{}
+	for _, k := range keys {
+		q.Add("meta", k+"="+filter[k])
+	}
+	return "/history?" + q.Encode()
+}
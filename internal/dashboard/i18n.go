@@ -0,0 +1,43 @@
+package dashboard
+
+import (
+	"html/template"
+	"strings"
+
+	"healthcheck/internal/i18n"
+)
+
+// pageLanguage ページの表示言語（未対応・未指定の場合はデフォルト言語）
+func pageLanguage(lang string) string {
+	if !i18n.Supported(lang) {
+		return i18n.DefaultLanguage
+	}
+	return lang
+}
+
+// LanguageSwitch 表示言語の切り替えリンク
+// 現在のクエリパラメータにlangを加えて再表示する（選択した言語はサーバーがクッキーに保存する）
+func LanguageSwitch(lang string) template.HTML {
+	var b strings.Builder
+	b.WriteString(`<p class="lang-switch" style="float: right; font-size: 13px;">`)
+	for i, l := range i18n.Languages {
+		if i > 0 {
+			b.WriteString(" | ")
+		}
+		name := template.HTMLEscapeString(i18n.Name(l))
+		if l == lang {
+			b.WriteString("<strong>" + name + "</strong>")
+			continue
+		}
+		b.WriteString(`<a href="?lang=` + l + `" data-lang="` + l + `" style="color: inherit;">` + name + `</a>`)
+	}
+	b.WriteString(`</p>
+    <script>
+        document.querySelectorAll('.lang-switch [data-lang]').forEach(function(a) {
+            const params = new URLSearchParams(location.search);
+            params.set('lang', a.dataset.lang);
+            a.search = '?' + params.toString();
+        });
+    </script>`)
+	return template.HTML(b.String())
+}
@@ -0,0 +1,26 @@
+package dashboard
+
+// LiveScript /wsに接続してイベントを受け取るスクリプト（切断時は再接続する）
+// ページ側でconnectLive(function(event) { ... })を呼び出して使う。liveBaseを定義した場合は{liveBase}/wsに接続する
+// PageFuncsのtimeZoneを使うため、PageFuncsを登録したテンプレートに埋め込む
+const LiveScript = `
+        // 時刻を表示するタイムゾーン（display_timezoneを指定していない場合はブラウザのタイムゾーン）で表示する
+        function formatTime(value) {
+            return new Date(value).toLocaleTimeString(undefined, {timeZone: {{timeZone}} || undefined});
+        }
+        function connectLive(onEvent, onState) {
+            const protocol = location.protocol === 'https:' ? 'wss://' : 'ws://';
+            let delay = 1000;
+            function open() {
+                const ws = new WebSocket(protocol + location.host + (window.liveBase || '') + '/ws');
+                ws.onopen = () => { delay = 1000; if (onState) onState(true); };
+                ws.onmessage = e => onEvent(JSON.parse(e.data));
+                ws.onclose = () => {
+                    if (onState) onState(false);
+                    setTimeout(open, delay);
+                    delay = Math.min(delay * 2, 30000);
+                };
+            }
+            open();
+        }
+`
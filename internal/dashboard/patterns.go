@@ -0,0 +1,180 @@
+package dashboard
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// GeneratePatterns 時間帯別・曜日別のレイテンシと失敗率の分析ページを生成
+func GeneratePatterns(lang string, theme Theme) string {
+	lang = pageLanguage(lang)
+	tmpl := `<!DOCTYPE html>
+<html lang="{{.Lang}}" data-theme="{{themeMode}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{pageTitle "Health Check Patterns"}}</title>
+    <script src="https://cdn.jsdelivr.net/npm/chart.js@3.9.1/dist/chart.min.js"></script>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            background: #f5f5f5;
+            padding: 20px;
+        }
+        .container {
+            max-width: 1400px;
+            margin: 0 auto;
+        }
+        .header {
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            padding: 30px;
+            border-radius: 10px;
+            margin-bottom: 20px;
+            box-shadow: 0 5px 15px rgba(0,0,0,0.1);
+        }
+        .header h1 {
+            font-size: 2em;
+            margin-bottom: 10px;
+        }
+        .card {
+            background: white;
+            padding: 20px;
+            border-radius: 8px;
+            box-shadow: 0 2px 5px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+        .card h3 {
+            margin-bottom: 15px;
+            color: #333;
+        }
+        .card select {
+            padding: 8px;
+            border: 2px solid #e0e0e0;
+            border-radius: 5px;
+            font-size: 14px;
+            min-width: 400px;
+        }
+        .charts-grid {
+            display: grid;
+            grid-template-columns: repeat(auto-fit, minmax(500px, 1fr));
+            gap: 20px;
+        }
+        .actions {
+            text-align: center;
+            margin-top: 30px;
+        }
+        .btn {
+            display: inline-block;
+            padding: 12px 24px;
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            text-decoration: none;
+            border-radius: 5px;
+            font-weight: 600;
+            margin: 0 10px;
+        }
+    </style>
+    {{themeStyle}}
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            {{themeSwitch}}
+            {{langSwitch}}
+            {{brand}}
+            <h1>{{t "patterns_title"}}</h1>
+            <p>{{t "patterns_description"}}</p>
+        </div>
+
+        <div class="card">
+            <label>{{t "target_label"}} <select id="target"></select></label>
+        </div>
+
+        <div class="charts-grid">
+            <div class="card">
+                <h3>{{t "patterns_hourly"}}</h3>
+                <canvas id="hourlyChart"></canvas>
+            </div>
+            <div class="card">
+                <h3>{{t "patterns_weekday"}}</h3>
+                <canvas id="weekdayChart"></canvas>
+            </div>
+        </div>
+
+        <div class="actions">
+            <a href="/" class="btn">{{t "new_check"}}</a>
+        </div>
+    </div>
+
+    <script>
+        const charts = {};
+        let targets = [];
+
+        function render(id, labels, buckets) {
+            if (charts[id]) {
+                charts[id].destroy();
+            }
+            charts[id] = new Chart(document.getElementById(id), {
+                type: 'bar',
+                data: {
+                    labels: labels,
+                    datasets: [
+                        { label: {{t "avg_latency_ms"}}, data: buckets.map(b => Math.round(b.avg_latency_ms / 1e6)), backgroundColor: '#3b82f6', yAxisID: 'ms' },
+                        { label: {{t "failure_rate_percent"}}, data: buckets.map(b => b.failure_rate), type: 'line', borderColor: '#ef4444', yAxisID: 'rate' }
+                    ]
+                },
+                options: {
+                    responsive: true,
+                    scales: {
+                        ms: { position: 'left', beginAtZero: true },
+                        rate: { position: 'right', beginAtZero: true, max: 100, grid: { drawOnChartArea: false } }
+                    }
+                }
+            });
+        }
+
+        function show() {
+            const t = targets.find(t => t.url === document.getElementById('target').value);
+            if (!t) {
+                return;
+            }
+            render('hourlyChart', Array.from({length: 24}, (_, i) => i + {{t "hour_unit"}}), t.hourly);
+            render('weekdayChart', {{t "weekdays"}}.split(','), t.weekday);
+        }
+
+        async function load() {
+            const response = await fetch('/api/patterns');
+            const data = await response.json();
+            targets = data.targets || [];
+
+            const select = document.getElementById('target');
+            targets.forEach(t => {
+                const option = document.createElement('option');
+                option.value = t.url;
+                option.textContent = t.url;
+                select.appendChild(option);
+            });
+            show();
+        }
+
+        document.getElementById('target').addEventListener('change', show);
+        load();
+    </script>
+</body>
+</html>`
+
+	t, err := template.New("patterns").Funcs(PageFuncs(lang, theme)).Parse(tmpl)
+	if err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, struct{ Lang string }{lang}); err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,228 @@
+package dashboard
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"healthcheck/internal/i18n"
+	"healthcheck/internal/incident"
+	"healthcheck/internal/stats"
+)
+
+// StatusPage 公開ステータスページの内容
+type StatusPage struct {
+	Title       string
+	GeneratedAt string
+	Services    []ServiceStatus
+	Incidents   []*incident.Incident // 継続中のインシデント
+	Language    string               // 表示言語（ja / en、空の場合はデフォルト言語）
+	Static      bool                 // 静的HTMLとして書き出す（言語とテーマの切り替えを表示しない）
+	Theme       Theme                // 表示モードとブランドの設定
+}
+
+// ServiceStatus サービス（対象のグループ）ごとの状態
+type ServiceStatus struct {
+	Name    string
+	Targets []TargetStatus
+}
+
+// TargetStatus 対象ごとの現在の状態と稼働率
+type TargetStatus struct {
+	Name   string
+	State  string // up / down / degraded / flapping / unknown
+	Uptime float64
+	Days   []stats.DayUptime
+}
+
+// AllUp すべての対象が稼働中かどうか
+func (p StatusPage) AllUp() bool {
+	for _, s := range p.Services {
+		for _, t := range s.Targets {
+			if t.State != "up" && t.State != "unknown" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// GenerateStatusPage 公開用の読み取り専用ステータスページを生成
+func GenerateStatusPage(page StatusPage) string {
+	lang := pageLanguage(page.Language)
+	page.Language = lang
+	tmpl := `<!DOCTYPE html>
+<html lang="{{.Language}}" data-theme="{{themeMode}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Title}}</title>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            background: #f5f5f5;
+            padding: 20px;
+        }
+        .container {
+            max-width: 900px;
+            margin: 0 auto;
+        }
+        h1 {
+            color: #333;
+            margin-bottom: 20px;
+        }
+        .overall {
+            padding: 20px;
+            border-radius: 8px;
+            color: white;
+            font-size: 1.2em;
+            font-weight: 600;
+            margin-bottom: 20px;
+        }
+        .overall.up { background: #10b981; }
+        .overall.down { background: #ef4444; }
+        .card {
+            background: white;
+            padding: 20px;
+            border-radius: 8px;
+            box-shadow: 0 2px 5px rgba(0,0,0,0.1);
+            margin-bottom: 20px;
+        }
+        .card h2 {
+            font-size: 1.1em;
+            color: #333;
+            margin-bottom: 15px;
+        }
+        .target {
+            padding: 10px 0;
+            border-bottom: 1px solid #e5e5e5;
+        }
+        .target:last-child { border-bottom: none; }
+        .target-header {
+            display: flex;
+            justify-content: space-between;
+            margin-bottom: 6px;
+            font-size: 14px;
+        }
+        .state-up { color: #10b981; }
+        .state-down { color: #ef4444; }
+        .state-degraded { color: #f59e0b; }
+        .state-flapping { color: #9333ea; }
+        .state-unknown { color: #999; }
+        .bars {
+            display: flex;
+            gap: 2px;
+            height: 30px;
+        }
+        .bar {
+            flex: 1;
+            border-radius: 2px;
+            background: #e5e5e5;
+        }
+        .bar.good { background: #10b981; }
+        .bar.warn { background: #f59e0b; }
+        .bar.bad { background: #ef4444; }
+        .bars-legend {
+            display: flex;
+            justify-content: space-between;
+            font-size: 11px;
+            color: #999;
+            margin-top: 4px;
+        }
+        .incident {
+            padding: 10px 0;
+            border-bottom: 1px solid #e5e5e5;
+            font-size: 14px;
+        }
+        .incident:last-child { border-bottom: none; }
+        .footer {
+            text-align: center;
+            color: #999;
+            font-size: 12px;
+        }
+    </style>
+    {{themeStyle}}
+</head>
+<body>
+    <div class="container">
+        {{if not .Static}}{{themeSwitch}}{{langSwitch}}{{end}}
+        {{brand}}
+        <h1>{{.Title}}</h1>
+
+        {{if .AllUp}}
+        <div class="overall up">{{t "status_all_up"}}</div>
+        {{else}}
+        <div class="overall down">{{t "status_some_down"}}</div>
+        {{end}}
+
+        {{if .Incidents}}
+        <div class="card">
+            <h2>{{t "status_incidents"}}</h2>
+            {{range .Incidents}}
+            <div class="incident">
+                <strong>{{.URL}}</strong> — {{.Error}}<br>
+                {{t "status_ongoing_since" (datetime .Start "2006-01-02 15:04 MST")}}
+            </div>
+            {{end}}
+        </div>
+        {{end}}
+
+        {{range .Services}}
+        <div class="card">
+            <h2>{{.Name}}</h2>
+            {{range .Targets}}
+            <div class="target">
+                <div class="target-header">
+                    <span>{{.Name}}</span>
+                    <span class="state-{{.State}}">{{stateLabel .State}}{{if ge .Uptime 0.0}} · {{printf "%.2f" .Uptime}}%{{end}}</span>
+                </div>
+                <div class="bars">
+                    {{range .Days}}
+                    <div class="bar {{barClass .}}" title="{{.Date}}{{if .HasData}}: {{printf "%.2f" .UptimePercent}}%{{else}}: {{t "no_data"}}{{end}}"></div>
+                    {{end}}
+                </div>
+                <div class="bars-legend"><span>{{t "days_ago" (len .Days)}}</span><span>{{t "today"}}</span></div>
+            </div>
+            {{end}}
+        </div>
+        {{end}}
+
+        <p class="footer">{{t "last_updated" .GeneratedAt}}</p>
+    </div>
+</body>
+</html>`
+
+	funcs := template.FuncMap{
+		"stateLabel": func(state string) string {
+			switch state {
+			case "up", "down", "degraded", "flapping":
+				return i18n.T(lang, "state_"+state)
+			}
+			return i18n.T(lang, "state_unknown")
+		},
+		"barClass": func(d stats.DayUptime) string {
+			switch {
+			case !d.HasData():
+				return ""
+			case d.UptimePercent >= 99:
+				return "good"
+			case d.UptimePercent >= 95:
+				return "warn"
+			}
+			return "bad"
+		},
+	}
+
+	t, err := template.New("status").Funcs(PageFuncs(lang, page.Theme)).Funcs(funcs).Parse(tmpl)
+	if err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	var buf strings.Builder
+	if err := t.Execute(&buf, page); err != nil {
+		return fmt.Sprintf("<html><body>Error: %v</body></html>", err)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,148 @@
+package dashboard
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+	"time"
+
+	"healthcheck/internal/i18n"
+)
+
+// Theme ページの表示モードとブランドの設定
+type Theme struct {
+	Mode   string // 表示モード（light / dark、空の場合はOSの設定に合わせる）
+	Accent string // ヘッダーとボタンの色（#rgb / #rrggbb、空の場合は標準の紫）
+	Logo   string // ヘッダーに表示するロゴ画像のURL
+	Title  string // ヘッダーとページのタイトルに表示する名前
+}
+
+// accentPattern CSSに埋め込める色の形式
+var accentPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// darkRules ダークモードで各ページの既定の色を置き換えるCSSの規則（セレクタ, 宣言）
+// 優先度を上げないよう:where()の中で表示モードを判定し、ページの規則より後に読み込んで上書きする
+var darkRules = [][2]string{
+	{"body:not(.accent)", "background: #111827; color: #e5e7eb;"},
+	{"body.accent .container, .card, .stat-card, .results-section, .chart-card, .day, .heatmap th.target", "background: #1f2937; box-shadow: 0 2px 5px rgba(0,0,0,0.4);"},
+	{"h1, h2, h3, label, .stat-card .value, .card h2, .card h3, .day h2, .results-section h2, .chart-card h3, .live h2", "color: #e5e7eb;"},
+	{".subtitle, .help-text, .help, .progress-count, #runningURLs li, .heatmap th, .legend, .empty, .event .time, .stat-card h3, .results-table th, .snippet summary, .live-state, .bars-legend, .footer", "color: #9ca3af;"},
+	{".results-table th, .results-table tr:hover, .snippet pre", "background: #273244;"},
+	{".results-table td, .event, .target, .incident, .har-form, .live, #liveEvents li, #partialResults li", "border-color: #374151;"},
+	{"textarea, input, select", "background: #111827; color: #e5e7eb; border-color: #374151;"},
+	{".btn-small", "background: #1f2937;"},
+	{".bar, .budget-bar, .progress-bar", "background: #374151;"},
+	{".hint", "color: #fbbf24;"},
+	{".meta-chip", "background: #312e81; color: #c7d2fe;"},
+	{".results-table a, .card a, .day a", "color: #93c5fd;"},
+}
+
+// accentRules アクセントカラーで置き換える規則（セレクタ, プロパティ）
+var accentRules = [][2]string{
+	{".header, .btn, .controls button, body.accent, body.accent button, .progress-bar div", "background"},
+	{".btn-small, #loading", "color"},
+	{".btn-small, textarea:focus, input:focus", "border-color"},
+	{".day.today", "border-left-color"},
+	{".spinner", "border-top-color"},
+}
+
+// PageFuncs ページのテンプレートで使う、表示言語のメッセージ・言語とテーマの切り替え・ブランドの関数
+func PageFuncs(lang string, theme Theme) template.FuncMap {
+	return template.FuncMap{
+		"t":           i18n.Translator(lang),
+		"langSwitch":  func() template.HTML { return LanguageSwitch(lang) },
+		"themeMode":   func() string { return themeMode(theme) },
+		"themeStyle":  func() template.HTML { return ThemeStyle(theme) },
+		"themeSwitch": func() template.HTML { return ThemeSwitch(lang) },
+		"brand":       func() template.HTML { return Brand(theme) },
+		// 日時を表示するタイムゾーンで指定した形式にする
+		"datetime": func(t time.Time, layout string) string { return i18n.In(t).Format(layout) },
+		// ブラウザで日時を表示するタイムゾーン（空の場合はブラウザのタイムゾーン）
+		"timeZone": i18n.TimeZoneName,
+		// ブランドのタイトルを付けたページのタイトル
+		"pageTitle": func(title string) string {
+			if theme.Title == "" {
+				return title
+			}
+			return theme.Title + " - " + title
+		},
+	}
+}
+
+// themeMode html要素のdata-themeに設定する表示モード（指定がない場合はauto）
+func themeMode(theme Theme) string {
+	if theme.Mode == "light" || theme.Mode == "dark" {
+		return theme.Mode
+	}
+	return "auto"
+}
+
+// ThemeStyle ダークモードとアクセントカラーのCSS（ページのstyle要素の後に置く）
+// autoの場合はOSの設定（prefers-color-scheme）がダークのときだけダークモードの規則を適用する
+func ThemeStyle(theme Theme) template.HTML {
+	var b strings.Builder
+	b.WriteString("<style>\n")
+	b.WriteString(`        html[data-theme="dark"] { color-scheme: dark; }` + "\n")
+	writeDarkRules(&b, `html[data-theme="dark"]`, "        ")
+	b.WriteString("        @media (prefers-color-scheme: dark) {\n")
+	b.WriteString(`            html[data-theme="auto"] { color-scheme: dark; }` + "\n")
+	writeDarkRules(&b, `html[data-theme="auto"]`, "            ")
+	b.WriteString("        }\n")
+	if accentPattern.MatchString(theme.Accent) {
+		for _, r := range accentRules {
+			b.WriteString("        " + r[0] + " { " + r[1] + ": " + theme.Accent + "; }\n")
+		}
+	}
+	b.WriteString("    </style>")
+	return template.HTML(b.String())
+}
+
+// writeDarkRules ダークモードの規則をmodeの条件付きで書き出す
+func writeDarkRules(b *strings.Builder, mode, indent string) {
+	for _, r := range darkRules {
+		selectors := strings.Split(r[0], ", ")
+		for i, s := range selectors {
+			selectors[i] = ":where(" + mode + ") " + s
+		}
+		b.WriteString(indent + strings.Join(selectors, ", ") + " { " + r[1] + " }\n")
+	}
+}
+
+// ThemeSwitch ライトモードとダークモードの切り替えリンク
+// 現在の表示（autoの場合はOSの設定）と逆のモードをthemeに指定して再表示する（選択したモードはサーバーがクッキーに保存する）
+func ThemeSwitch(lang string) template.HTML {
+	dark := template.JSEscapeString(i18n.T(lang, "theme_dark"))
+	light := template.JSEscapeString(i18n.T(lang, "theme_light"))
+	return template.HTML(`<p class="theme-switch" style="float: right; font-size: 13px; margin-left: 12px;"><a href="?theme=dark" style="color: inherit; text-decoration: none;">🌙</a></p>
+    <script>
+        document.querySelectorAll('.theme-switch a').forEach(function(a) {
+            let mode = document.documentElement.dataset.theme;
+            if (mode !== 'light' && mode !== 'dark') {
+                mode = window.matchMedia('(prefers-color-scheme: dark)').matches ? 'dark' : 'light';
+            }
+            const next = mode === 'dark' ? 'light' : 'dark';
+            const params = new URLSearchParams(location.search);
+            params.set('theme', next);
+            a.search = '?' + params.toString();
+            a.textContent = next === 'dark' ? '🌙' : '☀️';
+            a.title = next === 'dark' ? '` + dark + `' : '` + light + `';
+        });
+    </script>`)
+}
+
+// Brand ヘッダーに表示するロゴとタイトル（どちらも指定がない場合は空）
+func Brand(theme Theme) template.HTML {
+	if theme.Logo == "" && theme.Title == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(`<div class="brand" style="display: flex; align-items: center; gap: 10px; margin-bottom: 10px; font-weight: 600;">`)
+	if theme.Logo != "" {
+		b.WriteString(`<img src="` + template.HTMLEscapeString(theme.Logo) + `" alt="" style="max-height: 32px;">`)
+	}
+	if theme.Title != "" {
+		b.WriteString(`<span>` + template.HTMLEscapeString(theme.Title) + `</span>`)
+	}
+	b.WriteString(`</div>`)
+	return template.HTML(b.String())
+}
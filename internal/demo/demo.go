@@ -0,0 +1,193 @@
+package demo
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// Days 合成する履歴の日数
+const Days = 30
+
+// target デモ用の対象と、その応答の傾向
+type target struct {
+	config.Target
+	base     time.Duration                                                  // 通常時の応答時間
+	behavior func(at time.Time, now time.Time) (checker.ErrorCategory, int) // 障害を返す場合はエラー種別とステータスコード
+	slow     func(at time.Time) float64                                     // 応答時間の倍率
+}
+
+// targets デモ用の対象
+var targets = []target{
+	{
+		Target: config.Target{Name: "トップページ", URL: "https://www.demo.example/", Service: "Webサイト"},
+		base:   120 * time.Millisecond,
+	},
+	{
+		// 5日前に6時間の障害
+		Target: config.Target{Name: "ログイン", URL: "https://www.demo.example/login", Service: "Webサイト"},
+		base:   180 * time.Millisecond,
+		behavior: func(at, now time.Time) (checker.ErrorCategory, int) {
+			start := now.AddDate(0, 0, -5).Truncate(time.Hour)
+			if !at.Before(start) && at.Before(start.Add(6*time.Hour)) {
+				return checker.CategoryHTTP5xx, 500
+			}
+			return "", 0
+		},
+	},
+	{
+		// 毎晩2〜4時のバックアップ中に遅くなる
+		Target: config.Target{Name: "API", URL: "https://api.demo.example/health", Service: "API"},
+		base:   80 * time.Millisecond,
+		slow: func(at time.Time) float64 {
+			if h := at.Hour(); h >= 2 && h < 4 {
+				return 5
+			}
+			return 1
+		},
+	},
+	{
+		// まれにタイムアウト
+		Target: config.Target{Name: "決済API", URL: "https://pay.demo.example/health", Service: "API"},
+		base:   250 * time.Millisecond,
+		behavior: func(at, now time.Time) (checker.ErrorCategory, int) {
+			if at.Unix()/3600%97 == 0 {
+				return checker.CategoryTimeout, 0
+			}
+			return "", 0
+		},
+	},
+	{
+		// 日曜日の深夜にメンテナンスで停止
+		Target: config.Target{Name: "バッチ管理画面", URL: "https://batch.demo.example/", Service: "社内ツール"},
+		base:   300 * time.Millisecond,
+		behavior: func(at, now time.Time) (checker.ErrorCategory, int) {
+			if at.Weekday() == time.Sunday && at.Hour() >= 1 && at.Hour() < 5 {
+				return checker.CategoryHTTP5xx, 503
+			}
+			return "", 0
+		},
+	},
+	{
+		// 9時間前から停止中
+		Target: config.Target{Name: "旧ファイルサーバー", URL: "https://files.demo.example/", Service: "社内ツール"},
+		base:   400 * time.Millisecond,
+		behavior: func(at, now time.Time) (checker.ErrorCategory, int) {
+			if at.After(now.Add(-9 * time.Hour)) {
+				return checker.CategoryConnectRefused, 0
+			}
+			return "", 0
+		},
+	},
+}
+
+// Setup デモ用の対象と合成した履歴を一時ディレクトリに用意し、設定に反映
+// 履歴の保存先は一時ディレクトリに切り替わるため、実際の履歴には影響しない
+func Setup(cfg *config.Config) (string, error) {
+	dir, err := os.MkdirTemp("", "healthcheck-demo-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create demo directory: %w", err)
+	}
+	storage.ResultsDir = filepath.Join(dir, "results")
+	cfg.ResultsDir = storage.ResultsDir
+	storage.SetHistoryLimit(Days*24 + 100)
+
+	now := time.Now().Truncate(time.Hour)
+	rng := rand.New(rand.NewSource(1))
+
+	for at := now.AddDate(0, 0, -Days); !at.After(now); at = at.Add(time.Hour) {
+		var results []*checker.CheckResult
+		for _, t := range targets {
+			results = append(results, t.result(at, now, rng))
+		}
+		statistics := stats.CalculateStatistics(results, 2*time.Second)
+		entry := &storage.HistoryEntry{Timestamp: at, Results: results, Statistics: statistics}
+		if _, err := storage.SaveHistoryEntry(entry); err != nil {
+			return "", err
+		}
+	}
+
+	cfg.Targets = nil
+	for _, t := range targets {
+		cfg.Targets = append(cfg.Targets, t.Target)
+	}
+	tomorrow := now.Truncate(24*time.Hour).AddDate(0, 0, 1)
+	cfg.MaintenanceWindows = []config.MaintenanceWindow{
+		{
+			Name:    "決済API 定期メンテナンス",
+			Start:   tomorrow.Add(1 * time.Hour),
+			End:     tomorrow.Add(3 * time.Hour),
+			Targets: []string{"https://pay.demo.example/health"},
+		},
+	}
+
+	return dir, nil
+}
+
+// result 対象の指定時刻の合成結果を作成
+func (t target) result(at, now time.Time, rng *rand.Rand) *checker.CheckResult {
+	// 応答時間は基準値の±30%でばらつかせる
+	jitter := 0.7 + rng.Float64()*0.6
+	responseTime := time.Duration(float64(t.base) * jitter)
+	if t.slow != nil {
+		responseTime = time.Duration(float64(responseTime) * t.slow(at))
+	}
+	dns := time.Duration(5+rng.Intn(20)) * time.Millisecond
+
+	result := &checker.CheckResult{
+		URL:          t.URL,
+		StatusCode:   200,
+		ResponseTime: responseTime,
+		Latency:      responseTime + dns,
+		Timestamp:    at.Add(time.Duration(rng.Intn(30)) * time.Second),
+		Success:      true,
+	}
+	if t.slow != nil && t.slow(at) > 1 {
+		result.Degraded = true
+		result.DegradedMessage = "response time is well above baseline (demo data)"
+	}
+
+	if t.behavior == nil {
+		return result
+	}
+	errorType, status := t.behavior(at, now)
+	if errorType == "" {
+		return result
+	}
+
+	result.Success = false
+	result.Degraded = false
+	result.DegradedMessage = ""
+	result.StatusCode = status
+	result.Error = errorType
+	switch errorType {
+	case checker.CategoryHTTP5xx:
+		result.ErrorMessage = fmt.Sprintf("HTTP %d: %d %s", status, status, httpStatusText(status))
+	case checker.CategoryTimeout:
+		result.ResponseTime = 0
+		result.ErrorMessage = "Response time exceeded 30s (demo data)"
+	case checker.CategoryConnectRefused:
+		result.ResponseTime = 0
+		result.ErrorMessage = "dial tcp: connect: connection refused (demo data)"
+		result.Hint = "DNS resolves but TCP connect to port 443 is refused"
+	}
+	return result
+}
+
+// httpStatusText デモで使用するステータスコードの説明
+func httpStatusText(status int) string {
+	switch status {
+	case 500:
+		return "Internal Server Error"
+	case 503:
+		return "Service Unavailable"
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package digest
+
+import (
+	"sort"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/incident"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// Report 期間内の履歴をまとめた稼働レポート
+type Report struct {
+	Name      string               `json:"name"`
+	Period    string               `json:"period"` // daily / weekly
+	From      time.Time            `json:"from"`
+	To        time.Time            `json:"to"`
+	Runs      int                  `json:"runs"`     // 期間内の実行数
+	Checks    int                  `json:"checks"`   // 期間内のチェック数
+	Failures  int                  `json:"failures"` // 期間内の失敗数
+	Targets   []*stats.TargetSLA   `json:"targets"`  // 対象ごとの稼働率（稼働率の低い順）
+	Slowest   []*Slow              `json:"slowest"`  // 平均応答時間の遅い対象
+	Incidents []*incident.Incident `json:"incidents"`
+}
+
+// Slow 平均応答時間の遅い対象
+type Slow struct {
+	URL           string  `json:"url"`
+	AvgResponseMs float64 `json:"avg_response_ms"`
+	Checks        int     `json:"checks"` // 平均の計算に使った成功したチェックの数
+}
+
+// Build 保存された履歴から、toまでのwindowの期間の稼働レポートを作成
+func Build(entries []*storage.HistoryEntry, to time.Time, window time.Duration, slo float64, slowest int) *Report {
+	from := to.Add(-window)
+	report := &Report{From: from, To: to}
+
+	var inWindow []*storage.HistoryEntry
+	var results []*checker.CheckResult
+	for _, e := range entries {
+		if e.Timestamp.Before(from) || e.Timestamp.After(to) {
+			continue
+		}
+		inWindow = append(inWindow, e)
+		results = append(results, e.Results...)
+		report.Runs++
+		for _, r := range e.Results {
+			report.Checks++
+			if !r.Success {
+				report.Failures++
+			}
+		}
+	}
+
+	report.Targets = stats.CalculateSLA(results, window, slo, to)
+	sort.SliceStable(report.Targets, func(i, j int) bool {
+		return report.Targets[i].UptimePercent < report.Targets[j].UptimePercent
+	})
+
+	report.Slowest = slowestTargets(inWindow, slowest)
+
+	// 期間内に発生したか、期間の開始時点で継続していたインシデント
+	for _, inc := range incident.Detect(entries) {
+		if inc.Start.After(to) || (!inc.Ongoing && inc.End.Before(from)) {
+			continue
+		}
+		report.Incidents = append(report.Incidents, inc)
+	}
+	return report
+}
+
+// slowestTargets 成功したチェックの平均応答時間が遅い順に上位limit件の対象
+func slowestTargets(entries []*storage.HistoryEntry, limit int) []*Slow {
+	byURL := make(map[string]*Slow)
+	total := make(map[string]time.Duration)
+	for _, e := range entries {
+		for _, r := range e.Results {
+			if !r.Success {
+				continue
+			}
+			s, ok := byURL[r.URL]
+			if !ok {
+				s = &Slow{URL: r.URL}
+				byURL[r.URL] = s
+			}
+			s.Checks++
+			total[r.URL] += r.ResponseTime
+		}
+	}
+
+	slow := make([]*Slow, 0, len(byURL))
+	for url, s := range byURL {
+		s.AvgResponseMs = float64(total[url].Nanoseconds()) / float64(s.Checks) / 1e6
+		slow = append(slow, s)
+	}
+	sort.Slice(slow, func(i, j int) bool {
+		if slow[i].AvgResponseMs != slow[j].AvgResponseMs {
+			return slow[i].AvgResponseMs > slow[j].AvgResponseMs
+		}
+		return slow[i].URL < slow[j].URL
+	})
+	if len(slow) > limit {
+		slow = slow[:limit]
+	}
+	return slow
+}
@@ -0,0 +1,96 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// ConsulProvider Consulのカタログからサービスのインスタンスを検出
+type ConsulProvider struct {
+	config config.DiscoveryConfig
+	client *http.Client
+}
+
+// consulService /v1/catalog/service のレスポンスの1インスタンス
+type consulService struct {
+	Address        string `json:"Address"`
+	ServiceAddress string `json:"ServiceAddress"`
+	ServicePort    int    `json:"ServicePort"`
+}
+
+// NewConsulProvider 新しいConsulProviderを作成
+func NewConsulProvider(cfg config.DiscoveryConfig) *ConsulProvider {
+	return &ConsulProvider{
+		config: cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name 検出元の名前
+func (p *ConsulProvider) Name() string {
+	return p.config.Name
+}
+
+// Discover サービスのインスタンスを対象として返す
+func (p *ConsulProvider) Discover(ctx context.Context) ([]config.Target, error) {
+	address := p.config.Address
+	if address == "" {
+		address = "http://127.0.0.1:8500"
+	}
+
+	query := url.Values{}
+	if p.config.Tag != "" {
+		query.Set("tag", p.config.Tag)
+	}
+	if p.config.Datacenter != "" {
+		query.Set("dc", p.config.Datacenter)
+	}
+	endpoint := strings.TrimSuffix(address, "/") + "/v1/catalog/service/" + url.PathEscape(p.config.Service)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if p.config.Token != "" {
+		req.Header.Set("X-Consul-Token", p.config.Token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consul: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consul returned status %d", resp.StatusCode)
+	}
+
+	var services []consulService
+	if err := json.NewDecoder(resp.Body).Decode(&services); err != nil {
+		return nil, fmt.Errorf("failed to decode consul response: %w", err)
+	}
+
+	var targets []config.Target
+	for _, s := range services {
+		// サービスのアドレスが未登録の場合はノードのアドレスを使う
+		host := s.ServiceAddress
+		if host == "" {
+			host = s.Address
+		}
+		if host == "" || s.ServicePort == 0 {
+			continue
+		}
+		targets = append(targets, buildTarget(p.config, p.config.Service, host, s.ServicePort))
+	}
+	return targets, nil
+}
@@ -0,0 +1,141 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// Provider 対象を検出する検出元
+type Provider interface {
+	Name() string
+	Discover(ctx context.Context) ([]config.Target, error)
+}
+
+// NewProvider 設定から検出元を作成
+func NewProvider(cfg config.DiscoveryConfig) (Provider, error) {
+	switch cfg.Type {
+	case "consul":
+		return NewConsulProvider(cfg), nil
+	case "dns_srv":
+		return NewSRVProvider(cfg), nil
+	case "kubernetes":
+		return NewKubernetesProvider(cfg)
+	}
+	return nil, fmt.Errorf("unknown discovery type %q", cfg.Type)
+}
+
+// Manager 検出元から定期的に対象を取得し、設定に反映する
+type Manager struct {
+	config    *config.Config
+	providers []Provider
+
+	mutex  sync.Mutex
+	stopCh chan struct{}
+}
+
+// NewManager 新しいManagerを作成（作成できない検出元は警告を出して無視する）
+func NewManager(cfg *config.Config) *Manager {
+	m := &Manager{config: cfg}
+	for _, d := range cfg.Discovery {
+		p, err := NewProvider(d)
+		if err != nil {
+			slog.Warn("skipping discovery", "discovery", d.Name, "error", err)
+			continue
+		}
+		m.providers = append(m.providers, p)
+	}
+	return m
+}
+
+// Start 検出を開始（検出元がない場合は何もしない）
+// 最初の検出は定期チェックより先に反映されるよう同期的に実行する
+func (m *Manager) Start() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.providers) == 0 || m.stopCh != nil {
+		return
+	}
+	m.Refresh(context.Background())
+
+	m.stopCh = make(chan struct{})
+	go m.loop(m.stopCh)
+}
+
+// Stop 検出を停止
+func (m *Manager) Stop() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.stopCh == nil {
+		return
+	}
+	close(m.stopCh)
+	m.stopCh = nil
+}
+
+// loop 更新間隔ごとに検出を実行
+func (m *Manager) loop(stopCh chan struct{}) {
+	ticker := time.NewTicker(m.config.DiscoveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			m.Refresh(context.Background())
+		}
+	}
+}
+
+// Refresh すべての検出元から対象を取得して設定に反映
+// 取得に失敗した検出元は前回の検出結果をそのまま使う
+func (m *Manager) Refresh(ctx context.Context) {
+	for _, p := range m.providers {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		targets, err := p.Discover(ctx)
+		cancel()
+		if err != nil {
+			slog.WarnContext(ctx, "discovery failed", "discovery", p.Name(), "error", err)
+			continue
+		}
+		slog.DebugContext(ctx, "discovered targets", "discovery", p.Name(), "targets", len(targets))
+		m.config.SetDiscoveredTargets(p.Name(), targets)
+	}
+}
+
+// buildTarget 検出したアドレスとポートから対象を作成
+func buildTarget(cfg config.DiscoveryConfig, service, host string, port int) config.Target {
+	scheme := cfg.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	path := cfg.Path
+	if path == "" {
+		path = "/"
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	group := cfg.Group
+	if group == "" {
+		group = service
+	}
+
+	address := net.JoinHostPort(strings.TrimSuffix(host, "."), strconv.Itoa(port))
+	return config.Target{
+		Name:    service + " " + address,
+		URL:     scheme + "://" + address + path,
+		Service: group,
+		Tags:    cfg.Tags,
+	}
+}
@@ -0,0 +1,297 @@
+package discovery
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"healthcheck/internal/config"
+)
+
+// クラスタ内で実行する場合のサービスアカウントの認証情報
+const (
+	serviceAccountToken = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	serviceAccountCA    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+// KubernetesProvider KubernetesのIngressまたはServiceから対象を検出
+type KubernetesProvider struct {
+	config  config.DiscoveryConfig
+	address string
+	token   string
+	client  *http.Client
+}
+
+// k8sMeta リソースのメタデータ
+type k8sMeta struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// k8sIngressList /apis/networking.k8s.io/v1/ingresses のレスポンス
+type k8sIngressList struct {
+	Items []struct {
+		Metadata k8sMeta `json:"metadata"`
+		Spec     struct {
+			TLS []struct {
+				Hosts []string `json:"hosts"`
+			} `json:"tls"`
+			Rules []struct {
+				Host string `json:"host"`
+				HTTP *struct {
+					Paths []struct {
+						Path string `json:"path"`
+					} `json:"paths"`
+				} `json:"http"`
+			} `json:"rules"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+// k8sServiceList /api/v1/services のレスポンス
+type k8sServiceList struct {
+	Items []struct {
+		Metadata k8sMeta `json:"metadata"`
+		Spec     struct {
+			Ports []struct {
+				Name        string `json:"name"`
+				Port        int    `json:"port"`
+				AppProtocol string `json:"appProtocol"`
+			} `json:"ports"`
+		} `json:"spec"`
+	} `json:"items"`
+}
+
+// NewKubernetesProvider 新しいKubernetesProviderを作成
+// addressを省略した場合はクラスタ内のサービスアカウントで接続する
+func NewKubernetesProvider(cfg config.DiscoveryConfig) (*KubernetesProvider, error) {
+	p := &KubernetesProvider{config: cfg, address: cfg.Address, token: cfg.Token}
+
+	caFile := cfg.CAFile
+	if p.address == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, fmt.Errorf("address is required outside of a cluster")
+		}
+		p.address = "https://" + net.JoinHostPort(host, port)
+		if caFile == "" {
+			caFile = serviceAccountCA
+		}
+		if p.token == "" {
+			token, err := os.ReadFile(serviceAccountToken)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read service account token: %w", err)
+			}
+			p.token = strings.TrimSpace(string(token))
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caFile != "" {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	p.client = &http.Client{Transport: transport, Timeout: 10 * time.Second}
+
+	return p, nil
+}
+
+// Name 検出元の名前
+func (p *KubernetesProvider) Name() string {
+	return p.config.Name
+}
+
+// Discover IngressのホストとパスまたはServiceのクラスタ内アドレスを対象として返す
+func (p *KubernetesProvider) Discover(ctx context.Context) ([]config.Target, error) {
+	if p.config.Resource == "service" {
+		return p.discoverServices(ctx)
+	}
+	return p.discoverIngresses(ctx)
+}
+
+// discoverIngresses Ingressのルールごとに対象を作成
+func (p *KubernetesProvider) discoverIngresses(ctx context.Context) ([]config.Target, error) {
+	var list k8sIngressList
+	if err := p.list(ctx, "/apis/networking.k8s.io/v1", "ingresses", &list); err != nil {
+		return nil, err
+	}
+
+	var targets []config.Target
+	for _, ing := range list.Items {
+		if !p.selected(ing.Metadata) {
+			continue
+		}
+		tlsHosts := make(map[string]bool)
+		for _, t := range ing.Spec.TLS {
+			for _, h := range t.Hosts {
+				tlsHosts[h] = true
+			}
+		}
+
+		for _, rule := range ing.Spec.Rules {
+			// ホストのないルールとワイルドカードはURLにできないため除外
+			if rule.Host == "" || strings.HasPrefix(rule.Host, "*") {
+				continue
+			}
+			scheme := "http"
+			if tlsHosts[rule.Host] {
+				scheme = "https"
+			}
+			if p.config.Scheme != "" {
+				scheme = p.config.Scheme
+			}
+
+			paths := []string{"/"}
+			if rule.HTTP != nil && len(rule.HTTP.Paths) > 0 {
+				paths = nil
+				for _, path := range rule.HTTP.Paths {
+					if path.Path == "" {
+						path.Path = "/"
+					}
+					paths = append(paths, path.Path)
+				}
+			}
+			if p.config.Path != "" {
+				paths = []string{p.config.Path}
+			}
+
+			seen := make(map[string]bool)
+			for _, path := range paths {
+				targetURL := scheme + "://" + rule.Host + path
+				if seen[targetURL] {
+					continue
+				}
+				seen[targetURL] = true
+				targets = append(targets, config.Target{
+					Name:    ing.Metadata.Namespace + "/" + ing.Metadata.Name + " " + rule.Host + path,
+					URL:     targetURL,
+					Service: p.group(ing.Metadata),
+					Tags:    p.config.Tags,
+				})
+			}
+		}
+	}
+	return targets, nil
+}
+
+// discoverServices Serviceのポートごとにクラスタ内のアドレスで対象を作成
+// HTTPのポートが名前やappProtocolで判別できる場合はそのポートのみを使う
+func (p *KubernetesProvider) discoverServices(ctx context.Context) ([]config.Target, error) {
+	var list k8sServiceList
+	if err := p.list(ctx, "/api/v1", "services", &list); err != nil {
+		return nil, err
+	}
+
+	var targets []config.Target
+	for _, svc := range list.Items {
+		if !p.selected(svc.Metadata) || len(svc.Spec.Ports) == 0 {
+			continue
+		}
+
+		type httpPort struct {
+			port   int
+			scheme string
+		}
+		var ports []httpPort
+		for _, port := range svc.Spec.Ports {
+			protocol := strings.ToLower(port.AppProtocol)
+			if protocol == "" {
+				protocol = strings.ToLower(port.Name)
+			}
+			switch {
+			case strings.HasPrefix(protocol, "https"):
+				ports = append(ports, httpPort{port.Port, "https"})
+			case strings.HasPrefix(protocol, "http"):
+				ports = append(ports, httpPort{port.Port, "http"})
+			}
+		}
+		if len(ports) == 0 {
+			scheme := "http"
+			if svc.Spec.Ports[0].Port == 443 {
+				scheme = "https"
+			}
+			ports = append(ports, httpPort{svc.Spec.Ports[0].Port, scheme})
+		}
+
+		host := svc.Metadata.Name + "." + svc.Metadata.Namespace + ".svc"
+		for _, port := range ports {
+			cfg := p.config
+			if cfg.Scheme == "" {
+				cfg.Scheme = port.scheme
+			}
+			cfg.Group = p.group(svc.Metadata)
+			target := buildTarget(cfg, svc.Metadata.Namespace+"/"+svc.Metadata.Name, host, port.port)
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+// list リソースの一覧を取得
+func (p *KubernetesProvider) list(ctx context.Context, prefix, resource string, out interface{}) error {
+	path := prefix + "/" + resource
+	if p.config.Namespace != "" {
+		path = prefix + "/namespaces/" + url.PathEscape(p.config.Namespace) + "/" + resource
+	}
+	endpoint := strings.TrimSuffix(p.address, "/") + path
+	if p.config.LabelSelector != "" {
+		endpoint += "?labelSelector=" + url.QueryEscape(p.config.LabelSelector)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", resource, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("kubernetes API returned status %d for %s", resp.StatusCode, resource)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", resource, err)
+	}
+	return nil
+}
+
+// selected アノテーションの条件を満たすか
+func (p *KubernetesProvider) selected(meta k8sMeta) bool {
+	if p.config.Annotation == "" {
+		return true
+	}
+	value, _ := strconv.ParseBool(meta.Annotations[p.config.Annotation])
+	return value
+}
+
+// group ステータスページでのグループ名（省略時は名前空間）
+func (p *KubernetesProvider) group(meta k8sMeta) string {
+	if p.config.Group != "" {
+		return p.config.Group
+	}
+	return meta.Namespace
+}
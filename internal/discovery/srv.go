@@ -0,0 +1,42 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"healthcheck/internal/config"
+)
+
+// SRVProvider DNSのSRVレコードからインスタンスを検出
+type SRVProvider struct {
+	config   config.DiscoveryConfig
+	resolver *net.Resolver
+}
+
+// NewSRVProvider 新しいSRVProviderを作成
+func NewSRVProvider(cfg config.DiscoveryConfig) *SRVProvider {
+	return &SRVProvider{
+		config:   cfg,
+		resolver: net.DefaultResolver,
+	}
+}
+
+// Name 検出元の名前
+func (p *SRVProvider) Name() string {
+	return p.config.Name
+}
+
+// Discover SRVレコードの各ターゲットを対象として返す
+func (p *SRVProvider) Discover(ctx context.Context) ([]config.Target, error) {
+	_, records, err := p.resolver.LookupSRV(ctx, "", "", p.config.Record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup SRV %s: %w", p.config.Record, err)
+	}
+
+	var targets []config.Target
+	for _, r := range records {
+		targets = append(targets, buildTarget(p.config, p.config.Record, r.Target, int(r.Port)))
+	}
+	return targets, nil
+}
@@ -0,0 +1,74 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Event 接続中のブラウザなどに配信するイベント
+type Event struct {
+	Type      string      `json:"type"` // result / run_started / run_finished / scheduler / alert
+	Timestamp time.Time   `json:"timestamp"`
+	RunID     string      `json:"run_id,omitempty"`
+	Project   string      `json:"project,omitempty"` // プロジェクトの定期チェックのイベントの場合はプロジェクト名
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// projectKey コンテキストにプロジェクト名を格納するキー
+type projectKey struct{}
+
+// WithProject プロジェクトの定期チェックの実行であることをコンテキストに設定（実行中に配信するイベントにプロジェクト名を付ける）
+func WithProject(ctx context.Context, project string) context.Context {
+	return context.WithValue(ctx, projectKey{}, project)
+}
+
+// ProjectFromContext コンテキストのプロジェクト名（全体の実行の場合は空文字）
+func ProjectFromContext(ctx context.Context) string {
+	project, _ := ctx.Value(projectKey{}).(string)
+	return project
+}
+
+// subscriberBuffer 購読者ごとに溜めておけるイベント数（超えた分は破棄する）
+const subscriberBuffer = 256
+
+var (
+	mutex       sync.Mutex
+	subscribers = make(map[chan Event]struct{})
+)
+
+// Subscribe イベントの購読を開始
+func Subscribe() chan Event {
+	ch := make(chan Event, subscriberBuffer)
+	mutex.Lock()
+	defer mutex.Unlock()
+	subscribers[ch] = struct{}{}
+	return ch
+}
+
+// Unsubscribe イベントの購読を終了
+func Unsubscribe(ch chan Event) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if _, ok := subscribers[ch]; ok {
+		delete(subscribers, ch)
+		close(ch)
+	}
+}
+
+// Publish すべての購読者にイベントを配信
+// 受信が追いつかない購読者にはイベントを破棄し、チェックの実行を止めない
+func Publish(e Event) {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for ch := range subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package har
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"healthcheck/internal/checker"
+)
+
+// File HARファイルのルート構造
+type File struct {
+	Log Log `json:"log"`
+}
+
+// Log HARのlogセクション
+type Log struct {
+	Pages   []Page  `json:"pages"`
+	Entries []Entry `json:"entries"`
+}
+
+// Page HARのページ情報
+type Page struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// Entry 記録された1リクエスト分のエントリ
+type Entry struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request 記録されたリクエスト
+type Request struct {
+	Method   string    `json:"method"`
+	URL      string    `json:"url"`
+	Headers  []Header  `json:"headers"`
+	PostData *PostData `json:"postData,omitempty"`
+}
+
+// Response 記録されたレスポンス
+type Response struct {
+	Status  int     `json:"status"`
+	Content Content `json:"content"`
+}
+
+// Header HARのヘッダー
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData リクエストボディ
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Content レスポンスボディの情報
+type Content struct {
+	MimeType string `json:"mimeType"`
+}
+
+// 静的アセットとみなす拡張子
+var staticExtensions = map[string]bool{
+	".js": true, ".mjs": true, ".css": true, ".map": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true, ".ico": true, ".avif": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+	".mp4": true, ".webm": true, ".mp3": true,
+}
+
+// 静的アセットとみなすContent-Typeのプレフィックス
+var staticMimePrefixes = []string{
+	"image/", "font/", "audio/", "video/",
+	"text/css", "text/javascript", "application/javascript", "application/x-javascript",
+	"application/font", "application/x-font",
+}
+
+// ステップに引き継がないヘッダー（クッキーはジャーで、その他はトランスポートで管理）
+var skippedHeaders = map[string]bool{
+	"cookie": true, "host": true, "content-length": true, "connection": true,
+	"accept-encoding": true, "keep-alive": true, "transfer-encoding": true, "upgrade": true,
+}
+
+// Parse HARデータをパース
+func Parse(data []byte) (*File, error) {
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse HAR: %w", err)
+	}
+	return &f, nil
+}
+
+// IsStatic エントリが静的アセットへのリクエストかどうか
+func IsStatic(e Entry) bool {
+	u, err := url.Parse(e.Request.URL)
+	if err != nil {
+		return true
+	}
+	if staticExtensions[strings.ToLower(path.Ext(u.Path))] {
+		return true
+	}
+	mime := strings.ToLower(e.Response.Content.MimeType)
+	for _, prefix := range staticMimePrefixes {
+		if strings.HasPrefix(mime, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToTransaction HARを静的アセットを除いたトランザクションチェックに変換
+func ToTransaction(name string, f *File) (*checker.Transaction, error) {
+	if name == "" && len(f.Log.Pages) > 0 {
+		name = f.Log.Pages[0].Title
+	}
+
+	tx := &checker.Transaction{Name: name}
+	for _, e := range f.Log.Entries {
+		if !strings.HasPrefix(e.Request.URL, "http://") && !strings.HasPrefix(e.Request.URL, "https://") {
+			continue
+		}
+		if IsStatic(e) {
+			continue
+		}
+
+		step := checker.Step{
+			Method:         strings.ToUpper(e.Request.Method),
+			URL:            e.Request.URL,
+			ExpectedStatus: e.Response.Status,
+		}
+		for _, h := range e.Request.Headers {
+			// HTTP/2の疑似ヘッダー（:authority等）も除外
+			if strings.HasPrefix(h.Name, ":") || skippedHeaders[strings.ToLower(h.Name)] {
+				continue
+			}
+			if step.Headers == nil {
+				step.Headers = make(map[string]string)
+			}
+			step.Headers[h.Name] = h.Value
+		}
+		if e.Request.PostData != nil {
+			step.Body = e.Request.PostData.Text
+		}
+		tx.Steps = append(tx.Steps, step)
+	}
+
+	if len(tx.Steps) == 0 {
+		return nil, fmt.Errorf("no checkable requests found in HAR")
+	}
+	if tx.Name == "" {
+		tx.Name = tx.Steps[0].URL
+	}
+	return tx, nil
+}
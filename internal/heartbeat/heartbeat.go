@@ -0,0 +1,209 @@
+package heartbeat
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/storage"
+)
+
+// Monitor 外部のジョブから送られるハートビートを受け付け、途絶えた対象を失敗とするチェック方法
+// checker.Registerで"heartbeat"スキームのチェック方法として登録する
+type Monitor struct {
+	mutex   sync.Mutex
+	path    string
+	started time.Time
+	targets map[string]config.Target           // URLごとのハートビート対象
+	states  map[string]*storage.HeartbeatState // URLごとのトークンと最終受信日時
+}
+
+// Status ハートビート対象の現在の状態
+type Status struct {
+	Name     string    `json:"name"`
+	URL      string    `json:"url"`
+	Token    string    `json:"-"`
+	Period   string    `json:"period"`
+	LastPing time.Time `json:"last_ping"`
+	Deadline time.Time `json:"deadline"`
+	Up       bool      `json:"up"`
+}
+
+// NewMonitor 設定のheartbeat対象からMonitorを作成
+// トークンは保存済みのものを引き継ぎ、未発行の対象には新しく発行する
+func NewMonitor(cfg *config.Config) *Monitor {
+	m := &Monitor{
+		path:    storage.HeartbeatsFile,
+		started: time.Now(),
+		targets: make(map[string]config.Target),
+	}
+
+	states, err := storage.LoadHeartbeats(m.path)
+	if err != nil {
+		slog.Warn("failed to load heartbeats", "error", err)
+		states = make(map[string]*storage.HeartbeatState)
+	}
+	m.states = states
+	m.register(cfg.Targets)
+	return m
+}
+
+// Update 再読み込みした設定のheartbeat対象に置き換える（受信済みの状態とトークンは引き継ぐ）
+func (m *Monitor) Update(cfg *config.Config) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.targets = make(map[string]config.Target)
+	m.register(cfg.Targets)
+}
+
+// register heartbeat対象を登録し、未発行の対象にはトークンを発行する（呼び出し側でロックする）
+func (m *Monitor) register(targets []config.Target) {
+	changed := false
+	for _, t := range targets {
+		if t.Type != "heartbeat" {
+			continue
+		}
+		m.targets[t.URL] = t
+
+		state, ok := m.states[t.URL]
+		if !ok {
+			state = &storage.HeartbeatState{}
+			m.states[t.URL] = state
+		}
+		token := t.Token
+		if token == "" && state.Token == "" {
+			token = newToken()
+		}
+		if token != "" && token != state.Token {
+			state.Token = token
+			changed = true
+		}
+	}
+	if changed {
+		m.save()
+	}
+}
+
+// newToken 推測できないトークンを生成
+func newToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// save 状態をファイルに保存（呼び出し側でロックする）
+func (m *Monitor) save() {
+	if err := storage.SaveHeartbeats(m.path, m.states); err != nil {
+		slog.Warn("failed to save heartbeats", "error", err)
+	}
+}
+
+// Ping トークンに対応する対象のハートビートを記録（該当する対象がない場合はfalse）
+func (m *Monitor) Ping(token string) (config.Target, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for u, t := range m.targets {
+		state := m.states[u]
+		if state.Token != token {
+			continue
+		}
+		state.LastPing = time.Now()
+		m.save()
+		return t, true
+	}
+	return config.Target{}, false
+}
+
+// Scheme 担当するスキーム
+func (m *Monitor) Scheme() string { return "heartbeat" }
+
+// Check 最後のハートビートから期待する間隔と猶予を過ぎていれば失敗とする
+// 起動後にまだ受信していない場合は、起動時刻から数える
+func (m *Monitor) Check(ctx context.Context, target config.Target) *checker.CheckResult {
+	result := &checker.CheckResult{
+		URL:       target.URL,
+		Timestamp: time.Now().UTC(),
+	}
+
+	status, ok := m.status(target.URL)
+	if !ok {
+		result.Error = checker.CategoryHeartbeatError
+		result.ErrorMessage = "Heartbeat target is not configured"
+		return result
+	}
+
+	result.Success = status.Up
+	if !status.Up {
+		result.Error = checker.CategoryHeartbeatMissed
+		if status.LastPing.IsZero() {
+			result.ErrorMessage = fmt.Sprintf("No heartbeat received since %s (expected every %s)",
+				m.started.Format(time.RFC3339), status.Period)
+		} else {
+			result.ErrorMessage = fmt.Sprintf("Last heartbeat at %s (expected every %s)",
+				status.LastPing.Format(time.RFC3339), status.Period)
+		}
+	}
+	return result
+}
+
+// status 対象の現在の状態
+func (m *Monitor) status(targetURL string) (Status, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	t, ok := m.targets[targetURL]
+	if !ok {
+		return Status{}, false
+	}
+	state := m.states[targetURL]
+
+	// 設定の読み込み時に検証済み
+	period, _ := time.ParseDuration(t.Period)
+	grace, _ := time.ParseDuration(t.Grace)
+
+	since := state.LastPing
+	if since.IsZero() {
+		since = m.started
+	}
+	deadline := since.Add(period + grace)
+
+	return Status{
+		Name:     t.Name,
+		URL:      t.URL,
+		Token:    state.Token,
+		Period:   t.Period,
+		LastPing: state.LastPing,
+		Deadline: deadline,
+		Up:       time.Now().Before(deadline),
+	}, true
+}
+
+// Statuses すべてのハートビート対象の状態を名前順に返す
+func (m *Monitor) Statuses() []Status {
+	m.mutex.Lock()
+	urls := make([]string, 0, len(m.targets))
+	for u := range m.targets {
+		urls = append(urls, u)
+	}
+	m.mutex.Unlock()
+
+	var statuses []Status
+	for _, u := range urls {
+		if status, ok := m.status(u); ok {
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
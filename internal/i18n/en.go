@@ -0,0 +1,250 @@
+package i18n
+
+// en 英語のメッセージ
+var en = map[string]string{
+	// 共通
+	"success":             "Success",
+	"failure":             "Failed",
+	"success_rate":        "Success rate",
+	"status":              "Status",
+	"status_code":         "Status code",
+	"response_time":       "Response time",
+	"latency":             "Latency",
+	"error":               "Error",
+	"error_prefix":        "Error: ",
+	"degraded":            "Slow",
+	"flapping":            "Flapping",
+	"dependency_down":     "Not alerted individually because dependency %s is down",
+	"redirect":            "Redirect",
+	"recovered":           "Recovered",
+	"new_failure":         "New failure",
+	"uptime":              "Uptime",
+	"checks":              "Checks",
+	"failures":            "Failures",
+	"downtime":            "Downtime",
+	"error_budget_burned": "Error budget burned",
+	"avg_response_time":   "Avg response time",
+	"p95_response_time":   "p95 response time",
+	"max_response_time":   "Max response time",
+	"avg_latency":         "Avg latency",
+	"cache_hit_rate":      "Cache hit rate",
+	"count":               "Count",
+	"value":               "Value",
+	"min":                 "Min",
+	"avg":                 "Avg",
+	"max":                 "Max",
+	"error_rate":          "Error rate",
+	"minutes":             "%.1f min",
+	"line_number":         "Line %d",
+	"window_all":          "All",
+	"window_24h":          "24 hours",
+	"window_7d":           "7 days",
+	"window_30d":          "30 days",
+	"no_data":             "no data",
+	"today":               "Today",
+	"days_ago":            "%d days ago",
+	"run":                 "Run",
+	"running":             "Running...",
+	"checking":            "Checking...",
+	"check_failed":        "Check failed",
+	"action_failed":       "Action failed: ",
+	"connecting":          "Connecting...",
+	"connected":           "Connected",
+	"reconnecting":        "Reconnecting...",
+	"requests_unit":       " requests",
+	"results_unit":        " results",
+	"completed_unit":      " completed",
+	"seconds_unit":        "s",
+	"new_check":           "New check",
+	"nav_calendar":        "Calendar",
+	"nav_explorer":        "Explorer",
+	"nav_patterns":        "Patterns",
+	"nav_history":         "History",
+	"nav_heatmap":         "Heatmap",
+	"pause":               "Pause",
+	"resume":              "Resume",
+	"check_now":           "Check now",
+	"acknowledged":        "Acknowledged",
+	"acknowledged_until":  "until %s",
+	"acknowledge":         "Acknowledge",
+	"unacknowledge":       "Unacknowledge",
+	"acknowledge_note":    "Note (optional)",
+	"acknowledge_expires": "Expires after (e.g. 2h, leave empty to keep until recovery)",
+	"scheduler_running":   "▶ Running",
+	"scheduler_paused":    "⏸ Paused",
+	"scheduler_stopped":   "⏹ Stopped",
+	"theme_dark":          "Dark mode",
+	"theme_light":         "Light mode",
+
+	// ログイン
+	"login":          "Log in",
+	"logout":         "Log out",
+	"username":       "Username",
+	"password":       "Password",
+	"login_failed":   "Incorrect username or password",
+	"login_required": "Please log in",
+	"role_required":  "This action requires the %s role",
+
+	// インデックスページ
+	"index_subtitle":         "Check whether many URLs are alive, in parallel",
+	"index_urls":             "URLs (one per line):",
+	"index_urls_help":        "Comment lines (starting with #) and blank lines are ignored. Host names without a scheme are treated as https://, and duplicate URLs are merged. Use sitemap:URL or robots:URL to expand the pages in a sitemap",
+	"index_concurrency":      "Concurrency:",
+	"index_timeout":          "Timeout (seconds):",
+	"index_retries":          "Retries:",
+	"index_run":              "Run health check",
+	"index_targets":          "Check a target list (CSV, TSV or Excel):",
+	"index_targets_help":     "Columns are URL, name, expected status, tags (key=value separated by ;) and timeout. If the first row is a header, columns are matched by header name",
+	"index_targets_run":      "Check list",
+	"index_har":              "Transaction check from a HAR file:",
+	"index_har_help":         "Loads a HAR recorded in the browser and replays its requests in order, skipping static assets",
+	"index_har_run":          "Check HAR",
+	"live":                   "Live",
+	"runs_title":             "Running checks",
+	"runs_none":              "No checks are running",
+	"live_run_started":       "▶ Scheduled run started",
+	"live_run_finished":      "■ Scheduled run finished",
+	"live_scheduler_running": "⏱ Scheduled checks: running",
+	"live_scheduler_paused":  "⏸ Scheduled checks: paused",
+	"live_scheduler_stopped": "⏱ Scheduled checks: stopped",
+
+	// ダッシュボード
+	"dash_run_at":                "Run at: %s",
+	"dash_live_notice":           "🟢 Updates automatically when a scheduled run finishes",
+	"dash_scheduler":             "Scheduled checks:",
+	"total_requests":             "Total requests",
+	"dash_rejected":              "Rejected input",
+	"dash_regression":            "Changes since the last run",
+	"dash_compared_with":         "Compared with: %s",
+	"dash_no_regression":         "No significant changes since the last run",
+	"response_time_change":       "Response time %+.0f%%",
+	"status_code_distribution":   "Status codes",
+	"response_time_distribution": "Response time distribution",
+	"latency_distribution":       "Latency distribution",
+	"dash_details":               "Details",
+	"response_body":              "Response",
+	"ip_info":                    "Connected IP",
+	"ip_address":                 "IP address",
+	"reverse_dns":                "Reverse DNS",
+	"location":                   "Location",
+	"resolved_ips":               "Resolved IPs",
+	"browser_metrics":            "Browser page load",
+	"network_idle":               "Network idle",
+	"page_requests":              "Requests",
+	"console_errors":             "Console errors",
+	"failed_resources":           "Failed resources",
+	"resource_scan":              "Subresources (%d checked)",
+	"insecure_resource":          "loaded over HTTP (mixed content)",
+	"dash_uptime_slo":            "Uptime (SLO %.2f%%)",
+	"no_history_in_window":       "No history in this period",
+
+	// ベンチマーク
+	"bench_description": "Checks each URL the given number of times and shows the response time distribution and error rate (results are not saved to history)",
+	"bench_count":       "Count:",
+	"bench_rate":        "Rate (req/s, 0 for unlimited):",
+	"bench_concurrency": "Concurrency:",
+
+	// 時間帯・曜日別分析
+	"patterns_title":       "🕒 Hourly and weekday patterns",
+	"patterns_description": "Shows recurring trends in latency and failure rate per target from history",
+	"patterns_hourly":      "By hour",
+	"patterns_weekday":     "By weekday",
+	"target_label":         "Target:",
+	"avg_latency_ms":       "Avg latency (ms)",
+	"failure_rate_percent": "Failure rate (%)",
+	"hour_unit":            ":00",
+	"weekdays":             "Sun,Mon,Tue,Wed,Thu,Fri,Sat",
+
+	// ヒートマップ
+	"heatmap_title":            "🌡️ Latency heatmap",
+	"heatmap_description":      "Shows latency and failures per target and time slot from history",
+	"heatmap_bucket":           "Slot:",
+	"heatmap_bucket_auto":      "Auto",
+	"heatmap_color_by":         "Color by:",
+	"heatmap_by_latency":       "Latency (vs. median)",
+	"heatmap_by_status":        "Success / failure",
+	"heatmap_legend_normal":    "Normal",
+	"heatmap_legend_slow":      "Up to 2× median",
+	"heatmap_legend_very_slow": "Up to 4× median",
+	"heatmap_legend_extreme":   "Over 4× median",
+	"heatmap_legend_partial":   "Some failures",
+
+	// 実行の履歴
+	"history_title":         "🗂️ Run History",
+	"history_description":   "Saved runs, newest first. Click a metadata value to show only runs with the same value",
+	"history_filter":        "Filter",
+	"history_remove_filter": "Remove this filter",
+	"history_time":          "Time",
+	"history_run_id":        "Run ID",
+	"history_metadata":      "Metadata",
+	"history_targets":       "Targets",
+	"history_failures":      "%d failed",
+	"history_empty":         "No matching runs",
+
+	// カレンダー
+	"event_scheduled":      "Scheduled",
+	"event_maintenance":    "Maintenance",
+	"event_incident":       "Incident",
+	"calendar_today":       " (today)",
+	"calendar_empty":       "Nothing scheduled",
+	"calendar_scheduled":   "Scheduled checks: %d runs (%d targets, every %v)",
+	"calendar_maintenance": "%s (%d targets)",
+	"calendar_correlated":  "%s: %d targets failed at the same time",
+	"calendar_ongoing":     " (ongoing)",
+
+	// エクスプローラー
+	"explorer_description":   "Groups and aggregates saved results",
+	"explorer_group_by":      "Group by:",
+	"explorer_window":        "Period:",
+	"explorer_tag":           "Tag:",
+	"avg_response_time_ms":   "Avg response time (ms)",
+	"dimension_domain":       "Domain",
+	"dimension_status_class": "Status class",
+	"dimension_url":          "URL",
+	"dimension_error":        "Error type",
+	"dimension_hour":         "Hour",
+	"dimension_region":       "Region",
+	"dimension_tag":          "Tag: %s",
+
+	// ステータスページ
+	"status_title":           "Status",
+	"status_default_service": "Services",
+	"status_all_up":          "✅ All systems operational",
+	"status_some_down":       "⚠️ Some systems are experiencing problems",
+	"status_incidents":       "Active incidents",
+	"status_ongoing_since":   "Ongoing since %s",
+	"state_up":               "Operational",
+	"state_down":             "Down",
+	"state_degraded":         "Degraded",
+	"state_flapping":         "Flapping",
+	"state_unknown":          "Unknown",
+	"last_updated":           "Last updated: %s",
+
+	// コマンドライン
+	"cli_error":              "Error: %v",
+	"cli_summary":            "Success: %d / %d (%.1f%%)  Avg: %.0fms  p95: %.0fms  Duration: %v",
+	"cli_p95_exceeded":       "Threshold not met: p95 response time %v exceeds %v",
+	"cli_success_rate_below": "Threshold not met: success rate %.1f%% is below %.1f%%",
+	"cli_watch_header":       "Checking every %v (run %d)  %s  Press Ctrl+C to quit",
+	"cli_histogram":          "Response time histogram: %s",
+
+	"main_option_error":        "Invalid option: %v",
+	"main_invalid_listen":      "-listen must be a host and port (e.g. 0.0.0.0:8080): %q",
+	"main_invalid_timeout":     "-timeout must be a positive duration (e.g. 10s): %v",
+	"main_invalid_concurrency": "-concurrency must be at least 1: %d",
+	"main_invalid_retries":     "-retries must be 0 or more: %d",
+	"main_invalid_domain_rate": "-domain-rate must be at least 1: %d",
+	"main_invalid_global_rate": "-global-rate must be at least 1: %d",
+	"main_invalid_results_dir": "-results-dir must be a directory",
+	"main_invalid_output":      "-output must be one of %s: %q",
+	"main_invalid_duplicates":  "-duplicates must be one of %s: %q",
+	"main_hash_error":          "Failed to generate hash: %v",
+	"main_import_error":        "Import failed: %v",
+	"main_import_saved":        "%s (%d steps): %s",
+	"main_config_error":        "Failed to load config file: %v",
+	"main_logging_error":       "Failed to set up logging: %v",
+	"main_demo_error":          "Failed to create demo data: %v",
+	"main_export_status_error": "Failed to export status page: %v",
+	"main_export_status_done":  "Exported status page: %s",
+	"main_server_error":        "Failed to start server: %v",
+}
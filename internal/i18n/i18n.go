@@ -0,0 +1,114 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage 言語が指定されていない場合に使用する言語
+const DefaultLanguage = "ja"
+
+// Languages 対応している言語（切り替えの表示順）
+var Languages = []string{"ja", "en"}
+
+// names 言語の切り替えに表示する言語の名前
+var names = map[string]string{
+	"ja": "日本語",
+	"en": "English",
+}
+
+// catalogs 言語別のメッセージのカタログ
+var catalogs = map[string]map[string]string{
+	"ja": ja,
+	"en": en,
+}
+
+// Supported 言語に対応しているかどうか
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Name 言語の名前（その言語での表記）
+func Name(lang string) string {
+	return names[lang]
+}
+
+// Match 言語タグ（en-US、ja_JP.UTF-8など）を対応している言語に変換（対応していない場合は空）
+func Match(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_.@"); i >= 0 {
+		tag = tag[:i]
+	}
+	if Supported(tag) {
+		return tag
+	}
+	return ""
+}
+
+// FromAcceptLanguage Accept-Languageヘッダーのうち優先度が最も高い対応言語（ない場合は空）
+func FromAcceptLanguage(header string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		quality := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			quality = q
+		}
+		if lang := Match(tag); lang != "" && quality > 0 {
+			candidates = append(candidates, candidate{lang, quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	// 同じ優先度の場合はヘッダーでの順番を優先する
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].lang
+}
+
+// FromEnv 環境変数（LC_ALL・LC_MESSAGES・LANGの順）の言語（対応していない場合は空）
+func FromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return Match(v)
+		}
+	}
+	return ""
+}
+
+// T カタログから指定言語のメッセージを取得
+// 未対応の言語・カタログにないメッセージはデフォルト言語を使い、それもない場合はキーを返す
+func T(lang, key string, args ...interface{}) string {
+	format, ok := catalogs[lang][key]
+	if !ok {
+		format, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Translator 指定言語のメッセージを取得する関数（テンプレートのtとして使う）
+func Translator(lang string) func(key string, args ...interface{}) string {
+	return func(key string, args ...interface{}) string {
+		return T(lang, key, args...)
+	}
+}
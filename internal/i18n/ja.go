@@ -0,0 +1,250 @@
+package i18n
+
+// ja 日本語のメッセージ
+var ja = map[string]string{
+	// 共通
+	"success":             "成功",
+	"failure":             "失敗",
+	"success_rate":        "成功率",
+	"status":              "ステータス",
+	"status_code":         "ステータスコード",
+	"response_time":       "応答時間",
+	"latency":             "レイテンシ",
+	"error":               "エラー",
+	"error_prefix":        "エラー: ",
+	"degraded":            "遅延",
+	"flapping":            "不安定",
+	"dependency_down":     "依存先 %s がダウンしているため、個別には通知していません",
+	"redirect":            "リダイレクト",
+	"recovered":           "復旧",
+	"new_failure":         "新たな失敗",
+	"uptime":              "稼働率",
+	"checks":              "チェック数",
+	"failures":            "失敗数",
+	"downtime":            "ダウンタイム",
+	"error_budget_burned": "エラーバジェット消費",
+	"avg_response_time":   "平均応答時間",
+	"p95_response_time":   "p95応答時間",
+	"max_response_time":   "最大応答時間",
+	"avg_latency":         "平均レイテンシ",
+	"cache_hit_rate":      "キャッシュヒット率",
+	"count":               "件数",
+	"value":               "値",
+	"min":                 "最小",
+	"avg":                 "平均",
+	"max":                 "最大",
+	"error_rate":          "エラー率",
+	"minutes":             "%.1f分",
+	"line_number":         "%d行目",
+	"window_all":          "すべて",
+	"window_24h":          "24時間",
+	"window_7d":           "7日間",
+	"window_30d":          "30日間",
+	"no_data":             "データなし",
+	"today":               "今日",
+	"days_ago":            "%d日前",
+	"run":                 "実行",
+	"running":             "実行中...",
+	"checking":            "チェック中...",
+	"check_failed":        "チェックに失敗しました",
+	"action_failed":       "操作に失敗しました: ",
+	"connecting":          "接続中...",
+	"connected":           "接続済み",
+	"reconnecting":        "再接続中...",
+	"requests_unit":       "件",
+	"results_unit":        "件の結果",
+	"completed_unit":      "件完了",
+	"seconds_unit":        "秒",
+	"new_check":           "新しいチェック",
+	"nav_calendar":        "カレンダー",
+	"nav_explorer":        "エクスプローラー",
+	"nav_patterns":        "時間帯分析",
+	"nav_history":         "履歴",
+	"nav_heatmap":         "ヒートマップ",
+	"pause":               "一時停止",
+	"resume":              "再開",
+	"check_now":           "今すぐチェック",
+	"acknowledged":        "確認済み",
+	"acknowledged_until":  "%s まで",
+	"acknowledge":         "確認済みにする",
+	"unacknowledge":       "確認を取り消す",
+	"acknowledge_note":    "対応状況のメモ（空欄可）",
+	"acknowledge_expires": "確認の期限（例: 2h、空欄の場合は復旧するまで）",
+	"scheduler_running":   "▶ 実行中",
+	"scheduler_paused":    "⏸ 一時停止中",
+	"scheduler_stopped":   "⏹ 停止中",
+	"theme_dark":          "ダークモード",
+	"theme_light":         "ライトモード",
+
+	// ログイン
+	"login":          "ログイン",
+	"logout":         "ログアウト",
+	"username":       "ユーザー名",
+	"password":       "パスワード",
+	"login_failed":   "ユーザー名またはパスワードが正しくありません",
+	"login_required": "ログインしてください",
+	"role_required":  "この操作には%sの権限が必要です",
+
+	// インデックスページ
+	"index_subtitle":         "複数のURLの生存確認を並列で実行します",
+	"index_urls":             "URLリスト（1行に1つのURL）:",
+	"index_urls_help":        "コメント行（#で始まる行）と空行は無視されます。スキームを省略したホスト名は https:// とみなし、重複したURLは1つにまとめます。sitemap:URL または robots:URL と指定するとサイトマップのページに展開します",
+	"index_concurrency":      "並列度:",
+	"index_timeout":          "タイムアウト（秒）:",
+	"index_retries":          "リトライ回数:",
+	"index_run":              "ヘルスチェック実行",
+	"index_targets":          "対象の一覧（CSV・TSV・Excel）からチェック:",
+	"index_targets_help":     "列はURL・名前・期待するステータス・タグ（key=valueを;区切り）・タイムアウトです。1行目に見出しがある場合は見出しで列を判定します",
+	"index_targets_run":      "一覧をチェック",
+	"index_har":              "HARファイルからトランザクションチェック:",
+	"index_har_help":         "ブラウザで記録したHARを読み込み、静的アセットを除いたリクエストを順番に実行します",
+	"index_har_run":          "HARをチェック",
+	"live":                   "ライブ",
+	"runs_title":             "実行中のチェック",
+	"runs_none":              "実行中のチェックはありません",
+	"live_run_started":       "▶ 定期チェックを開始しました",
+	"live_run_finished":      "■ 定期チェックが完了しました",
+	"live_scheduler_running": "⏱ 定期チェック: 実行中",
+	"live_scheduler_paused":  "⏸ 定期チェック: 一時停止",
+	"live_scheduler_stopped": "⏱ 定期チェック: 停止",
+
+	// ダッシュボード
+	"dash_run_at":                "実行日時: %s",
+	"dash_live_notice":           "🟢 定期チェックの完了時に自動で更新します",
+	"dash_scheduler":             "定期チェック:",
+	"total_requests":             "総リクエスト数",
+	"dash_rejected":              "受け付けなかった入力",
+	"dash_regression":            "前回の実行からの変化",
+	"dash_compared_with":         "比較対象: %s",
+	"dash_no_regression":         "前回から大きな変化はありません",
+	"response_time_change":       "応答時間 %+.0f%%",
+	"status_code_distribution":   "ステータスコード分布",
+	"response_time_distribution": "応答時間分布",
+	"latency_distribution":       "レイテンシ分布",
+	"dash_details":               "詳細結果",
+	"response_body":              "応答の内容",
+	"ip_info":                    "接続先のIPアドレス",
+	"ip_address":                 "IPアドレス",
+	"reverse_dns":                "逆引き",
+	"location":                   "地域",
+	"resolved_ips":               "名前解決の結果",
+	"browser_metrics":            "ブラウザでの読み込み",
+	"network_idle":               "ネットワークの完了",
+	"page_requests":              "リクエスト数",
+	"console_errors":             "コンソールのエラー",
+	"failed_resources":           "読み込みに失敗したリソース",
+	"resource_scan":              "サブリソースの確認（%d件）",
+	"insecure_resource":          "HTTPで読み込み（混在コンテンツ）",
+	"dash_uptime_slo":            "稼働率（SLO %.2f%%）",
+	"no_history_in_window":       "この期間の履歴はありません",
+
+	// ベンチマーク
+	"bench_description": "各URLを指定回数ずつチェックし、応答時間の分布とエラー率を表示します（結果は履歴に保存されません）",
+	"bench_count":       "回数:",
+	"bench_rate":        "レート (req/s、0で無制限):",
+	"bench_concurrency": "並列数:",
+
+	// 時間帯・曜日別分析
+	"patterns_title":       "🕒 時間帯・曜日別分析",
+	"patterns_description": "履歴から対象ごとのレイテンシと失敗率の周期的な傾向を表示します",
+	"patterns_hourly":      "時間帯別",
+	"patterns_weekday":     "曜日別",
+	"target_label":         "対象:",
+	"avg_latency_ms":       "平均レイテンシ (ms)",
+	"failure_rate_percent": "失敗率 (%)",
+	"hour_unit":            "時",
+	"weekdays":             "日,月,火,水,木,金,土",
+
+	// ヒートマップ
+	"heatmap_title":            "🌡️ レイテンシのヒートマップ",
+	"heatmap_description":      "履歴から対象と時間枠ごとのレイテンシと失敗を色で表示します",
+	"heatmap_bucket":           "時間枠:",
+	"heatmap_bucket_auto":      "自動",
+	"heatmap_color_by":         "色分け:",
+	"heatmap_by_latency":       "レイテンシ（中央値との比）",
+	"heatmap_by_status":        "成功・失敗",
+	"heatmap_legend_normal":    "通常",
+	"heatmap_legend_slow":      "中央値の2倍まで",
+	"heatmap_legend_very_slow": "中央値の4倍まで",
+	"heatmap_legend_extreme":   "中央値の4倍超",
+	"heatmap_legend_partial":   "一部失敗",
+
+	// 実行の履歴
+	"history_title":         "🗂️ 実行の履歴",
+	"history_description":   "保存された実行を新しい順に表示します。メタデータをクリックすると同じ値の実行に絞り込みます",
+	"history_filter":        "絞り込む",
+	"history_remove_filter": "この条件を外す",
+	"history_time":          "実行日時",
+	"history_run_id":        "実行ID",
+	"history_metadata":      "メタデータ",
+	"history_targets":       "対象数",
+	"history_failures":      "%d件失敗",
+	"history_empty":         "該当する履歴はありません",
+
+	// カレンダー
+	"event_scheduled":      "実行予定",
+	"event_maintenance":    "メンテナンス",
+	"event_incident":       "インシデント",
+	"calendar_today":       "（今日）",
+	"calendar_empty":       "予定はありません",
+	"calendar_scheduled":   "定期チェック %d回（%d件の対象、%v間隔）",
+	"calendar_maintenance": "%s（%d件の対象）",
+	"calendar_correlated":  "%s: %d件の対象が同時に失敗",
+	"calendar_ongoing":     "（継続中）",
+
+	// エクスプローラー
+	"explorer_description":   "保存された結果をグループ化して集計します",
+	"explorer_group_by":      "集計軸:",
+	"explorer_window":        "期間:",
+	"explorer_tag":           "タグ:",
+	"avg_response_time_ms":   "平均応答時間 (ms)",
+	"dimension_domain":       "ドメイン",
+	"dimension_status_class": "ステータス区分",
+	"dimension_url":          "URL",
+	"dimension_error":        "エラー種別",
+	"dimension_hour":         "時間帯",
+	"dimension_region":       "地域",
+	"dimension_tag":          "タグ: %s",
+
+	// ステータスページ
+	"status_title":           "ステータス",
+	"status_default_service": "サービス",
+	"status_all_up":          "✅ すべてのシステムは正常に稼働しています",
+	"status_some_down":       "⚠️ 一部のシステムで障害が発生しています",
+	"status_incidents":       "発生中のインシデント",
+	"status_ongoing_since":   "%s から継続中",
+	"state_up":               "稼働中",
+	"state_down":             "停止",
+	"state_degraded":         "遅延",
+	"state_flapping":         "不安定",
+	"state_unknown":          "不明",
+	"last_updated":           "最終更新: %s",
+
+	// コマンドライン
+	"cli_error":              "エラー: %v",
+	"cli_summary":            "成功: %d / %d（%.1f%%）  平均: %.0fms  p95: %.0fms  所要時間: %v",
+	"cli_p95_exceeded":       "基準を満たしていません: 応答時間のp95 %v が %v を超えています",
+	"cli_success_rate_below": "基準を満たしていません: 成功率 %.1f%% が %.1f%% を下回っています",
+	"cli_watch_header":       "%v ごとにチェック（%d回目）  %s  Ctrl+Cで終了",
+	"cli_histogram":          "応答時間の分布: %s",
+
+	"main_option_error":        "オプションの指定エラー: %v",
+	"main_invalid_listen":      "-listen はホストとポート（例: 0.0.0.0:8080）で指定してください: %q",
+	"main_invalid_timeout":     "-timeout は正の時間を指定してください（例: 10s）: %v",
+	"main_invalid_concurrency": "-concurrency は1以上を指定してください: %d",
+	"main_invalid_retries":     "-retries は0以上を指定してください: %d",
+	"main_invalid_domain_rate": "-domain-rate は1以上を指定してください: %d",
+	"main_invalid_global_rate": "-global-rate は1以上を指定してください: %d",
+	"main_invalid_results_dir": "-results-dir にディレクトリを指定してください",
+	"main_invalid_output":      "-output は %s のいずれかを指定してください: %q",
+	"main_invalid_duplicates":  "-duplicates は %s のいずれかを指定してください: %q",
+	"main_hash_error":          "ハッシュの生成エラー: %v",
+	"main_import_error":        "取り込みエラー: %v",
+	"main_import_saved":        "%s（%dステップ）: %s",
+	"main_config_error":        "設定ファイルの読み込みエラー: %v",
+	"main_logging_error":       "ログの設定エラー: %v",
+	"main_demo_error":          "デモデータの作成エラー: %v",
+	"main_export_status_error": "ステータスページの書き出しエラー: %v",
+	"main_export_status_done":  "ステータスページを書き出しました: %s",
+	"main_server_error":        "サーバー起動エラー: %v",
+}
@@ -0,0 +1,46 @@
+package i18n
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// location 画面・通知・レポートで日時を表示するタイムゾーン（設定のdisplay_timezoneを起動時・再読み込み時に反映する）
+// 保存する日時はUTCで、表示するときだけこのタイムゾーンに変換する
+var location atomic.Pointer[time.Location]
+
+// SetLocation 日時を表示するタイムゾーンを設定（nilの場合はサーバーのローカルタイムゾーン）
+func SetLocation(loc *time.Location) {
+	location.Store(loc)
+}
+
+// Location 日時を表示するタイムゾーン
+func Location() *time.Location {
+	if loc := location.Load(); loc != nil {
+		return loc
+	}
+	return time.Local
+}
+
+// DateTimeLayout 日時の表示形式（タイムゾーンの略称を付ける）
+const DateTimeLayout = "2006-01-02 15:04:05 MST"
+
+// In 日時を表示するタイムゾーンに変換
+func In(t time.Time) time.Time {
+	return t.In(Location())
+}
+
+// FormatTime 日時を表示するタイムゾーンでDateTimeLayoutの形式にする
+func FormatTime(t time.Time) string {
+	return In(t).Format(DateTimeLayout)
+}
+
+// TimeZoneName 表示するタイムゾーンのIANAの名前（サーバーのローカルタイムゾーンを使う場合は空）
+// ブラウザで日時を表示するときにIntl.DateTimeFormatのtimeZoneとして使う
+func TimeZoneName() string {
+	loc := Location()
+	if loc == time.Local {
+		return ""
+	}
+	return loc.String()
+}
@@ -0,0 +1,257 @@
+package importer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"healthcheck/internal/checker"
+)
+
+// ParseCurl curlコマンドの一覧をコマンドごとのトランザクションに変換
+// 行末の「\」による継続行と、#で始まるコメント行に対応する
+func ParseCurl(text, name string) ([]*checker.Transaction, error) {
+	var commands []string
+	var current strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if current.Len() == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "#")) {
+			continue
+		}
+		if strings.HasSuffix(trimmed, "\\") {
+			current.WriteString(strings.TrimSuffix(trimmed, "\\"))
+			current.WriteString(" ")
+			continue
+		}
+		current.WriteString(trimmed)
+		commands = append(commands, current.String())
+		current.Reset()
+	}
+	if current.Len() > 0 {
+		commands = append(commands, current.String())
+	}
+
+	var transactions []*checker.Transaction
+	for i, command := range commands {
+		step, err := parseCurlCommand(command)
+		if err != nil {
+			return nil, fmt.Errorf("command %d: %w", i+1, err)
+		}
+		txName := step.Method + " " + step.URL
+		if name != "" {
+			txName = name
+			if len(commands) > 1 {
+				txName = fmt.Sprintf("%s #%d", name, i+1)
+			}
+		}
+		transactions = append(transactions, &checker.Transaction{Name: txName, Steps: []checker.Step{step}})
+	}
+
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("no curl commands found")
+	}
+	return transactions, nil
+}
+
+// parseCurlCommand 1つのcurlコマンドをステップに変換
+func parseCurlCommand(command string) (checker.Step, error) {
+	args, err := splitShellWords(command)
+	if err != nil {
+		return checker.Step{}, err
+	}
+	if len(args) == 0 || args[0] != "curl" {
+		return checker.Step{}, fmt.Errorf("not a curl command")
+	}
+
+	step := checker.Step{}
+	var data []string
+	next := func(i *int, flag string) (string, error) {
+		*i++
+		if *i >= len(args) {
+			return "", fmt.Errorf("missing value for %s", flag)
+		}
+		return args[*i], nil
+	}
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		// --header=value の形式にも対応
+		flag, inline, hasInline := strings.Cut(arg, "=")
+		if !strings.HasPrefix(arg, "--") || !hasInline {
+			flag, inline, hasInline = arg, "", false
+		}
+		value := func() (string, error) {
+			if hasInline {
+				return inline, nil
+			}
+			return next(&i, flag)
+		}
+
+		switch flag {
+		case "-X", "--request":
+			v, err := value()
+			if err != nil {
+				return step, err
+			}
+			step.Method = strings.ToUpper(v)
+		case "-H", "--header":
+			v, err := value()
+			if err != nil {
+				return step, err
+			}
+			key, val, ok := strings.Cut(v, ":")
+			if !ok {
+				return step, fmt.Errorf("invalid header %q", v)
+			}
+			if step.Headers == nil {
+				step.Headers = make(map[string]string)
+			}
+			step.Headers[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		case "-d", "--data", "--data-raw", "--data-binary", "--data-ascii", "--data-urlencode":
+			v, err := value()
+			if err != nil {
+				return step, err
+			}
+			data = append(data, v)
+		case "--json":
+			v, err := value()
+			if err != nil {
+				return step, err
+			}
+			data = append(data, v)
+			setDefaultHeader(&step, "Content-Type", "application/json")
+			setDefaultHeader(&step, "Accept", "application/json")
+		case "-u", "--user":
+			v, err := value()
+			if err != nil {
+				return step, err
+			}
+			setDefaultHeader(&step, "Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(v)))
+		case "-A", "--user-agent":
+			v, err := value()
+			if err != nil {
+				return step, err
+			}
+			setDefaultHeader(&step, "User-Agent", v)
+		case "-b", "--cookie":
+			v, err := value()
+			if err != nil {
+				return step, err
+			}
+			setDefaultHeader(&step, "Cookie", v)
+		case "-e", "--referer":
+			v, err := value()
+			if err != nil {
+				return step, err
+			}
+			setDefaultHeader(&step, "Referer", v)
+		case "-I", "--head":
+			step.Method = http.MethodHead
+		case "--url":
+			v, err := value()
+			if err != nil {
+				return step, err
+			}
+			step.URL = v
+		case "-o", "--output", "-w", "--write-out", "-m", "--max-time", "--connect-timeout", "--retry":
+			// チェックには影響しないオプションは値ごと読み飛ばす
+			if _, err := value(); err != nil {
+				return step, err
+			}
+		default:
+			if !strings.HasPrefix(arg, "-") && step.URL == "" {
+				step.URL = arg
+			}
+		}
+	}
+
+	if step.URL == "" {
+		return step, fmt.Errorf("no URL found")
+	}
+	if !strings.Contains(step.URL, "://") {
+		step.URL = "http://" + step.URL
+	}
+	if len(data) > 0 {
+		step.Body = strings.Join(data, "&")
+		if step.Method == "" {
+			step.Method = http.MethodPost
+		}
+		setDefaultHeader(&step, "Content-Type", "application/x-www-form-urlencoded")
+	}
+	if step.Method == "" {
+		step.Method = http.MethodGet
+	}
+	return step, nil
+}
+
+// setDefaultHeader ヘッダーが未設定の場合のみ設定
+func setDefaultHeader(step *checker.Step, name, value string) {
+	if step.Headers == nil {
+		step.Headers = make(map[string]string)
+	}
+	for key := range step.Headers {
+		if strings.EqualFold(key, name) {
+			return
+		}
+	}
+	step.Headers[name] = value
+}
+
+// splitShellWords シェルと同様に引用符とエスケープを解釈して引数に分割
+func splitShellWords(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else if r == '\\' && i+1 < len(runes) && strings.ContainsRune(`"\$`+"`", runes[i+1]) {
+				i++
+				current.WriteRune(runes[i])
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			// $'...' はそのまま単一引用符として扱う
+			quote = '\''
+			inWord = true
+			i++
+		case r == '\\' && i+1 < len(runes):
+			i++
+			current.WriteRune(runes[i])
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
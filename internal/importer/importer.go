@@ -0,0 +1,64 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/har"
+)
+
+// Formats 取り込みに対応する形式
+var Formats = []string{"har", "curl", "postman"}
+
+// Import 既存のテスト資産をトランザクションチェックの定義に変換
+// formatが空の場合は内容から形式を判定する
+// HARは記録順の1つのトランザクション、curlとPostmanはリクエストごとのトランザクションになる
+func Import(data []byte, format, name string) ([]*checker.Transaction, error) {
+	if format == "" {
+		format = DetectFormat(data)
+	}
+
+	switch format {
+	case "har":
+		f, err := har.Parse(data)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := har.ToTransaction(name, f)
+		if err != nil {
+			return nil, err
+		}
+		return []*checker.Transaction{tx}, nil
+	case "curl":
+		return ParseCurl(string(data), name)
+	case "postman":
+		return ParsePostman(data)
+	}
+	return nil, fmt.Errorf("unsupported import format %q", format)
+}
+
+// DetectFormat 内容から形式を判定（JSONでない場合はcurlとみなす）
+func DetectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return "curl"
+	}
+
+	var probe struct {
+		Log  *json.RawMessage `json:"log"`
+		Info *json.RawMessage `json:"info"`
+		Item *json.RawMessage `json:"item"`
+	}
+	if err := json.Unmarshal(trimmed, &probe); err != nil {
+		return "curl"
+	}
+	if probe.Log != nil {
+		return "har"
+	}
+	if probe.Info != nil || probe.Item != nil {
+		return "postman"
+	}
+	return "curl"
+}
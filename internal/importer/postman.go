@@ -0,0 +1,189 @@
+package importer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"healthcheck/internal/checker"
+)
+
+// postmanCollection Postmanのコレクション（v2.0 / v2.1）
+type postmanCollection struct {
+	Info struct {
+		Name string `json:"name"`
+	} `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanKeyValue `json:"variable"`
+}
+
+// postmanItem リクエストまたはフォルダ
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item"`
+	Request *postmanRequest `json:"request"`
+}
+
+// postmanRequest リクエストの定義
+type postmanRequest struct {
+	Method string             `json:"method"`
+	Header []postmanKeyValue  `json:"header"`
+	URL    json.RawMessage    `json:"url"` // 文字列または{raw: ...}
+	Body   *postmanBody       `json:"body"`
+	Auth   *postmanAuthConfig `json:"auth"`
+}
+
+// postmanBody リクエストの本文
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw"`
+	URLEncoded []postmanKeyValue `json:"urlencoded"`
+}
+
+// postmanAuthConfig 認証の設定（bearerとbasicのみ対応）
+type postmanAuthConfig struct {
+	Type   string            `json:"type"`
+	Bearer []postmanKeyValue `json:"bearer"`
+	Basic  []postmanKeyValue `json:"basic"`
+}
+
+// postmanKeyValue ヘッダーや変数のキーと値
+type postmanKeyValue struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Disabled bool        `json:"disabled"`
+}
+
+// postmanVariable {{変数}}の参照
+var postmanVariable = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
+// ParsePostman Postmanのコレクションをリクエストごとのトランザクションに変換
+// フォルダ名はトランザクション名の接頭辞になり、コレクション変数は展開する
+func ParsePostman(data []byte) ([]*checker.Transaction, error) {
+	var collection postmanCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("failed to parse Postman collection: %w", err)
+	}
+
+	variables := make(map[string]string)
+	for _, v := range collection.Variable {
+		variables[v.Key] = fmt.Sprint(v.Value)
+	}
+	expand := func(s string) string {
+		return postmanVariable.ReplaceAllStringFunc(s, func(m string) string {
+			if v, ok := variables[strings.TrimSpace(m[2:len(m)-2])]; ok {
+				return v
+			}
+			return m
+		})
+	}
+
+	var transactions []*checker.Transaction
+	var walk func(items []postmanItem, prefix string) error
+	walk = func(items []postmanItem, prefix string) error {
+		for _, item := range items {
+			name := item.Name
+			if prefix != "" {
+				name = prefix + " / " + item.Name
+			}
+			if item.Request == nil {
+				if err := walk(item.Item, name); err != nil {
+					return err
+				}
+				continue
+			}
+
+			step, err := postmanStep(item.Request, expand)
+			if err != nil {
+				return fmt.Errorf("item %q: %w", name, err)
+			}
+			transactions = append(transactions, &checker.Transaction{Name: name, Steps: []checker.Step{step}})
+		}
+		return nil
+	}
+	if err := walk(collection.Item, collection.Info.Name); err != nil {
+		return nil, err
+	}
+
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("no requests found in Postman collection")
+	}
+	return transactions, nil
+}
+
+// postmanStep Postmanのリクエストをステップに変換
+func postmanStep(req *postmanRequest, expand func(string) string) (checker.Step, error) {
+	var rawURL string
+	if err := json.Unmarshal(req.URL, &rawURL); err != nil {
+		var structured struct {
+			Raw string `json:"raw"`
+		}
+		if err := json.Unmarshal(req.URL, &structured); err != nil {
+			return checker.Step{}, fmt.Errorf("invalid url")
+		}
+		rawURL = structured.Raw
+	}
+	rawURL = expand(rawURL)
+	if rawURL == "" {
+		return checker.Step{}, fmt.Errorf("url is empty")
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+
+	step := checker.Step{Method: strings.ToUpper(req.Method), URL: rawURL}
+	if step.Method == "" {
+		step.Method = "GET"
+	}
+	for _, h := range req.Header {
+		if h.Disabled {
+			continue
+		}
+		if step.Headers == nil {
+			step.Headers = make(map[string]string)
+		}
+		step.Headers[h.Key] = expand(fmt.Sprint(h.Value))
+	}
+
+	if req.Auth != nil {
+		switch req.Auth.Type {
+		case "bearer":
+			if token := postmanValue(req.Auth.Bearer, "token"); token != "" {
+				setDefaultHeader(&step, "Authorization", "Bearer "+expand(token))
+			}
+		case "basic":
+			credentials := expand(postmanValue(req.Auth.Basic, "username")) + ":" + expand(postmanValue(req.Auth.Basic, "password"))
+			setDefaultHeader(&step, "Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+		}
+	}
+
+	if req.Body != nil {
+		switch req.Body.Mode {
+		case "raw":
+			step.Body = expand(req.Body.Raw)
+		case "urlencoded":
+			form := url.Values{}
+			for _, kv := range req.Body.URLEncoded {
+				if !kv.Disabled {
+					form.Add(kv.Key, expand(fmt.Sprint(kv.Value)))
+				}
+			}
+			step.Body = form.Encode()
+			setDefaultHeader(&step, "Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	return step, nil
+}
+
+// postmanValue キーと値の一覧から値を取り出す
+func postmanValue(values []postmanKeyValue, key string) string {
+	for _, kv := range values {
+		if kv.Key == key {
+			return fmt.Sprint(kv.Value)
+		}
+	}
+	return ""
+}
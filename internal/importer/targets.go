@@ -0,0 +1,341 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"healthcheck/internal/config"
+	"healthcheck/internal/urllist"
+)
+
+// targetColumns 対象の一覧の列と、見出しとして受け付ける名前（大文字・小文字と空白・ハイフンの違いは無視する）
+var targetColumns = []struct {
+	field   string
+	headers []string
+}{
+	{"url", []string{"url", "address", "アドレス"}},
+	{"name", []string{"name", "名前", "名称"}},
+	{"expected_status", []string{"expected_status", "status", "ステータス", "期待するステータス"}},
+	{"tags", []string{"tags", "tag", "タグ"}},
+	{"timeout", []string{"timeout", "タイムアウト"}},
+}
+
+// TargetFormats 対象の一覧の取り込みに対応する形式
+var TargetFormats = []string{"csv", "tsv", "xlsx", "json"}
+
+// sheetRow 表の1行（lineは表での行番号）
+type sheetRow struct {
+	line  int
+	cells []string
+}
+
+// ImportedTarget 取り込んだ対象と、取り込み元での位置（表の行番号、JSONの場合は配列の何番目か）
+type ImportedTarget struct {
+	Line   int
+	Text   string
+	Target config.Target
+}
+
+// ImportTargets CSV・TSV・Excel（xlsx）の表、またはJSONの配列を対象の一覧に変換
+// 表は1行目がURL・名前・期待するステータス・タグ・タイムアウトの見出しの場合は見出しで列を判定し、
+// 見出しがない場合はこの順の列とみなす。空行と#で始まる行は無視し、不正な行は理由とともにrejectedで返す
+// formatが空の場合はファイル名と内容から形式を判定する
+func ImportTargets(data []byte, format, filename string) (targets []config.Target, rejected []urllist.Rejected, err error) {
+	imported, rejected, err := ImportTargetEntries(data, format, filename)
+	for _, t := range imported {
+		targets = append(targets, t.Target)
+	}
+	return targets, rejected, err
+}
+
+// ImportTargetEntries ImportTargetsと同じく変換し、対象ごとに取り込み元での位置を返す
+func ImportTargetEntries(data []byte, format, filename string) (targets []ImportedTarget, rejected []urllist.Rejected, err error) {
+	if format == "" {
+		format = DetectTargetFormat(data, filename)
+	}
+
+	var candidates []ImportedTarget
+	switch format {
+	case "csv", "tsv", "xlsx":
+		candidates, rejected, err = sheetTargets(data, format)
+	case "json":
+		candidates, rejected, err = jsonTargets(data)
+	default:
+		return nil, nil, fmt.Errorf("unsupported target format %q", format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := make(map[string]int)
+	for _, c := range candidates {
+		key := c.Target.Name + "\x00" + c.Target.URL
+		if first, ok := seen[key]; ok {
+			rejected = append(rejected, urllist.Rejected{Line: c.Line, Text: c.Text, Reason: fmt.Sprintf("%d行目と重複しています", first)})
+			continue
+		}
+		seen[key] = c.Line
+		targets = append(targets, c)
+	}
+	slices.SortStableFunc(rejected, func(a, b urllist.Rejected) int { return a.Line - b.Line })
+	return targets, rejected, nil
+}
+
+// sheetTargets 表の各行を対象に変換
+func sheetTargets(data []byte, format string) (targets []ImportedTarget, rejected []urllist.Rejected, err error) {
+	var rows []sheetRow
+	switch format {
+	case "csv":
+		rows, err = readDelimited(data, ',')
+	case "tsv":
+		rows, err = readDelimited(data, '\t')
+	case "xlsx":
+		rows, err = readXLSX(data)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := map[string]int{"url": 0, "name": 1, "expected_status": 2, "tags": 3, "timeout": 4}
+	if len(rows) > 0 {
+		if header, ok := headerColumns(rows[0].cells); ok {
+			columns = header
+			rows = rows[1:]
+		}
+	}
+
+	for _, row := range rows {
+		if isBlankRow(row.cells) || strings.HasPrefix(strings.TrimSpace(row.cells[0]), "#") {
+			continue
+		}
+		target, reason := rowTarget(row.cells, columns)
+		if reason != "" {
+			rejected = append(rejected, urllist.Rejected{Line: row.line, Text: rowText(row.cells), Reason: reason})
+			continue
+		}
+		targets = append(targets, ImportedTarget{Line: row.line, Text: rowText(row.cells), Target: target})
+	}
+	return targets, rejected, nil
+}
+
+// jsonTargets JSONの配列の各要素を対象に変換（要素はURLの文字列、または設定ファイルのtargetsと同じ形式のオブジェクト）
+// lineは配列の何番目の要素か（1から）
+func jsonTargets(data []byte) (targets []ImportedTarget, rejected []urllist.Rejected, err error) {
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse JSON: must be an array of URLs or targets: %w", err)
+	}
+
+	for i, raw := range entries {
+		line := i + 1
+		text := compactJSON(raw)
+
+		var target config.Target
+		var u string
+		if err := json.Unmarshal(raw, &u); err == nil {
+			target.URL = u
+		} else {
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&target); err != nil {
+				rejected = append(rejected, urllist.Rejected{Line: line, Text: text, Reason: "URLの文字列、または対象のオブジェクトを指定してください: " + err.Error()})
+				continue
+			}
+		}
+
+		// httpの対象はURLリストと同じく検証・正規化する
+		if target.Type == "" || target.Type == "http" {
+			if strings.TrimSpace(target.URL) == "" {
+				rejected = append(rejected, urllist.Rejected{Line: line, Text: text, Reason: "URLが空です"})
+				continue
+			}
+			normalized, err := urllist.Normalize(target.URL)
+			if err != nil {
+				rejected = append(rejected, urllist.Rejected{Line: line, Text: text, Reason: err.Error()})
+				continue
+			}
+			target.URL = normalized
+		}
+		if target.Name == "" {
+			target.Name = target.URL
+		}
+		targets = append(targets, ImportedTarget{Line: line, Text: text, Target: target})
+	}
+	return targets, rejected, nil
+}
+
+// compactJSON 受け付けなかった要素として表示するJSON（改行・空白を詰める）
+func compactJSON(raw json.RawMessage) string {
+	var b bytes.Buffer
+	if err := json.Compact(&b, raw); err != nil {
+		return string(raw)
+	}
+	return b.String()
+}
+
+// DetectTargetFormat ファイル名の拡張子、なければ内容から形式を判定
+// （zipはxlsx、[で始まる場合はjson、1行目にタブを含む場合はtsv、それ以外はcsv）
+func DetectTargetFormat(data []byte, filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".xlsx":
+		return "xlsx"
+	case ".tsv", ".tab":
+		return "tsv"
+	case ".csv":
+		return "csv"
+	case ".json":
+		return "json"
+	}
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return "xlsx"
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return "json"
+	}
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Contains(firstLine, []byte("\t")) {
+		return "tsv"
+	}
+	return "csv"
+}
+
+// readDelimited 区切り文字で区切られたテキストを行に分ける（Excelが付けるBOMは取り除く）
+func readDelimited(data []byte, comma rune) ([]sheetRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var rows []sheetRow
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse table: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, sheetRow{line: line, cells: cells})
+	}
+}
+
+// headerColumns 1行目が見出しの場合に列の名前と位置の対応を返す（URLの列がない場合は見出しとみなさない）
+func headerColumns(cells []string) (map[string]int, bool) {
+	columns := make(map[string]int)
+	for i, cell := range cells {
+		name := normalizeHeader(cell)
+		for _, c := range targetColumns {
+			for _, h := range c.headers {
+				if _, ok := columns[c.field]; !ok && name == normalizeHeader(h) {
+					columns[c.field] = i
+				}
+			}
+		}
+	}
+	_, ok := columns["url"]
+	return columns, ok
+}
+
+// normalizeHeader 見出しを比較するために小文字にし、空白・ハイフンをアンダースコアにそろえる
+func normalizeHeader(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(s)
+}
+
+// rowText 受け付けなかった行として表示する行の内容（空のセルは除く）
+func rowText(cells []string) string {
+	var parts []string
+	for _, c := range cells {
+		if c = strings.TrimSpace(c); c != "" {
+			parts = append(parts, c)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// isBlankRow すべてのセルが空の行か
+func isBlankRow(cells []string) bool {
+	for _, c := range cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// rowTarget 1行を対象に変換（不正な場合は理由を返す）
+func rowTarget(cells []string, columns map[string]int) (config.Target, string) {
+	cell := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(cells) {
+			return ""
+		}
+		return strings.TrimSpace(cells[i])
+	}
+
+	raw := cell("url")
+	if raw == "" {
+		return config.Target{}, "URLが空です"
+	}
+	u, err := urllist.Normalize(raw)
+	if err != nil {
+		return config.Target{}, err.Error()
+	}
+	target := config.Target{Name: cell("name"), URL: u}
+	if target.Name == "" {
+		target.Name = u
+	}
+
+	if v := cell("expected_status"); v != "" {
+		code, err := strconv.Atoi(strings.TrimSuffix(v, ".0"))
+		if err != nil || code < 100 || code > 599 {
+			return config.Target{}, fmt.Sprintf("期待するステータス %q は100〜599の数値で指定してください", v)
+		}
+		target.ExpectedStatus = code
+	}
+
+	if v := cell("tags"); v != "" {
+		tags, err := config.ParseTagFilter(strings.FieldsFunc(v, func(r rune) bool { return r == ';' || r == ',' }))
+		if err != nil {
+			return config.Target{}, fmt.Sprintf("タグ %q はkey=valueを;区切りで指定してください", v)
+		}
+		target.Tags = trimTags(tags)
+	}
+
+	if v := cell("timeout"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			// 単位のない数値は秒とみなす
+			seconds, numErr := strconv.ParseFloat(v, 64)
+			if numErr != nil {
+				d = 0
+			} else {
+				d = time.Duration(seconds * float64(time.Second))
+			}
+		}
+		if d <= 0 {
+			return config.Target{}, fmt.Sprintf("タイムアウト %q は正の時間（例: 10s）または秒数で指定してください", v)
+		}
+		target.Timeout = d.String()
+	}
+	return target, ""
+}
+
+// trimTags タグのキーと値の前後の空白を取り除く
+func trimTags(tags map[string]string) map[string]string {
+	trimmed := make(map[string]string, len(tags))
+	for k, v := range tags {
+		trimmed[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return trimmed
+}
@@ -0,0 +1,204 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// xlsxWorkbook ブックのシートの一覧（xl/workbook.xml）
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxRelationships ブックが参照するファイル（xl/_rels/workbook.xml.rels）
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxSharedStrings 共有文字列（xl/sharedStrings.xml）
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxText 文字列（書式付きの場合は複数の断片に分かれる）
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String 断片をつなげた文字列
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+// xlsxSheet シートのセル（xl/worksheets/sheetN.xml）
+type xlsxSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string   `xml:"r,attr"`
+			T      string   `xml:"t,attr"`
+			V      string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX Excelのブックの最初のシートを行に分ける（数式は計算済みの値を使う）
+func readXLSX(data []byte) ([]sheetRow, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open xlsx: %w", err)
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXLSXPart(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("failed to open xlsx: %s not found", sheetPath)
+	}
+	var sheet xlsxSheet
+	if err := decodeXLSXPart(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([]sheetRow, 0, len(sheet.Rows))
+	for i, row := range sheet.Rows {
+		line := row.R
+		if line == 0 {
+			line = i + 1
+		}
+		var cells []string
+		for j, c := range row.Cells {
+			column := j
+			if index := columnIndex(c.R); index >= 0 {
+				column = index
+			}
+			if column >= maxXLSXColumns {
+				return nil, fmt.Errorf("failed to parse xlsx: column out of range in %s", c.R)
+			}
+			var value string
+			switch c.T {
+			case "s":
+				index, err := strconv.Atoi(c.V)
+				if err != nil || index < 0 || index >= len(shared.Items) {
+					return nil, fmt.Errorf("failed to parse xlsx: invalid shared string in %s", c.R)
+				}
+				value = shared.Items[index].String()
+			case "inlineStr":
+				value = c.Inline.String()
+			case "b":
+				value = strings.ToUpper(strconv.FormatBool(c.V == "1"))
+			default:
+				value = c.V
+			}
+			for len(cells) <= column {
+				cells = append(cells, "")
+			}
+			cells[column] = value
+		}
+		rows = append(rows, sheetRow{line: line, cells: cells})
+	}
+	return rows, nil
+}
+
+// firstSheetPath ブックの最初のシートのファイル名
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+	workbookFile, ok := files["xl/workbook.xml"]
+	relsFile, hasRels := files["xl/_rels/workbook.xml.rels"]
+	if !ok || !hasRels {
+		return fallback, nil
+	}
+	var workbook xlsxWorkbook
+	if err := decodeXLSXPart(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	var rels xlsxRelationships
+	if err := decodeXLSXPart(relsFile, &rels); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("failed to parse xlsx: no sheets")
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RID {
+			continue
+		}
+		// 参照先はxl/からの相対パス、または/から始まるパッケージ内の絶対パス
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+// decodeXLSXPart ブック内のXMLファイルを読み込む
+func decodeXLSXPart(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open xlsx: %w", err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, maxXLSXPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse xlsx %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// maxXLSXPartSize ブック内の1つのXMLファイルから読み込む最大サイズ（展開後のサイズが極端に大きいファイルを避ける）
+const maxXLSXPartSize = 64 << 20
+
+// maxXLSXColumns Excelのシートの列数の上限（XFD列まで）
+const maxXLSXColumns = 16384
+
+// columnIndex セルの参照（例: "C12"）の列の位置（Aが0）
+// 上限の列を超える参照は、桁あふれしないようmaxXLSXColumnsを返す
+func columnIndex(ref string) int {
+	index := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+		if index > maxXLSXColumns {
+			return maxXLSXColumns
+		}
+	}
+	return index - 1
+}
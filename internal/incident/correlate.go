@@ -0,0 +1,75 @@
+package incident
+
+import (
+	"sort"
+	"time"
+
+	"healthcheck/internal/checker"
+)
+
+// CorrelatedEvent 短時間に同じドメイン・同じAS・同じタグで発生した複数のインシデントをまとめたイベント
+type CorrelatedEvent struct {
+	Key       string      `json:"key"` // 相関の単位（例: domain:example.com、asn:AS13335、tag:team=payments）
+	Start     time.Time   `json:"start"`
+	End       time.Time   `json:"end,omitempty"`
+	Ongoing   bool        `json:"ongoing"`
+	URLs      []string    `json:"urls"`
+	Incidents []*Incident `json:"incidents"`
+}
+
+// Correlate 開始時刻がwindow以内に収まる同じドメイン・同じAS・同じタグのインシデントをまとめる
+// 複数の単位に当てはまるインシデントは、より多くのインシデントをまとめられる単位に含める
+// minTargets未満の対象しか含まないグループは相関イベントとせず、個別のインシデントとして返す
+func Correlate(incidents []*Incident, window time.Duration, minTargets int) ([]*CorrelatedEvent, []*Incident) {
+	sorted := make([]*Incident, len(incidents))
+	copy(sorted, incidents)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start.Before(sorted[j].Start)
+	})
+
+	var candidates []checker.CorrelationGroup
+	var starts []time.Time
+	open := make(map[string]int)
+	for i, inc := range sorted {
+		for _, key := range checker.CorrelationKeys(inc.URL, inc.ASN, inc.Tags) {
+			g, exists := open[key]
+			if !exists || inc.Start.Sub(starts[g]) > window {
+				g = len(candidates)
+				candidates = append(candidates, checker.CorrelationGroup{Key: key})
+				starts = append(starts, inc.Start)
+				open[key] = g
+			}
+			candidates[g].Members = append(candidates[g].Members, i)
+		}
+	}
+
+	var events []*CorrelatedEvent
+	assigned := make(map[int]bool)
+	for _, group := range checker.SelectCorrelated(candidates, minTargets) {
+		g := &CorrelatedEvent{Key: group.Key, Start: sorted[group.Members[0]].Start}
+		for _, m := range group.Members {
+			inc := sorted[m]
+			assigned[m] = true
+			g.Incidents = append(g.Incidents, inc)
+			g.URLs = append(g.URLs, inc.URL)
+			if inc.Ongoing {
+				g.Ongoing = true
+			} else if inc.End.After(g.End) {
+				g.End = inc.End
+			}
+		}
+		if g.Ongoing {
+			g.End = time.Time{}
+		}
+		events = append(events, g)
+	}
+
+	var single []*Incident
+	for i, inc := range sorted {
+		if !assigned[i] {
+			single = append(single, inc)
+		}
+	}
+
+	return events, single
+}
@@ -0,0 +1,82 @@
+package incident
+
+import (
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/storage"
+)
+
+// Incident 対象が連続して失敗していた期間
+type Incident struct {
+	URL     string                `json:"url"`
+	Start   time.Time             `json:"start"`
+	End     time.Time             `json:"end,omitempty"` // 復旧した時刻（継続中の場合はゼロ値）
+	Ongoing bool                  `json:"ongoing"`
+	Error   checker.ErrorCategory `json:"error"`
+	Message string                `json:"message,omitempty"`
+
+	Tags map[string]string `json:"tags,omitempty"` // 対象のタグ（相関イベントの判定に使う）
+	ASN  uint64            `json:"asn,omitempty"`  // 接続先のAS番号（相関イベントの判定に使う）
+}
+
+// Duration インシデントの継続時間（継続中の場合は現在までの時間）
+func (i *Incident) Duration() time.Duration {
+	if i.Ongoing {
+		return time.Since(i.Start)
+	}
+	return i.End.Sub(i.Start)
+}
+
+// Detect 履歴からインシデントを検出（古い順に並んだ履歴を前提とする）
+func Detect(entries []*storage.HistoryEntry) []*Incident {
+	var incidents []*Incident
+	open := make(map[string]*Incident)
+
+	for _, entry := range entries {
+		for _, result := range entry.Results {
+			at := result.Timestamp
+			if at.IsZero() {
+				at = entry.Timestamp
+			}
+
+			current, exists := open[result.URL]
+			if result.Success {
+				if exists {
+					current.End = at
+					current.Ongoing = false
+					delete(open, result.URL)
+				}
+				continue
+			}
+			if !exists {
+				current = &Incident{
+					URL:     result.URL,
+					Start:   at,
+					Ongoing: true,
+					Error:   result.Error,
+					Message: result.ErrorMessage,
+					Tags:    result.Tags,
+				}
+				if result.IPInfo != nil {
+					current.ASN = result.IPInfo.ASN
+				}
+				open[result.URL] = current
+				incidents = append(incidents, current)
+			}
+		}
+	}
+
+	return incidents
+}
+
+// Active 継続中のインシデントのみを返す
+func Active(incidents []*Incident) []*Incident {
+	var active []*Incident
+	for _, i := range incidents {
+		if i.Ongoing {
+			active = append(active, i)
+		}
+	}
+	return active
+}
@@ -0,0 +1,147 @@
+package ipinfo
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Info IPアドレスの逆引きと地域・AS番号
+type Info struct {
+	IP        string   `json:"ip"`
+	Hostnames []string `json:"hostnames,omitempty"` // 逆引きしたホスト名
+	Country   string   `json:"country,omitempty"`   // 国コード（ISO 3166-1）
+	City      string   `json:"city,omitempty"`      // 都市名（英語）
+	ASN       uint64   `json:"asn,omitempty"`       // AS番号
+	ASOrg     string   `json:"as_org,omitempty"`    // ASの組織名
+}
+
+// Options 調べる内容
+type Options struct {
+	ReverseDNS bool     // 逆引きする
+	Databases  []string // 地域・AS番号を調べるMaxMind DB形式のファイル（City / Country / ASN）
+}
+
+// Enabled 調べる内容が指定されているか
+func (o Options) Enabled() bool {
+	return o.ReverseDNS || len(o.Databases) > 0
+}
+
+const (
+	// reverseDNSTTL 逆引きの結果を再利用する時間
+	reverseDNSTTL = time.Hour
+	// reverseDNSTimeout 逆引きの期限
+	reverseDNSTimeout = 2 * time.Second
+)
+
+// cachedReader 読み込んだデータベースと読み込み時のファイルの更新日時
+type cachedReader struct {
+	modTime time.Time
+	reader  *Reader
+}
+
+// cachedHostnames 逆引きの結果と有効期限
+type cachedHostnames struct {
+	names   []string
+	expires time.Time
+}
+
+var (
+	// readers パスごとに読み込んだデータベース（ファイルが更新された場合は読み込み直す）
+	readers sync.Map
+	// hostnames IPアドレスごとの逆引きの結果
+	hostnames sync.Map
+)
+
+// Lookup IPアドレスを逆引きし、データベースで地域とAS番号を調べる
+// データベースを読み込めない場合や見つからない場合は、その項目を空にする
+func Lookup(ctx context.Context, address string, opts Options) *Info {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil
+	}
+	info := &Info{IP: ip.String()}
+	if opts.ReverseDNS {
+		info.Hostnames = reverseLookup(ctx, info.IP)
+	}
+	for _, path := range opts.Databases {
+		reader, err := Load(path)
+		if err != nil {
+			continue
+		}
+		record, err := reader.Lookup(ip)
+		if err != nil || record == nil {
+			continue
+		}
+		fill(info, record)
+	}
+	return info
+}
+
+// Load データベースを読み込む（前回から更新されていない場合は読み込み済みのものを返す）
+func Load(path string) (*Reader, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := readers.Load(path); ok && cached.(*cachedReader).modTime.Equal(stat.ModTime()) {
+		return cached.(*cachedReader).reader, nil
+	}
+	reader, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	readers.Store(path, &cachedReader{modTime: stat.ModTime(), reader: reader})
+	return reader, nil
+}
+
+// reverseLookup IPアドレスを逆引き（失敗した場合も一定時間は結果を再利用する）
+func reverseLookup(ctx context.Context, ip string) []string {
+	if cached, ok := hostnames.Load(ip); ok && time.Now().Before(cached.(*cachedHostnames).expires) {
+		return cached.(*cachedHostnames).names
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, reverseDNSTimeout)
+	defer cancel()
+	names, _ := net.DefaultResolver.LookupAddr(lookupCtx, ip)
+	for i, name := range names {
+		names[i] = strings.TrimSuffix(name, ".")
+	}
+	hostnames.Store(ip, &cachedHostnames{names: names, expires: time.Now().Add(reverseDNSTTL)})
+	return names
+}
+
+// fill データベースのレコードから地域とAS番号を設定（設定済みの項目は上書きしない）
+func fill(info *Info, record map[string]interface{}) {
+	if info.Country == "" {
+		info.Country = lookupString(record, "country", "iso_code")
+		if info.Country == "" {
+			info.Country = lookupString(record, "registered_country", "iso_code")
+		}
+	}
+	if info.City == "" {
+		info.City = lookupString(record, "city", "names", "en")
+	}
+	if info.ASN == 0 {
+		info.ASN = toUint(record["autonomous_system_number"])
+	}
+	if info.ASOrg == "" {
+		info.ASOrg, _ = record["autonomous_system_organization"].(string)
+	}
+}
+
+// lookupString 入れ子のマップから文字列をたどる
+func lookupString(record map[string]interface{}, keys ...string) string {
+	var value interface{} = record
+	for _, key := range keys {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = m[key]
+	}
+	s, _ := value.(string)
+	return s
+}
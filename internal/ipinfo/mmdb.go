@@ -0,0 +1,325 @@
+package ipinfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+)
+
+// metadataMarker MaxMind DB形式のメタデータの開始を示すバイト列
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSeparator 検索木とデータ部の間の区切りのバイト数
+const dataSeparator = 16
+
+// Reader MaxMind DB形式（.mmdb）のデータベース
+// GeoLite2/GeoIP2のCity・Country・ASNなど、同じ形式のデータベースを読み込める
+type Reader struct {
+	buffer     []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	dataStart  uint
+	ipv4Start  uint
+	DBType     string // データベースの種類（例: GeoLite2-City）
+}
+
+// Open MaxMind DB形式のファイルを読み込む
+func Open(path string) (*Reader, error) {
+	buffer, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newReader(buffer)
+}
+
+// newReader メタデータを読み取り、検索木とデータ部の位置を求める
+func newReader(buffer []byte) (*Reader, error) {
+	start := bytes.LastIndex(buffer, metadataMarker)
+	if start < 0 {
+		return nil, errors.New("not a MaxMind DB file: metadata not found")
+	}
+	metaStart := uint(start + len(metadataMarker))
+	d := decoder{buffer: buffer[metaStart:]}
+	value, _, err := d.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode metadata: %w", err)
+	}
+	meta, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid metadata")
+	}
+
+	r := &Reader{buffer: buffer}
+	r.nodeCount = uint(toUint(meta["node_count"]))
+	r.recordSize = uint(toUint(meta["record_size"]))
+	r.ipVersion = uint(toUint(meta["ip_version"]))
+	r.DBType, _ = meta["database_type"].(string)
+	if r.recordSize != 24 && r.recordSize != 28 && r.recordSize != 32 {
+		return nil, fmt.Errorf("unsupported record size %d", r.recordSize)
+	}
+	treeSize := r.nodeCount * r.recordSize / 4
+	r.dataStart = treeSize + dataSeparator
+	if r.dataStart > metaStart {
+		return nil, errors.New("invalid search tree size")
+	}
+
+	// IPv6のデータベースでIPv4のアドレスを調べる場合は、先頭96ビットが0の位置から検索する
+	if r.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < r.nodeCount; i++ {
+			if node, err = r.readNode(node, 0); err != nil {
+				return nil, err
+			}
+		}
+		r.ipv4Start = node
+	}
+	return r, nil
+}
+
+// Lookup IPアドレスに対応するデータを返す（見つからない場合はnil）
+func (r *Reader) Lookup(ip net.IP) (map[string]interface{}, error) {
+	node := uint(0)
+	bits := 128
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+		bits = 32
+		if r.ipVersion == 6 {
+			node = r.ipv4Start
+		}
+	} else if r.ipVersion == 4 {
+		return nil, nil
+	}
+
+	for i := 0; i < bits && node < r.nodeCount; i++ {
+		bit := uint(ip[i>>3]>>(7-uint(i&7))) & 1
+		var err error
+		if node, err = r.readNode(node, bit); err != nil {
+			return nil, err
+		}
+	}
+	if node <= r.nodeCount {
+		// nodeCountと同じ値はデータがないことを示す
+		return nil, nil
+	}
+
+	offset := node - r.nodeCount - dataSeparator
+	d := decoder{buffer: r.buffer[r.dataStart:]}
+	value, _, err := d.decode(offset)
+	if err != nil {
+		return nil, err
+	}
+	record, _ := value.(map[string]interface{})
+	return record, nil
+}
+
+// readNode 検索木のノードの左（bit=0）または右（bit=1）のレコードを読む
+func (r *Reader) readNode(node, bit uint) (uint, error) {
+	base := node * r.recordSize / 4
+	if base+r.recordSize/4 > uint(len(r.buffer)) {
+		return 0, errors.New("invalid search tree node")
+	}
+	b := r.buffer[base:]
+	switch r.recordSize {
+	case 24:
+		offset := bit * 3
+		return uint(b[offset])<<16 | uint(b[offset+1])<<8 | uint(b[offset+2]), nil
+	case 28:
+		if bit == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6]), nil
+	default:
+		offset := bit * 4
+		return uint(binary.BigEndian.Uint32(b[offset:])), nil
+	}
+}
+
+// データ部の型
+const (
+	typeExtended = 0
+	typePointer  = 1
+	typeString   = 2
+	typeDouble   = 3
+	typeBytes    = 4
+	typeUint16   = 5
+	typeUint32   = 6
+	typeMap      = 7
+	typeInt32    = 8
+	typeUint64   = 9
+	typeUint128  = 10
+	typeArray    = 11
+	typeBool     = 14
+	typeFloat    = 15
+)
+
+// decoder MaxMind DB形式のデータ部を復号する
+type decoder struct {
+	buffer []byte
+}
+
+// decode offsetの値を復号し、値と次の値の位置を返す
+func (d *decoder) decode(offset uint) (interface{}, uint, error) {
+	kind, size, offset, err := d.control(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if kind == typePointer {
+		pointer, next, err := d.pointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer)
+		return value, next, err
+	}
+	return d.value(kind, size, offset)
+}
+
+// control 制御バイトから型とサイズを読む
+func (d *decoder) control(offset uint) (int, uint, uint, error) {
+	if offset >= uint(len(d.buffer)) {
+		return 0, 0, 0, errors.New("unexpected end of data")
+	}
+	ctrl := d.buffer[offset]
+	offset++
+	kind := int(ctrl >> 5)
+	if kind == typeExtended {
+		if offset >= uint(len(d.buffer)) {
+			return 0, 0, 0, errors.New("unexpected end of data")
+		}
+		kind = 7 + int(d.buffer[offset])
+		offset++
+	}
+	size := uint(ctrl & 0x1f)
+	if kind == typePointer {
+		return kind, size, offset, nil
+	}
+	if size >= 29 {
+		n := size - 28
+		if offset+n > uint(len(d.buffer)) {
+			return 0, 0, 0, errors.New("unexpected end of data")
+		}
+		extra := uint(0)
+		for _, b := range d.buffer[offset : offset+n] {
+			extra = extra<<8 | uint(b)
+		}
+		switch size {
+		case 29:
+			size = 29 + extra
+		case 30:
+			size = 285 + extra
+		default:
+			size = 65821 + extra
+		}
+		offset += n
+	}
+	return kind, size, offset, nil
+}
+
+// pointer ポインターの参照先（データ部の先頭からの位置）を読む
+func (d *decoder) pointer(size, offset uint) (uint, uint, error) {
+	n := (size>>3)&0x3 + 1
+	if offset+n > uint(len(d.buffer)) {
+		return 0, 0, errors.New("unexpected end of data")
+	}
+	value := uint(0)
+	for _, b := range d.buffer[offset : offset+n] {
+		value = value<<8 | uint(b)
+	}
+	switch n {
+	case 1:
+		value |= (size & 0x7) << 8
+	case 2:
+		value = value | (size&0x7)<<16 + 2048
+	case 3:
+		value = value | (size&0x7)<<24 + 526336
+	}
+	return value, offset + n, nil
+}
+
+// value 型に応じて値を復号する
+func (d *decoder) value(kind int, size, offset uint) (interface{}, uint, error) {
+	switch kind {
+	case typeMap:
+		m := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			value, next, err := d.decode(next)
+			if err != nil {
+				return nil, 0, err
+			}
+			name, _ := key.(string)
+			m[name] = value
+			offset = next
+		}
+		return m, offset, nil
+	case typeArray:
+		a := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			value, next, err := d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			a = append(a, value)
+			offset = next
+		}
+		return a, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	}
+
+	if offset+size > uint(len(d.buffer)) {
+		return nil, 0, errors.New("unexpected end of data")
+	}
+	b := d.buffer[offset : offset+size]
+	next := offset + size
+	switch kind {
+	case typeString:
+		return string(b), next, nil
+	case typeBytes:
+		return append([]byte(nil), b...), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, errors.New("invalid double size")
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, errors.New("invalid float size")
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), next, nil
+	case typeUint16, typeUint32, typeUint64, typeInt32:
+		value := uint64(0)
+		for _, c := range b {
+			value = value<<8 | uint64(c)
+		}
+		if kind == typeInt32 {
+			return int64(int32(value)), next, nil
+		}
+		return value, next, nil
+	case typeUint128:
+		// AS番号や地域の情報には使われないため、そのままのバイト列で返す
+		return append([]byte(nil), b...), next, nil
+	}
+	return nil, 0, fmt.Errorf("unsupported data type %d", kind)
+}
+
+// toUint 復号した数値をuint64に変換
+func toUint(v interface{}) uint64 {
+	switch n := v.(type) {
+	case uint64:
+		return n
+	case int64:
+		if n > 0 {
+			return uint64(n)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"healthcheck/internal/tracing"
+)
+
+// Formats 対応しているログの形式
+var Formats = []string{"text", "json"}
+
+// Setup 既定のロガーを設定（verboseの場合はDEBUGレベルも出力する）
+func Setup(format string, verbose bool) error {
+	handler, err := NewHandler(os.Stderr, format, verbose)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// NewHandler 形式とレベルを指定してハンドラーを作成
+// コンテキストのリクエストIDと実行IDは自動でログの属性に追加する
+func NewHandler(w io.Writer, format string, verbose bool) (slog.Handler, error) {
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch format {
+	case "", "text":
+		return &contextHandler{slog.NewTextHandler(w, opts)}, nil
+	case "json":
+		return &contextHandler{slog.NewJSONHandler(w, opts)}, nil
+	}
+	return nil, fmt.Errorf("unknown log format: %s", format)
+}
+
+// contextHandler コンテキストのリクエストIDと実行IDを属性として追加するハンドラー
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle リクエストIDと実行IDを追加して出力
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if id := tracing.RunID(ctx); id != "" {
+		r.AddAttrs(slog.String("run_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs 属性を追加したハンドラー
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup グループを追加したハンドラー
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID リクエストIDをコンテキストに設定
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID コンテキストのリクエストID（設定されていない場合は空）
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
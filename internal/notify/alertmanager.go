@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"context"
+	"encoding/base64"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// alertmanagerFiringTTL 発火中のアラートのendsAt（Alertmanagerは再送されないアラートをendsAtで解決済みとする）
+// 復旧時は現在時刻のendsAtで送信して解決する
+const alertmanagerFiringTTL = 24 * time.Hour
+
+// invalidLabelChars Alertmanagerのラベル名に使えない文字
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// AlertmanagerNotifier PrometheusのAlertmanagerのv2 APIでアラートを発火・解決する通知チャネル
+// 対象ごとに同じラベルの組で送信するため、Alertmanagerのサイレンス・ルーティング・グループ化がそのまま使える
+type AlertmanagerNotifier struct {
+	name     string
+	lang     string
+	url      string
+	username string
+	password string
+
+	mu     sync.Mutex
+	firing map[string]alertmanagerAlert // 発火中のアラート（インシデントごと、解決時に同じラベルで送信する）
+}
+
+// alertmanagerAlert 発火したアラートのラベルと開始日時
+type alertmanagerAlert struct {
+	labels   map[string]string
+	startsAt time.Time
+}
+
+// Name 通知チャネル名
+func (n *AlertmanagerNotifier) Name() string {
+	return n.name
+}
+
+// Notify ダウン・エスカレーションは発火、復旧は解決として送信（それ以外のアラートは送信しない）
+func (n *AlertmanagerNotifier) Notify(ctx context.Context, alert Alert) error {
+	action := pagerAction(alert)
+	if action == "" {
+		return nil
+	}
+
+	sent := n.track(action, alert)
+	endsAt := alert.Timestamp.Add(alertmanagerFiringTTL)
+	if action == "resolve" {
+		endsAt = alert.Timestamp
+	}
+	text := alert.Text(n.lang)
+	annotations := map[string]string{
+		"summary":     strings.SplitN(text, "\n", 2)[0],
+		"description": text,
+	}
+	if alert.Error != "" {
+		annotations["error"] = string(alert.Error)
+	}
+	if len(alert.Targets) > 0 {
+		annotations["targets"] = strings.Join(alert.Targets, "\n")
+	}
+	body := []map[string]interface{}{{
+		"labels":      sent.labels,
+		"annotations": annotations,
+		"startsAt":    sent.startsAt.UTC().Format(time.RFC3339),
+		"endsAt":      endsAt.UTC().Format(time.RFC3339),
+	}}
+
+	var headers map[string]string
+	if n.username != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte(n.username + ":" + n.password))
+		headers = map[string]string{"Authorization": "Basic " + credentials}
+	}
+	return postJSONWithHeaders(ctx, n.url+"/api/v2/alerts", headers, body)
+}
+
+// track 発火したアラートを記録し、解決時は発火時と同じラベルを返す
+// 相関アラートの復旧は最後に復旧した対象のアラートのため、記録がある場合は発火時のラベルで解決する
+func (n *AlertmanagerNotifier) track(action string, alert Alert) alertmanagerAlert {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.firing == nil {
+		n.firing = make(map[string]alertmanagerAlert)
+	}
+
+	sent, ok := n.firing[alert.Incident]
+	if action == "resolve" {
+		delete(n.firing, alert.Incident)
+		if ok {
+			return sent
+		}
+		return alertmanagerAlert{labels: alertmanagerLabels(alert), startsAt: alert.Timestamp}
+	}
+	if !ok {
+		sent = alertmanagerAlert{labels: alertmanagerLabels(alert), startsAt: alert.Timestamp}
+		if !alert.Since.IsZero() {
+			sent.startsAt = alert.Since
+		}
+		n.firing[alert.Incident] = sent
+	}
+	return sent
+}
+
+// alertmanagerLabels アラートを識別するラベル（対象のタグ・重大度・インシデントから作る）
+// タグのキーはラベル名に使えない文字を_に置き換える（alertname・instanceなどの予約したラベルは上書きしない）
+func alertmanagerLabels(alert Alert) map[string]string {
+	labels := make(map[string]string, len(alert.Tags)+5)
+	for k, v := range alert.Tags {
+		name := invalidLabelChars.ReplaceAllString(k, "_")
+		if name == "" || (name[0] >= '0' && name[0] <= '9') {
+			name = "_" + name
+		}
+		labels[name] = v
+	}
+	severity := alert.Severity
+	if severity == "" {
+		severity = "critical"
+	}
+	labels["alertname"] = "HealthcheckTargetDown"
+	labels["instance"] = alert.URL
+	labels["incident"] = alert.Incident
+	labels["severity"] = severity
+	labels["source"] = "healthcheck"
+	if alert.Correlation != "" {
+		labels["alertname"] = "HealthcheckCorrelatedDown"
+	}
+	return labels
+}
@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// EmailNotifier SMTPでメールを送信する通知チャネル
+type EmailNotifier struct {
+	name     string
+	lang     string
+	smtpAddr string // host:port
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// Name 通知チャネル名
+func (n *EmailNotifier) Name() string {
+	return n.name
+}
+
+// Notify アラートをメールで送信
+func (n *EmailNotifier) Notify(ctx context.Context, alert Alert) error {
+	text := alert.Text(n.lang)
+	subject := strings.SplitN(text, "\n", 2)[0]
+	return n.send(subject, text)
+}
+
+// send メールを送信
+func (n *EmailNotifier) send(subject, body string) error {
+	var auth smtp.Auth
+	if n.username != "" {
+		host, _, err := net.SplitHostPort(n.smtpAddr)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP address: %w", err)
+		}
+		auth = smtp.PlainAuth("", n.username, n.password, host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + n.from,
+		"To: " + strings.Join(n.to, ", "),
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	if err := smtp.SendMail(n.smtpAddr, auth, n.from, n.to, []byte(msg)); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package notify
+
+import "fmt"
+
+// DefaultLanguage 言語が指定されていない通知チャネルで使用する言語
+const DefaultLanguage = "ja"
+
+// messages 通知文の言語別カタログ
+var messages = map[string]map[string]string{
+	"ja": {
+		"down":       "🔴 ダウン",
+		"recovered":  "🟢 復旧",
+		"degraded":   "🟡 応答遅延",
+		"regression": "📈 前回からの変化",
+		"escalated":  "🚨 エスカレーション",
+		"unresolved": "%[2]s のダウンが%[1]s解決していません",
+		"digest":     "📋 稼働レポート",
+
+		"digest_period":         "%s: %s 〜 %s（実行%d回、チェック%d件、失敗%d件）",
+		"digest_uptime":         "稼働率:",
+		"digest_uptime_target":  "- %s: %.3f%%（ダウンタイム %.1f分）",
+		"digest_no_data":        "- この期間の履歴はありません",
+		"digest_slowest":        "応答時間の遅い対象:",
+		"digest_slowest_target": "- %s: 平均 %.0fms",
+		"digest_incidents":      "インシデント（%d件）:",
+		"digest_incident":       "- %s: %s 〜 %s（%s）",
+		"digest_ongoing":        "継続中",
+		"correlated":            "%[2]s の対象%[1]d件が同時に失敗しました",
+		"new_failure":           "新たな失敗: %s",
+		"recovered_target":      "復旧: %s",
+		"latency_change":        "応答時間 %+.0f%%: %s（%.0fms → %.0fms）",
+		"flapping":              "🔁 不安定（失敗と復旧を繰り返しています）",
+		"flapping_stopped":      "✅ 安定",
+		"flap_rate":             "直近のチェックでの状態の変化率: %.0f%%",
+		"dependents":            "この対象に依存する%d件の対象もダウンしています:",
+	},
+	"en": {
+		"down":       "🔴 DOWN",
+		"recovered":  "🟢 RECOVERED",
+		"degraded":   "🟡 DEGRADED",
+		"regression": "📈 Changes since last run",
+		"escalated":  "🚨 ESCALATED",
+		"unresolved": "Still down after %s (since %s)",
+		"digest":     "📋 Uptime report",
+
+		"digest_period":         "%s: %s - %s (%d runs, %d checks, %d failures)",
+		"digest_uptime":         "Uptime:",
+		"digest_uptime_target":  "- %s: %.3f%% (downtime %.1f min)",
+		"digest_no_data":        "- No history in this period",
+		"digest_slowest":        "Slowest targets:",
+		"digest_slowest_target": "- %s: avg %.0fms",
+		"digest_incidents":      "Incidents (%d):",
+		"digest_incident":       "- %s: %s - %s (%s)",
+		"digest_ongoing":        "ongoing",
+		"correlated":            "%d targets on %s failed at the same time",
+		"new_failure":           "New failure: %s",
+		"recovered_target":      "Recovered: %s",
+		"latency_change":        "Latency %+.0f%%: %s (%.0fms -> %.0fms)",
+		"flapping":              "🔁 FLAPPING",
+		"flapping_stopped":      "✅ STOPPED FLAPPING",
+		"flap_rate":             "State change rate over recent checks: %.0f%%",
+		"dependents":            "%d dependent targets are also down:",
+	},
+}
+
+// SupportedLanguage 通知文の言語がサポートされているかどうか
+func SupportedLanguage(lang string) bool {
+	_, ok := messages[lang]
+	return ok
+}
+
+// message カタログから指定言語のメッセージを取得（未対応の言語はデフォルト言語）
+func message(lang, key string, args ...interface{}) string {
+	catalog, ok := messages[lang]
+	if !ok {
+		catalog = messages[DefaultLanguage]
+	}
+	format, ok := catalog[key]
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
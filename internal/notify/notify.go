@@ -0,0 +1,254 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/digest"
+	"healthcheck/internal/i18n"
+	"healthcheck/internal/stats"
+)
+
+// Alert 通知するイベント
+type Alert struct {
+	Kind      string    `json:"kind"` // down / recovered / degraded / flapping / flapping_stopped / regression / escalated / digest
+	URL       string    `json:"url,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Severity  string    `json:"severity,omitempty"` // 重大度（config.Severitiesのいずれか）
+	Since     time.Time `json:"since,omitzero"`     // ダウンした日時（escalatedの場合）
+	Incident  string    `json:"incident,omitempty"` // 通知先でインシデントを対応付ける識別子（down・escalated、すべての対象が復旧したrecovered）
+
+	Error    checker.ErrorCategory `json:"error,omitempty"`     // 失敗の種類（downの場合）
+	FlapRate float64               `json:"flap_rate,omitempty"` // 直近の状態の変化率（%、flapping・flapping_stoppedの場合）
+
+	Correlation string            `json:"correlation,omitempty"` // 相関イベントの単位（例: domain:example.com、asn:AS13335、tag:team=payments）
+	Targets     []string          `json:"targets,omitempty"`     // 相関イベントに含まれる対象
+	Dependents  []string          `json:"dependents,omitempty"`  // この対象に依存していて同時にダウンした対象（個別には送信しない）
+	Regression  *stats.Regression `json:"regression,omitempty"`  // 前回の実行との差分（regressionの場合）
+	Digest      *digest.Report    `json:"digest,omitempty"`      // 稼働レポート（digestの場合）
+
+	Tags map[string]string `json:"tags,omitempty"` // 対象のタグ（相関イベントでは全対象に共通するタグ）
+}
+
+// Text 指定した言語（ja/en）で通知本文を返す
+func (a Alert) Text(lang string) string {
+	title := message(lang, a.Kind)
+	text := fmt.Sprintf("[%s] %s (%s)", title, a.URL, i18n.FormatTime(a.Timestamp))
+	if a.URL == "" {
+		text = fmt.Sprintf("[%s] (%s)", title, i18n.FormatTime(a.Timestamp))
+	}
+	if a.Correlation != "" {
+		text += "\n" + message(lang, "correlated", len(a.Targets), a.URL)
+	}
+	if a.Kind == "escalated" {
+		text += "\n" + message(lang, "unresolved", a.Timestamp.Sub(a.Since).Round(time.Minute).String(), i18n.FormatTime(a.Since))
+	}
+	if a.Kind == "flapping" || a.Kind == "flapping_stopped" {
+		text += "\n" + message(lang, "flap_rate", a.FlapRate)
+	}
+	if a.Message != "" {
+		text += "\n" + a.Message
+	}
+	for _, t := range a.Targets {
+		text += "\n- " + t
+	}
+	if len(a.Dependents) > 0 {
+		text += "\n" + message(lang, "dependents", len(a.Dependents))
+		for _, t := range a.Dependents {
+			text += "\n- " + t
+		}
+	}
+	if a.Regression != nil {
+		for _, u := range a.Regression.NewFailures {
+			text += "\n" + message(lang, "new_failure", u)
+		}
+		for _, u := range a.Regression.Recovered {
+			text += "\n" + message(lang, "recovered_target", u)
+		}
+		for _, c := range a.Regression.LatencyChanges {
+			text += "\n" + message(lang, "latency_change", c.ChangePercent, c.URL, c.PreviousMs(), c.CurrentMs())
+		}
+	}
+	if a.Digest != nil {
+		text += "\n" + digestText(lang, a.Digest)
+	}
+	return text
+}
+
+// digestText 稼働レポートの本文
+func digestText(lang string, r *digest.Report) string {
+	const layout = "2006-01-02 15:04 MST"
+	lines := []string{message(lang, "digest_period", r.Name, i18n.In(r.From).Format(layout), i18n.In(r.To).Format(layout), r.Runs, r.Checks, r.Failures)}
+
+	lines = append(lines, "", message(lang, "digest_uptime"))
+	for _, t := range r.Targets {
+		lines = append(lines, message(lang, "digest_uptime_target", t.URL, t.UptimePercent, t.DowntimeMinutes()))
+	}
+	if len(r.Targets) == 0 {
+		lines = append(lines, message(lang, "digest_no_data"))
+	}
+
+	if len(r.Slowest) > 0 {
+		lines = append(lines, "", message(lang, "digest_slowest"))
+		for _, s := range r.Slowest {
+			lines = append(lines, message(lang, "digest_slowest_target", s.URL, s.AvgResponseMs))
+		}
+	}
+
+	lines = append(lines, "", message(lang, "digest_incidents", len(r.Incidents)))
+	for _, inc := range r.Incidents {
+		end := message(lang, "digest_ongoing")
+		if !inc.Ongoing {
+			end = i18n.In(inc.End).Format(layout)
+		}
+		lines = append(lines, message(lang, "digest_incident", inc.URL, i18n.In(inc.Start).Format(layout), end, inc.Error))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Notifier 通知チャネルのインターフェース
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// NewNotifier 設定から通知チャネルを作成
+func NewNotifier(cfg config.NotifierConfig) (Notifier, error) {
+	lang := cfg.Language
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	if !SupportedLanguage(lang) {
+		return nil, fmt.Errorf("unsupported language: %s", lang)
+	}
+
+	switch cfg.Type {
+	case "webhook":
+		return &WebhookNotifier{name: cfg.Name, url: cfg.URL, lang: lang}, nil
+	case "slack":
+		return &SlackNotifier{name: cfg.Name, webhookURL: cfg.URL, lang: lang}, nil
+	case "pagerduty":
+		endpoint := cfg.URL
+		if endpoint == "" {
+			endpoint = pagerDutyEventsURL
+		}
+		return &PagerDutyNotifier{name: cfg.Name, lang: lang, url: endpoint, routingKey: cfg.RoutingKey}, nil
+	case "opsgenie":
+		endpoint := cfg.URL
+		if endpoint == "" {
+			endpoint = opsgenieAPIURL
+		}
+		return &OpsgenieNotifier{name: cfg.Name, lang: lang, url: strings.TrimSuffix(endpoint, "/"), apiKey: cfg.APIKey}, nil
+	case "alertmanager":
+		return &AlertmanagerNotifier{
+			name:     cfg.Name,
+			lang:     lang,
+			url:      strings.TrimSuffix(cfg.URL, "/"),
+			username: cfg.Username,
+			password: cfg.Password,
+		}, nil
+	case "email":
+		return &EmailNotifier{
+			name:     cfg.Name,
+			lang:     lang,
+			smtpAddr: cfg.SMTPAddr,
+			username: cfg.Username,
+			password: cfg.Password,
+			from:     cfg.From,
+			to:       cfg.To,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown notifier type: %s", cfg.Type)
+}
+
+// Send 名前を指定した通知チャネル（空の場合はすべて）にアラートを送信する（アラートのルールは使わない）
+func Send(ctx context.Context, cfgs []config.NotifierConfig, names []string, alert Alert) error {
+	var errs []error
+	for _, cfg := range cfgs {
+		if len(names) > 0 && !slices.Contains(names, cfg.Name) {
+			continue
+		}
+		n, err := NewNotifier(cfg)
+		if err != nil {
+			slog.WarnContext(ctx, "skipping notifier", "notifier", cfg.Name, "error", err)
+			continue
+		}
+		if err := n.Notify(ctx, alert); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", n.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Dispatcher 設定された通知チャネルにアラートを送信する構造体
+// アラートのルールによる振り分け・重複の抑止・エスカレーションのため、未解決のダウンを保持する
+type Dispatcher struct {
+	mutex        sync.Mutex
+	notifiers    []Notifier
+	tags         []map[string]string // 通知チャネルごとのタグの条件
+	rules        []config.AlertRule
+	dedupeWindow time.Duration
+	incidents    map[string]*incident // 対象のURL -> 未解決のダウン
+	sent         map[string]time.Time // 通知チャネル・種類・対象ごとの最後の送信日時
+}
+
+// NewDispatcher 設定から通知チャネルをまとめたDispatcherを作成
+func NewDispatcher(cfg *config.Config) *Dispatcher {
+	d := &Dispatcher{
+		incidents: make(map[string]*incident),
+		sent:      make(map[string]time.Time),
+	}
+	d.Update(cfg)
+	return d
+}
+
+// Update 再読み込みした設定の通知チャネルとルールに置き換える（未解決のダウンと送信履歴は引き継ぐ）
+func (d *Dispatcher) Update(cfg *config.Config) {
+	var notifiers []Notifier
+	var tags []map[string]string
+	for _, nc := range cfg.Notifiers {
+		n, err := NewNotifier(nc)
+		if err != nil {
+			slog.Warn("skipping notifier", "notifier", nc.Name, "error", err)
+			continue
+		}
+		notifiers = append(notifiers, n)
+		tags = append(tags, nc.Tags)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.notifiers = notifiers
+	d.tags = tags
+	d.rules = cfg.AlertRules
+	d.dedupeWindow = cfg.AlertDedupeWindow
+}
+
+// Dispatch アラートをルールに従って通知チャネルに送信（失敗は警告として出力）
+// あわせて、解決しないまま期限を過ぎたダウンをエスカレーションする
+func (d *Dispatcher) Dispatch(ctx context.Context, alerts []Alert) {
+	now := time.Now()
+	d.mutex.Lock()
+	var deliveries []delivery
+	for _, alert := range alerts {
+		deliveries = append(deliveries, d.route(alert, now)...)
+	}
+	deliveries = append(deliveries, d.escalate(now)...)
+	d.prune(now)
+	d.mutex.Unlock()
+
+	for _, dl := range deliveries {
+		if err := dl.notifier.Notify(ctx, dl.alert); err != nil {
+			slog.WarnContext(ctx, "failed to notify", "notifier", dl.notifier.Name(), "kind", dl.alert.Kind, "error", err)
+		}
+	}
+}
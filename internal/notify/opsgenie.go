@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// opsgenieAPIURL OpsgenieのAPIのベースURL（EUのアカウントはhttps://api.eu.opsgenie.comを指定する）
+const opsgenieAPIURL = "https://api.opsgenie.com"
+
+// opsgeniePriorities 重大度に対応するOpsgenieの優先度
+var opsgeniePriorities = map[string]string{
+	"critical": "P1",
+	"warning":  "P3",
+	"info":     "P5",
+}
+
+// OpsgenieNotifier OpsgenieのAlert APIでアラートを作成・クローズする通知チャネル
+// 対象ごとのaliasで、ダウンでアラートを作成し、復旧でクローズする
+type OpsgenieNotifier struct {
+	name   string
+	lang   string
+	url    string
+	apiKey string
+}
+
+// Name 通知チャネル名
+func (n *OpsgenieNotifier) Name() string {
+	return n.name
+}
+
+// Notify ダウン・エスカレーションはアラートの作成、復旧はクローズとして送信（それ以外のアラートは送信しない）
+func (n *OpsgenieNotifier) Notify(ctx context.Context, alert Alert) error {
+	alias := "healthcheck:" + alert.Incident
+	headers := map[string]string{"Authorization": "GenieKey " + n.apiKey}
+
+	switch pagerAction(alert) {
+	case "trigger":
+		text := alert.Text(n.lang)
+		var tags []string
+		for k, v := range alert.Tags {
+			tags = append(tags, k+"="+v)
+		}
+		sort.Strings(tags)
+		priority, ok := opsgeniePriorities[alert.Severity]
+		if !ok {
+			priority = opsgeniePriorities["critical"]
+		}
+		body := map[string]interface{}{
+			"message":     truncate(strings.SplitN(text, "\n", 2)[0], 130),
+			"alias":       truncate(alias, 512),
+			"description": truncate(text, 15000),
+			"entity":      alert.URL,
+			"source":      "healthcheck",
+			"priority":    priority,
+			"tags":        tags,
+			"details":     alert.Tags,
+		}
+		return postJSONWithHeaders(ctx, n.url+"/v2/alerts", headers, body)
+	case "resolve":
+		endpoint := n.url + "/v2/alerts/" + url.PathEscape(truncate(alias, 512)) + "/close?identifierType=alias"
+		return postJSONWithHeaders(ctx, endpoint, headers, map[string]string{
+			"source": "healthcheck",
+			"note":   alert.Text(n.lang),
+		})
+	}
+	return nil
+}
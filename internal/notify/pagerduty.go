@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// pagerDutyEventsURL PagerDutyのEvents API v2の送信先
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyNotifier PagerDutyのEvents API v2でインシデントを作成・解決する通知チャネル
+// 対象ごとのdedup_keyで、ダウンでインシデントを作成し、復旧で解決する
+type PagerDutyNotifier struct {
+	name       string
+	lang       string
+	url        string
+	routingKey string
+}
+
+// Name 通知チャネル名
+func (n *PagerDutyNotifier) Name() string {
+	return n.name
+}
+
+// Notify ダウン・エスカレーションはtrigger、復旧はresolveとして送信（それ以外のアラートは送信しない）
+func (n *PagerDutyNotifier) Notify(ctx context.Context, alert Alert) error {
+	action := pagerAction(alert)
+	if action == "" {
+		return nil
+	}
+
+	event := map[string]interface{}{
+		"routing_key":  n.routingKey,
+		"event_action": action,
+		"dedup_key":    truncate("healthcheck:"+alert.Incident, 255),
+	}
+	if action == "trigger" {
+		severity := alert.Severity
+		if severity == "" {
+			severity = "critical"
+		}
+		text := alert.Text(n.lang)
+		details := map[string]interface{}{"text": text}
+		if alert.Error != "" {
+			details["error"] = alert.Error
+		}
+		if len(alert.Targets) > 0 {
+			details["targets"] = alert.Targets
+		}
+		for k, v := range alert.Tags {
+			details["tag:"+k] = v
+		}
+		event["payload"] = map[string]interface{}{
+			"summary":        truncate(strings.SplitN(text, "\n", 2)[0], 1024),
+			"source":         alert.URL,
+			"severity":       severity,
+			"timestamp":      alert.Timestamp.Format(time.RFC3339),
+			"component":      alert.URL,
+			"class":          string(alert.Error),
+			"custom_details": details,
+		}
+	}
+	return postJSON(ctx, n.url, event)
+}
+
+// pagerAction インシデントを扱う通知先での操作（trigger / resolve、対象外のアラートは空）
+// 相関アラートの一部の対象だけが復旧した場合はIncidentが空のため解決しない
+func pagerAction(alert Alert) string {
+	if alert.Incident == "" {
+		return ""
+	}
+	switch alert.Kind {
+	case "down", "escalated":
+		return "trigger"
+	case "recovered":
+		return "resolve"
+	}
+	return ""
+}
+
+// truncate 文字列を指定した文字数までに切り詰める
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
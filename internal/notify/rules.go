@@ -0,0 +1,239 @@
+package notify
+
+import (
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
+
+	"healthcheck/internal/ack"
+	"healthcheck/internal/config"
+)
+
+// incident 未解決のダウン（エスカレーションと復旧は、ダウンを送信した通知チャネルにも送信する）
+type incident struct {
+	alert     Alert             // 最初に受け取ったダウンのアラート
+	rule      *config.AlertRule // 振り分けたルール（ルールを設定していない場合はnil）
+	notified  []string          // ダウン・エスカレーションを送信した通知チャネルの名前
+	targets   map[string]bool   // 未解決の対象（相関アラートの場合は複数）
+	escalated bool
+}
+
+// delivery 通知チャネルに送信するアラート
+type delivery struct {
+	notifier Notifier
+	alert    Alert
+}
+
+// route アラートの送信先を決め、ダウンの場合は未解決のダウンとして記録する
+// 復旧はダウンを送信した通知チャネルに送信し、記録がない場合（起動前のダウンなど）はダウンと同じ振り分けで送信する
+func (d *Dispatcher) route(alert Alert, now time.Time) []delivery {
+	if alert.Kind == "recovered" {
+		if inc, ok := d.incidents[alert.URL]; ok {
+			delete(d.incidents, alert.URL)
+			delete(inc.targets, alert.URL)
+			// 相関アラートは、含まれるすべての対象が復旧した時点でインシデントを解決する
+			if len(inc.targets) == 0 {
+				alert.Incident = inc.alert.Incident
+			}
+			return d.deliverTo(alert, inc.notified)
+		}
+		alert.Incident = alertKey(alert)
+		rule, ok := d.match("down", alert)
+		if !ok {
+			return nil
+		}
+		return d.deliver(alert, rule, now)
+	}
+
+	if alert.Kind == "down" {
+		alert.Incident = alertKey(alert)
+	}
+	kind := alert.Kind
+	if kind == "flapping_stopped" {
+		// 不安定の終了は開始と同じ振り分けで送信する
+		kind = "flapping"
+	}
+	rule, ok := d.match(kind, alert)
+	if !ok {
+		slog.Debug("no alert rule matched", "kind", alert.Kind, "url", alert.URL, "severity", alert.Severity)
+		return nil
+	}
+	var deliveries []delivery
+	if alert.Kind == "down" && acknowledged(alertTargets(alert), now) {
+		// 確認済みの対象は送信しないが、復旧を送信する通知チャネルがないよう未解決のダウンとして記録する
+		slog.Debug("suppressed acknowledged alert", "kind", alert.Kind, "url", alert.URL)
+	} else {
+		deliveries = d.deliver(alert, rule, now)
+	}
+
+	if alert.Kind == "down" {
+		inc := &incident{alert: alert, rule: rule, targets: make(map[string]bool)}
+		for _, dl := range deliveries {
+			inc.notified = appendName(inc.notified, dl.notifier.Name())
+		}
+		for _, t := range alertTargets(alert) {
+			if prev, ok := d.incidents[t]; ok {
+				delete(prev.targets, t)
+			}
+			d.incidents[t] = inc
+			inc.targets[t] = true
+		}
+	}
+	return deliveries
+}
+
+// match アラートに一致する最初のルール
+// ルールを設定していない場合はnilとtrueを返し、すべての通知チャネルに送信する
+func (d *Dispatcher) match(kind string, alert Alert) (*config.AlertRule, bool) {
+	if len(d.rules) == 0 {
+		return nil, true
+	}
+	for i := range d.rules {
+		if d.rules[i].Matches(kind, alert.Severity, alert.Tags) {
+			return &d.rules[i], true
+		}
+	}
+	return nil, false
+}
+
+// deliver ルールの通知チャネルにアラートを送信する（同じアラートを期間内に送信済みの通知チャネルは除く）
+// ルールがない場合は、タグの条件が一致するすべての通知チャネルに送信する
+func (d *Dispatcher) deliver(alert Alert, rule *config.AlertRule, now time.Time) []delivery {
+	window := d.dedupeWindow
+	if rule != nil && rule.DedupeWindow != "" {
+		window, _ = time.ParseDuration(rule.DedupeWindow)
+	}
+
+	var deliveries []delivery
+	for i, n := range d.notifiers {
+		if rule != nil && !slices.Contains(rule.Notifiers, n.Name()) {
+			continue
+		}
+		if rule == nil && len(d.tags[i]) > 0 && (len(alert.Tags) == 0 || !config.MatchTags(alert.Tags, d.tags[i])) {
+			continue
+		}
+
+		key := n.Name() + "|" + alert.Kind + "|" + alertKey(alert)
+		if last, ok := d.sent[key]; ok && window > 0 && now.Sub(last) < window {
+			slog.Debug("suppressed duplicate alert", "notifier", n.Name(), "kind", alert.Kind, "url", alert.URL)
+			continue
+		}
+		d.sent[key] = now
+		deliveries = append(deliveries, delivery{notifier: n, alert: alert})
+	}
+	return deliveries
+}
+
+// deliverTo 指定した名前の通知チャネルにアラートを送信する（重複の抑止はしない）
+func (d *Dispatcher) deliverTo(alert Alert, names []string) []delivery {
+	var deliveries []delivery
+	for _, n := range d.notifiers {
+		if slices.Contains(names, n.Name()) {
+			deliveries = append(deliveries, delivery{notifier: n, alert: alert})
+		}
+	}
+	return deliveries
+}
+
+// escalate ルールのescalate_afterを過ぎても解決しないダウンを、escalate_toの通知チャネルに送信する
+func (d *Dispatcher) escalate(now time.Time) []delivery {
+	var pending []*incident
+	seen := make(map[*incident]bool)
+	for _, inc := range d.incidents {
+		if seen[inc] || inc.escalated || inc.rule == nil || inc.rule.EscalateAfter == "" {
+			continue
+		}
+		seen[inc] = true
+		// 確認済みの間はエスカレーションせず、確認の期限を過ぎてから行う
+		targets := make([]string, 0, len(inc.targets))
+		for t := range inc.targets {
+			targets = append(targets, t)
+		}
+		if acknowledged(targets, now) {
+			continue
+		}
+		if after, _ := time.ParseDuration(inc.rule.EscalateAfter); now.Sub(inc.alert.Timestamp) >= after {
+			pending = append(pending, inc)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].alert.Timestamp.Before(pending[j].alert.Timestamp)
+	})
+
+	var deliveries []delivery
+	for _, inc := range pending {
+		inc.escalated = true
+		alert := inc.alert
+		alert.Kind = "escalated"
+		alert.Since = inc.alert.Timestamp
+		alert.Timestamp = now
+		if alert.Correlation != "" {
+			alert.Targets = nil
+			for t := range inc.targets {
+				alert.Targets = append(alert.Targets, t)
+			}
+			sort.Strings(alert.Targets)
+		}
+
+		slog.Info("escalating unresolved alert", "url", alert.URL, "since", alert.Since, "escalate_to", inc.rule.EscalateTo)
+		escalations := d.deliverTo(alert, inc.rule.EscalateTo)
+		for _, dl := range escalations {
+			inc.notified = appendName(inc.notified, dl.notifier.Name())
+		}
+		deliveries = append(deliveries, escalations...)
+	}
+	return deliveries
+}
+
+// prune 重複の抑止に使わなくなった送信履歴を削除
+func (d *Dispatcher) prune(now time.Time) {
+	longest := d.dedupeWindow
+	for _, rule := range d.rules {
+		if w, err := time.ParseDuration(rule.DedupeWindow); err == nil && w > longest {
+			longest = w
+		}
+	}
+	for key, last := range d.sent {
+		if now.Sub(last) >= longest {
+			delete(d.sent, key)
+		}
+	}
+}
+
+// alertTargets アラートの対象のURL（相関アラートの場合は含まれるすべての対象）
+func alertTargets(alert Alert) []string {
+	if alert.Correlation != "" {
+		return alert.Targets
+	}
+	return []string{alert.URL}
+}
+
+// acknowledged 対象がすべて確認済みか
+func acknowledged(targets []string, now time.Time) bool {
+	if len(targets) == 0 {
+		return false
+	}
+	for _, t := range targets {
+		if ack.Get(t, now) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// alertKey 重複を判定するアラートの単位（相関アラートは相関の単位、それ以外は対象のURL）
+func alertKey(alert Alert) string {
+	if alert.Correlation != "" {
+		return alert.Correlation
+	}
+	return alert.URL
+}
+
+// appendName 重複しないように通知チャネルの名前を追加
+func appendName(names []string, name string) []string {
+	if slices.Contains(names, name) {
+		return names
+	}
+	return append(names, name)
+}
@@ -0,0 +1,341 @@
+package notify
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+)
+
+// Tracker 対象ごとの状態を保持し、状態の変化をアラートに変換する構造体
+type Tracker struct {
+	config *config.Config
+	states map[string]string     // URL -> 最後に通知した状態（up / down / degraded）
+	flaps  map[string]*flapState // URL -> 不安定（フラッピング）の判定に使う直近の状態
+	held   map[string]Alert      // URL -> 依存先がダウンしているため送信していないダウンのアラート
+	mutex  sync.Mutex
+}
+
+// flapState 対象の直近のチェックの成否と、不安定と判定中か
+type flapState struct {
+	history  []bool // 古い順、最大でflap_window+1件
+	flapping bool
+	rate     float64 // 直近の状態の変化率（%）
+}
+
+// NewTracker 新しいTrackerインスタンスを作成
+func NewTracker(cfg *config.Config) *Tracker {
+	return &Tracker{
+		config: cfg,
+		states: make(map[string]string),
+		flaps:  make(map[string]*flapState),
+		held:   make(map[string]Alert),
+	}
+}
+
+// Update 再読み込みした設定に置き換える（対象ごとの状態と保留中のアラートは引き継ぐ）
+func (t *Tracker) Update(cfg *config.Config) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.config = cfg
+}
+
+// Evaluate 結果を前回の状態と比較し、変化があった対象のアラートを返す
+// 不安定（フラッピング）と判定した対象は結果のFlappingを設定し、状態の変化ごとのアラートの代わりに
+// 判定の開始（flapping）と終了（flapping_stopped）のアラートだけを返す
+func (t *Tracker) Evaluate(results []*checker.CheckResult) []Alert {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var alerts []Alert
+	for _, r := range results {
+		state := "up"
+		if !r.Success {
+			state = "down"
+		} else if r.Degraded {
+			state = "degraded"
+		}
+
+		wasFlapping, flap := t.observeFlapping(r.URL, r.Success)
+		if flap != nil && flap.flapping {
+			r.Flapping = true
+			if !wasFlapping {
+				alerts = append(alerts, Alert{Kind: "flapping", URL: r.URL, Timestamp: r.Timestamp, Severity: warningSeverity(r), FlapRate: flap.rate, Tags: r.Tags})
+			}
+			// 不安定な間は最後に通知した状態のままにし、安定したときにその状態と比較する
+			continue
+		}
+		if wasFlapping {
+			alerts = append(alerts, Alert{Kind: "flapping_stopped", URL: r.URL, Timestamp: r.Timestamp, Severity: warningSeverity(r), FlapRate: flap.rate, Tags: r.Tags})
+		}
+
+		prev := t.states[r.URL]
+		t.states[r.URL] = state
+		if prev == state {
+			continue
+		}
+
+		switch state {
+		case "down":
+			message := r.ErrorMessage
+			if r.Hint != "" {
+				message += "\n" + r.Hint
+			}
+			alerts = append(alerts, Alert{Kind: "down", URL: r.URL, Message: message, Timestamp: r.Timestamp, Severity: severity(r), Error: r.Error, Tags: r.Tags})
+		case "degraded":
+			if prev == "down" {
+				alerts = append(alerts, Alert{Kind: "recovered", URL: r.URL, Timestamp: r.Timestamp, Severity: severity(r), Tags: r.Tags})
+			}
+			alerts = append(alerts, Alert{Kind: "degraded", URL: r.URL, Message: r.DegradedMessage, Timestamp: r.Timestamp, Severity: warningSeverity(r), Tags: r.Tags})
+		case "up":
+			if prev == "down" {
+				alerts = append(alerts, Alert{Kind: "recovered", URL: r.URL, Timestamp: r.Timestamp, Severity: severity(r), Tags: r.Tags})
+			}
+		}
+	}
+	alerts = t.groupDependents(alerts, results)
+	return correlate(alerts, results, t.config.CorrelationMinTargets)
+}
+
+// groupDependents 依存先がダウンしている対象のダウンを個別に送信せず、依存先のダウンのアラートのDependentsにまとめる
+// 依存先が復旧しても対象のダウンが続いている場合は、その時点で対象のダウンを送信する
+func (t *Tracker) groupDependents(alerts []Alert, results []*checker.CheckResult) []Alert {
+	deps := t.config.Dependencies()
+	if len(deps) == 0 && len(t.held) == 0 {
+		return alerts
+	}
+
+	// downAncestor 依存先をたどってダウンしている最上位の対象（ない場合は空）
+	var downAncestor func(url string, depth int) string
+	downAncestor = func(url string, depth int) string {
+		if depth > len(deps) {
+			return ""
+		}
+		for _, p := range deps[url] {
+			if t.states[p] == "down" {
+				if top := downAncestor(p, depth+1); top != "" {
+					return top
+				}
+				return p
+			}
+		}
+		return ""
+	}
+
+	for _, r := range results {
+		if !r.Success {
+			r.DependencyDown = downAncestor(r.URL, 0)
+		}
+	}
+
+	downs := make(map[string]int)
+	for i, a := range alerts {
+		if a.Kind == "down" {
+			downs[a.URL] = i
+		}
+	}
+	grouped := make(map[int]bool)
+	for i, a := range alerts {
+		switch a.Kind {
+		case "down":
+			parent := downAncestor(a.URL, 0)
+			if parent == "" {
+				continue
+			}
+			t.held[a.URL] = a
+			grouped[i] = true
+			if j, ok := downs[parent]; ok {
+				alerts[j].Dependents = append(alerts[j].Dependents, a.URL)
+			}
+		case "recovered":
+			// ダウンを送信していない対象の復旧は送信しない
+			if _, ok := t.held[a.URL]; ok {
+				delete(t.held, a.URL)
+				grouped[i] = true
+			}
+		}
+	}
+
+	var kept []Alert
+	for i, a := range alerts {
+		if !grouped[i] {
+			kept = append(kept, a)
+		}
+	}
+
+	urls := make([]string, 0, len(t.held))
+	for url := range t.held {
+		urls = append(urls, url)
+	}
+	slices.Sort(urls)
+	now := time.Now().UTC()
+	for _, url := range urls {
+		a := t.held[url]
+		switch {
+		case t.states[url] != "down":
+			delete(t.held, url)
+		case downAncestor(url, 0) == "":
+			delete(t.held, url)
+			a.Timestamp = now
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// observeFlapping 今回の成否を記録し、Nagiosと同じく直近の状態の変化率で不安定かを判定する
+// 変化率がflap_high_threshold以上になったら不安定とし、flap_low_thresholdを下回るまで不安定のままとする
+// 判定しない設定の場合はnilを返す。wasFlappingは今回の判定の前に不安定だったか
+func (t *Tracker) observeFlapping(url string, success bool) (wasFlapping bool, flap *flapState) {
+	window := t.config.FlapWindow
+	if window <= 0 {
+		delete(t.flaps, url)
+		return false, nil
+	}
+	flap, ok := t.flaps[url]
+	if !ok {
+		flap = &flapState{}
+		t.flaps[url] = flap
+	}
+	wasFlapping = flap.flapping
+
+	flap.history = append(flap.history, success)
+	if len(flap.history) > window+1 {
+		flap.history = flap.history[len(flap.history)-window-1:]
+	}
+	flap.rate = stateChangeRate(flap.history)
+	switch {
+	case flap.flapping && flap.rate < t.config.FlapLowThreshold:
+		flap.flapping = false
+	case !flap.flapping && len(flap.history) == window+1 && flap.rate >= t.config.FlapHighThreshold:
+		// チェック回数がそろうまでは開始を判定しない
+		flap.flapping = true
+	}
+	return wasFlapping, flap
+}
+
+// stateChangeRate 状態の変化率（%）。新しい変化ほど重く（古い変化は0.8倍、最新の変化は1.2倍）数える
+func stateChangeRate(history []bool) float64 {
+	changes := len(history) - 1
+	if changes <= 0 {
+		return 0
+	}
+	var total float64
+	for i := 1; i < len(history); i++ {
+		if history[i] == history[i-1] {
+			continue
+		}
+		weight := 1.0
+		if changes > 1 {
+			weight = 0.8 + 0.4*float64(i-1)/float64(changes-1)
+		}
+		total += weight
+	}
+	return total / float64(changes) * 100
+}
+
+// correlate 同じドメイン・同じAS・同じタグで同時にダウンしたアラートを1件の相関アラートにまとめる
+// 複数の単位に当てはまる対象は、より多くの対象をまとめられる単位に含める
+func correlate(alerts []Alert, results []*checker.CheckResult, minTargets int) []Alert {
+	if minTargets < 2 {
+		return alerts
+	}
+	asns := make(map[string]uint64)
+	for _, r := range results {
+		if r.IPInfo != nil && r.IPInfo.ASN != 0 {
+			asns[r.URL] = r.IPInfo.ASN
+		}
+	}
+
+	var candidates []checker.CorrelationGroup
+	index := make(map[string]int)
+	for i, a := range alerts {
+		if a.Kind != "down" {
+			continue
+		}
+		for _, key := range checker.CorrelationKeys(a.URL, asns[a.URL], a.Tags) {
+			g, ok := index[key]
+			if !ok {
+				g = len(candidates)
+				candidates = append(candidates, checker.CorrelationGroup{Key: key})
+				index[key] = g
+			}
+			candidates[g].Members = append(candidates[g].Members, i)
+		}
+	}
+
+	groups := make(map[int]checker.CorrelationGroup) // 最初の要素の番号ごとの相関アラート
+	grouped := make(map[int]bool)
+	for _, group := range checker.SelectCorrelated(candidates, minTargets) {
+		groups[group.Members[0]] = group
+		for _, m := range group.Members {
+			grouped[m] = true
+		}
+	}
+
+	var merged []Alert
+	for i, a := range alerts {
+		if !grouped[i] {
+			merged = append(merged, a)
+			continue
+		}
+		group, first := groups[i]
+		if !first {
+			continue
+		}
+
+		_, unit, _ := strings.Cut(group.Key, ":")
+		correlated := Alert{
+			Kind:        "down",
+			URL:         unit,
+			Timestamp:   a.Timestamp,
+			Correlation: group.Key,
+		}
+		for j, m := range group.Members {
+			g := alerts[m]
+			correlated.Targets = append(correlated.Targets, g.URL)
+			if j == 0 {
+				correlated.Tags = g.Tags
+				correlated.Severity = g.Severity
+			} else {
+				correlated.Tags = commonTags(correlated.Tags, g.Tags)
+				// 含まれる対象のうち最も重大なもの
+				if slices.Index(config.Severities, g.Severity) < slices.Index(config.Severities, correlated.Severity) {
+					correlated.Severity = g.Severity
+				}
+			}
+		}
+		merged = append(merged, correlated)
+	}
+	return merged
+}
+
+// warningSeverity 遅延・不安定のアラートの重大度（対象がinfoの場合はinfo、それ以外はwarning）
+func warningSeverity(r *checker.CheckResult) string {
+	if r.Severity == "info" {
+		return "info"
+	}
+	return "warning"
+}
+
+// severity ダウンのアラートの重大度（対象に指定がない場合はcritical）
+func severity(r *checker.CheckResult) string {
+	if r.Severity != "" {
+		return r.Severity
+	}
+	return "critical"
+}
+
+// commonTags 両方に共通するタグ
+func commonTags(a, b map[string]string) map[string]string {
+	common := make(map[string]string)
+	for k, v := range a {
+		if b[k] == v {
+			common[k] = v
+		}
+	}
+	return common
+}
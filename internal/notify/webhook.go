@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// httpClient 通知送信用のHTTPクライアント
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// WebhookNotifier アラートをJSONでPOSTする通知チャネル
+type WebhookNotifier struct {
+	name string
+	url  string
+	lang string
+}
+
+// Name 通知チャネル名
+func (n *WebhookNotifier) Name() string {
+	return n.name
+}
+
+// Notify アラートをJSONで送信（textに設定した言語の本文を含める）
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	payload := struct {
+		Alert
+		Text string `json:"text"`
+	}{alert, alert.Text(n.lang)}
+	return postJSON(ctx, n.url, payload)
+}
+
+// SlackNotifier SlackのIncoming Webhookに送信する通知チャネル
+type SlackNotifier struct {
+	name       string
+	webhookURL string
+	lang       string
+}
+
+// Name 通知チャネル名
+func (n *SlackNotifier) Name() string {
+	return n.name
+}
+
+// Notify アラートをSlackに送信
+func (n *SlackNotifier) Notify(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, n.webhookURL, map[string]string{"text": alert.Text(n.lang)})
+}
+
+// postJSON JSONをPOSTし、2xx以外のステータスをエラーとして返す
+func postJSON(ctx context.Context, url string, payload interface{}) error {
+	return postJSONWithHeaders(ctx, url, nil, payload)
+}
+
+// postJSONWithHeaders 指定したヘッダー（認証など）を付けてJSONをPOSTする
+func postJSONWithHeaders(ctx context.Context, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "HealthCheck/1.0")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return nil
+}
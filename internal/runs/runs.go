@@ -0,0 +1,106 @@
+package runs
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxRecent 完了した実行を保持する数
+const maxRecent = 50
+
+// Run 実行中または最近完了したチェックの実行
+type Run struct {
+	ID        string     `json:"id"`                  // 実行ID（トレースID）
+	Trigger   string     `json:"trigger"`             // 実行のきっかけ（web / scheduler / hook / check_now / priority など）
+	Initiator string     `json:"initiator,omitempty"` // 実行したユーザーまたは接続元
+	Project   string     `json:"project,omitempty"`   // プロジェクトの定期チェックの場合はプロジェクト名
+	Started   time.Time  `json:"started"`
+	Finished  *time.Time `json:"finished,omitempty"`  // 実行中の場合は空
+	Total     int        `json:"total"`               // チェックする対象の数（展開後）
+	Completed int        `json:"completed"`           // 完了した対象の数
+	Failures  int        `json:"failures"`            // 失敗した対象の数
+	Coalesced int        `json:"coalesced,omitempty"` // 新たに実行せずこの実行に合流した要求の数
+}
+
+// Running 実行中か
+func (r *Run) Running() bool {
+	return r.Finished == nil
+}
+
+var (
+	mutex   sync.Mutex
+	running = make(map[string]*Run)
+	recent  []*Run // 完了した実行（新しい順）
+)
+
+// Start 実行の開始を登録
+func Start(id, trigger, initiator, project string) {
+	if id == "" {
+		return
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	running[id] = &Run{ID: id, Trigger: trigger, Initiator: initiator, Project: project, Started: time.Now()}
+}
+
+// AddTotal 実行のチェックする対象の数を加える
+func AddTotal(id string, n int) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if run, ok := running[id]; ok {
+		run.Total += n
+	}
+}
+
+// Record 実行の対象のチェックの完了を記録
+func Record(id string, success bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if run, ok := running[id]; ok {
+		run.Completed++
+		if !success {
+			run.Failures++
+		}
+	}
+}
+
+// Coalesce 実行に要求が合流したことを記録
+func Coalesce(id string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if run, ok := running[id]; ok {
+		run.Coalesced++
+	}
+}
+
+// Finish 実行の完了を登録し、最近の実行として保持する
+func Finish(id string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	run, ok := running[id]
+	if !ok {
+		return
+	}
+	delete(running, id)
+	now := time.Now()
+	run.Finished = &now
+	recent = append([]*Run{run}, recent...)
+	if len(recent) > maxRecent {
+		recent = recent[:maxRecent]
+	}
+}
+
+// List 実行中の実行（開始の古い順）と最近完了した実行（新しい順）の複製を返す
+func List() (active, finished []Run) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, run := range running {
+		active = append(active, *run)
+	}
+	slices.SortFunc(active, func(a, b Run) int { return a.Started.Compare(b.Started) })
+	for _, run := range recent {
+		finished = append(finished, *run)
+	}
+	return active, finished
+}
@@ -0,0 +1,450 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"healthcheck/internal/ack"
+	"healthcheck/internal/agent"
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/events"
+	"healthcheck/internal/notify"
+	"healthcheck/internal/runs"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+	"healthcheck/internal/tracing"
+)
+
+// Scheduler 設定された対象を一定間隔でチェックする構造体
+type Scheduler struct {
+	config     *config.Config // 現在の設定（再読み込みで置き換えるため、mutexを取得して読む）
+	checker    *checker.Checker
+	tracker    *notify.Tracker
+	dispatcher *notify.Dispatcher
+	agent      *agent.Client // コーディネーターへの送信（エージェントとして動作する場合のみ）
+	project    string        // プロジェクトの定期チェックの場合はプロジェクト名
+	mutex      sync.Mutex
+	running    bool
+	paused     bool          // 一時停止中（再開するまで開始・再読み込みで定期チェックを始めない）
+	interval   time.Duration // 実行中のループの間隔
+	priority   time.Duration // 実行中のループの優先度の高い対象の間隔（0の場合は行わない）
+	lastRun    time.Time
+	nextRun    time.Time
+	stop       chan struct{}
+	history    [][]*checker.CheckResult  // 劣化の判定の基準にする直近の実行の結果（起動時に履歴から読み込み、保存した実行を追加する）
+	baselines  map[string]stats.Baseline // historyから計算した対象ごとの応答時間の基準値
+}
+
+// NewScheduler 新しいSchedulerインスタンスを作成
+func NewScheduler(cfg *config.Config) *Scheduler {
+	return newScheduler(cfg, "")
+}
+
+// NewProjectScheduler プロジェクトの定期チェックを行うSchedulerを作成
+// 履歴は設定のresults_dirに保存し、イベントにはプロジェクト名を付けて配信する。HARから登録されたトランザクションはチェックしない
+func NewProjectScheduler(cfg *config.Config, project string) *Scheduler {
+	return newScheduler(cfg, project)
+}
+
+// newScheduler Schedulerを作成し、保存された履歴から応答時間の基準値を計算する
+// 履歴を読み込むのは作成時だけで、以降は実行のたびにメモリ上の基準値を更新する
+func newScheduler(cfg *config.Config, project string) *Scheduler {
+	s := &Scheduler{
+		config:     cfg,
+		checker:    checker.NewChecker(cfg),
+		tracker:    notify.NewTracker(cfg),
+		dispatcher: notify.NewDispatcher(cfg),
+		agent:      agent.NewClient(cfg),
+		project:    project,
+	}
+	entries, err := storage.LoadHistoryEntries(s.resultsDir())
+	if err != nil {
+		slog.Warn("failed to load history", "error", err)
+	}
+	for _, entry := range entries {
+		s.recordHistory(entry.Results)
+	}
+	return s
+}
+
+// publish イベントを配信（プロジェクトの定期チェックの場合はプロジェクト名を付ける）
+func (s *Scheduler) publish(e events.Event) {
+	e.Project = s.project
+	events.Publish(e)
+}
+
+// currentConfig 現在の設定（1回の実行の中では最初に取得したものを使う）
+func (s *Scheduler) currentConfig() *config.Config {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.config
+}
+
+// resultsDir 履歴の保存先
+func (s *Scheduler) resultsDir() string {
+	if s.project != "" {
+		return s.currentConfig().ResultsDir
+	}
+	return storage.ResultsDir
+}
+
+// Start 定期チェックを開始（間隔が未設定の場合は何もしない）
+func (s *Scheduler) Start() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.running || s.paused || s.config.Interval <= 0 {
+		return
+	}
+	s.running = true
+	s.interval = s.config.Interval
+	s.priority = s.config.PriorityInterval
+	s.stop = make(chan struct{})
+	s.nextRun = time.Now()
+	go s.loop(s.stop, s.interval, s.priority)
+	s.publish(events.Event{Type: "scheduler", Data: map[string]interface{}{"running": true}})
+}
+
+// Stop 定期チェックを停止
+func (s *Scheduler) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.running {
+		return
+	}
+	close(s.stop)
+	s.running = false
+	s.publish(events.Event{Type: "scheduler", Data: map[string]interface{}{"running": false, "paused": s.paused}})
+}
+
+// Pause 再開するまで定期チェックを止める（障害試験の間など）
+// すでに一時停止中の場合はfalseを返す
+func (s *Scheduler) Pause() bool {
+	s.mutex.Lock()
+	if s.paused {
+		s.mutex.Unlock()
+		return false
+	}
+	s.paused = true
+	running := s.running
+	s.mutex.Unlock()
+
+	if running {
+		s.Stop()
+	} else {
+		s.publish(events.Event{Type: "scheduler", Data: map[string]interface{}{"running": false, "paused": true}})
+	}
+	return true
+}
+
+// Resume 一時停止した定期チェックを再開し、すぐに1回目を実行する
+// 一時停止中でなかった場合はfalseを返す
+func (s *Scheduler) Resume() bool {
+	s.mutex.Lock()
+	if !s.paused {
+		s.mutex.Unlock()
+		return false
+	}
+	s.paused = false
+	s.mutex.Unlock()
+
+	s.Start()
+	return true
+}
+
+// Paused 定期チェックが一時停止中かどうか
+func (s *Scheduler) Paused() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.paused
+}
+
+// Reload 再読み込みした設定を反映する
+// 状態の変化の判定に使う前回の状態と未解決のアラートは引き継ぎ、間隔（priority_intervalを含む）が変わった場合のみ定期チェックをやり直す（一時停止中は再開しない）
+func (s *Scheduler) Reload(cfg *config.Config) {
+	s.mutex.Lock()
+	s.config = cfg
+	s.checker = checker.NewChecker(cfg)
+	s.dispatcher.Update(cfg)
+	s.tracker.Update(cfg)
+	s.agent = agent.NewClient(cfg)
+	restart := s.running && (s.interval != cfg.Interval || s.priority != cfg.PriorityInterval)
+	stopped := !s.running && !s.paused
+	s.mutex.Unlock()
+
+	if restart {
+		s.Stop()
+	}
+	if restart || stopped {
+		s.Start()
+	}
+}
+
+// Running 定期チェックが実行中かどうか
+func (s *Scheduler) Running() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.running
+}
+
+// loop 停止されるまで一定間隔でチェックを実行
+// priorityがintervalより短い場合は、その間隔で優先度の高い対象だけもチェックする
+func (s *Scheduler) loop(stop <-chan struct{}, interval, priority time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var priorityTick <-chan time.Time
+	if priority > 0 && priority < interval {
+		priorityTicker := time.NewTicker(priority)
+		defer priorityTicker.Stop()
+		priorityTick = priorityTicker.C
+	}
+
+	for {
+		s.RunOnce(context.Background())
+
+		s.mutex.Lock()
+		s.nextRun = time.Now().Add(interval)
+		nextRun := s.nextRun
+		s.mutex.Unlock()
+		s.publish(events.Event{Type: "scheduler", Data: map[string]interface{}{"running": true, "next_run": nextRun}})
+
+		for waiting := true; waiting; {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				waiting = false
+			case <-priorityTick:
+				s.RunPriority(context.Background())
+			}
+		}
+	}
+}
+
+// RunOnce メンテナンス中の対象を除いて1回分のチェックを実行し、履歴に保存
+func (s *Scheduler) RunOnce(ctx context.Context) ([]*checker.CheckResult, *stats.Statistics) {
+	now := time.Now().UTC()
+	s.mutex.Lock()
+	s.lastRun = now
+	cfg, c, dispatcher, agentClient := s.config, s.checker, s.dispatcher, s.agent
+	s.mutex.Unlock()
+
+	// 1回の実行を1つのトレースとし、トレースIDを実行IDとして履歴に残す
+	ctx, span := tracing.Start(events.WithProject(ctx, s.project), "run")
+	defer span.Finish()
+	span.SetAttribute("healthcheck.trigger", "scheduler")
+	runs.Start(span.TraceID, "scheduler", "", s.project)
+	defer runs.Finish(span.TraceID)
+	s.publish(events.Event{Type: "run_started", RunID: span.TraceID, Data: map[string]interface{}{"trigger": "scheduler"}})
+
+	var targets []config.Target
+	for _, t := range cfg.AllTargets() {
+		if cfg.InMaintenance(t.URL, now) {
+			continue
+		}
+		targets = append(targets, t)
+	}
+	// sitemap:/robots:の対象を個別のページに展開
+	targets = c.ExpandTargets(ctx, targets)
+
+	// HARから登録されたトランザクションも対象にする
+	var transactions []*checker.Transaction
+	if s.project == "" {
+		var err error
+		transactions, err = storage.LoadTransactions("transactions")
+		if err != nil {
+			slog.WarnContext(ctx, "failed to load transactions", "error", err)
+		}
+	}
+
+	// spread・jitterを設定した場合は対象ごとに開始時刻をずらす（トランザクションはずらさない）
+	targetsCtx := ctx
+	if st := stagger(cfg); st != nil {
+		targetsCtx = checker.WithStagger(ctx, st)
+	}
+	results := checkTargets(targetsCtx, c, targets)
+	for _, tx := range transactions {
+		if cfg.InMaintenance(tx.Name, now) {
+			continue
+		}
+		runs.AddTotal(span.TraceID, 1)
+		result := c.CheckTransaction(ctx, tx)
+		runs.Record(span.TraceID, result.Success)
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		s.publish(events.Event{Type: "run_finished", RunID: span.TraceID})
+		return nil, nil
+	}
+
+	s.markDegraded(cfg, results)
+	ack.Annotate(results, time.Now())
+	// 不安定な対象を履歴に残すため、保存の前に状態の変化を判定する
+	alerts := s.tracker.Evaluate(results)
+
+	// 前回の実行との差分
+	var regression *stats.Regression
+	if previous, err := storage.LatestHistoryEntry(s.resultsDir()); err == nil && previous != nil {
+		regression = stats.CompareRuns(previous.Results, results, previous.Timestamp, cfg.RegressionThreshold)
+	}
+
+	statistics := stats.CalculateStatistics(results, time.Since(now))
+	slog.InfoContext(ctx, "check finished", "trigger", "scheduler", "targets", statistics.TotalRequests,
+		"failures", statistics.FailureCount, "duration", statistics.TotalDuration)
+	span.SetAttribute("healthcheck.targets", statistics.TotalRequests)
+	span.SetAttribute("healthcheck.failures", statistics.FailureCount)
+	if _, err := storage.SaveHistoryIn(s.resultsDir(), span.TraceID, nil, results, statistics); err != nil {
+		slog.WarnContext(ctx, "failed to save scheduled results", "error", err)
+	}
+	s.recordHistory(results)
+	if agentClient != nil {
+		if err := agentClient.Push(ctx, span.TraceID, now, results); err != nil {
+			slog.WarnContext(ctx, "failed to push results to coordinator", "coordinator", cfg.Coordinator, "error", err)
+		}
+	}
+
+	// 状態が変化した対象と前回からの差分を通知
+	if regression != nil && !regression.Empty() {
+		alerts = append(alerts, notify.Alert{
+			Kind:       "regression",
+			Timestamp:  now,
+			Severity:   "info",
+			Regression: regression,
+		})
+	}
+	s.dispatch(ctx, dispatcher, span.TraceID, alerts)
+	s.publish(events.Event{Type: "run_finished", RunID: span.TraceID, Data: statistics})
+
+	return results, statistics
+}
+
+// RunPriority 優先度の高い対象だけをチェックし、状態の変化を定期チェックと同様に通知する
+// 定期チェックの間に障害を早く検出するためのもので、履歴には保存しない（メンテナンス中の対象は除く）
+func (s *Scheduler) RunPriority(ctx context.Context) []*checker.CheckResult {
+	now := time.Now()
+	s.mutex.Lock()
+	cfg, c, dispatcher := s.config, s.checker, s.dispatcher
+	s.mutex.Unlock()
+
+	var targets []config.Target
+	for _, t := range cfg.AllTargets() {
+		if t.HighPriority() && !cfg.InMaintenance(t.URL, now) {
+			targets = append(targets, t)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	ctx, span := tracing.Start(events.WithProject(ctx, s.project), "run")
+	defer span.Finish()
+	span.SetAttribute("healthcheck.trigger", "priority")
+	runs.Start(span.TraceID, "priority", "", s.project)
+	defer runs.Finish(span.TraceID)
+
+	results := checkTargets(ctx, c, c.ExpandTargets(ctx, targets))
+	s.markDegraded(cfg, results)
+	ack.Annotate(results, time.Now())
+	statistics := stats.CalculateStatistics(results, time.Since(now))
+	slog.InfoContext(ctx, "check finished", "trigger", "priority", "targets", statistics.TotalRequests,
+		"failures", statistics.FailureCount, "duration", statistics.TotalDuration)
+
+	s.dispatch(ctx, dispatcher, span.TraceID, s.tracker.Evaluate(results))
+	return results
+}
+
+// CheckNow 指定した対象だけをすぐにチェックし、状態の変化を定期チェックと同様に通知する
+// 一時停止中やメンテナンス中でも実行する（修正後の確認のため）。定期チェックの履歴には保存しない
+// initiatorは実行中の一覧に表示する実行したユーザーまたは接続元
+func (s *Scheduler) CheckNow(ctx context.Context, target config.Target, initiator string) ([]*checker.CheckResult, *stats.Statistics) {
+	start := time.Now()
+	s.mutex.Lock()
+	cfg, c, dispatcher := s.config, s.checker, s.dispatcher
+	s.mutex.Unlock()
+
+	ctx, span := tracing.Start(events.WithProject(ctx, s.project), "run")
+	defer span.Finish()
+	span.SetAttribute("healthcheck.trigger", "check_now")
+	runs.Start(span.TraceID, "check_now", initiator, s.project)
+	defer runs.Finish(span.TraceID)
+
+	results := checkTargets(ctx, c, c.ExpandTargets(ctx, []config.Target{target}))
+	s.markDegraded(cfg, results)
+	ack.Annotate(results, time.Now())
+	statistics := stats.CalculateStatistics(results, time.Since(start))
+	slog.InfoContext(ctx, "check finished", "trigger", "check_now", "target", target.ID(),
+		"failures", statistics.FailureCount, "duration", statistics.TotalDuration)
+
+	s.dispatch(ctx, dispatcher, span.TraceID, s.tracker.Evaluate(results))
+	return results, statistics
+}
+
+// checkTargets 対象をチェックして結果をまとめて返す
+func checkTargets(ctx context.Context, c *checker.Checker, targets []config.Target) []*checker.CheckResult {
+	var results []*checker.CheckResult
+	if len(targets) > 0 {
+		resultChan := make(chan *checker.CheckResult, len(targets))
+		go c.CheckTargets(ctx, targets, resultChan, nil)
+		for result := range resultChan {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// markDegraded 直近の実行の基準値と比較して応答時間の劣化を判定
+func (s *Scheduler) markDegraded(cfg *config.Config, results []*checker.CheckResult) {
+	s.mutex.Lock()
+	baselines := s.baselines
+	s.mutex.Unlock()
+	stats.MarkDegraded(results, baselines, cfg.AnomalySigma, cfg.AnomalyMinSamples)
+}
+
+// recordHistory 実行の結果を基準値の計算に加える（履歴のファイルと同じく最新HistoryLimit件のみ保持）
+// 履歴に保存しない優先度の高い対象のチェック・今すぐチェックの結果は加えない
+func (s *Scheduler) recordHistory(results []*checker.CheckResult) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.history = append(s.history, results)
+	if extra := len(s.history) - storage.HistoryLimit(); extra > 0 {
+		s.history = slices.Delete(s.history, 0, extra)
+	}
+	var all []*checker.CheckResult
+	for _, run := range s.history {
+		all = append(all, run...)
+	}
+	s.baselines = stats.CalculateBaselines(all)
+}
+
+// dispatch アラートを通知先に送信し、イベントとしても配信する
+func (s *Scheduler) dispatch(ctx context.Context, dispatcher *notify.Dispatcher, runID string, alerts []notify.Alert) {
+	dispatcher.Dispatch(ctx, alerts)
+	for _, alert := range alerts {
+		s.publish(events.Event{Type: "alert", RunID: runID, Data: alert})
+	}
+}
+
+// NextRun 次回の実行予定日時（定期チェックが停止中の場合はゼロ値）
+func (s *Scheduler) NextRun() time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.running {
+		return time.Time{}
+	}
+	return s.nextRun
+}
+
+// LastRun 最後に実行した日時
+func (s *Scheduler) LastRun() time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.lastRun
+}
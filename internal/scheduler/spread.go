@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+)
+
+// stagger 定期チェックで対象ごとの開始時刻をずらす遅延（spread・jitterを設定していない場合はnil）
+// spreadは対象の識別子のハッシュで間隔の中の位置を決めるため、同じ対象は毎回同じ時刻に開始し、チェックの間隔が保たれる
+// 最後の対象も次の実行までに終わるよう、間隔からjitterとタイムアウトを引いた範囲に分散する
+func stagger(cfg *config.Config) checker.Stagger {
+	if cfg.Interval <= 0 || (!cfg.Spread && cfg.Jitter <= 0) {
+		return nil
+	}
+	var window time.Duration
+	if cfg.Spread {
+		window = cfg.Interval - cfg.Jitter - cfg.Timeout
+	}
+	jitter := cfg.Jitter
+
+	return func(target config.Target) time.Duration {
+		var delay time.Duration
+		if window > 0 {
+			h := fnv.New64a()
+			h.Write([]byte(target.ID()))
+			delay = time.Duration(h.Sum64() % uint64(window))
+		}
+		if jitter > 0 {
+			delay += rand.N(jitter)
+		}
+		return delay
+	}
+}
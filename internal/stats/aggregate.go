@@ -0,0 +1,140 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/i18n"
+)
+
+// Dimensions 集計に使用できる軸（このほかに"tag:キー"でタグの値ごとに集計できる）
+var Dimensions = []string{"domain", "status_class", "url", "error", "hour", "region"}
+
+// Group 集計軸の値ごとの集計結果
+type Group struct {
+	Key             string        `json:"key"`
+	Count           int           `json:"count"`
+	SuccessCount    int           `json:"success_count"`
+	FailureCount    int           `json:"failure_count"`
+	SuccessRate     float64       `json:"success_rate"`
+	AvgResponseTime time.Duration `json:"avg_response_time_ms"`
+	P95ResponseTime time.Duration `json:"p95_response_time_ms"`
+	MaxResponseTime time.Duration `json:"max_response_time_ms"`
+}
+
+// GroupKey 結果の集計軸の値を返す
+func GroupKey(dimension string, r *checker.CheckResult) (string, error) {
+	switch dimension {
+	case "domain":
+		return checker.ExtractDomain(r.URL), nil
+	case "status_class":
+		if r.StatusCode == 0 {
+			return "no_response", nil
+		}
+		return fmt.Sprintf("%dxx", r.StatusCode/100), nil
+	case "url":
+		return r.URL, nil
+	case "error":
+		if r.Error == "" {
+			return "none", nil
+		}
+		return string(r.Error), nil
+	case "hour":
+		return fmt.Sprintf("%02d", i18n.In(r.Timestamp).Hour()), nil
+	case "region":
+		return RegionName(r), nil
+	}
+	if key, ok := strings.CutPrefix(dimension, "tag:"); ok && key != "" {
+		if value, ok := r.Tags[key]; ok {
+			return value, nil
+		}
+		return "none", nil
+	}
+	return "", fmt.Errorf("unknown dimension: %s", dimension)
+}
+
+// Aggregate 結果を指定した軸でグループ化して集計
+func Aggregate(results []*checker.CheckResult, dimension string) ([]*Group, error) {
+	groups := make(map[string]*Group)
+	responseTimes := make(map[string][]time.Duration)
+
+	for _, r := range results {
+		key, err := GroupKey(dimension, r)
+		if err != nil {
+			return nil, err
+		}
+
+		g, exists := groups[key]
+		if !exists {
+			g = &Group{Key: key}
+			groups[key] = g
+		}
+		g.Count++
+		if r.Success {
+			g.SuccessCount++
+			responseTimes[key] = append(responseTimes[key], r.ResponseTime)
+		} else {
+			g.FailureCount++
+		}
+	}
+
+	var aggregated []*Group
+	for key, g := range groups {
+		g.SuccessRate = float64(g.SuccessCount) / float64(g.Count) * 100
+		if rts := responseTimes[key]; len(rts) > 0 {
+			var total time.Duration
+			for _, rt := range rts {
+				total += rt
+			}
+			g.AvgResponseTime = total / time.Duration(len(rts))
+			g.P95ResponseTime = Percentile(rts, 95)
+			g.MaxResponseTime = Percentile(rts, 100)
+		}
+		aggregated = append(aggregated, g)
+	}
+
+	sort.Slice(aggregated, func(i, j int) bool {
+		return aggregated[i].Key < aggregated[j].Key
+	})
+	return aggregated, nil
+}
+
+// Percentile 値のパーセンタイルを計算（最近接順位法）
+func Percentile(values []time.Duration, p float64) time.Duration {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	rank := int(math.Ceil(float64(len(sorted))*p/100)) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// FilterByTags タグが条件にすべて一致する結果のみを返す（条件が空の場合はそのまま返す）
+func FilterByTags(results []*checker.CheckResult, filter map[string]string) []*checker.CheckResult {
+	if len(filter) == 0 {
+		return results
+	}
+	var filtered []*checker.CheckResult
+	for _, r := range results {
+		if config.MatchTags(r.Tags, filter) {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
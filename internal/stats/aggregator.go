@@ -0,0 +1,269 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"time"
+
+	"healthcheck/internal/checker"
+)
+
+// sketchGamma 分位点の推定で使う区間の比（隣り合う区間の境界が2%ずつ大きくなり、推定の相対誤差は約1%）
+const sketchGamma = 1.02
+
+// quantileSketch 値を対数の区間ごとに数えて分位点を推定する（件数によらず区間の数でメモリが決まる）
+type quantileSketch struct {
+	counts map[int]int
+	total  int
+}
+
+// add 値を追加
+func (s *quantileSketch) add(d time.Duration) {
+	if s.counts == nil {
+		s.counts = make(map[int]int)
+	}
+	index := 0
+	if d > 0 {
+		index = int(math.Ceil(math.Log(float64(d)) / math.Log(sketchGamma)))
+	}
+	s.counts[index]++
+	s.total++
+}
+
+// quantile p（%）の分位点の推定値（Percentileと同じく小さい方からceil(n*p/100)番目の値が入る区間の代表値）
+func (s *quantileSketch) quantile(p float64) time.Duration {
+	if s.total == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(s.total) * p / 100))
+	rank = max(1, min(rank, s.total))
+
+	indexes := make([]int, 0, len(s.counts))
+	for i := range s.counts {
+		indexes = append(indexes, i)
+	}
+	slices.Sort(indexes)
+	seen := 0
+	for _, i := range indexes {
+		seen += s.counts[i]
+		if seen >= rank {
+			if i == 0 {
+				return 0
+			}
+			// 区間 (γ^(i-1), γ^i] の中で相対誤差が最小になる値
+			return time.Duration(2 * math.Pow(sketchGamma, float64(i)) / (sketchGamma + 1))
+		}
+	}
+	return 0
+}
+
+// Aggregator 結果を1件ずつ受け取りながら統計情報を計算する
+// 結果はlimit件まではメモリに保持し、超えた場合はそれまでの分も含めて一時ファイルに書き出して以降はメモリに残さない
+type Aggregator struct {
+	limit int // メモリに保持する最大件数（0の場合は無制限）
+
+	stats         Statistics
+	totalResponse time.Duration
+	totalLatency  time.Duration
+	responseTimes quantileSketch
+
+	results []*checker.CheckResult // メモリに保持している結果（書き出した後はnil）
+	spill   *os.File               // 結果を1行ずつ書き出した一時ファイル（書き出していない場合はnil）
+	writer  *bufio.Writer
+	err     error // 一時ファイルへの書き出しで最初に発生したエラー
+}
+
+// NewAggregator 結果をlimit件までメモリに保持する集計を作成（0の場合は書き出さない）
+func NewAggregator(limit int) *Aggregator {
+	return &Aggregator{
+		limit: limit,
+		stats: Statistics{
+			ResponseTimeHistogram: NewHistogram(HistogramBuckets()),
+			LatencyHistogram:      NewHistogram(HistogramBuckets()),
+		},
+	}
+}
+
+// Add 結果を集計に追加
+func (a *Aggregator) Add(result *checker.CheckResult) {
+	a.stats.TotalRequests++
+	if result.Duplicate {
+		a.stats.DuplicateCount++
+	}
+	if result.Cache != nil {
+		if a.stats.CacheCounts == nil {
+			a.stats.CacheCounts = make(map[string]int)
+		}
+		a.stats.CacheCounts[result.Cache.Status]++
+	}
+	if result.Success {
+		if a.stats.SuccessCount == 0 {
+			a.stats.MinResponseTime, a.stats.MaxResponseTime = result.ResponseTime, result.ResponseTime
+			a.stats.MinLatency, a.stats.MaxLatency = result.Latency, result.Latency
+		}
+		a.stats.SuccessCount++
+		a.totalResponse += result.ResponseTime
+		a.totalLatency += result.Latency
+		a.stats.MinResponseTime = min(a.stats.MinResponseTime, result.ResponseTime)
+		a.stats.MaxResponseTime = max(a.stats.MaxResponseTime, result.ResponseTime)
+		a.stats.MinLatency = min(a.stats.MinLatency, result.Latency)
+		a.stats.MaxLatency = max(a.stats.MaxLatency, result.Latency)
+		a.responseTimes.add(result.ResponseTime)
+		a.stats.ResponseTimeHistogram.Observe(result.ResponseTime)
+		a.stats.LatencyHistogram.Observe(result.Latency)
+	} else {
+		a.stats.FailureCount++
+		if a.stats.ErrorCounts == nil {
+			a.stats.ErrorCounts = make(map[checker.ErrorCategory]int)
+		}
+		a.stats.ErrorCounts[result.Error]++
+	}
+
+	if a.spill == nil {
+		a.results = append(a.results, result)
+		if a.limit <= 0 || len(a.results) <= a.limit {
+			return
+		}
+		a.startSpill()
+		return
+	}
+	a.write(result)
+}
+
+// startSpill 一時ファイルを作成し、メモリに保持していた結果を書き出す
+func (a *Aggregator) startSpill() {
+	f, err := os.CreateTemp("", "healthcheck-results-*.jsonl")
+	if err != nil {
+		// 書き出せない場合はメモリに保持し続ける
+		a.err = fmt.Errorf("failed to create spill file: %w", err)
+		a.limit = 0
+		return
+	}
+	a.spill = f
+	a.writer = bufio.NewWriter(f)
+	for _, r := range a.results {
+		a.write(r)
+	}
+	a.results = nil
+}
+
+// write 結果を一時ファイルに1行のJSONとして書き出す
+func (a *Aggregator) write(result *checker.CheckResult) {
+	if a.err != nil {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err == nil {
+		data = append(data, '\n')
+		_, err = a.writer.Write(data)
+	}
+	if err != nil {
+		a.err = fmt.Errorf("failed to write spill file: %w", err)
+	}
+}
+
+// Spilled 結果を一時ファイルに書き出したか（書き出した場合はResultsではなくEachで読む）
+func (a *Aggregator) Spilled() bool {
+	return a.spill != nil
+}
+
+// Err 一時ファイルの作成・書き出しで発生したエラー
+func (a *Aggregator) Err() error {
+	return a.err
+}
+
+// Results メモリに保持している結果（書き出した場合はnil）
+func (a *Aggregator) Results() []*checker.CheckResult {
+	return a.results
+}
+
+// Each 追加した順に全ての結果を渡す（書き出した場合は一時ファイルから1件ずつ読み込む）
+func (a *Aggregator) Each(fn func(*checker.CheckResult) error) error {
+	if a.spill == nil {
+		for _, r := range a.results {
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if a.err != nil {
+		return a.err
+	}
+	if err := a.writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write spill file: %w", err)
+	}
+	if _, err := a.spill.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read spill file: %w", err)
+	}
+	decoder := json.NewDecoder(bufio.NewReader(a.spill))
+	for {
+		var r checker.CheckResult
+		if err := decoder.Decode(&r); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to read spill file: %w", err)
+		}
+		if err := fn(&r); err != nil {
+			return err
+		}
+	}
+	// 続けてAddされた場合に末尾へ書き足す
+	if _, err := a.spill.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("failed to read spill file: %w", err)
+	}
+	return nil
+}
+
+// Statistics ここまでに追加した結果の統計情報
+// p95はメモリに保持している場合は全件から、書き出した場合は推定値（相対誤差約1%）を返す
+func (a *Aggregator) Statistics(totalDuration time.Duration) *Statistics {
+	if a.stats.TotalRequests == 0 {
+		return &Statistics{}
+	}
+	s := a.stats
+	s.TotalDuration = totalDuration
+	s.SuccessRate = float64(s.SuccessCount) / float64(s.TotalRequests) * 100
+	s.ResponseTimeHistogram = a.stats.ResponseTimeHistogram.clone()
+	s.LatencyHistogram = a.stats.LatencyHistogram.clone()
+	if s.ErrorCounts != nil {
+		s.ErrorCounts = make(map[checker.ErrorCategory]int, len(a.stats.ErrorCounts))
+		for k, v := range a.stats.ErrorCounts {
+			s.ErrorCounts[k] = v
+		}
+	}
+	if s.CacheCounts != nil {
+		s.CacheCounts = maps.Clone(a.stats.CacheCounts)
+	}
+	if s.SuccessCount > 0 {
+		s.AvgResponseTime = a.totalResponse / time.Duration(s.SuccessCount)
+		s.AvgLatency = a.totalLatency / time.Duration(s.SuccessCount)
+		if a.spill == nil {
+			var times []time.Duration
+			for _, r := range a.results {
+				if r.Success {
+					times = append(times, r.ResponseTime)
+				}
+			}
+			s.P95ResponseTime = Percentile(times, 95)
+		} else {
+			s.P95ResponseTime = a.responseTimes.quantile(95)
+		}
+	}
+	return &s
+}
+
+// Close 一時ファイルを削除
+func (a *Aggregator) Close() error {
+	if a.spill == nil {
+		return nil
+	}
+	a.spill.Close()
+	return os.Remove(a.spill.Name())
+}
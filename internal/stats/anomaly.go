@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"healthcheck/internal/checker"
+)
+
+// Baseline 対象ごとの過去の応答時間の基準値
+type Baseline struct {
+	Mean    time.Duration `json:"mean_ms"`
+	StdDev  time.Duration `json:"stddev_ms"`
+	Samples int           `json:"samples"`
+}
+
+// CalculateBaselines 過去の成功した結果から対象ごとの応答時間の平均と標準偏差を計算
+func CalculateBaselines(history []*checker.CheckResult) map[string]Baseline {
+	samples := make(map[string][]float64)
+	for _, r := range history {
+		if !r.Success {
+			continue
+		}
+		samples[r.URL] = append(samples[r.URL], float64(r.ResponseTime))
+	}
+
+	baselines := make(map[string]Baseline)
+	for url, values := range samples {
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		mean := sum / float64(len(values))
+
+		var variance float64
+		for _, v := range values {
+			variance += (v - mean) * (v - mean)
+		}
+		variance /= float64(len(values))
+
+		baselines[url] = Baseline{
+			Mean:    time.Duration(mean),
+			StdDev:  time.Duration(math.Sqrt(variance)),
+			Samples: len(values),
+		}
+	}
+	return baselines
+}
+
+// MarkDegraded 基準値より統計的に遅い成功結果を「劣化」としてマーク
+// 基準値のサンプル数がminSamples未満の対象は判定しない
+func MarkDegraded(results []*checker.CheckResult, baselines map[string]Baseline, sigma float64, minSamples int) {
+	for _, r := range results {
+		if !r.Success {
+			continue
+		}
+		b, ok := baselines[r.URL]
+		if !ok || b.Samples < minSamples || b.StdDev <= 0 {
+			continue
+		}
+
+		z := float64(r.ResponseTime-b.Mean) / float64(b.StdDev)
+		if z >= sigma {
+			r.Degraded = true
+			r.DegradedMessage = fmt.Sprintf("response time %v is %.1fσ above baseline %v (±%v, n=%d)",
+				r.ResponseTime.Round(time.Millisecond), z, b.Mean.Round(time.Millisecond), b.StdDev.Round(time.Millisecond), b.Samples)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"healthcheck/internal/checker"
+)
+
+// Distribution ベンチマークでのURLごとの応答時間の分布
+type Distribution struct {
+	URL            string        `json:"url"`
+	Count          int           `json:"count"`
+	SuccessCount   int           `json:"success_count"`
+	FailureCount   int           `json:"failure_count"`
+	ErrorRate      float64       `json:"error_rate"` // %
+	MinResponse    time.Duration `json:"min_response_time_ms"`
+	AvgResponse    time.Duration `json:"avg_response_time_ms"`
+	P50Response    time.Duration `json:"p50_response_time_ms"`
+	P95Response    time.Duration `json:"p95_response_time_ms"`
+	P99Response    time.Duration `json:"p99_response_time_ms"`
+	MaxResponse    time.Duration `json:"max_response_time_ms"`
+	RequestsPerSec float64       `json:"requests_per_sec"` // 実行時間あたりのリクエスト数
+}
+
+// CalculateDistributions URLごとの応答時間の分布を計算（応答時間は成功した結果のみ）
+func CalculateDistributions(results []*checker.CheckResult, totalDuration time.Duration) []*Distribution {
+	byURL := make(map[string][]*checker.CheckResult)
+	var urls []string
+	for _, r := range results {
+		if _, ok := byURL[r.URL]; !ok {
+			urls = append(urls, r.URL)
+		}
+		byURL[r.URL] = append(byURL[r.URL], r)
+	}
+	sort.Strings(urls)
+
+	var distributions []*Distribution
+	for _, url := range urls {
+		d := &Distribution{URL: url}
+		var times []time.Duration
+		var total time.Duration
+		for _, r := range byURL[url] {
+			d.Count++
+			if !r.Success {
+				d.FailureCount++
+				continue
+			}
+			d.SuccessCount++
+			times = append(times, r.ResponseTime)
+			total += r.ResponseTime
+			if d.MinResponse == 0 || r.ResponseTime < d.MinResponse {
+				d.MinResponse = r.ResponseTime
+			}
+			if r.ResponseTime > d.MaxResponse {
+				d.MaxResponse = r.ResponseTime
+			}
+		}
+
+		d.ErrorRate = float64(d.FailureCount) / float64(d.Count) * 100
+		if len(times) > 0 {
+			d.AvgResponse = total / time.Duration(len(times))
+			d.P50Response = Percentile(times, 50)
+			d.P95Response = Percentile(times, 95)
+			d.P99Response = Percentile(times, 99)
+		}
+		if totalDuration > 0 {
+			d.RequestsPerSec = float64(d.Count) / totalDuration.Seconds()
+		}
+		distributions = append(distributions, d)
+	}
+	return distributions
+}
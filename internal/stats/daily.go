@@ -0,0 +1,68 @@
+package stats
+
+import (
+	"time"
+
+	"healthcheck/internal/checker"
+)
+
+// DayUptime 1日分の稼働状況
+type DayUptime struct {
+	Date          string  `json:"date"` // YYYY-MM-DD
+	Checks        int     `json:"checks"`
+	Failures      int     `json:"failures"`
+	UptimePercent float64 `json:"uptime_percent"`
+}
+
+// HasData その日にチェック結果があるかどうか
+func (d DayUptime) HasData() bool {
+	return d.Checks > 0
+}
+
+// CalculateDailyUptime 対象の直近days日間の日ごとの稼働率を古い順に返す
+// 稼働率はその日のチェックのうち成功した割合とする
+func CalculateDailyUptime(results []*checker.CheckResult, targetURL string, days int, now time.Time) []DayUptime {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	first := today.AddDate(0, 0, -(days - 1))
+
+	daily := make([]DayUptime, days)
+	for i := range daily {
+		daily[i].Date = first.AddDate(0, 0, i).Format("2006-01-02")
+	}
+
+	for _, r := range results {
+		if r.URL != targetURL {
+			continue
+		}
+		at := r.Timestamp.In(now.Location())
+		day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, now.Location())
+		idx := int(day.Sub(first).Hours() / 24)
+		if idx < 0 || idx >= days {
+			continue
+		}
+		daily[idx].Checks++
+		if !r.Success {
+			daily[idx].Failures++
+		}
+	}
+
+	for i := range daily {
+		if daily[i].Checks > 0 {
+			daily[i].UptimePercent = float64(daily[i].Checks-daily[i].Failures) / float64(daily[i].Checks) * 100
+		}
+	}
+	return daily
+}
+
+// OverallUptime 日ごとの稼働状況から期間全体の稼働率を計算（データがない場合は-1）
+func OverallUptime(daily []DayUptime) float64 {
+	checks, failures := 0, 0
+	for _, d := range daily {
+		checks += d.Checks
+		failures += d.Failures
+	}
+	if checks == 0 {
+		return -1
+	}
+	return float64(checks-failures) / float64(checks) * 100
+}
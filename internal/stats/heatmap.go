@@ -0,0 +1,72 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"healthcheck/internal/checker"
+)
+
+// HeatmapBuckets 集計期間ごとの時間枠の幅の既定値
+var HeatmapBuckets = map[string]time.Duration{
+	"24h": time.Hour,
+	"7d":  time.Hour,
+	"30d": 6 * time.Hour,
+}
+
+// HeatmapRow 対象ごとの時間枠別のレイテンシと失敗率
+type HeatmapRow struct {
+	URL           string          `json:"url"`
+	MedianLatency time.Duration   `json:"median_latency_ms"` // 期間全体の成功した結果のレイテンシの中央値（色分けの基準）
+	Cells         []PatternBucket `json:"cells"`             // Heatmap.Timesと同じ順の時間枠
+}
+
+// Heatmap 対象×時間枠のレイテンシと失敗率
+type Heatmap struct {
+	Times   []time.Time   `json:"times"` // 各時間枠の開始時刻
+	Targets []*HeatmapRow `json:"targets"`
+}
+
+// CalculateHeatmap fromからtoまでをbucketごとの時間枠に分け、対象ごとに集計
+// 折れ線グラフでは埋もれる断続的な遅延（夜間だけの遅延など）を見つけるために使用する
+func CalculateHeatmap(results []*checker.CheckResult, from, to time.Time, bucket time.Duration) *Heatmap {
+	// 時間枠の区切りをローカル時刻の0時起点に揃える
+	_, offset := from.Zone()
+	shift := time.Duration(offset) * time.Second
+	from = from.Add(shift).Truncate(bucket).Add(-shift)
+
+	count := int((to.Sub(from) + bucket - 1) / bucket)
+	heatmap := &Heatmap{Times: make([]time.Time, count)}
+	for i := range heatmap.Times {
+		heatmap.Times[i] = from.Add(time.Duration(i) * bucket)
+	}
+
+	rows := make(map[string]*HeatmapRow)
+	latencies := make(map[string][]time.Duration)
+	for _, r := range results {
+		if r.Timestamp.Before(from) || !r.Timestamp.Before(to) {
+			continue
+		}
+		row, exists := rows[r.URL]
+		if !exists {
+			row = &HeatmapRow{URL: r.URL, Cells: make([]PatternBucket, count)}
+			rows[r.URL] = row
+		}
+		row.Cells[int(r.Timestamp.Sub(from)/bucket)].add(r)
+		if r.Success {
+			latencies[r.URL] = append(latencies[r.URL], r.Latency)
+		}
+	}
+
+	for _, row := range rows {
+		for i := range row.Cells {
+			row.Cells[i].finish()
+		}
+		row.MedianLatency = Percentile(latencies[row.URL], 50)
+		heatmap.Targets = append(heatmap.Targets, row)
+	}
+	sort.Slice(heatmap.Targets, func(i, j int) bool {
+		return heatmap.Targets[i].URL < heatmap.Targets[j].URL
+	})
+	return heatmap
+}
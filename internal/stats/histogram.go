@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+)
+
+// histogramBuckets 応答時間・レイテンシのヒストグラムの区間の上限（設定のhistogram_bucketsを起動時・再読み込み時に反映する）
+var histogramBuckets atomic.Pointer[[]time.Duration]
+
+func init() {
+	SetHistogramBuckets(slices.Clone(config.DefaultHistogramBuckets))
+}
+
+// HistogramBuckets 応答時間・レイテンシのヒストグラムの区間の上限
+func HistogramBuckets() []time.Duration {
+	return *histogramBuckets.Load()
+}
+
+// SetHistogramBuckets ヒストグラムの区間の上限を設定（チェックの実行中に再読み込みで変更しても安全）
+func SetHistogramBuckets(buckets []time.Duration) {
+	histogramBuckets.Store(&buckets)
+}
+
+// Histogram 成功した結果の時間の分布（Prometheusのヒストグラムと同じ区間の分け方）
+type Histogram struct {
+	Bounds []time.Duration `json:"bounds_ms"` // 区間の上限（昇順、各区間は上限の値を含む）
+	Counts []int           `json:"counts"`    // 区間ごとの件数（最後の要素は最大の上限を超えた件数）
+	Count  int             `json:"count"`
+	Sum    time.Duration   `json:"sum_ms"`
+}
+
+// NewHistogram 区間の上限を指定してヒストグラムを作成
+func NewHistogram(bounds []time.Duration) *Histogram {
+	return &Histogram{
+		Bounds: slices.Clone(bounds),
+		Counts: make([]int, len(bounds)+1),
+	}
+}
+
+// Observe 値を追加
+func (h *Histogram) Observe(d time.Duration) {
+	i, _ := slices.BinarySearch(h.Bounds, d)
+	h.Counts[i]++
+	h.Count++
+	h.Sum += d
+}
+
+// Cumulative 上限以下の件数の累計（Prometheusの_bucketの値、最後の要素は+Infで全件数）
+func (h *Histogram) Cumulative() []int {
+	cumulative := make([]int, len(h.Counts))
+	total := 0
+	for i, c := range h.Counts {
+		total += c
+		cumulative[i] = total
+	}
+	return cumulative
+}
+
+// Histograms 統計情報のヒストグラム（ヒストグラムを含まない保存済みの統計情報の場合は結果から計算する）
+func Histograms(statistics *Statistics, results []*checker.CheckResult) (responseTimes, latencies *Histogram) {
+	if statistics != nil && statistics.ResponseTimeHistogram != nil && statistics.LatencyHistogram != nil {
+		return statistics.ResponseTimeHistogram, statistics.LatencyHistogram
+	}
+	responseTimes, latencies = NewHistogram(HistogramBuckets()), NewHistogram(HistogramBuckets())
+	for _, r := range results {
+		if r.Success {
+			responseTimes.Observe(r.ResponseTime)
+			latencies.Observe(r.Latency)
+		}
+	}
+	return responseTimes, latencies
+}
+
+// clone ヒストグラムの複製
+func (h *Histogram) clone() *Histogram {
+	if h == nil {
+		return nil
+	}
+	copied := *h
+	copied.Bounds = slices.Clone(h.Bounds)
+	copied.Counts = slices.Clone(h.Counts)
+	return &copied
+}
@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/i18n"
+)
+
+// PatternBucket 時間帯または曜日ごとの集計結果
+type PatternBucket struct {
+	Count        int           `json:"count"`
+	Failures     int           `json:"failures"`
+	FailureRate  float64       `json:"failure_rate"`
+	AvgLatency   time.Duration `json:"avg_latency_ms"`
+	totalLatency time.Duration
+	successCount int
+}
+
+// TargetPattern 対象ごとの時間帯別・曜日別のレイテンシと失敗率
+type TargetPattern struct {
+	URL     string            `json:"url"`
+	Hourly  [24]PatternBucket `json:"hourly"`  // 0時〜23時
+	Weekday [7]PatternBucket  `json:"weekday"` // 日曜日〜土曜日
+}
+
+// CalculatePatterns 結果を対象ごとに時間帯別・曜日別に集計
+// 周期的な問題（夜間バックアップ中の遅延など）を見つけるために使用する
+func CalculatePatterns(results []*checker.CheckResult) []*TargetPattern {
+	patterns := make(map[string]*TargetPattern)
+	for _, r := range results {
+		p, exists := patterns[r.URL]
+		if !exists {
+			p = &TargetPattern{URL: r.URL}
+			patterns[r.URL] = p
+		}
+
+		at := i18n.In(r.Timestamp)
+		p.Hourly[at.Hour()].add(r)
+		p.Weekday[at.Weekday()].add(r)
+	}
+
+	var list []*TargetPattern
+	for _, p := range patterns {
+		for i := range p.Hourly {
+			p.Hourly[i].finish()
+		}
+		for i := range p.Weekday {
+			p.Weekday[i].finish()
+		}
+		list = append(list, p)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].URL < list[j].URL
+	})
+	return list
+}
+
+// add 結果をバケットに追加
+func (b *PatternBucket) add(r *checker.CheckResult) {
+	b.Count++
+	if !r.Success {
+		b.Failures++
+		return
+	}
+	b.successCount++
+	b.totalLatency += r.Latency
+}
+
+// finish 平均レイテンシと失敗率を確定
+func (b *PatternBucket) finish() {
+	if b.Count > 0 {
+		b.FailureRate = float64(b.Failures) / float64(b.Count) * 100
+	}
+	if b.successCount > 0 {
+		b.AvgLatency = b.totalLatency / time.Duration(b.successCount)
+	}
+}
@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"sort"
+
+	"healthcheck/internal/checker"
+)
+
+// LocalRegion 地域が設定されていないインスタンスの結果の地域名
+const LocalRegion = "local"
+
+// RegionStatus 対象の地域ごとの最新の結果
+type RegionStatus struct {
+	URL     string                          `json:"url"`
+	State   string                          `json:"state"` // up / partial / down
+	Down    []string                        `json:"down,omitempty"`
+	Regions map[string]*checker.CheckResult `json:"regions"`
+}
+
+// RegionName 結果の地域名（未設定の場合はLocalRegion）
+func RegionName(r *checker.CheckResult) string {
+	if r.Region == "" {
+		return LocalRegion
+	}
+	return r.Region
+}
+
+// LatestByRegion 対象ごとに地域別の最新の結果をまとめ、全地域で失敗しているか一部のみかを判定
+func LatestByRegion(results []*checker.CheckResult) map[string]*RegionStatus {
+	statuses := make(map[string]*RegionStatus)
+	for _, r := range results {
+		s, ok := statuses[r.URL]
+		if !ok {
+			s = &RegionStatus{URL: r.URL, Regions: make(map[string]*checker.CheckResult)}
+			statuses[r.URL] = s
+		}
+		region := RegionName(r)
+		if prev, ok := s.Regions[region]; !ok || r.Timestamp.After(prev.Timestamp) {
+			s.Regions[region] = r
+		}
+	}
+
+	for _, s := range statuses {
+		for region, r := range s.Regions {
+			if !r.Success {
+				s.Down = append(s.Down, region)
+			}
+		}
+		sort.Strings(s.Down)
+		switch {
+		case len(s.Down) == 0:
+			s.State = "up"
+		case len(s.Down) == len(s.Regions):
+			s.State = "down"
+		default:
+			s.State = "partial"
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"healthcheck/internal/checker"
+)
+
+// Regression 前回の実行との差分
+type Regression struct {
+	PreviousTimestamp time.Time       `json:"previous_timestamp"`
+	NewFailures       []string        `json:"new_failures,omitempty"`
+	Recovered         []string        `json:"recovered,omitempty"`
+	LatencyChanges    []LatencyChange `json:"latency_changes,omitempty"`
+}
+
+// LatencyChange 前回から閾値以上変化した応答時間
+type LatencyChange struct {
+	URL           string        `json:"url"`
+	Previous      time.Duration `json:"previous_ms"`
+	Current       time.Duration `json:"current_ms"`
+	ChangePercent float64       `json:"change_percent"`
+}
+
+// PreviousMs 前回の応答時間をミリ秒で返す
+func (c LatencyChange) PreviousMs() float64 {
+	return float64(c.Previous.Nanoseconds()) / 1e6
+}
+
+// CurrentMs 今回の応答時間をミリ秒で返す
+func (c LatencyChange) CurrentMs() float64 {
+	return float64(c.Current.Nanoseconds()) / 1e6
+}
+
+// CompareRuns 前回の実行結果と比較し、新たな失敗・復旧・応答時間の変化を返す
+// 両方の実行に含まれるURLのみを比較し、応答時間は両方で成功した場合のみ比較する
+func CompareRuns(previous, current []*checker.CheckResult, previousTimestamp time.Time, thresholdPercent float64) *Regression {
+	prevByURL := make(map[string]*checker.CheckResult)
+	for _, r := range previous {
+		prevByURL[r.URL] = r
+	}
+
+	regression := &Regression{PreviousTimestamp: previousTimestamp}
+	for _, cur := range current {
+		prev, ok := prevByURL[cur.URL]
+		if !ok {
+			continue
+		}
+
+		switch {
+		case prev.Success && !cur.Success:
+			regression.NewFailures = append(regression.NewFailures, cur.URL)
+		case !prev.Success && cur.Success:
+			regression.Recovered = append(regression.Recovered, cur.URL)
+		case prev.Success && cur.Success && prev.ResponseTime > 0:
+			change := float64(cur.ResponseTime-prev.ResponseTime) / float64(prev.ResponseTime) * 100
+			if change >= thresholdPercent || change <= -thresholdPercent {
+				regression.LatencyChanges = append(regression.LatencyChanges, LatencyChange{
+					URL:           cur.URL,
+					Previous:      prev.ResponseTime,
+					Current:       cur.ResponseTime,
+					ChangePercent: change,
+				})
+			}
+		}
+	}
+
+	sort.Strings(regression.NewFailures)
+	sort.Strings(regression.Recovered)
+	sort.Slice(regression.LatencyChanges, func(i, j int) bool {
+		return regression.LatencyChanges[i].ChangePercent > regression.LatencyChanges[j].ChangePercent
+	})
+	return regression
+}
+
+// Empty 差分がないかどうか
+func (r *Regression) Empty() bool {
+	return len(r.NewFailures) == 0 && len(r.Recovered) == 0 && len(r.LatencyChanges) == 0
+}
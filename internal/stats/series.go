@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"healthcheck/internal/checker"
+)
+
+// SeriesMetrics 時系列として取得できる指標
+var SeriesMetrics = []string{"response_time", "latency", "success", "status_code"}
+
+// Point 時系列の1点
+type Point struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// Series 対象の指標を期間内の時系列として返す（古い順）
+// maxPointsを超える場合は等間隔の区間ごとに平均して間引く（0の場合は間引かない）
+func Series(results []*checker.CheckResult, url, metric string, from, to time.Time, maxPoints int) []Point {
+	var points []Point
+	for _, r := range results {
+		if r.URL != url || r.Timestamp.Before(from) || r.Timestamp.After(to) {
+			continue
+		}
+		value, ok := metricValue(r, metric)
+		if !ok {
+			continue
+		}
+		points = append(points, Point{Timestamp: r.Timestamp, Value: value})
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Timestamp.Before(points[j].Timestamp)
+	})
+
+	if maxPoints <= 0 || len(points) <= maxPoints {
+		return points
+	}
+	return downsample(points, from, to, maxPoints)
+}
+
+// metricValue 結果から指標の値を取り出す（失敗した結果の応答時間は対象外）
+func metricValue(r *checker.CheckResult, metric string) (float64, bool) {
+	switch metric {
+	case "response_time":
+		return r.ResponseTimeMs(), r.Success
+	case "latency":
+		return r.LatencyMs(), r.Success
+	case "success":
+		if r.Success {
+			return 1, true
+		}
+		return 0, true
+	case "status_code":
+		return float64(r.StatusCode), r.StatusCode != 0
+	}
+	return 0, false
+}
+
+// downsample 期間をmaxPoints個の区間に分け、区間ごとの平均値にまとめる
+func downsample(points []Point, from, to time.Time, maxPoints int) []Point {
+	step := to.Sub(from) / time.Duration(maxPoints)
+	if step <= 0 {
+		return points[len(points)-maxPoints:]
+	}
+
+	var result []Point
+	var sum float64
+	var count int
+	bucket := -1
+	for _, p := range points {
+		b := int(p.Timestamp.Sub(from) / step)
+		if b != bucket && count > 0 {
+			result = append(result, Point{Timestamp: from.Add(time.Duration(bucket) * step), Value: sum / float64(count)})
+			sum, count = 0, 0
+		}
+		bucket = b
+		sum += p.Value
+		count++
+	}
+	if count > 0 {
+		result = append(result, Point{Timestamp: from.Add(time.Duration(bucket) * step), Value: sum / float64(count)})
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"healthcheck/internal/checker"
+)
+
+// SLAWindows 選択可能な集計期間
+var SLAWindows = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// TargetSLA 対象ごとの稼働率とエラーバジェット
+type TargetSLA struct {
+	URL                  string        `json:"url"`
+	Checks               int           `json:"checks"`
+	Failures             int           `json:"failures"`
+	UptimePercent        float64       `json:"uptime_percent"`
+	Downtime             time.Duration `json:"downtime_ms"`
+	ObservedTime         time.Duration `json:"observed_time_ms"`
+	ErrorBudget          time.Duration `json:"error_budget_ms"`           // 期間全体で許容されるダウンタイム
+	ErrorBudgetRemaining time.Duration `json:"error_budget_remaining_ms"` // 残りのエラーバジェット（超過時は負の値）
+	ErrorBudgetBurned    float64       `json:"error_budget_burned"`       // 消費したエラーバジェットの割合（%）
+}
+
+// DowntimeMinutes ダウンタイムを分で返す
+func (s *TargetSLA) DowntimeMinutes() float64 {
+	return s.Downtime.Minutes()
+}
+
+// CalculateSLA 期間内の結果から対象ごとの稼働率を計算
+// 各チェックの状態は次のチェックまで継続したものとみなし、最後のチェックは現在時刻まで継続したものとする
+func CalculateSLA(results []*checker.CheckResult, window time.Duration, slo float64, now time.Time) []*TargetSLA {
+	windowStart := now.Add(-window)
+	byURL := make(map[string][]*checker.CheckResult)
+	for _, r := range results {
+		if r.Timestamp.Before(windowStart) || r.Timestamp.After(now) {
+			continue
+		}
+		byURL[r.URL] = append(byURL[r.URL], r)
+	}
+
+	errorBudget := time.Duration(float64(window) * (100 - slo) / 100)
+
+	var slas []*TargetSLA
+	for url, rs := range byURL {
+		sort.Slice(rs, func(i, j int) bool {
+			return rs[i].Timestamp.Before(rs[j].Timestamp)
+		})
+
+		sla := &TargetSLA{
+			URL:         url,
+			Checks:      len(rs),
+			ErrorBudget: errorBudget,
+		}
+		for i, r := range rs {
+			end := now
+			if i+1 < len(rs) {
+				end = rs[i+1].Timestamp
+			}
+			span := end.Sub(r.Timestamp)
+			sla.ObservedTime += span
+			if !r.Success {
+				sla.Failures++
+				sla.Downtime += span
+			}
+		}
+
+		if sla.ObservedTime > 0 {
+			sla.UptimePercent = float64(sla.ObservedTime-sla.Downtime) / float64(sla.ObservedTime) * 100
+		} else if sla.Failures == 0 {
+			sla.UptimePercent = 100
+		}
+		sla.ErrorBudgetRemaining = errorBudget - sla.Downtime
+		if errorBudget > 0 {
+			sla.ErrorBudgetBurned = float64(sla.Downtime) / float64(errorBudget) * 100
+		}
+		slas = append(slas, sla)
+	}
+
+	sort.Slice(slas, func(i, j int) bool {
+		return slas[i].URL < slas[j].URL
+	})
+	return slas
+}
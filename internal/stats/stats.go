@@ -20,16 +20,33 @@ func CalculateStatistics(results []*checker.CheckResult, totalDuration time.Dura
 	var totalLatency time.Duration
 	var successResponseTimes []time.Duration
 	var successLatencies []time.Duration
+	stats.ResponseTimeHistogram = NewHistogram(HistogramBuckets())
+	stats.LatencyHistogram = NewHistogram(HistogramBuckets())
 
 	for _, result := range results {
+		if result.Duplicate {
+			stats.DuplicateCount++
+		}
+		if result.Cache != nil {
+			if stats.CacheCounts == nil {
+				stats.CacheCounts = make(map[string]int)
+			}
+			stats.CacheCounts[result.Cache.Status]++
+		}
 		if result.Success {
 			stats.SuccessCount++
 			successResponseTimes = append(successResponseTimes, result.ResponseTime)
 			successLatencies = append(successLatencies, result.Latency)
 			totalResponseTime += result.ResponseTime
 			totalLatency += result.Latency
+			stats.ResponseTimeHistogram.Observe(result.ResponseTime)
+			stats.LatencyHistogram.Observe(result.Latency)
 		} else {
 			stats.FailureCount++
+			if stats.ErrorCounts == nil {
+				stats.ErrorCounts = make(map[checker.ErrorCategory]int)
+			}
+			stats.ErrorCounts[result.Error]++
 		}
 	}
 
@@ -52,6 +69,7 @@ func CalculateStatistics(results []*checker.CheckResult, totalDuration time.Dura
 				stats.MaxResponseTime = rt
 			}
 		}
+		stats.P95ResponseTime = Percentile(successResponseTimes, 95)
 	}
 
 	// レイテンシの統計（成功したリクエストのみ）
@@ -1,6 +1,10 @@
 package stats
 
-import "time"
+import (
+	"time"
+
+	"healthcheck/internal/checker"
+)
 
 // Statistics 統計情報
 type Statistics struct {
@@ -11,10 +15,18 @@ type Statistics struct {
 	AvgResponseTime time.Duration `json:"avg_response_time_ms"`
 	MinResponseTime time.Duration `json:"min_response_time_ms"`
 	MaxResponseTime time.Duration `json:"max_response_time_ms"`
+	P95ResponseTime time.Duration `json:"p95_response_time_ms"`
 	AvgLatency      time.Duration `json:"avg_latency_ms"`
 	MinLatency      time.Duration `json:"min_latency_ms"`
 	MaxLatency      time.Duration `json:"max_latency_ms"`
 	TotalDuration   time.Duration `json:"total_duration_ms"`
+	DuplicateCount  int           `json:"duplicate_count,omitempty"` // 同じ対象の2回目以降の出現の数
+
+	ErrorCounts map[checker.ErrorCategory]int `json:"error_counts,omitempty"` // 失敗の種類ごとの件数
+	CacheCounts map[string]int                `json:"cache_counts,omitempty"` // CDNのキャッシュの状態（hit・missなど）ごとの件数
+
+	ResponseTimeHistogram *Histogram `json:"response_time_histogram,omitempty"` // 成功した結果の応答時間の分布（区間はHistogramBuckets）
+	LatencyHistogram      *Histogram `json:"latency_histogram,omitempty"`       // 成功した結果のレイテンシの分布
 }
 
 // AvgResponseTimeMs 平均応答時間をミリ秒で返す
@@ -22,6 +34,18 @@ func (s *Statistics) AvgResponseTimeMs() float64 {
 	return float64(s.AvgResponseTime.Nanoseconds()) / 1e6
 }
 
+// CacheHitRate キャッシュの状態を返した結果のうちキャッシュから応答した割合（%、該当する結果がない場合は0）
+func (s *Statistics) CacheHitRate() float64 {
+	total := 0
+	for _, n := range s.CacheCounts {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(s.CacheCounts["hit"]) / float64(total) * 100
+}
+
 // AvgLatencyMs 平均レイテンシをミリ秒で返す
 func (s *Statistics) AvgLatencyMs() float64 {
 	return float64(s.AvgLatency.Nanoseconds()) / 1e6
@@ -1,28 +1,91 @@
 package storage
 
 import (
+	"bufio"
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync/atomic"
 	"time"
 
 	"healthcheck/internal/checker"
 	"healthcheck/internal/stats"
 )
 
+// historyLimit 保持する履歴ファイル数（設定のhistory_limitを起動時・再読み込み時に反映する）
+var historyLimit atomic.Int64
+
+func init() {
+	historyLimit.Store(10)
+}
+
+// HistoryLimit 保持する履歴ファイル数
+func HistoryLimit() int {
+	return int(historyLimit.Load())
+}
+
+// SetHistoryLimit 保持する履歴ファイル数を設定（チェックの実行中に再読み込みで変更しても安全）
+func SetHistoryLimit(n int) {
+	historyLimit.Store(int64(n))
+}
+
+// ResultsDir 履歴を保存するディレクトリ
+var ResultsDir = "results"
+
+// fileTimestampLayout 履歴・トランザクションのファイル名に付ける日時の形式（UTC）
+const fileTimestampLayout = "20060102_150405Z"
+
+// fileTimestamp ファイル名に付ける日時（サーバーのタイムゾーンによらずUTCにそろえる）
+func fileTimestamp(t time.Time) string {
+	return t.UTC().Format(fileTimestampLayout)
+}
+
+// createNewFile base（拡張子を除いたパス）に.jsonを付けたファイルを新しく作成
+// 同じ名前のファイルがある場合は上書きせず_2, _3...の連番を付ける（同時に保存しても重ならないようO_EXCLで作成する）
+func createNewFile(base string) (*os.File, error) {
+	path := base + ".json"
+	for i := 2; ; i++ {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if !os.IsExist(err) {
+			return f, err
+		}
+		path = fmt.Sprintf("%s_%d.json", base, i)
+	}
+}
+
+// writeNewFile createNewFileで作成したファイルにdataを書き込み、そのパスを返す
+func writeNewFile(base string, data []byte) (string, error) {
+	f, err := createNewFile(base)
+	if err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	return f.Name(), nil
+}
+
 // SaveResultsJSON JSON形式で結果を保存
 func SaveResultsJSON(results []*checker.CheckResult, statistics *stats.Statistics, outputPath string) error {
-	data := map[string]interface{}{
-		"timestamp":  time.Now().Format(time.RFC3339),
-		"results":    results,
-		"statistics": statistics,
-	}
+	return saveResultsJSON("", nil, results, statistics, outputPath)
+}
 
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+// saveResultsJSON 実行IDとメタデータ付きでJSON形式の結果を保存
+func saveResultsJSON(runID string, metadata map[string]string, results []*checker.CheckResult, statistics *stats.Statistics, outputPath string) error {
+	jsonData, err := resultsJSON(runID, metadata, results, statistics)
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
+		return err
 	}
 
 	// ディレクトリが存在しない場合は作成
@@ -40,6 +103,27 @@ func SaveResultsJSON(results []*checker.CheckResult, statistics *stats.Statistic
 	return nil
 }
 
+// resultsJSON 実行IDとメタデータ付きの結果をJSONに変換
+func resultsJSON(runID string, metadata map[string]string, results []*checker.CheckResult, statistics *stats.Statistics) ([]byte, error) {
+	data := map[string]interface{}{
+		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+		"results":    results,
+		"statistics": statistics,
+	}
+	if runID != "" {
+		data["run_id"] = runID
+	}
+	if len(metadata) > 0 {
+		data["metadata"] = metadata
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return jsonData, nil
+}
+
 // SaveResultsCSV CSV形式で結果を保存
 func SaveResultsCSV(results []*checker.CheckResult, outputPath string) error {
 	// ディレクトリが存在しない場合は作成
@@ -60,7 +144,7 @@ func SaveResultsCSV(results []*checker.CheckResult, outputPath string) error {
 	defer writer.Flush()
 
 	// ヘッダーを書き込み
-	headers := []string{"URL", "Status Code", "Success", "Response Time (ms)", "Latency (ms)", "Error", "Error Message", "Timestamp"}
+	headers := []string{"URL", "Status Code", "Success", "Response Time (ms)", "Latency (ms)", "Error", "Error Message", "Timestamp (UTC)"}
 	if err := writer.Write(headers); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
@@ -73,9 +157,9 @@ func SaveResultsCSV(results []*checker.CheckResult, outputPath string) error {
 			fmt.Sprintf("%t", result.Success),
 			fmt.Sprintf("%.2f", result.ResponseTimeMs()),
 			fmt.Sprintf("%.2f", result.LatencyMs()),
-			result.Error,
+			string(result.Error),
 			result.ErrorMessage,
-			result.Timestamp.Format(time.RFC3339),
+			result.Timestamp.UTC().Format(time.RFC3339),
 		}
 		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("failed to write row: %w", err)
@@ -86,27 +170,157 @@ func SaveResultsCSV(results []*checker.CheckResult, outputPath string) error {
 }
 
 // SaveHistory 履歴を保存（タイムスタンプ付きファイル名）
-func SaveHistory(results []*checker.CheckResult, statistics *stats.Statistics) (string, error) {
-	resultsDir := "results"
+// runIDにはトレースと対応付けるための実行ID、metadataには呼び出し元が付けた実行の情報（デプロイのSHAなど）を指定する
+func SaveHistory(runID string, metadata map[string]string, results []*checker.CheckResult, statistics *stats.Statistics) (string, error) {
+	return SaveHistoryIn(ResultsDir, runID, metadata, results, statistics)
+}
+
+// SaveHistoryIn 指定したディレクトリに履歴を保存（プロジェクトごとの履歴に使用）
+func SaveHistoryIn(resultsDir, runID string, metadata map[string]string, results []*checker.CheckResult, statistics *stats.Statistics) (string, error) {
 	if err := os.MkdirAll(resultsDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create results directory: %w", err)
 	}
 
-	timestamp := time.Now().Format("20060102_150405")
-	filename := fmt.Sprintf("results_%s.json", timestamp)
-	filepath := filepath.Join(resultsDir, filename)
-
-	if err := SaveResultsJSON(results, statistics, filepath); err != nil {
+	jsonData, err := resultsJSON(runID, metadata, results, statistics)
+	if err != nil {
+		return "", err
+	}
+	path, err := writeNewFile(filepath.Join(resultsDir, "results_"+fileTimestamp(time.Now())), jsonData)
+	if err != nil {
 		return "", err
 	}
 
-	// 最新10件のみ保持
-	if err := cleanupOldResults(resultsDir, 10); err != nil {
+	// 最新HistoryLimit件のみ保持
+	if err := cleanupOldResults(resultsDir, HistoryLimit()); err != nil {
 		// エラーは無視（ログに記録するだけ）
-		fmt.Printf("Warning: failed to cleanup old results: %v\n", err)
+		slog.Warn("failed to cleanup old results", "error", err)
 	}
 
-	return filepath, nil
+	return path, nil
+}
+
+// SaveAggregatedHistory 集計の結果を1件ずつ書き込んで履歴を保存（一時ファイルに書き出した大量の結果をメモリに読み込まずに保存する）
+func SaveAggregatedHistory(runID string, metadata map[string]string, agg *stats.Aggregator, statistics *stats.Statistics) (string, error) {
+	if err := os.MkdirAll(ResultsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create results directory: %w", err)
+	}
+
+	timestamp := time.Now().UTC()
+	f, err := createNewFile(filepath.Join(ResultsDir, "results_"+fileTimestamp(timestamp)))
+	if err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	defer f.Close()
+	path := f.Name()
+
+	header := map[string]interface{}{
+		"timestamp":  timestamp.Format(time.RFC3339),
+		"statistics": statistics,
+	}
+	if runID != "" {
+		header["run_id"] = runID
+	}
+	if len(metadata) > 0 {
+		header["metadata"] = metadata
+	}
+	w := bufio.NewWriter(f)
+	if err := WriteWithResults(w, header, agg); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	if err := cleanupOldResults(ResultsDir, HistoryLimit()); err != nil {
+		slog.Warn("failed to cleanup old results", "error", err)
+	}
+	return path, nil
+}
+
+// WriteWithResults valueのJSONオブジェクトに集計の全ての結果を "results" として1件ずつ書き込む
+func WriteWithResults(w io.Writer, value map[string]interface{}, agg *stats.Aggregator) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	// 閉じ括弧の手前に結果の配列を追加する
+	data = data[:len(data)-1]
+	if len(value) > 0 {
+		data = append(data, ',')
+	}
+	data = append(data, `"results":[`...)
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	first := true
+	err = agg.Each(func(r *checker.CheckResult) error {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "]}")
+	return err
+}
+
+// CheckWritable 履歴のディレクトリに書き込めるかを一時ファイルの作成で確認
+func CheckWritable() error {
+	if err := os.MkdirAll(ResultsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create results directory: %w", err)
+	}
+	f, err := os.CreateTemp(ResultsDir, ".writable-*")
+	if err != nil {
+		return fmt.Errorf("failed to write results directory: %w", err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// SaveHistoryEntry 実行日時を指定して履歴を保存（デモデータの投入やエージェントから受信した結果に使用）
+func SaveHistoryEntry(entry *HistoryEntry) (string, error) {
+	if err := os.MkdirAll(ResultsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create results directory: %w", err)
+	}
+
+	entry.normalizeTimestamps()
+	jsonData, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	// 複数の地域から同じ秒に受信しても上書きしないよう地域名と連番を付ける
+	base := filepath.Join(ResultsDir, "results_"+fileTimestamp(entry.Timestamp))
+	if entry.Region != "" {
+		base += "_" + entry.Region
+	}
+	path, err := writeNewFile(base, jsonData)
+	if err != nil {
+		return "", err
+	}
+
+	// 古い順の削除が実行日時どおりになるよう更新日時を合わせる
+	if err := os.Chtimes(path, entry.Timestamp, entry.Timestamp); err != nil {
+		return "", fmt.Errorf("failed to set file time: %w", err)
+	}
+
+	if err := cleanupOldResults(ResultsDir, HistoryLimit()); err != nil {
+		slog.Warn("failed to cleanup old results", "error", err)
+	}
+
+	return path, nil
 }
 
 // cleanupOldResults 古い結果ファイルを削除（最新N件のみ保持）
@@ -138,13 +352,9 @@ func cleanupOldResults(resultsDir string, keepCount int) error {
 	}
 
 	// 更新日時でソート（新しい順）
-	for i := 0; i < len(fileInfos)-1; i++ {
-		for j := i + 1; j < len(fileInfos); j++ {
-			if fileInfos[i].modTime.Before(fileInfos[j].modTime) {
-				fileInfos[i], fileInfos[j] = fileInfos[j], fileInfos[i]
-			}
-		}
-	}
+	sort.Slice(fileInfos, func(i, j int) bool {
+		return fileInfos[i].modTime.After(fileInfos[j].modTime)
+	})
 
 	// 古いファイルを削除
 	if len(fileInfos) > keepCount {
@@ -159,6 +369,120 @@ func cleanupOldResults(resultsDir string, keepCount int) error {
 	return nil
 }
 
+// HistoryEntry 保存された1回分の実行結果
+type HistoryEntry struct {
+	Timestamp  time.Time              `json:"timestamp"`
+	RunID      string                 `json:"run_id,omitempty"`
+	Region     string                 `json:"region,omitempty"`   // エージェントから受信した結果の地域
+	Metadata   map[string]string      `json:"metadata,omitempty"` // 呼び出し元が付けた実行の情報（triggered_by・deploy_sha・environmentなど）
+	Results    []*checker.CheckResult `json:"results"`
+	Statistics *stats.Statistics      `json:"statistics"`
+}
+
+// normalizeTimestamps 実行日時と結果の日時をUTCにそろえる（ローカル時刻で保存していた以前の履歴用）
+func (e *HistoryEntry) normalizeTimestamps() {
+	e.Timestamp = e.Timestamp.UTC()
+	for _, r := range e.Results {
+		r.Timestamp = r.Timestamp.UTC()
+	}
+}
+
+// LoadHistoryEntries 過去の結果を実行日時の古い順に読み込み
+func LoadHistoryEntries(resultsDir string) ([]*HistoryEntry, error) {
+	files, err := os.ReadDir(resultsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*HistoryEntry{}, nil
+		}
+		return nil, err
+	}
+
+	var entries []*HistoryEntry
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		entry, err := readHistoryEntry(filepath.Join(resultsDir, file.Name()))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	return entries, nil
+}
+
+// readHistoryEntry 1回分の実行結果のファイルを読み込み
+func readHistoryEntry(path string) (*HistoryEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entry HistoryEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	entry.normalizeTimestamps()
+	return &entry, nil
+}
+
+// LatestHistoryEntry 最新の実行結果を読み込み（履歴がない場合はnil）
+// ファイル名は実行日時の順に並ぶため、最も新しいファイルだけを読み込む（読み込めない場合はその前のファイル）
+func LatestHistoryEntry(resultsDir string) (*HistoryEntry, error) {
+	files, err := os.ReadDir(resultsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// ReadDirはファイル名の順に返す
+	for i := len(files) - 1; i >= 0; i-- {
+		if files[i].IsDir() || filepath.Ext(files[i].Name()) != ".json" {
+			continue
+		}
+		if entry, err := readHistoryEntry(filepath.Join(resultsDir, files[i].Name())); err == nil {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindHistoryEntry 実行IDが一致する実行結果を読み込み（見つからない場合はnil）
+// エージェントから受信した結果など同じ実行IDが複数ある場合は最新のもの
+func FindHistoryEntry(resultsDir, runID string) (*HistoryEntry, error) {
+	entries, err := LoadHistoryEntries(resultsDir)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].RunID == runID {
+			return entries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// LoadHistoryResults 過去のすべての実行結果を1つのスライスにまとめて読み込み
+func LoadHistoryResults(resultsDir string) ([]*checker.CheckResult, error) {
+	entries, err := LoadHistoryEntries(resultsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*checker.CheckResult
+	for _, entry := range entries {
+		results = append(results, entry.Results...)
+	}
+	return results, nil
+}
+
 // LoadHistory 過去の結果を読み込み
 func LoadHistory(resultsDir string) ([]map[string]interface{}, error) {
 	files, err := os.ReadDir(resultsDir)
@@ -195,3 +519,87 @@ func LoadHistory(resultsDir string) ([]map[string]interface{}, error) {
 
 	return history, nil
 }
+
+// SaveTransaction トランザクション定義を保存（継続監視用）
+func SaveTransaction(tx *checker.Transaction, transactionsDir string) (string, error) {
+	if err := os.MkdirAll(transactionsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create transactions directory: %w", err)
+	}
+
+	jsonData, err := json.MarshalIndent(tx, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	// 同じ秒に複数保存しても上書きしないよう連番を付ける
+	return writeNewFile(filepath.Join(transactionsDir, "transaction_"+fileTimestamp(time.Now())), jsonData)
+}
+
+// LoadTransactions 保存済みのトランザクション定義を読み込み
+func LoadTransactions(transactionsDir string) ([]*checker.Transaction, error) {
+	files, err := os.ReadDir(transactionsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*checker.Transaction{}, nil
+		}
+		return nil, err
+	}
+
+	var transactions []*checker.Transaction
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(transactionsDir, file.Name()))
+		if err != nil {
+			continue
+		}
+
+		var tx checker.Transaction
+		if err := json.Unmarshal(data, &tx); err != nil {
+			continue
+		}
+		transactions = append(transactions, &tx)
+	}
+
+	return transactions, nil
+}
+
+// HeartbeatsFile ハートビートのトークンと最終受信日時を保存するファイル
+var HeartbeatsFile = "heartbeats.json"
+
+// HeartbeatState ハートビート対象ごとの保存状態
+type HeartbeatState struct {
+	Token    string    `json:"token"`
+	LastPing time.Time `json:"last_ping"`
+}
+
+// LoadHeartbeats ハートビートの状態を対象のURLごとに読み込み
+func LoadHeartbeats(path string) (map[string]*HeartbeatState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]*HeartbeatState{}, nil
+		}
+		return nil, fmt.Errorf("failed to read heartbeats: %w", err)
+	}
+
+	states := make(map[string]*HeartbeatState)
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("failed to parse heartbeats: %w", err)
+	}
+	return states, nil
+}
+
+// SaveHeartbeats ハートビートの状態を保存
+func SaveHeartbeats(path string, states map[string]*HeartbeatState) error {
+	jsonData, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if err := os.WriteFile(path, jsonData, 0600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// flushInterval 終了した区間を送信する間隔
+const flushInterval = 5 * time.Second
+
+// maxBatchSize 1回の送信にまとめる最大の区間数（超えた場合はすぐに送信）
+const maxBatchSize = 512
+
+// maxPending 送信待ちとして保持する最大の区間数（超えた分は破棄）
+const maxPending = 10000
+
+// Exporter OTLP/HTTP（JSONエンコーディング）で区間を送信
+type Exporter struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+
+	mutex   sync.Mutex
+	pending []*Span
+}
+
+// exporter 設定されたエクスポーター（nilの場合は区間を送信しない）
+var exporter *Exporter
+
+// Setup OTLPのエンドポイントを設定して定期送信を開始
+// endpointにはコレクターのトレース受信URL（例: http://localhost:4318/v1/traces）を指定
+func Setup(endpoint string, headers map[string]string, serviceName string) {
+	if endpoint == "" {
+		return
+	}
+	exporter = &Exporter{
+		endpoint:    endpoint,
+		headers:     headers,
+		serviceName: serviceName,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+	go exporter.loop()
+}
+
+// Shutdown 送信待ちの区間を送信（プロセスの終了前に呼び出す）
+func Shutdown() {
+	if exporter != nil {
+		exporter.Flush()
+	}
+}
+
+// export 終了した区間を送信待ちに追加
+func export(span *Span) {
+	e := exporter
+	if e == nil {
+		return
+	}
+
+	e.mutex.Lock()
+	if len(e.pending) < maxPending {
+		e.pending = append(e.pending, span)
+	}
+	full := len(e.pending) >= maxBatchSize
+	e.mutex.Unlock()
+
+	if full {
+		go e.Flush()
+	}
+}
+
+// loop 一定間隔で送信待ちの区間を送信
+func (e *Exporter) loop() {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		e.Flush()
+	}
+}
+
+// Flush 送信待ちの区間をすべて送信
+func (e *Exporter) Flush() {
+	e.mutex.Lock()
+	spans := e.pending
+	e.pending = nil
+	e.mutex.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+	if err := e.send(spans); err != nil {
+		slog.Warn("failed to export spans", "spans", len(spans), "error", err)
+	}
+}
+
+// send OTLP/HTTPで区間を送信
+func (e *Exporter) send(spans []*Span) error {
+	body, err := json.Marshal(e.payload(spans))
+	if err != nil {
+		return fmt.Errorf("failed to marshal spans: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send spans: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// otlpSpan OTLPのJSONエンコーディングでの区間
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+// otlpAttribute OTLPのキーと値の組
+type otlpAttribute struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+// otlpStatus OTLPの区間のステータス
+type otlpStatus struct {
+	Code    int    `json:"code"` // 2: エラー
+	Message string `json:"message,omitempty"`
+}
+
+// OTLPのSpanKind
+const (
+	spanKindInternal = 1
+	spanKindClient   = 3
+)
+
+// payload ExportTraceServiceRequestのJSONを作成
+func (e *Exporter) payload(spans []*Span) map[string]interface{} {
+	var converted []otlpSpan
+	for _, span := range spans {
+		span.mutex.Lock()
+		s := otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentID,
+			Name:              span.Name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        attributes(span.Attributes),
+		}
+		if span.Client {
+			s.Kind = spanKindClient
+		}
+		if span.Error != "" {
+			s.Status = &otlpStatus{Code: 2, Message: span.Error}
+		}
+		span.mutex.Unlock()
+		converted = append(converted, s)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": attributes(map[string]interface{}{"service.name": e.serviceName}),
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": "healthcheck"},
+						"spans": converted,
+					},
+				},
+			},
+		},
+	}
+}
+
+// attributes 属性をOTLPの形式に変換（キー順）
+func attributes(attrs map[string]interface{}) []otlpAttribute {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var converted []otlpAttribute
+	for _, k := range keys {
+		var value map[string]interface{}
+		switch v := attrs[k].(type) {
+		case bool:
+			value = map[string]interface{}{"boolValue": v}
+		case int:
+			value = map[string]interface{}{"intValue": strconv.Itoa(v)}
+		case int64:
+			value = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+		case float64:
+			value = map[string]interface{}{"doubleValue": v}
+		default:
+			value = map[string]interface{}{"stringValue": fmt.Sprint(v)}
+		}
+		converted = append(converted, otlpAttribute{Key: k, Value: value})
+	}
+	return converted
+}
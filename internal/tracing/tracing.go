@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Span トレース内の1つの処理区間
+type Span struct {
+	TraceID    string
+	SpanID     string
+	ParentID   string
+	Name       string
+	Client     bool // 外部へのリクエストを表す区間
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]interface{}
+	Error      string
+
+	mutex sync.Mutex
+}
+
+// spanKey コンテキストに現在の区間を格納するキー
+type spanKey struct{}
+
+// Start 新しい区間を開始（コンテキストに区間がない場合は新しいトレースを開始）
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	span := &Span{
+		SpanID:     newID(8),
+		Name:       name,
+		Start:      time.Now(),
+		Attributes: make(map[string]interface{}),
+	}
+	if parent := FromContext(ctx); parent != nil {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		span.TraceID = newID(16)
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// Record 開始・終了時刻が確定している子区間を記録
+func Record(ctx context.Context, name string, start, end time.Time) {
+	_, span := Start(ctx, name)
+	span.Start = start
+	span.End = end
+	export(span)
+}
+
+// FromContext コンテキストの現在の区間（ない場合はnil）
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// RunID コンテキストのトレースID（実行IDとして使用、ない場合は空文字）
+func RunID(ctx context.Context) string {
+	if span := FromContext(ctx); span != nil {
+		return span.TraceID
+	}
+	return ""
+}
+
+// Traceparent W3C Trace Contextのtraceparentヘッダーの値
+func (s *Span) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-01", s.TraceID, s.SpanID)
+}
+
+// Inject OTLPのエクスポーターを設定した場合に、リクエストへtraceparentヘッダーを付与する
+// 区間を送信しない場合はトレースをつなげる先がないため付与しない
+func (s *Span) Inject(header http.Header) {
+	if exporter == nil {
+		return
+	}
+	header.Set("traceparent", s.Traceparent())
+}
+
+// SetAttribute 属性を設定
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Attributes[key] = value
+}
+
+// SetError 区間をエラーとしてマーク
+func (s *Span) SetError(message string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Error = message
+}
+
+// Finish 区間を終了してエクスポート対象に追加
+func (s *Span) Finish() {
+	s.mutex.Lock()
+	s.End = time.Now()
+	s.mutex.Unlock()
+	export(s)
+}
+
+// newID ランダムなIDを16進数で生成
+func newID(size int) string {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		// 乱数が取得できない場合も一意になるよう時刻を使用
+		return fmt.Sprintf("%0*x", size*2, time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,294 @@
+package urllist
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// 展開対象として指定する際のURLの接頭辞（checker.SitemapPrefix / RobotsPrefixと同じ）
+var expandPrefixes = []string{"sitemap:", "robots:"}
+
+// Rejected 受け付けなかった入力の行
+type Rejected struct {
+	Line   int    `json:"line"`
+	Text   string `json:"text"`
+	Reason string `json:"reason"`
+}
+
+// Parse URLリストのテキスト（1行に1つ）を検証・正規化する
+// 空行と#で始まるコメント行は無視し、不正な行と重複した行は理由とともにrejectedで返す
+func Parse(text string) (urls []string, rejected []Rejected) {
+	seen := make(map[string]int)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		normalized, err := Normalize(line)
+		if err != nil {
+			rejected = append(rejected, Rejected{Line: i + 1, Text: line, Reason: err.Error()})
+			continue
+		}
+		if first, ok := seen[normalized]; ok {
+			rejected = append(rejected, Rejected{Line: i + 1, Text: line, Reason: fmt.Sprintf("%d行目と重複しています", first)})
+			continue
+		}
+		seen[normalized] = i + 1
+		urls = append(urls, normalized)
+	}
+	return urls, rejected
+}
+
+// Normalize 1つのURLを検証して正規化する
+//   - スキームのないホスト名にはhttps://を補う
+//   - スキームとホスト名を小文字にし、国際化ドメイン名はpunycodeに変換する
+//   - 既定のポートとフラグメントを取り除く
+//
+// sitemap:/robots:の接頭辞は残したまま、その後ろのURLを正規化する
+// エラーのメッセージは利用者に表示する
+func Normalize(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	for _, prefix := range expandPrefixes {
+		if rest, ok := cutPrefixFold(raw, prefix); ok {
+			u, err := normalizeHTTP(rest)
+			if err != nil {
+				return "", err
+			}
+			return prefix + u, nil
+		}
+	}
+	return normalizeHTTP(raw)
+}
+
+// normalizeHTTP HTTP/HTTPSのURLを検証して正規化
+func normalizeHTTP(raw string) (string, error) {
+	if raw == "" {
+		return "", fmt.Errorf("URLが空です")
+	}
+	if strings.ContainsAny(raw, " \t") {
+		return "", fmt.Errorf("URLに空白が含まれています")
+	}
+	if !strings.Contains(raw, "://") {
+		if scheme, _, ok := strings.Cut(raw, ":"); ok && isScheme(scheme) && !looksLikeHostPort(raw) {
+			return "", fmt.Errorf("対応していないスキームです（%s）", scheme)
+		}
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("URLの形式が不正です")
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("対応していないスキームです（%s）", u.Scheme)
+	}
+
+	hostname, port := u.Hostname(), u.Port()
+	if hostname == "" {
+		return "", fmt.Errorf("ホスト名がありません")
+	}
+	host, err := ToASCII(hostname)
+	if err != nil {
+		return "", err
+	}
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String(), nil
+}
+
+// ToASCII ホスト名を小文字にし、ASCII以外の文字を含むラベルをpunycode（xn--）に変換
+func ToASCII(hostname string) (string, error) {
+	if ip := net.ParseIP(hostname); ip != nil {
+		return ip.String(), nil
+	}
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+	// 全角ピリオドなどもラベルの区切りとして扱う
+	hostname = strings.NewReplacer("。", ".", "．", ".", "｡", ".").Replace(hostname)
+	if len(hostname) == 0 {
+		return "", fmt.Errorf("ホスト名がありません")
+	}
+
+	labels := strings.Split(hostname, ".")
+	for i, label := range labels {
+		if label == "" {
+			return "", fmt.Errorf("ホスト名が不正です（空のラベル）")
+		}
+		if !isASCII(label) {
+			encoded, err := encodePunycode(label)
+			if err != nil {
+				return "", err
+			}
+			label = "xn--" + encoded
+		}
+		if len(label) > 63 {
+			return "", fmt.Errorf("ホスト名のラベルが長すぎます（%s）", label)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return "", fmt.Errorf("ホスト名に使用できない文字が含まれています（%q）", r)
+			}
+		}
+		labels[i] = label
+	}
+	host := strings.Join(labels, ".")
+	if len(host) > 253 {
+		return "", fmt.Errorf("ホスト名が長すぎます")
+	}
+	return host, nil
+}
+
+// punycodeのパラメーター（RFC 3492）
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+// encodePunycode ラベルをpunycodeに変換（RFC 3492、xn--は含まない）
+func encodePunycode(label string) (string, error) {
+	runes := []rune(label)
+	var out strings.Builder
+	basic := 0
+	for _, r := range runes {
+		if r < 0x80 {
+			out.WriteRune(r)
+			basic++
+		}
+	}
+	handled := basic
+	if basic > 0 {
+		out.WriteByte('-')
+	}
+
+	n, delta, bias := rune(punyInitialN), 0, punyInitialBias
+	for handled < len(runes) {
+		m := rune(0x10FFFF)
+		for _, r := range runes {
+			if r >= n && r < m {
+				m = r
+			}
+		}
+		if int(m-n) > (1<<31-1-delta)/(handled+1) {
+			return "", fmt.Errorf("ホスト名を変換できません")
+		}
+		delta += int(m-n) * (handled + 1)
+		n = m
+		for _, r := range runes {
+			if r < n {
+				delta++
+			}
+			if r != n {
+				continue
+			}
+			q := delta
+			for k := punyBase; ; k += punyBase {
+				t := k - bias
+				if t < punyTMin {
+					t = punyTMin
+				} else if t > punyTMax {
+					t = punyTMax
+				}
+				if q < t {
+					break
+				}
+				out.WriteByte(punyDigit(t + (q-t)%(punyBase-t)))
+				q = (q - t) / (punyBase - t)
+			}
+			out.WriteByte(punyDigit(q))
+			bias = punyAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+		delta++
+		n++
+	}
+	return out.String(), nil
+}
+
+// punyDigit punycodeの数値を文字に変換
+func punyDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+// punyAdapt punycodeのバイアスを調整
+func punyAdapt(delta, numPoints int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+	k := 0
+	for delta > ((punyBase-punyTMin)*punyTMax)/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+// isASCII ASCII文字のみか
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// isScheme スキームとして使える文字列か
+func isScheme(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && (r >= '0' && r <= '9' || r == '+' || r == '-' || r == '.')) {
+			return false
+		}
+	}
+	return true
+}
+
+// looksLikeHostPort スキームのない「ホスト名:ポート」の形式か（例: example.com:8080/health）
+func looksLikeHostPort(s string) bool {
+	_, rest, _ := strings.Cut(s, ":")
+	port, _, _ := strings.Cut(rest, "/")
+	if port == "" {
+		return false
+	}
+	for _, r := range port {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// cutPrefixFold 大文字・小文字を区別せずに接頭辞を取り除く
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):], true
+	}
+	return s, false
+}
@@ -0,0 +1,279 @@
+package web
+
+import (
+	"context"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"healthcheck/internal/account"
+	"healthcheck/internal/agent"
+	"healthcheck/internal/audit"
+	"healthcheck/internal/config"
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/i18n"
+)
+
+// sessionCookie ログインしたブラウザに発行するクッキー
+const sessionCookie = "healthcheck_session"
+
+// userKey リクエストのコンテキストにログイン中のユーザーを保持するキー
+type userKey struct{}
+
+// currentUser リクエストのログイン中のユーザー（ユーザーを設定していない場合はfalse）
+func currentUser(r *http.Request) (config.User, bool) {
+	u, ok := r.Context().Value(userKey{}).(config.User)
+	return u, ok
+}
+
+// canEdit リクエストのユーザーがチェックの実行や定期チェックの変更をできるか（ユーザーを設定していない場合は常にできる）
+func canEdit(r *http.Request) bool {
+	u, ok := currentUser(r)
+	return !ok || account.Allows(u.Role, "editor")
+}
+
+// publicPaths ログインせずに使えるパス（独自の認証を持つものと、外部の監視・公開用のもの）
+var publicPaths = map[string]bool{
+	"/login":          true,
+	"/logout":         true,
+	"/healthz":        true,
+	"/readyz":         true,
+	"/status":         true,
+	agent.ResultsPath: true,
+}
+
+// publicPrefixes ログインせずに使えるパスの接頭辞
+// プロジェクトのページはトークンを設定していない場合のみwithProjectでログインを求める
+var publicPrefixes = []string{"/heartbeat/", "/badge/", "/embed/", "/shared/", "/p/", "/api/hooks/"}
+
+// adminPaths adminの役割が必要なパス
+var adminPaths = map[string]bool{
+	"/api/reload":          true,
+	"/api/audit":           true,
+	"/api/debug/ratelimit": true,
+}
+
+// requiredRole リクエストに必要な役割
+// 閲覧はviewer、チェックの実行や状態を変更する操作（GET以外と/probe）はeditor、設定の再読み込み・監査記録・デバッグ情報（/debug/ 以下を含む）はadmin
+// GrafanaのAPIは問い合わせにPOSTを使うが、履歴を読むだけなのでviewerで使える
+func requiredRole(r *http.Request) string {
+	switch {
+	case adminPaths[r.URL.Path], strings.HasPrefix(r.URL.Path, "/debug/"):
+		return "admin"
+	case strings.HasPrefix(r.URL.Path, "/api/grafana/"):
+		return "viewer"
+	case r.Method != http.MethodGet && r.Method != http.MethodHead, r.URL.Path == "/probe":
+		return "editor"
+	}
+	return "viewer"
+}
+
+// withAccounts ユーザーを設定した場合に、ログインと役割による権限を確認する
+func (s *Server) withAccounts(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(s.currentConfig().Users) == 0 || isPublicPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r, ok := s.authorizeUser(w, r)
+		if !ok {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isPublicPath ログインせずに使えるパスか
+func isPublicPath(path string) bool {
+	if publicPaths[path] {
+		return true
+	}
+	for _, prefix := range publicPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeUser セッションのクッキーかBasic認証でユーザーを確認し、役割の権限があればユーザーをコンテキストに設定する
+// 確認できない場合は、ブラウザからのページの表示はログインページへ移動し、それ以外は401・403を返す
+func (s *Server) authorizeUser(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	user, ok := s.requestUser(r)
+	if !ok {
+		if r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html") {
+			http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+			return r, false
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="healthcheck"`)
+		http.Error(w, tr(r, "login_required"), http.StatusUnauthorized)
+		return r, false
+	}
+	if e := audit.FromContext(r.Context()); e != nil {
+		e.User = user.Name
+	}
+	if required := requiredRole(r); !account.Allows(user.Role, required) {
+		http.Error(w, tr(r, "role_required", required), http.StatusForbidden)
+		return r, false
+	}
+	return r.WithContext(context.WithValue(r.Context(), userKey{}, user)), true
+}
+
+// requestUser セッションのクッキーまたはBasic認証のユーザー
+// 役割は設定ファイルの現在の値を使うため、再読み込みで変更・削除したユーザーはすぐに反映される
+func (s *Server) requestUser(r *http.Request) (config.User, bool) {
+	if name, password, ok := r.BasicAuth(); ok {
+		u, found := s.currentConfig().FindUser(name)
+		if found && s.verified.Verify(u.PasswordHash, password) {
+			return u, true
+		}
+		return config.User{}, false
+	}
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return config.User{}, false
+	}
+	name, ok := s.sessions.User(c.Value)
+	if !ok {
+		return config.User{}, false
+	}
+	return s.currentConfig().FindUser(name)
+}
+
+// loginTemplate ログインページ（ページの関数は表示のたびにリクエストの表示言語とテーマのものに置き換える）
+var loginTemplate = template.Must(template.New("login").Funcs(dashboard.PageFuncs(i18n.DefaultLanguage, dashboard.Theme{})).Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}" data-theme="{{themeMode}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{t "login"}} - {{pageTitle "Health Check Tool"}}</title>
+    <style>
+        * { margin: 0; padding: 0; box-sizing: border-box; }
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            min-height: 100vh;
+            padding: 20px;
+        }
+        .container {
+            max-width: 400px;
+            margin: 80px auto 0;
+            background: white;
+            border-radius: 10px;
+            box-shadow: 0 10px 40px rgba(0,0,0,0.2);
+            padding: 40px;
+        }
+        h1 { color: #333; margin-bottom: 20px; font-size: 1.6em; }
+        label { display: block; margin-bottom: 8px; color: #333; font-weight: 500; }
+        input {
+            width: 100%;
+            padding: 10px;
+            border: 2px solid #e0e0e0;
+            border-radius: 5px;
+            font-size: 14px;
+            margin-bottom: 16px;
+        }
+        input:focus { outline: none; border-color: #667eea; }
+        button {
+            background: linear-gradient(135deg, #667eea 0%, #764ba2 100%);
+            color: white;
+            border: none;
+            padding: 12px;
+            border-radius: 5px;
+            font-size: 16px;
+            font-weight: 600;
+            cursor: pointer;
+            width: 100%;
+        }
+        .error { color: #ef4444; margin-bottom: 16px; }
+    </style>
+    {{themeStyle}}
+</head>
+<body class="accent">
+    <div class="container">
+        {{themeSwitch}}
+        {{langSwitch}}
+        {{brand}}
+        <h1>🔍 Health Check Tool</h1>
+        {{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+        <form method="post" action="/login">
+            <input type="hidden" name="next" value="{{.Next}}">
+            <label for="name">{{t "username"}}</label>
+            <input type="text" id="name" name="name" value="{{.Name}}" autocomplete="username" required autofocus>
+            <label for="password">{{t "password"}}</label>
+            <input type="password" id="password" name="password" autocomplete="current-password" required>
+            <button type="submit">{{t "login"}}</button>
+        </form>
+    </div>
+</body>
+</html>`))
+
+// handleLogin ログインページの表示（GET）とログイン（POST）
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	next := safeNext(r.FormValue("next"))
+	switch r.Method {
+	case http.MethodGet:
+		renderLogin(w, r, http.StatusOK, next, "", "")
+	case http.MethodPost:
+		name := r.PostFormValue("name")
+		auditAction(r, "login", nil, nil)
+		if e := audit.FromContext(r.Context()); e != nil {
+			e.User = name
+		}
+		cfg := s.currentConfig()
+		u, ok := cfg.FindUser(name)
+		if !ok || !account.VerifyPassword(u.PasswordHash, r.PostFormValue("password")) {
+			slog.WarnContext(r.Context(), "login failed", "user", name, "remote", remoteHost(r))
+			renderLogin(w, r, http.StatusUnauthorized, next, name, tr(r, "login_failed"))
+			return
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:     sessionCookie,
+			Value:    s.sessions.Create(u.Name, cfg.SessionTTL),
+			Path:     "/",
+			MaxAge:   int(cfg.SessionTTL.Seconds()),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+			Secure:   r.TLS != nil,
+		})
+		http.Redirect(w, r, next, http.StatusSeeOther)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleLogout セッションを削除してログインページへ移動
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		s.sessions.Delete(c.Value)
+	}
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Path: "/", MaxAge: -1, HttpOnly: true})
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+// renderLogin ログインページを表示
+func renderLogin(w http.ResponseWriter, r *http.Request, status int, next, name, message string) {
+	t, err := loginTemplate.Clone()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	lang := language(r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	t.Funcs(pageFuncs(r)).Execute(w, map[string]string{"Lang": lang, "Next": next, "Name": name, "Error": message})
+}
+
+// safeNext ログイン後の移動先（同じサーバーのパスのみ、それ以外はダッシュボード）
+func safeNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/dashboard"
+	}
+	return next
+}
@@ -0,0 +1,102 @@
+package web
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"healthcheck/internal/ack"
+	"healthcheck/internal/checker"
+)
+
+// handleAPIAcks 失敗している対象の確認の一覧・登録・取り消し
+//   - GET: 有効な確認を対象のURLごとに返す
+//   - POST: target（対象の識別子またはURL）を確認済みにする（note=メモ、expires=期限の期間またはRFC3339の日時）
+//   - DELETE: target の確認を取り消す
+func (s *Server) handleAPIAcks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"acknowledgments": ack.Active(time.Now()),
+		})
+	case http.MethodPost:
+		s.acknowledge(w, r)
+	case http.MethodDelete:
+		s.unacknowledge(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// ackTargetURL 確認する対象のURL（設定済みの対象の識別子の場合はそのURL、それ以外は指定したURL）
+func (s *Server) ackTargetURL(r *http.Request) string {
+	id := strings.TrimSpace(r.FormValue("target"))
+	if t, ok := s.currentConfig().FindTarget(id); ok {
+		return t.URL
+	}
+	return id
+}
+
+// acknowledge 対象を確認済みにする
+func (s *Server) acknowledge(w http.ResponseWriter, r *http.Request) {
+	targetURL := s.ackTargetURL(r)
+	if targetURL == "" {
+		http.Error(w, "対象が指定されていません", http.StatusBadRequest)
+		return
+	}
+	auditAction(r, "acknowledge", []string{targetURL}, formOptions(r, "note", "expires"))
+
+	now := time.Now().UTC()
+	a := checker.Acknowledgment{
+		Note:      strings.TrimSpace(r.FormValue("note")),
+		By:        runInitiator(r),
+		Timestamp: now,
+	}
+	if v := strings.TrimSpace(r.FormValue("expires")); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			a.Expires = now.Add(d)
+		} else if t, err := time.Parse(time.RFC3339, v); err == nil && t.After(now) {
+			a.Expires = t
+		} else {
+			http.Error(w, "expiresには正の期間（例: 2h）または未来のRFC3339の日時を指定してください", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := ack.Acknowledge(targetURL, a); err != nil {
+		slog.ErrorContext(r.Context(), "failed to save acknowledgment", "url", targetURL, "error", err)
+		http.Error(w, "確認の保存に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"url":            targetURL,
+		"acknowledgment": a,
+	})
+}
+
+// unacknowledge 対象の確認を取り消す
+func (s *Server) unacknowledge(w http.ResponseWriter, r *http.Request) {
+	targetURL := s.ackTargetURL(r)
+	if targetURL == "" {
+		http.Error(w, "対象が指定されていません", http.StatusBadRequest)
+		return
+	}
+	auditAction(r, "unacknowledge", []string{targetURL}, nil)
+
+	cleared, err := ack.Clear(targetURL)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to save acknowledgment", "url", targetURL, "error", err)
+		http.Error(w, "確認の保存に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	if !cleared {
+		http.Error(w, "指定した対象は確認済みではありません", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,125 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcheck/internal/agent"
+	"healthcheck/internal/audit"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// maxReportSize エージェントから受け付ける結果の最大サイズ
+const maxReportSize = 32 << 20
+
+const (
+	// agentClockSkew エージェントの時計が進んでいる場合に許容するずれ
+	agentClockSkew = time.Minute
+	// maxAgentReportAge エージェントの結果の日時として受け付ける最も古い時間（エージェントはチェックの完了後すぐに送信する）
+	maxAgentReportAge = time.Hour
+)
+
+// handleAPIAgentResults エージェントから送信された結果を地域ごとに履歴へ保存
+// 地域はトークンから判定し、送信された地域名は使わない
+func (s *Server) handleAPIAgentResults(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	auditAction(r, "agent_results", nil, nil)
+	region, ok := s.agentRegion(r)
+	if !ok {
+		http.Error(w, "認証に失敗しました", http.StatusUnauthorized)
+		return
+	}
+
+	var report agent.Report
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxReportSize)).Decode(&report); err != nil {
+		http.Error(w, "結果の形式が不正です", http.StatusBadRequest)
+		return
+	}
+	if len(report.Results) == 0 {
+		http.Error(w, "結果が含まれていません", http.StatusBadRequest)
+		return
+	}
+	for _, result := range report.Results {
+		if result == nil {
+			http.Error(w, "結果の形式が不正です", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if e := audit.FromContext(r.Context()); e != nil {
+		e.User = region
+	}
+	for _, result := range report.Results {
+		result.Region = region
+	}
+	// 日時は履歴のファイル名と更新日時に使うため、未指定の場合やサーバーの時刻と大きくずれている場合はサーバーの時刻にする
+	now := time.Now().UTC()
+	if report.Timestamp.IsZero() || report.Timestamp.After(now.Add(agentClockSkew)) || report.Timestamp.Before(now.Add(-maxAgentReportAge)) {
+		report.Timestamp = now
+	}
+
+	path, err := storage.SaveHistoryEntry(&storage.HistoryEntry{
+		Timestamp:  report.Timestamp,
+		RunID:      report.RunID,
+		Region:     region,
+		Results:    report.Results,
+		Statistics: stats.CalculateStatistics(report.Results, 0),
+	})
+	if err != nil {
+		http.Error(w, "結果の保存に失敗しました", http.StatusInternalServerError)
+		return
+	}
+	auditResult(r, report.RunID, map[string]interface{}{"results": len(report.Results)})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"region":      region,
+		"saved":       len(report.Results),
+		"historyPath": path,
+	})
+}
+
+// agentRegion Authorizationヘッダーのトークンに対応するエージェントの地域名
+func (s *Server) agentRegion(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+	for region, expected := range s.currentConfig().Agents {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+			return region, true
+		}
+	}
+	return "", false
+}
+
+// handleAPIRegions 対象ごとに地域別の最新の結果を返す（全地域で失敗しているか一部のみかを含む）
+func (s *Server) handleAPIRegions(w http.ResponseWriter, r *http.Request) {
+	results, err := storage.LoadHistoryResults(storage.ResultsDir)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	var statuses []*stats.RegionStatus
+	for _, status := range stats.LatestByRegion(results) {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].URL < statuses[j].URL
+	})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"targets": statuses,
+	})
+}
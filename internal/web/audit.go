@@ -0,0 +1,194 @@
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"healthcheck/internal/audit"
+	"healthcheck/internal/logging"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// withAccessLog すべてのリクエストをアクセスログに出力し、
+// ハンドラーが操作内容を記録したAPI呼び出しは監査記録として保存する
+func (s *Server) withAccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		entry := &audit.Entry{
+			Timestamp:    start,
+			RequestID:    logging.RequestID(r.Context()),
+			Remote:       remoteHost(r),
+			ForwardedFor: r.Header.Get("X-Forwarded-For"),
+			Method:       r.Method,
+			Path:         r.URL.Path,
+		}
+		if user, _, ok := r.BasicAuth(); ok {
+			entry.User = user
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		ctx := audit.WithEntry(r.Context(), entry)
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		entry.Status = rec.status
+		entry.Duration = time.Since(start)
+		level := slog.LevelInfo
+		if probePaths[r.URL.Path] {
+			level = slog.LevelDebug
+		}
+		slog.Log(r.Context(), level, "request", "method", r.Method, "path", r.URL.Path, "status", rec.status,
+			"bytes", rec.bytes, "duration", entry.Duration, "remote", entry.Remote, "user_agent", r.UserAgent())
+
+		if entry.Action == "" || s.audit == nil {
+			return
+		}
+		if err := s.audit.Append(entry); err != nil {
+			slog.WarnContext(r.Context(), "failed to write audit log", "error", err)
+		}
+	})
+}
+
+// remoteHost 接続元のアドレス（ポートを除く）
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// statusRecorder 応答のステータスコードとサイズを記録するResponseWriter
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+// WriteHeader ステータスコードを記録して送信
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write 送信したサイズを記録
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Flush 元のResponseWriterがFlushに対応している場合は送信
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack WebSocketの接続のため元の接続を引き渡す
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijack not supported")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+// auditAction API呼び出しの操作内容を監査記録に設定
+func auditAction(r *http.Request, action string, targets []string, options map[string]string) {
+	if e := audit.FromContext(r.Context()); e != nil {
+		e.Action = action
+		e.Targets = targets
+		e.Options = options
+	}
+}
+
+// auditResult API呼び出しの結果の概要を監査記録に設定
+func auditResult(r *http.Request, runID string, summary map[string]interface{}) {
+	if e := audit.FromContext(r.Context()); e != nil {
+		e.RunID = runID
+		e.Summary = summary
+	}
+}
+
+// formOptions 指定したフォームの値のうち空でないもの
+func formOptions(r *http.Request, names ...string) map[string]string {
+	options := make(map[string]string)
+	for _, name := range names {
+		if v := r.FormValue(name); v != "" {
+			options[name] = v
+		}
+	}
+	return options
+}
+
+// handleAPIAudit 監査記録を新しい順にJSON形式で返す
+// since/untilはRFC3339の日時または現在からの期間（例: 24h）で指定する
+func (s *Server) handleAPIAudit(w http.ResponseWriter, r *http.Request) {
+	if s.audit == nil {
+		http.Error(w, "監査記録は無効になっています", http.StatusNotFound)
+		return
+	}
+
+	params := r.URL.Query()
+	q := audit.Query{
+		User:   params.Get("user"),
+		Action: params.Get("action"),
+		Target: params.Get("target"),
+		Limit:  defaultAuditLimit,
+	}
+	var err error
+	if q.Since, err = parseAuditTime(params.Get("since")); err != nil {
+		http.Error(w, "sinceにはRFC3339の日時または期間（例: 24h）を指定してください", http.StatusBadRequest)
+		return
+	}
+	if q.Until, err = parseAuditTime(params.Get("until")); err != nil {
+		http.Error(w, "untilにはRFC3339の日時または期間（例: 24h）を指定してください", http.StatusBadRequest)
+		return
+	}
+	if v := params.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxAuditLimit {
+			http.Error(w, "limitには1〜1000の整数を指定してください", http.StatusBadRequest)
+			return
+		}
+		q.Limit = n
+	}
+
+	entries, err := s.audit.Query(q)
+	if err != nil {
+		http.Error(w, "監査記録の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"entries": entries,
+	})
+}
+
+// parseAuditTime RFC3339の日時または現在からの期間を日時に変換（空の場合はゼロ値）
+func parseAuditTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
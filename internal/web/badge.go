@@ -0,0 +1,111 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// handleBadge 対象の最新の稼働率またはレイテンシをSVGバッジで返す
+// /badge/{対象名またはURLエンコードしたURL}?metric=uptime|latency&window=24h|7d|30d
+func (s *Server) handleBadge(w http.ResponseWriter, r *http.Request) {
+	name, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), "/badge/"))
+	if err != nil || name == "" {
+		http.Error(w, "対象が指定されていません", http.StatusBadRequest)
+		return
+	}
+	targetURL := s.resolveTargetURL(name)
+
+	metric := r.URL.Query().Get("metric")
+	if metric == "" {
+		metric = "uptime"
+	}
+	window := slaWindowParam(r)
+
+	results, err := storage.LoadHistoryResults(storage.ResultsDir)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	var svg string
+	switch metric {
+	case "uptime":
+		svg = uptimeBadge(results, targetURL, window, s.currentConfig().SLOTarget)
+	case "latency":
+		svg = latencyBadge(results, targetURL)
+	default:
+		http.Error(w, "metricにはuptimeまたはlatencyを指定してください", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache, max-age=0")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, svg)
+}
+
+// resolveTargetURL 対象名を設定された対象のURLに変換（一致しない場合はURLとして扱う）
+func (s *Server) resolveTargetURL(name string) string {
+	for _, t := range s.currentConfig().AllTargets() {
+		if t.Name == name || t.URL == name {
+			return t.URL
+		}
+	}
+	return name
+}
+
+// uptimeBadge 指定期間の稼働率のバッジ
+func uptimeBadge(results []*checker.CheckResult, targetURL, window string, slo float64) string {
+	label := "uptime " + window
+	for _, sla := range stats.CalculateSLA(results, stats.SLAWindows[window], slo, time.Now()) {
+		if sla.URL != targetURL {
+			continue
+		}
+		color := dashboard.BadgeRed
+		switch {
+		case sla.UptimePercent >= slo:
+			color = dashboard.BadgeGreen
+		case sla.UptimePercent >= 99:
+			color = dashboard.BadgeYellowGreen
+		case sla.UptimePercent >= 95:
+			color = dashboard.BadgeYellow
+		}
+		return dashboard.GenerateBadge(label, fmt.Sprintf("%.2f%%", sla.UptimePercent), color)
+	}
+	return dashboard.GenerateBadge(label, "no data", dashboard.BadgeGrey)
+}
+
+// latencyBadge 最新のチェック結果の応答時間のバッジ
+func latencyBadge(results []*checker.CheckResult, targetURL string) string {
+	var latest *checker.CheckResult
+	for _, r := range results {
+		if r.URL == targetURL && (latest == nil || r.Timestamp.After(latest.Timestamp)) {
+			latest = r
+		}
+	}
+
+	switch {
+	case latest == nil:
+		return dashboard.GenerateBadge("latency", "no data", dashboard.BadgeGrey)
+	case !latest.Success:
+		return dashboard.GenerateBadge("latency", "down", dashboard.BadgeRed)
+	}
+
+	ms := latest.ResponseTimeMs()
+	color := dashboard.BadgeGreen
+	switch {
+	case latest.Degraded || ms >= 1000:
+		color = dashboard.BadgeYellow
+	case ms >= 300:
+		color = dashboard.BadgeYellowGreen
+	}
+	return dashboard.GenerateBadge("latency", fmt.Sprintf("%.0fms", ms), color)
+}
@@ -0,0 +1,98 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/urllist"
+)
+
+const (
+	defaultBenchmarkCount = 10
+	maxBenchmarkCount     = 1000  // 1URLあたりの最大回数
+	maxBenchmarkTotal     = 10000 // 1回のベンチマークでの最大リクエスト数
+)
+
+// handleBenchmark ベンチマークページ表示
+func (s *Server) handleBenchmark(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, dashboard.GenerateBenchmark(defaultBenchmarkCount, maxBenchmarkCount, s.currentConfig().Concurrency, language(r), theme(r)))
+}
+
+// handleAPIBenchmark 各URLを指定回数ずつチェックし、URLごとの応答時間の分布をJSON形式で返す
+// 結果は負荷試験によるものなので履歴には保存しない
+func (s *Server) handleAPIBenchmark(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	urls, rejected := urllist.Parse(r.FormValue("urls"))
+	auditAction(r, "benchmark", urls, formOptions(r, "count", "rate", "concurrency"))
+	if len(urls) == 0 {
+		writeRejected(w, rejected)
+		return
+	}
+
+	count := defaultBenchmarkCount
+	if v := r.FormValue("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxBenchmarkCount {
+			http.Error(w, fmt.Sprintf("countには1〜%dの整数を指定してください", maxBenchmarkCount), http.StatusBadRequest)
+			return
+		}
+		count = n
+	}
+	var rate float64
+	if v := r.FormValue("rate"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			http.Error(w, "rateには0以上の数値を指定してください", http.StatusBadRequest)
+			return
+		}
+		rate = f
+	}
+
+	if !s.acquireRun(w) {
+		return
+	}
+	defer s.releaseRun()
+
+	ctx, span := startRun(r, "benchmark")
+	defer finishRun(span)
+
+	concurrency, _ := strconv.Atoi(r.FormValue("concurrency"))
+	bench := checker.NewBenchmarkChecker(s.currentConfig(), concurrency)
+
+	// sitemap:/robots:の対象を個別のページに展開
+	urls = bench.ExpandURLs(ctx, urls)
+	if len(urls)*count > maxBenchmarkTotal {
+		http.Error(w, fmt.Sprintf("リクエスト数の合計が上限（%d）を超えています", maxBenchmarkTotal), http.StatusBadRequest)
+		return
+	}
+
+	startTime := time.Now()
+	results := bench.Benchmark(ctx, urls, count, rate)
+	totalDuration := time.Since(startTime)
+	auditResult(r, span.TraceID, map[string]interface{}{"requests": len(results), "duration_ms": totalDuration})
+
+	response := map[string]interface{}{
+		"run_id":            span.TraceID,
+		"count":             count,
+		"rate":              rate,
+		"total":             len(results),
+		"total_duration_ms": totalDuration,
+		"distributions":     stats.CalculateDistributions(results, totalDuration),
+		"rejected":          rejected,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(response)
+}
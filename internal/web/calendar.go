@@ -0,0 +1,130 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/i18n"
+	"healthcheck/internal/incident"
+	"healthcheck/internal/storage"
+)
+
+// handleCalendar カレンダー表示
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	from, to := calendarRange(r)
+	events := s.calendarEvents(from, to, language(r))
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, dashboard.GenerateCalendar(events, from, to, language(r), theme(r)))
+}
+
+// handleAPICalendar カレンダーのイベントをJSON形式で返す
+func (s *Server) handleAPICalendar(w http.ResponseWriter, r *http.Request) {
+	from, to := calendarRange(r)
+	response := map[string]interface{}{
+		"from":   from,
+		"to":     to,
+		"events": s.calendarEvents(from, to, language(r)),
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(response)
+}
+
+// calendarRange 表示期間を取得（daysパラメータで前後の日数を指定、デフォルト7日）
+func calendarRange(r *http.Request) (time.Time, time.Time) {
+	days := 7
+	if d, err := strconv.Atoi(r.URL.Query().Get("days")); err == nil && d > 0 && d <= 90 {
+		days = d
+	}
+	now := i18n.In(time.Now())
+	return now.AddDate(0, 0, -days), now.AddDate(0, 0, days)
+}
+
+// calendarEvents 実行予定・メンテナンス期間・インシデントをイベントにまとめる（タイトルは指定した言語）
+func (s *Server) calendarEvents(from, to time.Time, lang string) []dashboard.CalendarEvent {
+	cfg := s.currentConfig()
+	var events []dashboard.CalendarEvent
+
+	// 実行予定は日ごとに1件にまとめる
+	if next := s.scheduler.NextRun(); !next.IsZero() {
+		interval := cfg.Interval
+		for next.Before(from) {
+			next = next.Add(interval)
+		}
+		for !next.After(to) {
+			dayEnd := truncateDay(next).AddDate(0, 0, 1)
+			if dayEnd.After(to) {
+				dayEnd = to
+			}
+			count := int((dayEnd.Sub(next)-1)/interval) + 1
+			last := next.Add(time.Duration(count-1) * interval)
+			events = append(events, dashboard.CalendarEvent{
+				Type:  "scheduled",
+				Title: i18n.T(lang, "calendar_scheduled", count, len(cfg.AllTargets()), interval),
+				Start: next,
+				End:   last,
+			})
+			next = last.Add(interval)
+		}
+	}
+
+	for _, m := range cfg.MaintenanceWindows {
+		if m.End.Before(from) || m.Start.After(to) {
+			continue
+		}
+		title := m.Name
+		if len(m.Targets) > 0 {
+			title = i18n.T(lang, "calendar_maintenance", m.Name, len(m.Targets))
+		}
+		events = append(events, dashboard.CalendarEvent{
+			Type:  "maintenance",
+			Title: title,
+			Start: m.Start,
+			End:   m.End,
+		})
+	}
+
+	entries, err := storage.LoadHistoryEntries(storage.ResultsDir)
+	if err == nil {
+		// 同時に発生したインシデントは1件のイベントにまとめる
+		correlated, single := incident.Correlate(incident.Detect(entries), cfg.CorrelationWindow, cfg.CorrelationMinTargets)
+		for _, c := range correlated {
+			title := i18n.T(lang, "calendar_correlated", c.Key, len(c.URLs))
+			events = appendIncidentEvent(events, title, c.Start, c.End, c.Ongoing, from, lang)
+		}
+		for _, inc := range single {
+			title := fmt.Sprintf("%s: %s", inc.URL, inc.Error)
+			events = appendIncidentEvent(events, title, inc.Start, inc.End, inc.Ongoing, from, lang)
+		}
+	}
+
+	return events
+}
+
+// appendIncidentEvent 表示期間内のインシデントをイベントとして追加
+func appendIncidentEvent(events []dashboard.CalendarEvent, title string, start, end time.Time, ongoing bool, from time.Time, lang string) []dashboard.CalendarEvent {
+	if ongoing {
+		end = time.Now()
+		title += i18n.T(lang, "calendar_ongoing")
+	}
+	if end.Before(from) {
+		return events
+	}
+	return append(events, dashboard.CalendarEvent{
+		Type:  "incident",
+		Title: title,
+		Start: start,
+		End:   end,
+	})
+}
+
+// truncateDay 時刻をその日の0時に切り捨てる
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,62 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// callbackTimeout 結果をコールバックのURLへ送信する期限
+const callbackTimeout = 10 * time.Second
+
+// validCallbackURL コールバックのURLがhttp(s)の絶対URLか
+func validCallbackURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// postCheckCallback /api/checkの実行の結果をcallback_urlにPOSTする（本文は同期的に実行した場合の応答と同じ）
+func (s *Server) postCheckCallback(ctx context.Context, options runOptions, run *inflightRun, response map[string]interface{}) error {
+	var payload bytes.Buffer
+	err := run.withResults(func(aggregator *stats.Aggregator) error {
+		return storage.WriteWithResults(&payload, response, aggregator)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return s.postCallback(ctx, options.callbackURL, options.callbackSecret, payload.Bytes())
+}
+
+// postCallback 結果のJSONをコールバックのURLにPOSTする
+// secretを指定した場合は、受信側で検証できるよう本文のHMAC-SHA256をX-Hub-Signature-256に付ける
+// 送信にはチェックの対象と同じくdenied_networksに接続しないクライアントを使い、リダイレクトはたどらない（3xxは失敗とする）
+func (s *Server) postCallback(ctx context.Context, callbackURL, secret string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, callbackTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(hookSignatureHeader, "sha256="+hookSignature(secret, payload))
+	}
+
+	resp, err := s.currentChecker().CallbackClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send callback: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,410 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/urllist"
+)
+
+// maxCheckRequestSize JSON形式のチェック要求の最大サイズ
+const maxCheckRequestSize = 10 << 20
+
+// checkMethods 対象のmethodに指定できるHTTPメソッド
+var checkMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// checkRequest /api/checkのJSON形式のリクエスト
+type checkRequest struct {
+	Targets  []checkTarget     `json:"targets"`
+	Options  checkOptions      `json:"options"`
+	Metadata map[string]string `json:"metadata"` // 履歴に保存する実行の情報（triggered_by・deploy_sha・environmentなど）
+
+	CallbackURL    string `json:"callback_url"`    // 指定した場合は応答を待たずに受け付け、完了後に結果をこのURLにPOSTする
+	CallbackSecret string `json:"callback_secret"` // 指定した場合はコールバックの本文にこのシークレットで署名する
+}
+
+// checkTarget JSON形式で指定するチェック対象（フォームでは指定できないリクエストの設定を含む）
+type checkTarget struct {
+	URL                     string            `json:"url"`
+	Name                    string            `json:"name"`
+	Method                  string            `json:"method"`
+	Headers                 map[string]string `json:"headers"`
+	Auth                    string            `json:"auth"`
+	Body                    string            `json:"body"`
+	ExpectedStatus          int               `json:"expected_status"`
+	Success                 string            `json:"success"`
+	Connection              string            `json:"connection"`
+	IPFamily                string            `json:"ip_family"`
+	Resolve                 string            `json:"resolve"`
+	Origin                  string            `json:"origin"`
+	SNIHosts                []string          `json:"sni_hosts"`
+	ExpectedLocation        string            `json:"expected_location"`
+	ExpectedLocationPattern string            `json:"expected_location_pattern"`
+	ExpectedContentType     string            `json:"expected_content_type"`
+	ExpectedCharset         string            `json:"expected_charset"`
+	ExpectedEncoding        []string          `json:"expected_encoding"`
+	ExpectedCache           string            `json:"expected_cache"`
+	WatchContent            bool              `json:"watch_content"`
+	ContentSelector         string            `json:"content_selector"`
+	ScanResources           bool              `json:"scan_resources"`
+	HTTP3                   bool              `json:"http3"`
+	Timeout                 string            `json:"timeout"`
+	HeaderTimeout           string            `json:"header_timeout"`
+	BodyTimeout             string            `json:"body_timeout"`
+	Tags                    map[string]string `json:"tags"`
+	Priority                string            `json:"priority"`
+	Template                string            `json:"template"`
+}
+
+// checkOptions JSON形式で指定する実行全体の設定（時間はtime.ParseDurationの形式）
+type checkOptions struct {
+	Concurrency int    `json:"concurrency"`
+	Timeout     string `json:"timeout"`
+	Retries     *int   `json:"retries"`
+	Duplicates  string `json:"duplicates"`
+	Coalesce    bool   `json:"coalesce"` // 同じ対象と設定のチェックが実行中の場合は、その実行に合流する
+}
+
+// runOptions 1回の実行で変更する設定（ゼロ値・nilの項目は変更しない）
+type runOptions struct {
+	concurrency int
+	timeout     time.Duration
+	retries     *int
+	duplicates  string            // 同じ対象が複数回指定された場合の扱い（空の場合は設定のまま）
+	metadata    map[string]string // 履歴に保存する実行の情報
+	coalesce    bool              // 同じ対象と設定のチェックが実行中の場合は、新たに実行せずその結果を返す
+
+	callbackURL    string // 結果をPOSTするURL（空の場合は応答で返す）
+	callbackSecret string // コールバックの本文の署名に使うシークレット
+}
+
+// fieldError 入力の検証エラー
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// isJSONRequest リクエストの本文がJSON形式か
+func isJSONRequest(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// decodeCheckRequest JSON形式のチェック要求を読み込んで検証し、対象と実行の設定に変換
+func (s *Server) decodeCheckRequest(w http.ResponseWriter, r *http.Request) ([]config.Target, runOptions, []fieldError) {
+	var req checkRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCheckRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return nil, runOptions{}, []fieldError{jsonFieldError(err)}
+	}
+
+	var errs []fieldError
+	addError := func(field, format string, args ...interface{}) {
+		errs = append(errs, fieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(req.Targets) == 0 {
+		addError("targets", "対象が指定されていません")
+	}
+	cfg := s.currentConfig()
+	targets := make([]config.Target, 0, len(req.Targets))
+	for i, t := range req.Targets {
+		field := fmt.Sprintf("targets[%d]", i)
+		target := config.Target{
+			Name:                    t.Name,
+			URL:                     strings.TrimSpace(t.URL),
+			Method:                  strings.ToUpper(t.Method),
+			Headers:                 t.Headers,
+			Auth:                    t.Auth,
+			Body:                    t.Body,
+			ExpectedStatus:          t.ExpectedStatus,
+			Success:                 t.Success,
+			Connection:              t.Connection,
+			IPFamily:                t.IPFamily,
+			Resolve:                 strings.TrimSpace(t.Resolve),
+			Origin:                  strings.TrimSpace(t.Origin),
+			SNIHosts:                t.SNIHosts,
+			ExpectedLocation:        t.ExpectedLocation,
+			ExpectedLocationPattern: t.ExpectedLocationPattern,
+			ExpectedContentType:     t.ExpectedContentType,
+			ExpectedCharset:         t.ExpectedCharset,
+			ExpectedEncoding:        t.ExpectedEncoding,
+			ExpectedCache:           t.ExpectedCache,
+			WatchContent:            t.WatchContent,
+			ContentSelector:         t.ContentSelector,
+			ScanResources:           t.ScanResources,
+			HTTP3:                   t.HTTP3,
+			Timeout:                 t.Timeout,
+			HeaderTimeout:           t.HeaderTimeout,
+			BodyTimeout:             t.BodyTimeout,
+			Tags:                    t.Tags,
+			Priority:                t.Priority,
+			Template:                t.Template,
+		}
+		if err := cfg.ApplyTemplate(&target); err != nil {
+			addError(field+".template", "templateに指定したひな形 %q がありません", t.Template)
+		}
+		if target.Name == "" {
+			target.Name = target.URL
+		}
+
+		if target.URL == "" {
+			addError(field+".url", "URLが指定されていません")
+		} else if normalized, err := urllist.Normalize(target.URL); err != nil {
+			addError(field+".url", "%s", err.Error())
+		} else {
+			target.URL = normalized
+		}
+		if target.Method != "" && !slices.Contains(checkMethods, target.Method) {
+			addError(field+".method", "methodには%sのいずれかを指定してください", strings.Join(checkMethods, "/"))
+		}
+		for name, value := range target.Headers {
+			if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+				addError(field+".headers", "ヘッダー名 %q が不正です", name)
+			}
+			if strings.ContainsAny(value, "\r\n") {
+				addError(field+".headers", "ヘッダー %q の値に改行を含めることはできません", name)
+			}
+		}
+		if target.Auth != "" && !slices.ContainsFunc(cfg.Auth, func(a config.AuthConfig) bool { return a.Name == target.Auth }) {
+			addError(field+".auth", "authに指定した認証の設定 %q がありません", target.Auth)
+		}
+		if target.ExpectedStatus != 0 && (target.ExpectedStatus < 100 || target.ExpectedStatus > 599) {
+			addError(field+".expected_status", "expected_statusには100〜599を指定してください")
+		}
+		if target.Connection != "" && target.Connection != "reuse" && target.Connection != "cold" {
+			addError(field+".connection", "connectionにはreuseまたはcoldを指定してください")
+		}
+		if target.IPFamily != "" && !slices.Contains(config.IPFamilies, target.IPFamily) {
+			addError(field+".ip_family", "ip_familyには%sのいずれかを指定してください", strings.Join(config.IPFamilies, "/"))
+		}
+		if target.Resolve != "" && config.ValidateResolve(target.Resolve) != nil {
+			addError(field+".resolve", "resolveにはIPアドレス、またはIPアドレスとポート（例: 192.0.2.10:443、[2001:db8::1]:443）を指定してください")
+		}
+		if target.Origin != "" && config.ValidateResolve(target.Origin) != nil {
+			addError(field+".origin", "originにはIPアドレス、またはIPアドレスとポート（例: 198.51.100.20:8443）を指定してください")
+		}
+		if err := config.ValidateSNIHosts(target.SNIHosts, target.IPFamily); err != nil {
+			addError(field+".sni_hosts", "sni_hostsにはポートを含まないホスト名を指定してください（ip_familyのdual・eachとは併用できません）")
+		}
+		if target.ExpectedContentType != "" {
+			if _, _, err := mime.ParseMediaType(target.ExpectedContentType); err != nil {
+				addError(field+".expected_content_type", "expected_content_typeにはメディアタイプ（例: application/json）を指定してください")
+			}
+		}
+		if target.ExpectedCache != "" && !slices.Contains(config.CacheExpectations, target.ExpectedCache) {
+			addError(field+".expected_cache", "expected_cacheには%sのいずれかを指定してください", strings.Join(config.CacheExpectations, "/"))
+		}
+		if target.ExpectedLocationPattern != "" {
+			if _, err := regexp.Compile(target.ExpectedLocationPattern); err != nil {
+				addError(field+".expected_location_pattern", "expected_location_patternの正規表現が不正です: %v", err)
+			}
+		}
+		if target.ContentSelector != "" {
+			if _, err := checker.CompileSelector(target.ContentSelector); err != nil {
+				addError(field+".content_selector", "content_selectorが不正です: %v", err)
+			}
+		}
+		if target.Success != "" {
+			if _, err := checker.CompileRule(target.Success); err != nil {
+				addError(field+".success", "成功条件の式が不正です: %v", err)
+			}
+		}
+		if target.Timeout != "" {
+			if d, err := time.ParseDuration(target.Timeout); err != nil || d <= 0 {
+				addError(field+".timeout", "timeoutには正の期間（例: 5s）を指定してください")
+			}
+		}
+		for _, rt := range [][2]string{{"header_timeout", target.HeaderTimeout}, {"body_timeout", target.BodyTimeout}} {
+			if rt[1] == "" {
+				continue
+			}
+			if d, err := time.ParseDuration(rt[1]); err != nil || d <= 0 {
+				addError(field+"."+rt[0], "%sには正の期間（例: 5s）を指定してください", rt[0])
+			}
+		}
+		if target.Priority != "" && !slices.Contains(config.Priorities, target.Priority) {
+			addError(field+".priority", "priorityには%sのいずれかを指定してください", strings.Join(config.Priorities, "/"))
+		}
+		for key := range target.Tags {
+			if key == "" {
+				addError(field+".tags", "タグのキーが空です")
+			}
+		}
+		targets = append(targets, target)
+	}
+
+	var options runOptions
+	if req.Options.Concurrency < 0 {
+		addError("options.concurrency", "concurrencyには1以上の整数を指定してください")
+	}
+	options.concurrency = req.Options.Concurrency
+	if req.Options.Timeout != "" {
+		d, err := time.ParseDuration(req.Options.Timeout)
+		if err != nil || d <= 0 {
+			addError("options.timeout", "timeoutには正の期間（例: 30s）を指定してください")
+		}
+		options.timeout = d
+	}
+	if req.Options.Retries != nil && *req.Options.Retries < 0 {
+		addError("options.retries", "retriesには0以上の整数を指定してください")
+	}
+	options.retries = req.Options.Retries
+	if req.Options.Duplicates != "" && !slices.Contains(config.DuplicateModes, req.Options.Duplicates) {
+		addError("options.duplicates", "duplicatesには%sのいずれかを指定してください", strings.Join(config.DuplicateModes, "/"))
+	}
+	options.duplicates = req.Options.Duplicates
+	options.coalesce = req.Options.Coalesce
+	if err := config.ValidateMetadata(req.Metadata); err != nil {
+		addError("metadata", "metadataが不正です: %v", err)
+	}
+	options.metadata = req.Metadata
+	if req.CallbackURL != "" && !validCallbackURL(req.CallbackURL) {
+		addError("callback_url", "callback_urlにはhttpまたはhttpsのURLを指定してください")
+	}
+	if req.CallbackSecret != "" && req.CallbackURL == "" {
+		addError("callback_secret", "callback_secretはcallback_urlと一緒に指定してください")
+	}
+	options.callbackURL = req.CallbackURL
+	options.callbackSecret = req.CallbackSecret
+
+	return targets, options, errs
+}
+
+// jsonFieldError JSONの読み込みエラーを利用者向けの検証エラーに変換
+func jsonFieldError(err error) fieldError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fieldError{Message: fmt.Sprintf("JSONの形式が不正です（%d文字目）", syntaxErr.Offset)}
+	case errors.As(err, &typeErr):
+		return fieldError{Field: typeErr.Field, Message: fmt.Sprintf("型が不正です（%sを指定してください）", typeErr.Type)}
+	case errors.As(err, &maxBytesErr):
+		return fieldError{Message: fmt.Sprintf("リクエストが大きすぎます（最大%dバイト）", maxBytesErr.Limit)}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return fieldError{Message: fmt.Sprintf("不明な項目 %s が指定されています", strings.TrimPrefix(err.Error(), "json: unknown field "))}
+	}
+	return fieldError{Message: "JSONの読み込みに失敗しました"}
+}
+
+// writeRejected 有効なURLがない場合に、受け付けなかった行を検証エラーとして返す
+func writeRejected(w http.ResponseWriter, rejected []urllist.Rejected) {
+	if len(rejected) == 0 {
+		http.Error(w, "URLが指定されていません", http.StatusBadRequest)
+		return
+	}
+	errs := make([]fieldError, len(rejected))
+	for i, r := range rejected {
+		errs[i] = fieldError{Field: fmt.Sprintf("urls[%d]", r.Line), Message: fmt.Sprintf("%d行目 %s: %s", r.Line, r.Text, r.Reason)}
+	}
+	writeFieldErrors(w, errs)
+}
+
+// noURLsMessage 有効なURLがない場合のメッセージ（受け付けなかった行とその理由を含む）
+func noURLsMessage(rejected []urllist.Rejected) string {
+	if len(rejected) == 0 {
+		return "URLが指定されていません"
+	}
+	lines := []string{"有効なURLがありません"}
+	for _, r := range rejected {
+		lines = append(lines, fmt.Sprintf("%d行目 %s: %s", r.Line, r.Text, r.Reason))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeFieldErrors 検証エラーをJSON形式で返す
+func writeFieldErrors(w http.ResponseWriter, errs []fieldError) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":  "リクエストの内容が不正です",
+		"errors": errs,
+	})
+}
+
+// formRunOptions フォームのconcurrency・timeout（秒）・retries・coalesce・callback_urlから実行の設定を取得
+// 数値として解釈できない値は無視する
+func formRunOptions(r *http.Request) runOptions {
+	var options runOptions
+	if c, err := strconv.Atoi(r.FormValue("concurrency")); err == nil && c > 0 {
+		options.concurrency = c
+	}
+	if t, err := strconv.Atoi(r.FormValue("timeout")); err == nil && t > 0 {
+		options.timeout = time.Duration(t) * time.Second
+	}
+	if n, err := strconv.Atoi(r.FormValue("retries")); err == nil && n >= 0 {
+		options.retries = &n
+	}
+	options.coalesce, _ = strconv.ParseBool(r.FormValue("coalesce"))
+	options.callbackURL = strings.TrimSpace(r.FormValue("callback_url"))
+	options.callbackSecret = r.FormValue("callback_secret")
+	return options
+}
+
+// formMetadata フォームのmeta（key=value、複数指定可）から実行のメタデータを取得
+func formMetadata(r *http.Request) (map[string]string, error) {
+	return config.ParseMetadata(r.Form["meta"])
+}
+
+// auditOptions 監査記録に残す形式の実行の設定
+func (o runOptions) auditOptions() map[string]string {
+	options := make(map[string]string)
+	if o.concurrency > 0 {
+		options["concurrency"] = strconv.Itoa(o.concurrency)
+	}
+	if o.timeout > 0 {
+		options["timeout"] = o.timeout.String()
+	}
+	if o.retries != nil {
+		options["retries"] = strconv.Itoa(*o.retries)
+	}
+	if o.duplicates != "" {
+		options["duplicates"] = o.duplicates
+	}
+	return options
+}
+
+// runChecker 実行の設定を反映した設定の複製と、その設定のチェッカーを作成
+// 実行中の設定と定期チェックの設定は変更しないため、他の実行・定期チェックには影響しない
+func (s *Server) runChecker(o runOptions) (*config.Config, *checker.Checker) {
+	cfg := s.currentConfig().Clone()
+	if o.concurrency > 0 {
+		cfg.Concurrency = o.concurrency
+	}
+	if o.timeout > 0 {
+		cfg.Timeout = o.timeout
+		cfg.MaxLatency = o.timeout
+	}
+	if o.retries != nil {
+		cfg.Retries = *o.retries
+	}
+	if o.duplicates != "" {
+		cfg.Duplicates = o.duplicates
+	}
+	return cfg, checker.NewChecker(cfg)
+}
+
+// targetURLs 対象のURLの一覧
+func targetURLs(targets []config.Target) []string {
+	urls := make([]string, len(targets))
+	for i, t := range targets {
+		urls[i] = t.URL
+	}
+	return urls
+}
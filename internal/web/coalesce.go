@@ -0,0 +1,180 @@
+package web
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+
+	"healthcheck/internal/config"
+	"healthcheck/internal/runs"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+	"healthcheck/internal/urllist"
+)
+
+// inflightRun 実行中の/api/checkの実行（同じ対象と設定の要求を合流させる）
+type inflightRun struct {
+	id      string
+	done    chan struct{} // 実行の完了で閉じる
+	outcome checkOutcome  // 実行の結果（doneを閉じた後に参照する）
+
+	mu   sync.Mutex // 結果の読み出しを直列にする（一時ファイルに書き出した場合はシークするため）
+	refs int        // 結果を使う要求の数（0になったら集計を閉じる）
+}
+
+// coalesceKey 合流の判定に使う対象と実行の設定のハッシュ
+// 対象は指定の順によらず同じになるよう、対象ごとのJSONを並べ替えてから連結する
+// メタデータとコールバックは実行の内容を変えないため含めない
+func coalesceKey(targets []config.Target, options runOptions) string {
+	encoded := make([]string, len(targets))
+	for i, t := range targets {
+		b, _ := json.Marshal(t)
+		encoded[i] = string(b)
+	}
+	slices.Sort(encoded)
+
+	h := sha256.New()
+	for _, e := range encoded {
+		io.WriteString(h, e)
+		io.WriteString(h, "\n")
+	}
+	json.NewEncoder(h).Encode(options.auditOptions())
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// joinRun 同じ対象と設定の実行中の実行があれば合流する（ない場合はnil）
+func (s *Server) joinRun(key string) *inflightRun {
+	s.inflightMutex.Lock()
+	defer s.inflightMutex.Unlock()
+	run, ok := s.inflight[key]
+	if !ok {
+		return nil
+	}
+	run.refs++
+	runs.Coalesce(run.id)
+	return run
+}
+
+// registerRun 実行を合流できるよう登録する（同じキーの実行がすでにある場合は登録しない）
+func (s *Server) registerRun(key, id string) *inflightRun {
+	run := &inflightRun{id: id, done: make(chan struct{}), refs: 1}
+	s.inflightMutex.Lock()
+	defer s.inflightMutex.Unlock()
+	if _, ok := s.inflight[key]; !ok {
+		s.inflight[key] = run
+	}
+	return run
+}
+
+// completeRun 実行の結果を合流した要求に渡し、以降の要求が合流しないよう登録を外す
+func (s *Server) completeRun(key string, run *inflightRun, outcome checkOutcome) {
+	s.inflightMutex.Lock()
+	if s.inflight[key] == run {
+		delete(s.inflight, key)
+	}
+	s.inflightMutex.Unlock()
+	run.outcome = outcome
+	close(run.done)
+}
+
+// release 結果を使い終えたことを記録し、最後の要求の場合は集計を閉じる
+func (run *inflightRun) release(s *Server) {
+	s.inflightMutex.Lock()
+	run.refs--
+	last := run.refs == 0
+	s.inflightMutex.Unlock()
+	if last {
+		run.outcome.aggregator.Close()
+	}
+}
+
+// withResults 実行の結果の集計を読み出す
+// 一時ファイルに書き出した結果はシークして読み出すため、複数の要求から同時に読み出さないよう直列にする
+func (run *inflightRun) withResults(fn func(aggregator *stats.Aggregator) error) error {
+	if run.outcome.aggregator.Spilled() {
+		run.mu.Lock()
+		defer run.mu.Unlock()
+	}
+	return fn(run.outcome.aggregator)
+}
+
+// writeResponse 実行の結果を応答として返す（streamの場合は進捗の最後のdoneとして送信する）
+func (run *inflightRun) writeResponse(w http.ResponseWriter, r *http.Request, response map[string]interface{}, stream bool) {
+	if stream {
+		run.withResults(func(aggregator *stats.Aggregator) error {
+			writeProgressDone(w, response, aggregator)
+			return nil
+		})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	err := run.withResults(func(aggregator *stats.Aggregator) error {
+		return storage.WriteWithResults(w, response, aggregator)
+	})
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to write response", "error", err)
+	}
+	io.WriteString(w, "\n")
+}
+
+// serveCoalesced 実行中の実行に合流した要求に、その実行の結果を返す
+// 応答は合流した実行のrun_idとcoalesced: trueを含み、rejectedはこの要求で受け付けなかった入力にする
+func (s *Server) serveCoalesced(w http.ResponseWriter, r *http.Request, run *inflightRun, options runOptions, rejected []urllist.Rejected) {
+	auditResult(r, run.id, map[string]interface{}{"coalesced": true})
+	if options.callbackURL != "" {
+		ctx := context.WithoutCancel(r.Context())
+		go func() {
+			<-run.done
+			defer run.release(s)
+			if err := s.postCheckCallback(ctx, options, run, coalescedResponse(run.outcome.response, rejected)); err != nil {
+				slog.WarnContext(ctx, "failed to post check callback", "callback_url", options.callbackURL, "error", err)
+			}
+		}()
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"run_id":    run.id,
+			"status":    "accepted",
+			"coalesced": true,
+			"metadata":  options.metadata,
+			"rejected":  rejected,
+		})
+		return
+	}
+
+	// 進捗の逐次送信を求められた場合は、合流した実行の進捗は送らず、完了時のdoneのみを送信する
+	stream := wantsProgress(r)
+	if stream {
+		w.Header().Set("Content-Type", progressContentType)
+		w.WriteHeader(http.StatusOK)
+		http.NewResponseController(w).Flush()
+	}
+	select {
+	case <-run.done:
+	case <-r.Context().Done():
+		// 結果を待たずに切断した場合も、実行の完了後に集計を閉じる
+		go func() {
+			<-run.done
+			run.release(s)
+		}()
+		return
+	}
+	defer run.release(s)
+	run.writeResponse(w, r, coalescedResponse(run.outcome.response, rejected), stream)
+}
+
+// coalescedResponse 合流した要求に返す応答の内容
+func coalescedResponse(response map[string]interface{}, rejected []urllist.Rejected) map[string]interface{} {
+	response = maps.Clone(response)
+	response["coalesced"] = true
+	response["rejected"] = rejected
+	return response
+}
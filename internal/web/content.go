@@ -0,0 +1,51 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"healthcheck/internal/checker"
+)
+
+// handleAPIContent 内容の変化の検出で保存している基準をJSON形式で返す
+func (s *Server) handleAPIContent(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"baselines": checker.ContentBaselines(),
+	})
+}
+
+// handleAPIContentAccept 検出した内容の変化を承認し、最新の内容を新しい基準にする（?url=対象のURL）
+func (s *Server) handleAPIContentAccept(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	targetURL := strings.TrimSpace(r.FormValue("url"))
+	if targetURL == "" {
+		http.Error(w, "URLが指定されていません", http.StatusBadRequest)
+		return
+	}
+	auditAction(r, "content_accept", []string{targetURL}, nil)
+
+	baseline, err := checker.AcceptContent(targetURL)
+	if errors.Is(err, checker.ErrNoContentBaseline) {
+		http.Error(w, "指定したURLの基準がありません", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to accept content", "url", targetURL, "error", err)
+		http.Error(w, "基準の保存に失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"url":      targetURL,
+		"baseline": baseline,
+	})
+}
@@ -0,0 +1,74 @@
+package web
+
+import (
+	"encoding/json"
+	"expvar"
+	"net"
+	"net/http"
+	_ "net/http/pprof" // /debug/pprof/ を登録する（公開するかはwithDebugEndpointsで判定）
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/runs"
+)
+
+// serverConns Webサーバーが受け付けて開いている接続の数
+var serverConns atomic.Int64
+
+// publishOnce 診断情報をexpvarに1回だけ登録する
+var publishOnce sync.Once
+
+// countConnState Webサーバーの接続の状態の変化から開いている接続の数を数える（http.ServerのConnState）
+func countConnState(_ net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		serverConns.Add(1)
+	case http.StateClosed, http.StateHijacked:
+		serverConns.Add(-1)
+	}
+}
+
+// publishDebugVars /debug/vars にゴルーチン数・接続数・レート制限器の数・実行中のチェック数を追加する
+// ヒープなどのメモリの統計はexpvarが標準で公開するmemstatsに含まれる
+func publishDebugVars() {
+	publishOnce.Do(func() {
+		expvar.Publish("healthcheck", expvar.Func(func() interface{} {
+			active, _ := runs.List()
+			return map[string]interface{}{
+				"goroutines": runtime.NumGoroutine(),
+				"open_connections": map[string]int64{
+					"checks": checker.OpenConnections(),
+					"server": serverConns.Load(),
+				},
+				"rate_limiters": checker.RateLimiters(),
+				"active_runs":   len(active),
+			}
+		}))
+	})
+}
+
+// withDebugEndpoints debug_endpointsが無効の場合は /debug/ 以下を見つからないものとして扱う
+// net/http/pprofとexpvarは読み込んだ時点でhttp.DefaultServeMuxに登録されるため、ここで公開するかを判定する
+func (s *Server) withDebugEndpoints(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.currentConfig().DebugEndpoints && strings.HasPrefix(r.URL.Path, "/debug/") {
+			http.NotFound(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleAPIDebugRateLimit 共有しているドメインごとのレート制限器の数を返す（デバッグ用）
+func (s *Server) handleAPIDebugRateLimit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(checker.RateLimiters())
+}
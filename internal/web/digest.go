@@ -0,0 +1,118 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"healthcheck/internal/config"
+	"healthcheck/internal/digest"
+	"healthcheck/internal/i18n"
+	"healthcheck/internal/notify"
+	"healthcheck/internal/storage"
+)
+
+// startDigests 稼働レポートを送信する時刻になったかを1分ごとに確認する
+// 起動前に予定されていたレポートは送信しない（再起動のたびに同じレポートを送信しないため）
+func (s *Server) startDigests() {
+	go func() {
+		lastSent := make(map[string]time.Time)
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			// 送信する時刻は表示するタイムゾーンの時刻とする
+			now := i18n.In(time.Now())
+			for _, d := range s.currentConfig().Digests {
+				due := d.LastDue(now)
+				last, ok := lastSent[d.Name]
+				lastSent[d.Name] = due
+				if !ok || !due.After(last) {
+					continue
+				}
+				if _, err := s.sendDigest(context.Background(), d, due); err != nil {
+					slog.Error("failed to send digest", "digest", d.Name, "error", err)
+				}
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// buildDigest 保存された履歴から、toまでの稼働レポートを作成
+func (s *Server) buildDigest(d config.DigestConfig, to time.Time) (*digest.Report, error) {
+	entries, err := storage.LoadHistoryEntries(storage.ResultsDir)
+	if err != nil {
+		return nil, err
+	}
+	report := digest.Build(entries, to, d.Window(), s.currentConfig().SLOTarget, d.Slowest)
+	report.Name = d.Name
+	report.Period = d.Period
+	return report, nil
+}
+
+// sendDigest 稼働レポートを作成して、設定された通知チャネルに送信
+func (s *Server) sendDigest(ctx context.Context, d config.DigestConfig, to time.Time) (*digest.Report, error) {
+	report, err := s.buildDigest(d, to)
+	if err != nil {
+		return nil, err
+	}
+	alert := notify.Alert{Kind: "digest", Timestamp: to, Digest: report}
+	slog.Info("sending digest", "digest", d.Name, "from", report.From, "to", report.To,
+		"targets", len(report.Targets), "incidents", len(report.Incidents))
+	return report, notify.Send(ctx, s.currentConfig().Notifiers, d.Notifiers, alert)
+}
+
+// findDigest 名前で稼働レポートの設定を探す
+func (s *Server) findDigest(name string) (config.DigestConfig, bool) {
+	for _, d := range s.currentConfig().Digests {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return config.DigestConfig{}, false
+}
+
+// handleAPIDigest 稼働レポートを返す（GET: 内容の確認、POST: すぐに送信）
+// /api/digest?name=daily（nameを省略した場合は最初のレポート）
+func (s *Server) handleAPIDigest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if digests := s.currentConfig().Digests; name == "" && len(digests) > 0 {
+		name = digests[0].Name
+	}
+	d, ok := s.findDigest(name)
+	if !ok {
+		http.Error(w, "稼働レポートが設定されていません: "+name, http.StatusNotFound)
+		return
+	}
+
+	var report *digest.Report
+	var err error
+	if r.Method == http.MethodPost {
+		auditAction(r, "digest", nil, map[string]string{"name": d.Name})
+		report, err = s.sendDigest(context.WithoutCancel(r.Context()), d, time.Now())
+		if report == nil {
+			http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+			return
+		}
+		if err != nil {
+			http.Error(w, "稼働レポートの送信に失敗しました: "+err.Error(), http.StatusBadGateway)
+			return
+		}
+	} else {
+		report, err = s.buildDigest(d, time.Now())
+		if err != nil {
+			http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,134 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// embedHistoryPoints ウィジェットのスパークラインに表示するチェック結果の数
+const embedHistoryPoints = 30
+
+// stateRank 対象の状態の悪さ（グループの状態は最も悪い対象の状態にする）
+var stateRank = map[string]int{"unknown": 0, "up": 1, "flapping": 2, "degraded": 3, "down": 4}
+
+// handleEmbed 対象またはグループの現在の状態と応答時間の推移を、iframeに埋め込むウィジェットで返す
+// /embed/{対象名・URLエンコードしたURL・グループ名}?format=json でウィジェットと同じ内容をJSON形式で返す
+func (s *Server) handleEmbed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), "/embed/"))
+	if err != nil || name == "" {
+		http.Error(w, "対象が指定されていません", http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "html" && format != "json" {
+		http.Error(w, "formatにはhtmlまたはjsonを指定してください", http.StatusBadRequest)
+		return
+	}
+
+	results, err := storage.LoadHistoryResults(storage.ResultsDir)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+	targets := s.embedTargets(name, results)
+	if len(targets) == 0 {
+		http.Error(w, fmt.Sprintf("対象またはグループ %q が見つかりません", name), http.StatusNotFound)
+		return
+	}
+	widget := buildEmbedWidget(name, targets, results)
+
+	w.Header().Set("Cache-Control", "no-cache, max-age=0")
+	if format == "json" {
+		// 独自のウィジェットから読み込めるよう、どのオリジンからも取得できるようにする
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(widget)
+		return
+	}
+	widget.Language = language(r)
+	widget.Theme = theme(r)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, dashboard.GenerateEmbed(widget))
+}
+
+// embedTargets ウィジェットに表示する対象（対象名・URLに一致する対象、またはグループ（service）に属する対象）
+// 設定されていないURLは履歴にある場合のみ表示する
+func (s *Server) embedTargets(name string, results []*checker.CheckResult) []config.Target {
+	all := s.currentConfig().AllTargets()
+	for _, t := range all {
+		if t.Name == name || t.URL == name {
+			return []config.Target{t}
+		}
+	}
+	var group []config.Target
+	for _, t := range all {
+		if t.Service == name {
+			group = append(group, t)
+		}
+	}
+	if len(group) > 0 {
+		return group
+	}
+	for _, r := range results {
+		if r.URL == name {
+			return []config.Target{{Name: name, URL: name}}
+		}
+	}
+	return nil
+}
+
+// buildEmbedWidget 履歴から対象ごとの最新の状態と直近のチェック結果をまとめる
+func buildEmbedWidget(name string, targets []config.Target, results []*checker.CheckResult) dashboard.EmbedWidget {
+	history := make(map[string][]*checker.CheckResult)
+	for _, r := range results {
+		history[r.URL] = append(history[r.URL], r)
+	}
+	regions := stats.LatestByRegion(results)
+
+	widget := dashboard.EmbedWidget{Name: name, State: "unknown", GeneratedAt: time.Now().UTC()}
+	for _, t := range targets {
+		recent := history[t.URL]
+		if len(recent) > embedHistoryPoints {
+			recent = recent[len(recent)-embedHistoryPoints:]
+		}
+		et := dashboard.EmbedTarget{Name: t.Name, State: "unknown", History: []dashboard.EmbedPoint{}}
+		if et.Name == "" {
+			et.Name = t.URL
+		}
+		for _, r := range recent {
+			et.History = append(et.History, dashboard.EmbedPoint{
+				Timestamp:      r.Timestamp,
+				ResponseTimeMs: r.ResponseTimeMs(),
+				Success:        r.Success,
+			})
+		}
+		if len(recent) > 0 {
+			latest := recent[len(recent)-1]
+			et.State = targetState(latest, regions[t.URL])
+			et.LastChecked = latest.Timestamp
+			if latest.Success {
+				et.ResponseTimeMs = latest.ResponseTimeMs()
+			}
+		}
+		if stateRank[et.State] > stateRank[widget.State] {
+			widget.State = et.State
+		}
+		widget.Targets = append(widget.Targets, et)
+	}
+	return widget
+}
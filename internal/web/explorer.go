@@ -0,0 +1,87 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// handleExplorer 結果エクスプローラー表示
+func (s *Server) handleExplorer(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	// 対象に付与されたタグも集計軸にする
+	dimensions := append([]string(nil), stats.Dimensions...)
+	for _, key := range config.TagKeys(s.currentConfig().AllTargets()) {
+		dimensions = append(dimensions, "tag:"+key)
+	}
+	fmt.Fprint(w, dashboard.GenerateExplorer(dimensions, language(r), theme(r)))
+}
+
+// handleAPIExplore 保存された結果を指定した軸で集計してJSON形式で返す
+func (s *Server) handleAPIExplore(w http.ResponseWriter, r *http.Request) {
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy == "" {
+		groupBy = "domain"
+	}
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = "all"
+	}
+	duration, ok := stats.SLAWindows[window]
+	if !ok && window != "all" {
+		http.Error(w, "windowにはall, 24h, 7d, 30dのいずれかを指定してください", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := storage.LoadHistoryEntries(storage.ResultsDir)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	filter, err := tagFilter(r)
+	if err != nil {
+		http.Error(w, "tagはkey=valueの形式で指定してください", http.StatusBadRequest)
+		return
+	}
+
+	since := time.Time{}
+	if window != "all" {
+		since = time.Now().Add(-duration)
+	}
+	var results []*checker.CheckResult
+	for _, entry := range entries {
+		for _, result := range entry.Results {
+			if result.Timestamp.Before(since) {
+				continue
+			}
+			results = append(results, result)
+		}
+	}
+	results = stats.FilterByTags(results, filter)
+
+	groups, err := stats.Aggregate(results, groupBy)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("group_byが不正です: %s", groupBy), http.StatusBadRequest)
+		return
+	}
+
+	response := map[string]interface{}{
+		"group_by": groupBy,
+		"window":   window,
+		"tags":     filter,
+		"total":    len(results),
+		"groups":   groups,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(response)
+}
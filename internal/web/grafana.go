@@ -0,0 +1,157 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// Grafanaの「JSON」データソース（simpod-json-datasource）互換のAPI
+// 系列名は「指標:URL」の形式（例: response_time:https://example.com）
+
+// grafanaQueryRequest /queryのリクエスト
+type grafanaQueryRequest struct {
+	Range struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	} `json:"range"`
+	MaxDataPoints int `json:"maxDataPoints"`
+	Targets       []struct {
+		Target string `json:"target"`
+		RefID  string `json:"refId"`
+		Hide   bool   `json:"hide"`
+	} `json:"targets"`
+}
+
+// grafanaSeries /queryのレスポンスの1系列（datapointsは[値, UNIXミリ秒]）
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+// handleGrafanaRoot データソースの接続テスト
+func (s *Server) handleGrafanaRoot(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/grafana/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleGrafanaSearch 選択できる系列名の一覧を返す
+func (s *Server) handleGrafanaSearch(w http.ResponseWriter, r *http.Request) {
+	results, err := storage.LoadHistoryResults(storage.ResultsDir)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	var names []string
+	for _, url := range s.grafanaURLs(results) {
+		for _, metric := range stats.SeriesMetrics {
+			names = append(names, metric+":"+url)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(names)
+}
+
+// handleGrafanaMetrics 選択できる系列を{label, value}の一覧で返す
+func (s *Server) handleGrafanaMetrics(w http.ResponseWriter, r *http.Request) {
+	results, err := storage.LoadHistoryResults(storage.ResultsDir)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	names := make(map[string]string)
+	for _, t := range s.currentConfig().AllTargets() {
+		names[t.URL] = t.Name
+	}
+
+	var metrics []map[string]string
+	for _, url := range s.grafanaURLs(results) {
+		label := url
+		if name, ok := names[url]; ok && name != url {
+			label = name + " (" + url + ")"
+		}
+		for _, metric := range stats.SeriesMetrics {
+			metrics = append(metrics, map[string]string{
+				"label": metric + ": " + label,
+				"value": metric + ":" + url,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(metrics)
+}
+
+// handleGrafanaQuery 指定された系列の期間内の時系列を返す
+func (s *Server) handleGrafanaQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req grafanaQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "リクエストの形式が不正です", http.StatusBadRequest)
+		return
+	}
+	if req.Range.To.IsZero() {
+		req.Range.To = time.Now()
+	}
+
+	results, err := storage.LoadHistoryResults(storage.ResultsDir)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	series := []grafanaSeries{}
+	for _, t := range req.Targets {
+		if t.Hide || t.Target == "" {
+			continue
+		}
+		metric, url, ok := strings.Cut(t.Target, ":")
+		if !ok {
+			http.Error(w, "系列名は「指標:URL」の形式で指定してください: "+t.Target, http.StatusBadRequest)
+			return
+		}
+
+		datapoints := [][2]float64{}
+		for _, p := range stats.Series(results, url, metric, req.Range.From, req.Range.To, req.MaxDataPoints) {
+			datapoints = append(datapoints, [2]float64{p.Value, float64(p.Timestamp.UnixMilli())})
+		}
+		series = append(series, grafanaSeries{Target: t.Target, Datapoints: datapoints})
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(series)
+}
+
+// grafanaURLs 設定された対象と履歴に含まれるURLの一覧
+func (s *Server) grafanaURLs(results []*checker.CheckResult) []string {
+	seen := make(map[string]bool)
+	for _, t := range s.currentConfig().AllTargets() {
+		seen[t.URL] = true
+	}
+	for _, r := range results {
+		seen[r.URL] = true
+	}
+
+	urls := make([]string, 0, len(seen))
+	for url := range seen {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	return urls
+}
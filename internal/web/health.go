@@ -0,0 +1,64 @@
+package web
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+
+	"healthcheck/internal/storage"
+)
+
+// probePaths コンテナのプローブ用のエンドポイント（アクセスログはDEBUGレベルで出力する）
+var probePaths = map[string]bool{"/healthz": true, "/readyz": true}
+
+// handleHealthz プロセスが応答できるかを返す（livenessProbe用）
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// handleReadyz 定期チェックと履歴の保存ができる状態かを返す（readinessProbe用）
+// いずれかを満たさない場合は503を返す
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	checks := make(map[string]string)
+	ready := true
+
+	// 一時停止は運用者の操作のため、準備ができていない扱いにはしない
+	checks["scheduler"] = s.workspace().schedulerState()
+	if checks["scheduler"] == "stopped" {
+		ready = false
+	}
+
+	if err := storage.CheckWritable(); err != nil {
+		checks["storage"] = err.Error()
+		ready = false
+	} else {
+		checks["storage"] = "writable"
+	}
+
+	status := "ready"
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if !ready {
+		status = "not_ready"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": status,
+		"checks": checks,
+	})
+}
+
+// displayURL 待ち受けアドレスからログに表示するURLを作成（すべてのアドレスの場合はlocalhost）
+func displayURL(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "http://" + addr
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return (&url.URL{Scheme: "http", Host: net.JoinHostPort(host, port)}).String()
+}
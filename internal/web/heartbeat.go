@@ -0,0 +1,35 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// handleHeartbeat 外部のジョブからのハートビートを受け付ける
+// /heartbeat/{トークン}（GET・POST・HEADのいずれでもよい）
+func (s *Server) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	token := strings.Trim(strings.TrimPrefix(r.URL.Path, "/heartbeat/"), "/")
+	if token == "" {
+		http.Error(w, "トークンが指定されていません", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := s.heartbeats.Ping(token); !ok {
+		http.Error(w, "不明なトークンです", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintln(w, "OK")
+}
+
+// handleAPIHeartbeats ハートビート対象の状態をJSON形式で返す（トークンは含めない）
+func (s *Server) handleAPIHeartbeats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"heartbeats": s.heartbeats.Statuses(),
+	})
+}
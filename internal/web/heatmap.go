@@ -0,0 +1,79 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// maxHeatmapBuckets ヒートマップの1行あたりの最大の時間枠の数
+const maxHeatmapBuckets = 1000
+
+// handleHeatmap レイテンシのヒートマップのページ表示
+func (s *Server) handleHeatmap(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, dashboard.GenerateHeatmap(language(r), theme(r)))
+}
+
+// handleAPIHeatmap 対象×時間枠のレイテンシと失敗率をJSON形式で返す
+// windowで期間（24h / 7d / 30d、デフォルト: 7d）、bucketで時間枠の幅（省略時は期間ごとの既定値）を指定する
+func (s *Server) handleAPIHeatmap(w http.ResponseWriter, r *http.Request) {
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = "7d"
+	}
+	duration, ok := stats.SLAWindows[window]
+	if !ok {
+		http.Error(w, "windowには24h, 7d, 30dのいずれかを指定してください", http.StatusBadRequest)
+		return
+	}
+	bucket := stats.HeatmapBuckets[window]
+	if raw := r.URL.Query().Get("bucket"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < time.Minute {
+			http.Error(w, "bucketには1m以上の期間（例: 30m, 1h）を指定してください", http.StatusBadRequest)
+			return
+		}
+		bucket = d
+	}
+	if duration/bucket > maxHeatmapBuckets {
+		http.Error(w, fmt.Sprintf("時間枠が多すぎます（最大%d）: bucketを大きくしてください", maxHeatmapBuckets), http.StatusBadRequest)
+		return
+	}
+
+	filter, err := tagFilter(r)
+	if err != nil {
+		http.Error(w, "tagはkey=valueの形式で指定してください", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := storage.LoadHistoryEntries(storage.ResultsDir)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+	var results []*checker.CheckResult
+	for _, entry := range entries {
+		results = append(results, entry.Results...)
+	}
+	results = stats.FilterByTags(results, filter)
+
+	now := time.Now()
+	heatmap := stats.CalculateHeatmap(results, now.Add(-duration), now, bucket)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"window":         window,
+		"bucket_seconds": int(bucket / time.Second),
+		"tags":           filter,
+		"times":          heatmap.Times,
+		"targets":        heatmap.Targets,
+	})
+}
@@ -0,0 +1,71 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"healthcheck/internal/config"
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/storage"
+)
+
+// handleHistory 実行の履歴の一覧を表示
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	runs, filter, err := historyRuns(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, dashboard.GenerateHistory(runs, filter, language(r), theme(r)))
+}
+
+// handleAPIHistory 実行の履歴の一覧をJSON形式で返す
+func (s *Server) handleAPIHistory(w http.ResponseWriter, r *http.Request) {
+	runs, _, err := historyRuns(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{"runs": runs})
+}
+
+// historyRuns 保存された実行を新しい順に返す
+// クエリパラメータのmeta（key=value、複数指定可）を指定した場合は、すべてのメタデータが一致する実行に絞り込む
+func historyRuns(r *http.Request) ([]dashboard.HistoryRun, map[string]string, error) {
+	filter, err := config.ParseMetadata(r.URL.Query()["meta"])
+	if err != nil {
+		return nil, nil, fmt.Errorf("metaはkey=valueの形式で指定してください")
+	}
+	entries, err := storage.LoadHistoryEntries(storage.ResultsDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("履歴の読み込みに失敗しました")
+	}
+
+	runs := []dashboard.HistoryRun{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if !config.MatchTags(e.Metadata, filter) {
+			continue
+		}
+		run := dashboard.HistoryRun{
+			Timestamp: e.Timestamp,
+			RunID:     e.RunID,
+			Region:    e.Region,
+			Metadata:  e.Metadata,
+		}
+		if e.Statistics != nil {
+			run.Total = e.Statistics.TotalRequests
+			run.Failures = e.Statistics.FailureCount
+			run.SuccessRate = e.Statistics.SuccessRate
+			run.AvgResponseTime = e.Statistics.AvgResponseTime
+		}
+		runs = append(runs, run)
+	}
+	return runs, filter, nil
+}
@@ -0,0 +1,192 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"healthcheck/internal/audit"
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/stats"
+)
+
+const (
+	// maxHookRequestSize フックの呼び出しで受け付ける本文の最大サイズ
+	maxHookRequestSize = 1 << 20
+	// hookSignatureHeader 本文のHMAC-SHA256の署名を付けるヘッダー（GitHubのWebhookと同じ形式）
+	hookSignatureHeader = "X-Hub-Signature-256"
+)
+
+// hookRequest フックの呼び出しの本文（省略可）
+type hookRequest struct {
+	Metadata map[string]string `json:"metadata"` // 履歴に保存する実行の情報（deploy_shaなど）
+}
+
+// hookResult フックで実行したチェックの結果（応答とコールバックの本文）
+type hookResult struct {
+	Hook        string                 `json:"hook"`
+	RunID       string                 `json:"run_id"`
+	Success     bool                   `json:"success"` // すべての対象が成功した
+	Metadata    map[string]string      `json:"metadata"`
+	Statistics  *stats.Statistics      `json:"statistics"`
+	Results     []*checker.CheckResult `json:"results"`
+	HistoryPath string                 `json:"historyPath"`
+}
+
+// handleAPIHook 設定ファイルのフックの対象をチェックする（/api/hooks/{name}）
+// CDのパイプラインからデプロイ後の確認に使う。シークレットはAuthorization: Bearer、
+// または本文のHMAC-SHA256をX-Hub-Signature-256に指定する
+// callback_urlを設定したフックは202ですぐに応答し、チェックの完了後に結果をPOSTする
+func (s *Server) handleAPIHook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.PathValue("name")
+	auditAction(r, "hook", nil, map[string]string{"hook": name})
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHookRequestSize))
+	if err != nil {
+		http.Error(w, "本文の読み込みに失敗しました", http.StatusBadRequest)
+		return
+	}
+	// 存在しないフックも認証の失敗として扱い、名前を推測できないようにする
+	hook, ok := s.currentConfig().FindHook(name)
+	if !ok || !verifyHook(r, hook.Secret, body) {
+		http.Error(w, "認証に失敗しました", http.StatusUnauthorized)
+		return
+	}
+	if e := audit.FromContext(r.Context()); e != nil {
+		e.User = "hook:" + name
+	}
+
+	var req hookRequest
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeFieldErrors(w, []fieldError{jsonFieldError(err)})
+			return
+		}
+	}
+	if err := config.ValidateMetadata(req.Metadata); err != nil {
+		writeFieldErrors(w, []fieldError{{Field: "metadata", Message: fmt.Sprintf("metadataが不正です: %v", err)}})
+		return
+	}
+	metadata := map[string]string{"triggered_by": "hook:" + name}
+	for k, v := range req.Metadata {
+		metadata[k] = v
+	}
+
+	targets := s.currentConfig().HookTargets(hook)
+	if len(targets) == 0 {
+		http.Error(w, "チェックする対象がありません", http.StatusConflict)
+		return
+	}
+	if e := audit.FromContext(r.Context()); e != nil {
+		e.Targets = targetURLs(targets)
+	}
+	if !s.checkURLLimit(w, targetURLs(targets)) || !s.acquireRun(w) {
+		return
+	}
+
+	ctx, span := startRun(r, "hook")
+	if hook.CallbackURL != "" {
+		// クライアントを待たせず、実行枠の解放とトレースの終了はチェックの完了後に行う
+		go func() {
+			defer s.releaseRun()
+			defer finishRun(span)
+			result := s.runHook(ctx, span.TraceID, name, targets, metadata)
+			if err := s.postHookCallback(ctx, hook, result); err != nil {
+				slog.WarnContext(ctx, "failed to post hook callback", "hook", name, "callback_url", hook.CallbackURL, "error", err)
+			}
+		}()
+		auditResult(r, span.TraceID, map[string]interface{}{"targets": len(targets), "callback": true})
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"hook":     name,
+			"run_id":   span.TraceID,
+			"status":   "accepted",
+			"metadata": metadata,
+		})
+		return
+	}
+	defer s.releaseRun()
+	defer finishRun(span)
+
+	result := s.runHook(ctx, span.TraceID, name, targets, metadata)
+	auditResult(r, span.TraceID, map[string]interface{}{"targets": result.Statistics.TotalRequests, "failures": result.Statistics.FailureCount})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(result)
+}
+
+// runHook フックの対象をチェックして履歴に保存
+func (s *Server) runHook(ctx context.Context, runID, name string, targets []config.Target, metadata map[string]string) *hookResult {
+	// sitemap:/robots:の対象を個別のページに展開
+	check := s.currentChecker()
+	targets = check.ExpandTargets(ctx, targets)
+	resultChan := make(chan *checker.CheckResult, len(targets))
+
+	startTime := time.Now()
+	go check.CheckTargets(ctx, targets, resultChan, nil)
+
+	var results []*checker.CheckResult
+	for result := range resultChan {
+		results = append(results, result)
+	}
+	totalDuration := time.Since(startTime)
+
+	s.markDegraded(results)
+	statistics := stats.CalculateStatistics(results, totalDuration)
+	slog.InfoContext(ctx, "check finished", "trigger", "hook", "hook", name, "targets", statistics.TotalRequests, "failures", statistics.FailureCount, "duration", totalDuration)
+
+	return &hookResult{
+		Hook:        name,
+		RunID:       runID,
+		Success:     statistics.FailureCount == 0,
+		Metadata:    metadata,
+		Statistics:  statistics,
+		Results:     results,
+		HistoryPath: saveHistory(ctx, runID, metadata, results, statistics),
+	}
+}
+
+// verifyHook Authorization: Bearerのシークレット、またはX-Hub-Signature-256の本文の署名を検証
+func verifyHook(r *http.Request, secret string, body []byte) bool {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+	if signature, ok := strings.CutPrefix(r.Header.Get(hookSignatureHeader), "sha256="); ok {
+		return hmac.Equal([]byte(signature), []byte(hookSignature(secret, body)))
+	}
+	return false
+}
+
+// hookSignature 本文のHMAC-SHA256（16進数）
+func hookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// postHookCallback 結果をフックのcallback_urlにPOSTする
+// 受信側で検証できるよう、フックのシークレットで本文に署名する
+func (s *Server) postHookCallback(ctx context.Context, hook config.Hook, result *hookResult) error {
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return s.postCallback(ctx, hook.CallbackURL, hook.Secret, payload)
+}
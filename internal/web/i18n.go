@@ -0,0 +1,64 @@
+package web
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/i18n"
+)
+
+// languageCookie 言語の切り替えで選択した言語を保存するクッキー
+const languageCookie = "healthcheck_lang"
+
+// languageKey リクエストのコンテキストに表示言語を保持するキー
+type languageKey struct{}
+
+// withLanguage リクエストの表示言語を決めてコンテキストに設定する
+// 優先順位: クエリパラメータのlang（クッキーに保存する）> クッキー > 設定ファイルのlanguage > Accept-Language > デフォルト言語
+func (s *Server) withLanguage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.Match(r.URL.Query().Get("lang"))
+		if lang != "" {
+			http.SetCookie(w, &http.Cookie{
+				Name:     languageCookie,
+				Value:    lang,
+				Path:     "/",
+				MaxAge:   365 * 24 * 60 * 60,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+		if c, err := r.Cookie(languageCookie); lang == "" && err == nil {
+			lang = i18n.Match(c.Value)
+		}
+		if lang == "" {
+			lang = s.currentConfig().Language
+		}
+		if lang == "" {
+			lang = i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+		}
+		if lang == "" {
+			lang = i18n.DefaultLanguage
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), languageKey{}, lang)))
+	})
+}
+
+// language リクエストの表示言語
+func language(r *http.Request) string {
+	if lang, ok := r.Context().Value(languageKey{}).(string); ok {
+		return lang
+	}
+	return i18n.DefaultLanguage
+}
+
+// pageFuncs ページのテンプレートで使う、リクエストの表示言語とテーマの関数
+func pageFuncs(r *http.Request) template.FuncMap {
+	return dashboard.PageFuncs(language(r), theme(r))
+}
+
+// tr リクエストの表示言語のメッセージ
+func tr(r *http.Request, key string, args ...interface{}) string {
+	return i18n.T(language(r), key, args...)
+}
@@ -0,0 +1,218 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"healthcheck/internal/audit"
+	"healthcheck/internal/config"
+	"healthcheck/internal/importer"
+	"healthcheck/internal/storage"
+	"healthcheck/internal/urllist"
+)
+
+// maxImportSize 取り込むファイルの最大サイズ
+const maxImportSize = 32 << 20
+
+// handleAPIImport HAR・curlコマンド・Postmanコレクションをトランザクションチェックとして登録
+// multipartのfileフィールド、またはリクエスト本文で受け取る（?format=har|curl|postman&name=）
+func (s *Server) handleAPIImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	auditAction(r, "import", nil, nil)
+	var data []byte
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxImportSize); err != nil {
+			http.Error(w, "ファイルの読み込みに失敗しました", http.StatusBadRequest)
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "ファイルが指定されていません", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		if data, err = io.ReadAll(io.LimitReader(file, maxImportSize)); err != nil {
+			http.Error(w, "ファイルの読み込みに失敗しました", http.StatusBadRequest)
+			return
+		}
+	} else {
+		var err error
+		if data, err = io.ReadAll(io.LimitReader(r.Body, maxImportSize)); err != nil {
+			http.Error(w, "リクエストの読み込みに失敗しました", http.StatusBadRequest)
+			return
+		}
+	}
+
+	format := r.FormValue("format")
+	if format != "" && !validImportFormat(format) {
+		http.Error(w, fmt.Sprintf("formatには%sのいずれかを指定してください", strings.Join(importer.Formats, "/")), http.StatusBadRequest)
+		return
+	}
+
+	transactions, err := importer.Import(data, format, r.FormValue("name"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("取り込みに失敗しました: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if e := audit.FromContext(r.Context()); e != nil {
+		e.Options = formOptions(r, "format", "name")
+		for _, tx := range transactions {
+			e.Targets = append(e.Targets, tx.Name)
+		}
+	}
+
+	// 定期チェックの対象になるよう定義を保存
+	var paths []string
+	for _, tx := range transactions {
+		path, err := storage.SaveTransaction(tx, "transactions")
+		if err != nil {
+			http.Error(w, "トランザクションの保存に失敗しました", http.StatusInternalServerError)
+			return
+		}
+		paths = append(paths, path)
+	}
+
+	auditResult(r, "", map[string]interface{}{"transactions": len(transactions)})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"transactions":     transactions,
+		"transactionPaths": paths,
+	})
+}
+
+// validImportFormat 対応している取り込み形式か
+func validImportFormat(format string) bool {
+	for _, f := range importer.Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// uploadedTargets フォームのtargetsにアップロードされた対象の一覧（CSV・TSV・xlsx）を対象に変換
+// ファイルがアップロードされていない場合はuploadedにfalseを返す
+func uploadedTargets(r *http.Request) (targets []config.Target, rejected []urllist.Rejected, uploaded bool, err error) {
+	file, header, err := r.FormFile("targets")
+	if err != nil {
+		return nil, nil, false, nil
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxImportSize))
+	if err != nil {
+		return nil, nil, true, err
+	}
+	targets, rejected, err = importer.ImportTargets(data, "", header.Filename)
+	return targets, rejected, true, err
+}
+
+// importedTargetsSource APIで取り込んだ対象の検出元の名前
+const importedTargetsSource = "api-import"
+
+// handleAPITargetsImport 対象の一覧を検証して定期チェックの対象に追加し、受け付けなかった行を理由とともに返す
+// multipartのfileフィールド、またはリクエスト本文で受け取る（?format=csv|tsv|xlsx|json、省略時はファイル名と内容から判定）
+// ?dry_run=trueの場合は検証のみ行い、対象は追加しない
+func (s *Server) handleAPITargetsImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	auditAction(r, "targets_import", nil, nil)
+	var data []byte
+	var filename string
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxImportSize); err != nil {
+			http.Error(w, "ファイルの読み込みに失敗しました", http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "ファイルが指定されていません", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		if data, err = io.ReadAll(io.LimitReader(file, maxImportSize)); err != nil {
+			http.Error(w, "ファイルの読み込みに失敗しました", http.StatusBadRequest)
+			return
+		}
+		filename = header.Filename
+	} else {
+		var err error
+		if data, err = io.ReadAll(io.LimitReader(r.Body, maxImportSize)); err != nil {
+			http.Error(w, "リクエストの読み込みに失敗しました", http.StatusBadRequest)
+			return
+		}
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			filename = "targets.json"
+		}
+	}
+
+	format := r.FormValue("format")
+	if format != "" && !slices.Contains(importer.TargetFormats, format) {
+		http.Error(w, fmt.Sprintf("formatには%sのいずれかを指定してください", strings.Join(importer.TargetFormats, "/")), http.StatusBadRequest)
+		return
+	}
+	dryRun := r.FormValue("dry_run") == "true"
+
+	entries, rejected, err := importer.ImportTargetEntries(data, format, filename)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("対象の一覧を読み込めませんでした: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// 設定ファイルの対象と同じく検証し、登録済みの対象と同じURLのものは受け付けない
+	cfg := s.currentConfig()
+	existing := make(map[string]bool)
+	for _, t := range cfg.AllTargets() {
+		existing[t.URL] = true
+	}
+	var targets []config.Target
+	for _, e := range entries {
+		target := e.Target
+		if err := cfg.ValidateTarget(&target); err != nil {
+			rejected = append(rejected, urllist.Rejected{Line: e.Line, Text: e.Text, Reason: err.Error()})
+			continue
+		}
+		if existing[target.URL] {
+			rejected = append(rejected, urllist.Rejected{Line: e.Line, Text: e.Text, Reason: "同じURLの対象がすでに登録されています"})
+			continue
+		}
+		existing[target.URL] = true
+		targets = append(targets, target)
+	}
+	slices.SortStableFunc(rejected, func(a, b urllist.Rejected) int { return a.Line - b.Line })
+
+	if !dryRun && len(targets) > 0 {
+		cfg.AddDiscoveredTargets(importedTargetsSource, targets)
+	}
+	if e := audit.FromContext(r.Context()); e != nil {
+		e.Options = formOptions(r, "format", "dry_run")
+		for _, t := range targets {
+			e.Targets = append(e.Targets, t.URL)
+		}
+	}
+	auditResult(r, "", map[string]interface{}{"created": len(targets), "rejected": len(rejected), "dry_run": dryRun})
+
+	status := http.StatusOK
+	if len(targets) == 0 && len(rejected) > 0 {
+		status = http.StatusBadRequest
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"created":  targets,
+		"rejected": rejected,
+		"dry_run":  dryRun,
+	})
+}
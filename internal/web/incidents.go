@@ -0,0 +1,33 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"healthcheck/internal/incident"
+	"healthcheck/internal/storage"
+)
+
+// handleAPIIncidents 履歴から検出したインシデントをJSON形式で返す
+// 同じドメインで同時に発生したインシデントはcorrelatedにまとめて返す
+func (s *Server) handleAPIIncidents(w http.ResponseWriter, r *http.Request) {
+	s.serveIncidents(w, storage.ResultsDir)
+}
+
+// serveIncidents 指定したディレクトリの履歴から検出したインシデントを返す
+func (s *Server) serveIncidents(w http.ResponseWriter, resultsDir string) {
+	entries, err := storage.LoadHistoryEntries(resultsDir)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	cfg := s.currentConfig()
+	correlated, single := incident.Correlate(incident.Detect(entries), cfg.CorrelationWindow, cfg.CorrelationMinTargets)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"correlated": correlated,
+		"incidents":  single,
+	})
+}
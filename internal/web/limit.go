@@ -0,0 +1,131 @@
+package web
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clientIdleTimeout この時間リクエストのない接続元のレート制限の状態を破棄する
+const clientIdleTimeout = 10 * time.Minute
+
+// clientLimiter 接続元ごとのリクエスト数を制限するトークンバケット
+type clientLimiter struct {
+	rate    float64 // 1秒あたりに補充するトークン数
+	burst   float64 // バケットの容量
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// bucket 1つの接続元のトークンバケット
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newClientLimiter 1分あたりperMinute回までのリクエストを許可するclientLimiterを作成
+// perMinuteが0以下の場合はnil（制限しない）を返す
+func newClientLimiter(perMinute int) *clientLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &clientLimiter{
+		rate:    float64(perMinute) / 60,
+		burst:   float64(perMinute),
+		buckets: make(map[string]*bucket),
+		swept:   time.Now(),
+	}
+}
+
+// allow 接続元のリクエストを許可するか（許可しない場合は次に許可されるまでの時間も返す）
+func (l *clientLimiter) allow(client string, now time.Time) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep しばらくリクエストのない接続元の状態を破棄（mutexを保持して呼び出す）
+func (l *clientLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < clientIdleTimeout {
+		return
+	}
+	for client, b := range l.buckets {
+		if now.Sub(b.last) >= clientIdleTimeout {
+			delete(l.buckets, client)
+		}
+	}
+	l.swept = now
+}
+
+// rateLimited 接続元ごとのレート制限の対象となるパスか
+// Prometheusのプローブやハートビートの受信など、外部から定期的に呼ばれるものは除く
+func rateLimited(path string) bool {
+	return path == "/check" || (strings.HasPrefix(path, "/api/") && !strings.HasPrefix(path, "/api/grafana/"))
+}
+
+// withRateLimit 接続元ごとのAPIリクエスト数を制限し、超えた場合は429を返す
+func (s *Server) withRateLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.clients != nil && rateLimited(r.URL.Path) {
+			if ok, wait := s.clients.allow(remoteHost(r), time.Now()); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				http.Error(w, "リクエストが多すぎます。しばらくしてから再度お試しください", http.StatusTooManyRequests)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acquireRun チェックの実行枠を確保（空きがない場合は429を返してfalse）
+// 確保できた場合は実行後にreleaseRunを呼び出す
+func (s *Server) acquireRun(w http.ResponseWriter) bool {
+	if s.runs == nil {
+		return true
+	}
+	select {
+	case s.runs <- struct{}{}:
+		return true
+	default:
+		w.Header().Set("Retry-After", "10")
+		http.Error(w, "実行中のチェックが多すぎます。しばらくしてから再度お試しください", http.StatusTooManyRequests)
+		return false
+	}
+}
+
+// releaseRun チェックの実行枠を解放
+func (s *Server) releaseRun() {
+	if s.runs != nil {
+		<-s.runs
+	}
+}
+
+// checkURLLimit 1回のチェックで受け付けるURL数の上限を超えていないか（超えている場合は400を返してfalse）
+func (s *Server) checkURLLimit(w http.ResponseWriter, urls []string) bool {
+	if limit := s.currentConfig().MaxRunURLs; limit > 0 && len(urls) > limit {
+		http.Error(w, fmt.Sprintf("URLの数が上限（%d）を超えています", limit), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
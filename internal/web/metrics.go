@@ -0,0 +1,48 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// handleMetrics 最新の実行の応答時間・レイテンシのヒストグラムをPrometheusのテキスト形式で返す
+// 区間はダッシュボード・API・コマンドラインと同じhistogram_buckets
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	entry, err := storage.LatestHistoryEntry(storage.ResultsDir)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	var b strings.Builder
+	if entry != nil {
+		responseTimes, latencies := stats.Histograms(entry.Statistics, entry.Results)
+		writeGauge(&b, "healthcheck_last_run_timestamp_seconds", "Unix time of the latest saved run", float64(entry.Timestamp.Unix()))
+		writeHistogram(&b, "healthcheck_response_time_seconds", "Response time of successful checks in the latest saved run", responseTimes)
+		writeHistogram(&b, "healthcheck_latency_seconds", "Latency of successful checks in the latest saved run", latencies)
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	fmt.Fprint(w, b.String())
+}
+
+// writeHistogram HELP/TYPE行付きでヒストグラムの_bucket・_sum・_countを書き出す
+func writeHistogram(b *strings.Builder, name, help string, h *stats.Histogram) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s histogram\n", name)
+	cumulative := h.Cumulative()
+	for i, bound := range h.Bounds {
+		fmt.Fprintf(b, "%s_bucket{le=%q} %d\n", name, formatFloat(bound.Seconds()), cumulative[i])
+	}
+	fmt.Fprintf(b, "%s_bucket{le=\"+Inf\"} %d\n", name, h.Count)
+	fmt.Fprintf(b, "%s_sum %s\n", name, formatFloat(h.Sum.Seconds()))
+	fmt.Fprintf(b, "%s_count %d\n", name, h.Count)
+}
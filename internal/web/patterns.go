@@ -0,0 +1,43 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// handlePatterns 時間帯別・曜日別の分析ページ表示
+func (s *Server) handlePatterns(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, dashboard.GeneratePatterns(language(r), theme(r)))
+}
+
+// handleAPIPatterns 対象ごとの時間帯別・曜日別のレイテンシと失敗率をJSON形式で返す
+func (s *Server) handleAPIPatterns(w http.ResponseWriter, r *http.Request) {
+	results, err := storage.LoadHistoryResults(storage.ResultsDir)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	patterns := stats.CalculatePatterns(results)
+	if target := r.URL.Query().Get("url"); target != "" {
+		var filtered []*stats.TargetPattern
+		for _, p := range patterns {
+			if p.URL == target {
+				filtered = append(filtered, p)
+			}
+		}
+		patterns = filtered
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"targets": patterns,
+	})
+}
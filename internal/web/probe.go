@@ -0,0 +1,110 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"healthcheck/internal/checker"
+)
+
+// probeModules 対応するblackbox_exporterのモジュール
+var probeModules = map[string]bool{
+	"http_2xx": true,
+}
+
+// handleProbe blackbox_exporter互換のプローブ結果をPrometheusのテキスト形式で返す
+// /probe?target=URL&module=http_2xx
+func (s *Server) handleProbe(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		http.Error(w, "targetパラメータが指定されていません", http.StatusBadRequest)
+		return
+	}
+	if !strings.Contains(target, "://") {
+		target = "http://" + target
+	}
+	module := r.URL.Query().Get("module")
+	if module == "" {
+		module = "http_2xx"
+	}
+	if !probeModules[module] {
+		http.Error(w, fmt.Sprintf("未対応のモジュールです: %s", module), http.StatusBadRequest)
+		return
+	}
+
+	// Prometheusのスクレイプタイムアウトより少し前に打ち切る
+	ctx := r.Context()
+	if v := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"); v != "" {
+		if seconds, err := strconv.ParseFloat(v, 64); err == nil && seconds > 0.5 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration((seconds-0.5)*float64(time.Second)))
+			defer cancel()
+		}
+	}
+
+	start := time.Now()
+	result := s.currentChecker().CheckURL(ctx, target)
+	duration := time.Since(start)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, probeMetrics(result, duration))
+}
+
+// probeMetrics チェック結果をblackbox_exporterと同じ名前のメトリクスに変換
+func probeMetrics(result *checker.CheckResult, duration time.Duration) string {
+	var b strings.Builder
+
+	phases := result.Phases
+	if phases == nil {
+		phases = &checker.Phases{}
+	}
+
+	writeGauge(&b, "probe_dns_lookup_time_seconds", "Returns the time taken for probe dns lookup in seconds", phases.DNS.Seconds())
+	writeGauge(&b, "probe_duration_seconds", "Returns how long the probe took to complete in seconds", duration.Seconds())
+
+	fmt.Fprintln(&b, "# HELP probe_http_duration_seconds Duration of http request by phase, summed over all redirects")
+	fmt.Fprintln(&b, "# TYPE probe_http_duration_seconds gauge")
+	for _, phase := range []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"resolve", phases.DNS},
+		{"connect", phases.Connect},
+		{"tls", phases.TLS},
+		{"processing", phases.Processing},
+	} {
+		fmt.Fprintf(&b, "probe_http_duration_seconds{phase=%q} %s\n", phase.name, formatFloat(phase.duration.Seconds()))
+	}
+
+	ssl := 0.0
+	if phases.TLS > 0 {
+		ssl = 1
+	}
+	writeGauge(&b, "probe_http_ssl", "Indicates if SSL was used for the final redirect", ssl)
+	writeGauge(&b, "probe_http_status_code", "Response HTTP status code", float64(result.StatusCode))
+
+	success := 0.0
+	if result.Success {
+		success = 1
+	}
+	writeGauge(&b, "probe_success", "Displays whether or not the probe was a success", success)
+
+	return b.String()
+}
+
+// writeGauge HELP/TYPE行付きでゲージを1つ書き出す
+func writeGauge(b *strings.Builder, name, help string, value float64) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(b, "%s %s\n", name, formatFloat(value))
+}
+
+// formatFloat Prometheusのテキスト形式で数値を表記
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,53 @@
+package web
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// progressContentType 進捗を逐次送信する形式（1行に1つのJSON）
+const progressContentType = "application/x-ndjson"
+
+// wantsProgress 進捗の逐次送信を求めるリクエストか（Acceptにapplication/x-ndjsonを含む）
+func wantsProgress(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(accept); err == nil && mediaType == progressContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// streamProgress チェックの進捗を完了まで1行ずつ送信（完了した結果は送信する前にonResultに渡す）
+func streamProgress(w http.ResponseWriter, progressChan <-chan checker.Progress, onResult func(*checker.CheckResult)) {
+	for progress := range progressChan {
+		if progress.Result != nil {
+			onResult(progress.Result)
+		}
+		writeProgressLine(w, "progress", progress)
+	}
+}
+
+// writeProgressDone 完了の行として、dataに集計の全ての結果を "results" として加えて送信する
+func writeProgressDone(w http.ResponseWriter, data map[string]interface{}, aggregator *stats.Aggregator) {
+	io.WriteString(w, `{"type":"done","data":`)
+	storage.WriteWithResults(w, data, aggregator)
+	io.WriteString(w, "}\n")
+	http.NewResponseController(w).Flush()
+}
+
+// writeProgressLine 種類とデータを1行のJSONとして送信し、すぐにクライアントへ届ける
+func writeProgressLine(w http.ResponseWriter, kind string, data interface{}) {
+	json.NewEncoder(w).Encode(struct {
+		Type string      `json:"type"`
+		Data interface{} `json:"data"`
+	}{kind, data})
+	http.NewResponseController(w).Flush()
+}
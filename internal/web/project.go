@@ -0,0 +1,234 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"healthcheck/internal/audit"
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/scheduler"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// projectCookie ?token=で認証したブラウザに発行するクッキー（プロジェクトのパスに限定する）
+const projectCookie = "healthcheck_project_token"
+
+// workspace 対象・履歴・定期チェックの組（全体、またはプロジェクトごと）
+type workspace struct {
+	project    config.Project // プロジェクトの設定（全体の場合はゼロ値）
+	config     *config.Config
+	scheduler  *scheduler.Scheduler
+	resultsDir string // 履歴の保存先
+}
+
+// workspace 全体の対象・履歴・定期チェック
+func (s *Server) workspace() *workspace {
+	return &workspace{config: s.currentConfig(), scheduler: s.scheduler, resultsDir: storage.ResultsDir}
+}
+
+// auditOptions 監査記録に残すプロジェクト名（全体の場合はnil）
+func (ws *workspace) auditOptions() map[string]string {
+	if ws.project.Name == "" {
+		return nil
+	}
+	return map[string]string{"project": ws.project.Name}
+}
+
+// basePath プロジェクトのページとAPIのURLの接頭辞
+func (ws *workspace) basePath() string {
+	return "/p/" + ws.project.Name
+}
+
+// syncProjects 設定のプロジェクトに合わせて、プロジェクトごとの定期チェックを作成・更新・停止する
+// 状態の変化の判定に使う前回の状態は、同じ名前のプロジェクトであれば引き継ぐ
+func (s *Server) syncProjects() {
+	s.projectsMutex.Lock()
+	defer s.projectsMutex.Unlock()
+
+	if s.projects == nil {
+		s.projects = make(map[string]*workspace)
+	}
+	cfg := s.currentConfig()
+	seen := make(map[string]bool)
+	for _, p := range cfg.Projects {
+		seen[p.Name] = true
+		pc := cfg.ForProject(p)
+		if ws, ok := s.projects[p.Name]; ok {
+			// 処理中の要求が参照している設定は変更せず、新しい設定のworkspaceに置き換える
+			ws.scheduler.Reload(pc)
+			s.projects[p.Name] = &workspace{project: p, config: pc, scheduler: ws.scheduler, resultsDir: pc.ResultsDir}
+			continue
+		}
+		ws := &workspace{
+			project:    p,
+			config:     pc,
+			scheduler:  scheduler.NewProjectScheduler(pc, p.Name),
+			resultsDir: pc.ResultsDir,
+		}
+		s.projects[p.Name] = ws
+		ws.scheduler.Start()
+	}
+	for name, ws := range s.projects {
+		if !seen[name] {
+			ws.scheduler.Stop()
+			delete(s.projects, name)
+		}
+	}
+}
+
+// withProject パスのプロジェクトを探してトークンを確認し、プロジェクトの対象・定期チェックでハンドラーを呼び出す
+// トークンはAuthorization: Bearer、クエリパラメータのtoken、発行済みのクッキーのいずれかで指定する
+func (s *Server) withProject(handler func(http.ResponseWriter, *http.Request, *workspace)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.projectsMutex.RLock()
+		ws, ok := s.projects[r.PathValue("project")]
+		s.projectsMutex.RUnlock()
+		if !ok {
+			http.Error(w, "プロジェクトが見つかりません", http.StatusNotFound)
+			return
+		}
+		if !ws.authorize(w, r) {
+			http.Error(w, "認証に失敗しました", http.StatusUnauthorized)
+			return
+		}
+		// トークンを設定していないプロジェクトは、ユーザーを設定した場合にログインと役割を確認する
+		if len(ws.project.Tokens) == 0 && len(s.currentConfig().Users) > 0 {
+			if r, ok = s.authorizeUser(w, r); !ok {
+				return
+			}
+		}
+		if e := audit.FromContext(r.Context()); e != nil && e.User == "" {
+			e.User = "project:" + ws.project.Name
+		}
+		handler(w, r, ws)
+	}
+}
+
+// authorize リクエストのトークンがプロジェクトのトークンのいずれかと一致するか（トークンを設定していない場合は常に許可）
+// クエリパラメータで認証した場合は、以降のページの操作とWebSocketのためにクッキーを発行する
+func (ws *workspace) authorize(w http.ResponseWriter, r *http.Request) bool {
+	if len(ws.project.Tokens) == 0 {
+		return true
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && ws.validToken(token) {
+		return true
+	}
+	if token := r.URL.Query().Get("token"); token != "" && ws.validToken(token) {
+		http.SetCookie(w, &http.Cookie{
+			Name:     projectCookie,
+			Value:    token,
+			Path:     ws.basePath() + "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteStrictMode,
+			Secure:   r.TLS != nil,
+		})
+		return true
+	}
+	if c, err := r.Cookie(projectCookie); err == nil && ws.validToken(c.Value) {
+		return true
+	}
+	return false
+}
+
+// validToken トークンがプロジェクトのトークンのいずれかと一致するか
+func (ws *workspace) validToken(token string) bool {
+	for _, expected := range ws.project.Tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// handleProjectDashboard プロジェクトの最新の保存済み結果をダッシュボードで表示
+func (s *Server) handleProjectDashboard(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var results []*checker.CheckResult
+	var statistics *stats.Statistics
+	if latest, err := storage.LatestHistoryEntry(ws.resultsDir); err == nil && latest != nil {
+		results = latest.Results
+		statistics = latest.Statistics
+	}
+	filter, err := tagFilter(r)
+	if err != nil {
+		http.Error(w, "tagはkey=valueの形式で指定してください", http.StatusBadRequest)
+		return
+	}
+	if len(filter) > 0 {
+		results = stats.FilterByTags(results, filter)
+		statistics = nil
+	}
+	if statistics == nil {
+		statistics = stats.CalculateStatistics(results, 0)
+	}
+
+	extras := s.dashboardExtras(r, ws.resultsDir)
+	extras.Live = true
+	extras.Scheduler = ws.schedulerState()
+	if !extras.ReadOnly {
+		extras.TargetIDs = make(map[string]string)
+		for _, t := range ws.config.Targets {
+			extras.TargetIDs[t.URL] = t.ID()
+		}
+	}
+	extras.Project = ws.project.Title
+	extras.BasePath = ws.basePath()
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, dashboard.GenerateDashboard(results, statistics, "", extras))
+}
+
+// handleProjectTargets プロジェクトの対象の一覧を返す（ヘッダーや認証などの設定は含めない）
+func (s *Server) handleProjectTargets(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	type target struct {
+		ID   string            `json:"id"`
+		Name string            `json:"name"`
+		URL  string            `json:"url"`
+		Tags map[string]string `json:"tags,omitempty"`
+	}
+	targets := make([]target, 0, len(ws.config.Targets))
+	for _, t := range ws.config.Targets {
+		targets = append(targets, target{ID: t.ID(), Name: t.Name, URL: t.URL, Tags: t.Tags})
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"project": ws.project.Name,
+		"targets": targets,
+	})
+}
+
+// handleProjectSLA プロジェクトの対象ごとの稼働率を返す
+func (s *Server) handleProjectSLA(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	s.serveSLA(w, r, ws.resultsDir)
+}
+
+// handleProjectIncidents プロジェクトの履歴から検出したインシデントを返す
+func (s *Server) handleProjectIncidents(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	s.serveIncidents(w, ws.resultsDir)
+}
+
+// handleProjectSchedulerPause プロジェクトの定期チェックを一時停止する
+func (s *Server) handleProjectSchedulerPause(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	handleSchedulerControl(w, r, ws, "scheduler_pause", (*scheduler.Scheduler).Pause)
+}
+
+// handleProjectSchedulerResume プロジェクトの定期チェックを再開する
+func (s *Server) handleProjectSchedulerResume(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	handleSchedulerControl(w, r, ws, "scheduler_resume", (*scheduler.Scheduler).Resume)
+}
+
+// handleProjectEvents プロジェクトの定期チェックのイベントだけをWebSocketで配信
+func (s *Server) handleProjectEvents(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	serveEvents(w, r, ws.project.Name)
+}
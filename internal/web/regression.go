@@ -0,0 +1,16 @@
+package web
+
+import (
+	"healthcheck/internal/checker"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// compareWithPrevious 保存済みの最新の実行結果と比較した差分を返す（履歴がない場合はnil）
+func (s *Server) compareWithPrevious(results []*checker.CheckResult) *stats.Regression {
+	previous, err := storage.LatestHistoryEntry(storage.ResultsDir)
+	if err != nil || previous == nil {
+		return nil
+	}
+	return stats.CompareRuns(previous.Results, results, previous.Timestamp, s.currentConfig().RegressionThreshold)
+}
@@ -0,0 +1,139 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/i18n"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// errReloadDisabled 設定ファイルを指定せずに起動したため再読み込みできない
+var errReloadDisabled = errors.New("config reload is not enabled")
+
+// reloader 設定ファイルの再読み込みの設定
+type reloader struct {
+	path     string                         // 変更を監視する設定ファイル
+	load     func() (*config.Config, error) // 設定ファイルを読み込んで検証する
+	interval time.Duration                  // 変更を確認する間隔（0の場合は監視しない）
+	modTime  time.Time                      // 最後に読み込んだ時点の更新日時
+}
+
+// EnableReload 設定ファイルの再読み込みを有効にする
+// SIGHUPの受信とPOST /api/reloadで再読み込みし、intervalが正の場合はその間隔でファイルの更新も監視する
+func (s *Server) EnableReload(path string, load func() (*config.Config, error), interval time.Duration) {
+	s.reload = &reloader{path: path, load: load, interval: interval, modTime: modTime(path)}
+}
+
+// startReload SIGHUPの受信とファイルの監視を開始（再読み込みが有効な場合のみ）
+func (s *Server) startReload() {
+	if s.reload == nil {
+		return
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			s.Reload("signal")
+		}
+	}()
+
+	if s.reload.interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(s.reload.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.reloadMutex.Lock()
+			changed := !modTime(s.reload.path).Equal(s.reload.modTime)
+			s.reloadMutex.Unlock()
+			if changed {
+				s.Reload("file")
+			}
+		}
+	}()
+}
+
+// modTime ファイルの更新日時（取得できない場合はゼロ値）
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// Reload 設定ファイルを読み込み直して実行中の定期チェックに反映する
+// 読み込みや検証に失敗した場合は現在の設定のまま動作を続ける。再起動が必要で反映しなかった項目の名前を返す
+func (s *Server) Reload(trigger string) ([]string, error) {
+	if s.reload == nil {
+		return nil, errReloadDisabled
+	}
+	s.reloadMutex.Lock()
+	defer s.reloadMutex.Unlock()
+
+	// 失敗した内容を繰り返し読み込まないよう、結果にかかわらず更新日時を記録する
+	s.reload.modTime = modTime(s.reload.path)
+	next, err := s.reload.load()
+	if err != nil {
+		slog.Error("failed to reload config", "trigger", trigger, "path", s.reload.path, "error", err)
+		return nil, err
+	}
+
+	// 実行中のチェック・処理中の要求が参照している設定は変更せず、複製に反映して置き換える
+	cfg := s.currentConfig().Clone()
+	restart := cfg.Apply(next)
+	storage.SetHistoryLimit(cfg.HistoryLimit)
+	stats.SetHistogramBuckets(cfg.HistogramBuckets)
+	i18n.SetLocation(cfg.DisplayLocation)
+	s.heartbeats.Update(cfg)
+	s.checker.Store(checker.NewChecker(cfg))
+	s.config.Store(cfg)
+	s.scheduler.Reload(cfg)
+	s.syncProjects()
+
+	slog.Info("config reloaded", "trigger", trigger, "path", s.reload.path,
+		"targets", len(cfg.Targets), "projects", len(cfg.Projects), "interval", cfg.Interval)
+	if len(restart) > 0 {
+		slog.Warn("some settings require a restart to take effect", "settings", restart)
+	}
+	return restart, nil
+}
+
+// handleAPIReload 設定ファイルを読み込み直して反映する
+func (s *Server) handleAPIReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	auditAction(r, "reload", nil, nil)
+
+	restart, err := s.Reload("api")
+	if errors.Is(err, errReloadDisabled) {
+		http.Error(w, "設定ファイルを指定せずに起動したため再読み込みできません", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "設定ファイルの読み込みに失敗しました: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cfg := s.currentConfig()
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"targets":          len(cfg.Targets),
+		"interval":         cfg.Interval.String(),
+		"restart_required": restart,
+	})
+}
@@ -0,0 +1,40 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"healthcheck/internal/runs"
+)
+
+// runInitiator 実行したユーザー（ユーザーを設定していない場合はBasic認証のユーザー名または接続元）
+func runInitiator(r *http.Request) string {
+	if u, ok := currentUser(r); ok {
+		return u.Name
+	}
+	if user, _, ok := r.BasicAuth(); ok {
+		return user
+	}
+	return remoteHost(r)
+}
+
+// handleAPIRuns 実行中のチェックと最近完了したチェックの一覧を返す
+func (s *Server) handleAPIRuns(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	running, recent := runs.List()
+	if running == nil {
+		running = []runs.Run{}
+	}
+	if recent == nil {
+		recent = []runs.Run{}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"running": running,
+		"recent":  recent,
+	})
+}
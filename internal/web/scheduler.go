@@ -0,0 +1,90 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"healthcheck/internal/scheduler"
+)
+
+// schedulerState 定期チェックの状態（running/paused/stopped、間隔を設定していない場合はdisabled）
+func (ws *workspace) schedulerState() string {
+	switch {
+	case ws.config.Interval <= 0:
+		return "disabled"
+	case ws.scheduler.Paused():
+		return "paused"
+	case ws.scheduler.Running():
+		return "running"
+	}
+	return "stopped"
+}
+
+// handleAPISchedulerPause 定期チェックを一時停止する（障害試験の間など）
+func (s *Server) handleAPISchedulerPause(w http.ResponseWriter, r *http.Request) {
+	handleSchedulerControl(w, r, s.workspace(), "scheduler_pause", (*scheduler.Scheduler).Pause)
+}
+
+// handleAPISchedulerResume 一時停止した定期チェックを再開する
+func (s *Server) handleAPISchedulerResume(w http.ResponseWriter, r *http.Request) {
+	handleSchedulerControl(w, r, s.workspace(), "scheduler_resume", (*scheduler.Scheduler).Resume)
+}
+
+// handleSchedulerControl 定期チェックの一時停止・再開を実行して状態を返す
+// すでにその状態だった場合も成功とし、changedで区別する
+func handleSchedulerControl(w http.ResponseWriter, r *http.Request, ws *workspace, action string, control func(*scheduler.Scheduler) bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	auditAction(r, action, nil, ws.auditOptions())
+
+	if ws.config.Interval <= 0 {
+		http.Error(w, "定期チェックの間隔（interval）が設定されていません", http.StatusConflict)
+		return
+	}
+	changed := control(ws.scheduler)
+
+	response := map[string]interface{}{
+		"state":   ws.schedulerState(),
+		"changed": changed,
+	}
+	if next := ws.scheduler.NextRun(); !next.IsZero() {
+		response["next_run"] = next
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleAPITargetCheckNow 指定した対象をすぐにチェックして結果を返す
+// /api/targets/{id}/check-now（idは対象の名前、名前がない場合はURLをエスケープしたもの）
+func (s *Server) handleAPITargetCheckNow(w http.ResponseWriter, r *http.Request) {
+	handleTargetCheckNow(w, r, s.workspace())
+}
+
+// handleTargetCheckNow 対象・定期チェックの組から対象を探してすぐにチェックする
+func handleTargetCheckNow(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.PathValue("id")
+	target, ok := ws.config.FindTarget(id)
+	if !ok {
+		http.Error(w, "指定した対象が見つかりません", http.StatusNotFound)
+		return
+	}
+	auditAction(r, "check_now", []string{target.URL}, ws.auditOptions())
+
+	// クライアントが切断しても、通知まで最後まで実行する
+	results, statistics := ws.scheduler.CheckNow(context.WithoutCancel(r.Context()), target, runInitiator(r))
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"target":     target.ID(),
+		"results":    results,
+		"statistics": statistics,
+	})
+}
@@ -2,44 +2,194 @@ package web
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
 	"net/http"
-	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"healthcheck/internal/ack"
+	"healthcheck/internal/account"
+	"healthcheck/internal/agent"
+	"healthcheck/internal/audit"
 	"healthcheck/internal/checker"
 	"healthcheck/internal/config"
 	"healthcheck/internal/dashboard"
+	"healthcheck/internal/discovery"
+	"healthcheck/internal/har"
+	"healthcheck/internal/heartbeat"
+	"healthcheck/internal/logging"
+	"healthcheck/internal/runs"
+	"healthcheck/internal/scheduler"
 	"healthcheck/internal/stats"
 	"healthcheck/internal/storage"
+	"healthcheck/internal/tracing"
+	"healthcheck/internal/urllist"
 )
 
 // Server Webサーバー
 type Server struct {
-	checker *checker.Checker
-	config  *config.Config
+	checker    atomic.Pointer[checker.Checker] // 現在の設定のチェッカー（再読み込みで置き換える）
+	config     atomic.Pointer[config.Config]   // 現在の設定（再読み込みで置き換えるため変更せず、1回の処理では最初に取得したものを使う）
+	scheduler  *scheduler.Scheduler
+	discovery  *discovery.Manager
+	heartbeats *heartbeat.Monitor
+	audit      *audit.Log     // API呼び出しの監査記録（無効の場合はnil）
+	clients    *clientLimiter // 接続元ごとのレート制限（無効の場合はnil）
+	runs       chan struct{}  // 同時に実行できるチェックの枠（無制限の場合はnil）
+
+	inflight      map[string]*inflightRun // 実行中の/api/checkの実行（合流の判定のキーごと）
+	inflightMutex sync.Mutex
+
+	reload      *reloader // 設定ファイルの再読み込み（無効の場合はnil）
+	reloadMutex sync.Mutex
+
+	projects      map[string]*workspace // プロジェクトごとの対象・履歴・定期チェック
+	projectsMutex sync.RWMutex
+
+	sessions *account.Sessions    // ログイン中のセッション
+	verified *account.VerifyCache // Basic認証で確認済みのパスワード
+
+	randomShareKey []byte // share_secretを設定していない場合に共有リンクの署名に使う鍵
 }
 
 // NewServer 新しいWebサーバーを作成
 func NewServer(cfg *config.Config) *Server {
-	return &Server{
-		checker: checker.NewChecker(cfg),
-		config:  cfg,
+	// ハートビートはチェッカーの作成前にチェック方法として登録する
+	heartbeats := heartbeat.NewMonitor(cfg)
+	checker.Register(heartbeats)
+
+	var auditLog *audit.Log
+	if cfg.AuditLog != "" {
+		auditLog = audit.NewLog(cfg.AuditLog)
+	}
+
+	var runs chan struct{}
+	if cfg.MaxConcurrentRuns > 0 {
+		runs = make(chan struct{}, cfg.MaxConcurrentRuns)
 	}
+
+	s := &Server{
+		scheduler:  scheduler.NewScheduler(cfg),
+		discovery:  discovery.NewManager(cfg),
+		heartbeats: heartbeats,
+		audit:      auditLog,
+		clients:    newClientLimiter(cfg.ClientRate),
+		runs:       runs,
+		inflight:   make(map[string]*inflightRun),
+		sessions:   account.NewSessions(),
+		verified:   account.NewVerifyCache(),
+
+		randomShareKey: newShareKey(),
+	}
+	s.config.Store(cfg)
+	s.checker.Store(checker.NewChecker(cfg))
+	return s
+}
+
+// currentConfig 現在の設定
+func (s *Server) currentConfig() *config.Config {
+	return s.config.Load()
+}
+
+// currentChecker 現在の設定のチェッカー
+func (s *Server) currentChecker() *checker.Checker {
+	return s.checker.Load()
 }
 
-// Start サーバーを起動
-func (s *Server) Start(port string) error {
+// Start サーバーを起動（addrは待ち受けるアドレス、例: ":8080"、"127.0.0.1:8080"）
+func (s *Server) Start(addr string) error {
 	http.HandleFunc("/", s.handleIndex)
 	http.HandleFunc("/check", s.handleCheck)
 	http.HandleFunc("/api/check", s.handleAPICheck)
 	http.HandleFunc("/dashboard", s.handleDashboard)
+	http.HandleFunc("/api/har", s.handleAPIHAR)
+	http.HandleFunc("/api/import", s.handleAPIImport)
+	http.HandleFunc("/calendar", s.handleCalendar)
+	http.HandleFunc("/api/calendar", s.handleAPICalendar)
+	http.HandleFunc("/api/sla", s.handleAPISLA)
+	http.HandleFunc("/api/incidents", s.handleAPIIncidents)
+	http.HandleFunc("/status", s.handleStatus)
+	http.HandleFunc("/badge/", s.handleBadge)
+	http.HandleFunc("/embed/", s.handleEmbed)
+	http.HandleFunc("/explorer", s.handleExplorer)
+	http.HandleFunc("/api/explore", s.handleAPIExplore)
+	http.HandleFunc("/patterns", s.handlePatterns)
+	http.HandleFunc("/api/patterns", s.handleAPIPatterns)
+	http.HandleFunc("/heatmap", s.handleHeatmap)
+	http.HandleFunc("/api/heatmap", s.handleAPIHeatmap)
+	http.HandleFunc("/history", s.handleHistory)
+	http.HandleFunc("/api/history", s.handleAPIHistory)
+	http.HandleFunc("/benchmark", s.handleBenchmark)
+	http.HandleFunc("/api/benchmark", s.handleAPIBenchmark)
+	http.HandleFunc(agent.ResultsPath, s.handleAPIAgentResults)
+	http.HandleFunc("/api/regions", s.handleAPIRegions)
+	http.HandleFunc("/ws", s.handleWebSocket)
+	http.HandleFunc("/api/audit", s.handleAPIAudit)
+	http.HandleFunc("/api/runs", s.handleAPIRuns)
+	http.HandleFunc("/api/runs/{id}/share", s.handleAPIRunShare)
+	http.HandleFunc("/shared/{id}", s.handleShared)
+	http.HandleFunc("/api/debug/ratelimit", s.handleAPIDebugRateLimit)
+	http.HandleFunc("/heartbeat/", s.handleHeartbeat)
+	http.HandleFunc("/api/heartbeats", s.handleAPIHeartbeats)
+	http.HandleFunc("/api/content", s.handleAPIContent)
+	http.HandleFunc("/api/content/accept", s.handleAPIContentAccept)
+	http.HandleFunc("/api/reload", s.handleAPIReload)
+	http.HandleFunc("/api/scheduler/pause", s.handleAPISchedulerPause)
+	http.HandleFunc("/api/scheduler/resume", s.handleAPISchedulerResume)
+	http.HandleFunc("/api/targets/import", s.handleAPITargetsImport)
+	http.HandleFunc("/api/acks", s.handleAPIAcks)
+	http.HandleFunc("/api/targets/{id}/check-now", s.handleAPITargetCheckNow)
+	http.HandleFunc("/api/digest", s.handleAPIDigest)
+	http.HandleFunc("/api/hooks/{name}", s.handleAPIHook)
+	http.HandleFunc("/p/{project}/dashboard", s.withProject(s.handleProjectDashboard))
+	http.HandleFunc("/p/{project}/ws", s.withProject(s.handleProjectEvents))
+	http.HandleFunc("/p/{project}/api/targets", s.withProject(s.handleProjectTargets))
+	http.HandleFunc("/p/{project}/api/targets/{id}/check-now", s.withProject(handleTargetCheckNow))
+	http.HandleFunc("/p/{project}/api/sla", s.withProject(s.handleProjectSLA))
+	http.HandleFunc("/p/{project}/api/incidents", s.withProject(s.handleProjectIncidents))
+	http.HandleFunc("/p/{project}/api/runs/{id}/share", s.withProject(s.handleProjectRunShare))
+	http.HandleFunc("/p/{project}/api/scheduler/pause", s.withProject(s.handleProjectSchedulerPause))
+	http.HandleFunc("/p/{project}/api/scheduler/resume", s.withProject(s.handleProjectSchedulerResume))
+	http.HandleFunc("/login", s.handleLogin)
+	http.HandleFunc("/logout", s.handleLogout)
+	http.HandleFunc("/healthz", s.handleHealthz)
+	http.HandleFunc("/readyz", s.handleReadyz)
+	http.HandleFunc("/probe", s.handleProbe)
+	http.HandleFunc("/metrics", s.handleMetrics)
+	http.HandleFunc("/api/grafana/", s.handleGrafanaRoot)
+	http.HandleFunc("/api/grafana/search", s.handleGrafanaSearch)
+	http.HandleFunc("/api/grafana/metrics", s.handleGrafanaMetrics)
+	http.HandleFunc("/api/grafana/query", s.handleGrafanaQuery)
+
+	// 対象の自動検出と定期チェックを開始（設定されている場合のみ）
+	s.discovery.Start()
+	s.scheduler.Start()
+	s.syncProjects()
+	s.startReload()
+	s.startDigests()
 
-	addr := ":" + port
-	fmt.Printf("Health Check Server started on http://localhost%s\n", addr)
-	fmt.Printf("Open your browser and navigate to http://localhost%s\n", addr)
-	return http.ListenAndServe(addr, nil)
+	base := displayURL(addr)
+	slog.Info("server started", "url", base, "listen", addr)
+	for _, hb := range s.heartbeats.Statuses() {
+		slog.Info("heartbeat endpoint", "target", hb.Name, "url", base+"/heartbeat/"+hb.Token)
+	}
+	if s.currentConfig().DebugEndpoints {
+		publishDebugVars()
+		slog.Info("debug endpoints enabled", "pprof", base+"/debug/pprof/", "vars", base+"/debug/vars")
+	}
+	server := &http.Server{
+		Addr:      addr,
+		Handler:   withRequestID(s.withAccessLog(s.withRateLimit(s.withLanguage(s.withTheme(s.withDebugEndpoints(s.withAccounts(http.DefaultServeMux))))))),
+		ConnState: countConnState,
+	}
+	return server.ListenAndServe()
 }
 
 // handleIndex インデックスページ
@@ -49,12 +199,13 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	html := `<!DOCTYPE html>
-<html lang="ja">
+	lang := language(r)
+	tmpl := `<!DOCTYPE html>
+<html lang="{{.Lang}}" data-theme="{{themeMode}}">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>Health Check Tool</title>
+    <title>{{pageTitle "Health Check Tool"}}</title>
     <style>
         * { margin: 0; padding: 0; box-sizing: border-box; }
         body {
@@ -170,91 +321,398 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
             animation: spin 1s linear infinite;
             margin: 0 auto 10px;
         }
+        .har-form {
+            margin-top: 30px;
+            padding-top: 20px;
+            border-top: 1px solid #e0e0e0;
+        }
+        .live {
+            margin-top: 30px;
+            padding-top: 20px;
+            border-top: 1px solid #e0e0e0;
+        }
+        .live h2 {
+            font-size: 1.1em;
+            color: #333;
+            margin-bottom: 10px;
+        }
+        .live-state {
+            font-size: 12px;
+            color: #999;
+            font-weight: normal;
+        }
+        #liveEvents {
+            list-style: none;
+            font-size: 13px;
+            font-family: monospace;
+            max-height: 240px;
+            overflow-y: auto;
+        }
+        #liveEvents li {
+            padding: 4px 0;
+            border-bottom: 1px solid #f0f0f0;
+        }
+        #liveEvents .ok { color: #10b981; }
+        #liveEvents .ng { color: #ef4444; }
+        #activeRuns {
+            list-style: none;
+            font-size: 13px;
+        }
+        #activeRuns li {
+            padding: 6px 0;
+            border-bottom: 1px solid #f0f0f0;
+        }
+        #activeRuns .progress-bar {
+            margin-top: 4px;
+        }
+        #runProgress {
+            display: none;
+            margin-top: 20px;
+        }
+        .progress-bar {
+            background: #e5e5e5;
+            border-radius: 4px;
+            height: 10px;
+            overflow: hidden;
+        }
+        .progress-bar div {
+            background: #667eea;
+            height: 100%;
+            width: 0;
+            transition: width 0.2s;
+        }
+        .progress-count {
+            color: #666;
+            font-size: 14px;
+            margin: 8px 0;
+        }
+        #runningURLs,
+        #partialResults {
+            list-style: none;
+            font-size: 13px;
+            font-family: monospace;
+        }
+        #runningURLs li {
+            color: #999;
+            padding: 2px 0;
+        }
+        #partialResults {
+            margin-top: 8px;
+            max-height: 240px;
+            overflow-y: auto;
+        }
+        #partialResults li {
+            padding: 4px 0;
+            border-bottom: 1px solid #f0f0f0;
+        }
+        #partialResults .ok { color: #10b981; }
+        #partialResults .ng { color: #ef4444; }
         @keyframes spin {
             0% { transform: rotate(0deg); }
             100% { transform: rotate(360deg); }
         }
     </style>
+    {{themeStyle}}
 </head>
-<body>
+<body class="accent">
     <div class="container">
+        {{themeSwitch}}
+        {{langSwitch}}
+        {{brand}}
         <h1>🔍 Health Check Tool</h1>
-        <p class="subtitle">複数のURLの生存確認を並列で実行します</p>
+        <p class="subtitle">{{t "index_subtitle"}}</p>
         
         <form id="checkForm">
             <div class="form-group">
-                <label for="urls">URLリスト（1行に1つのURL）:</label>
+                <label for="urls">{{t "index_urls"}}</label>
                 <textarea id="urls" name="urls" placeholder="https://example.com&#10;https://api.example.com&#10;https://www.google.com" required></textarea>
-                <div class="help-text">コメント行（#で始まる行）と空行は無視されます</div>
+                <div class="help-text">{{t "index_urls_help"}}</div>
             </div>
             
             <div class="options">
                 <div class="option-group">
-                    <label for="concurrency">並列度:</label>
+                    <label for="concurrency">{{t "index_concurrency"}}</label>
                     <input type="number" id="concurrency" name="concurrency" value="10" min="1" max="100">
                 </div>
                 <div class="option-group">
-                    <label for="timeout">タイムアウト（秒）:</label>
+                    <label for="timeout">{{t "index_timeout"}}</label>
                     <input type="number" id="timeout" name="timeout" value="30" min="1" max="300">
                 </div>
                 <div class="option-group">
-                    <label for="retries">リトライ回数:</label>
+                    <label for="retries">{{t "index_retries"}}</label>
                     <input type="number" id="retries" name="retries" value="3" min="0" max="10">
                 </div>
             </div>
             
-            <button type="submit">ヘルスチェック実行</button>
+            <button type="submit">{{t "index_run"}}</button>
+        </form>
+        
+        <form id="targetsForm" class="har-form">
+            <div class="form-group">
+                <label for="targets">{{t "index_targets"}}</label>
+                <input type="file" id="targets" name="targets" accept=".csv,.tsv,.txt,.xlsx,text/csv,text/tab-separated-values,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet" required>
+                <div class="help-text">{{t "index_targets_help"}}</div>
+            </div>
+            <button type="submit">{{t "index_targets_run"}}</button>
+        </form>
+        
+        <form id="harForm" class="har-form">
+            <div class="form-group">
+                <label for="har">{{t "index_har"}}</label>
+                <input type="file" id="har" name="har" accept=".har,application/json" required>
+                <div class="help-text">{{t "index_har_help"}}</div>
+            </div>
+            <button type="submit">{{t "index_har_run"}}</button>
         </form>
         
         <div id="loading">
             <div class="spinner"></div>
-            <p>チェック中...</p>
+            <p>{{t "checking"}}</p>
+        </div>
+
+        <div id="runProgress">
+            <div class="progress-bar"><div id="progressBar"></div></div>
+            <p class="progress-count" id="progressCount"></p>
+            <ul id="runningURLs"></ul>
+            <ul id="partialResults"></ul>
+        </div>
+
+        <div class="live">
+            <h2>{{t "runs_title"}}</h2>
+            <ul id="activeRuns"><li>{{t "runs_none"}}</li></ul>
+        </div>
+
+        <div class="live">
+            <h2>{{t "live"}} <span class="live-state" id="liveState">{{t "connecting"}}</span></h2>
+            <ul id="liveEvents"></ul>
         </div>
     </div>
     
-    <script>
-        document.getElementById('checkForm').addEventListener('submit', async function(e) {
+    <script>` + dashboard.LiveScript + `
+        // 結果・定期チェック・アラートのイベントを新しい順に表示
+        connectLive(function(event) {
+            const time = formatTime(event.timestamp);
+            let text = '', cls = '';
+            switch (event.type) {
+                case 'result':
+                    cls = event.data.success ? 'ok' : 'ng';
+                    text = (event.data.success ? '✓ ' : '✗ ') + event.data.url + ' ' + (event.data.status_code || event.data.error || '');
+                    break;
+                case 'run_started':
+                    text = {{t "live_run_started"}};
+                    break;
+                case 'run_finished':
+                    text = {{t "live_run_finished"}} + (event.data ? ' (' + {{t "success_rate"}} + ' ' + event.data.success_rate.toFixed(1) + '%)' : '');
+                    break;
+                case 'alert':
+                    cls = event.data.kind === 'recovered' ? 'ok' : 'ng';
+                    text = '🔔 ' + event.data.kind + ' ' + (event.data.url || '');
+                    break;
+                case 'scheduler':
+                    text = event.data.running ? {{t "live_scheduler_running"}} : event.data.paused ? {{t "live_scheduler_paused"}} : {{t "live_scheduler_stopped"}};
+                    break;
+                default:
+                    return;
+            }
+            const li = document.createElement('li');
+            li.className = cls;
+            li.textContent = time + ' ' + text;
+            const list = document.getElementById('liveEvents');
+            list.insertBefore(li, list.firstChild);
+            while (list.children.length > 50) {
+                list.removeChild(list.lastChild);
+            }
+        }, function(connected) {
+            document.getElementById('liveState').textContent = connected ? {{t "connected"}} : {{t "reconnecting"}};
+        });
+
+        // サーバーで実行中のチェック（定期チェック・Webhook・他の利用者の実行を含む）の進捗を表示
+        async function refreshRuns() {
+            try {
+                const response = await fetch('/api/runs');
+                if (!response.ok) {
+                    return;
+                }
+                const data = await response.json();
+                const list = document.getElementById('activeRuns');
+                if (data.running.length === 0) {
+                    const li = document.createElement('li');
+                    li.textContent = {{t "runs_none"}};
+                    list.replaceChildren(li);
+                    return;
+                }
+                list.replaceChildren(...data.running.map(function(run) {
+                    const li = document.createElement('li');
+                    const label = document.createElement('div');
+                    label.textContent = run.trigger + (run.initiator ? ' (' + run.initiator + ')' : '') + ' ' +
+                        formatTime(run.started) + ' ' + run.completed + ' / ' + run.total + {{t "completed_unit"}};
+                    const bar = document.createElement('div');
+                    bar.className = 'progress-bar';
+                    const fill = document.createElement('div');
+                    fill.style.width = (run.total > 0 ? run.completed * 100 / run.total : 0) + '%';
+                    bar.appendChild(fill);
+                    li.append(label, bar);
+                    return li;
+                }));
+            } catch (error) {
+                // 次の更新で再試行する
+            }
+        }
+        refreshRuns();
+        setInterval(refreshRuns, 3000);
+
+        // チェックの進捗（完了数・チェック中のURL・完了した結果）を表示
+        function showProgress(progress) {
+            const percent = progress.total > 0 ? progress.completed * 100 / progress.total : 0;
+            document.getElementById('progressBar').style.width = percent + '%';
+            document.getElementById('progressCount').textContent = progress.completed + ' / ' + progress.total + {{t "completed_unit"}};
+            const running = document.getElementById('runningURLs');
+            running.replaceChildren(...(progress.running || []).map(function(url) {
+                const li = document.createElement('li');
+                li.textContent = '⏳ ' + url;
+                return li;
+            }));
+            const result = progress.result;
+            if (result) {
+                const li = document.createElement('li');
+                li.className = result.success ? 'ok' : 'ng';
+                li.textContent = (result.success ? '✓ ' : '✗ ') + result.url + ' ' +
+                    (result.status_code || result.error || '') + ' ' + Math.round(result.response_time_ms / 1e6) + 'ms';
+                document.getElementById('partialResults').appendChild(li);
+            }
+        }
+
+        // 1行ずつ送信される進捗を表示し、最後の行（done）の結果を返す
+        async function readProgress(response) {
+            const reader = response.body.getReader();
+            const decoder = new TextDecoder();
+            let buffer = '', data = null;
+            for (;;) {
+                const { value, done } = await reader.read();
+                buffer += decoder.decode(value || new Uint8Array(), { stream: !done });
+                let newline;
+                while ((newline = buffer.indexOf('\n')) >= 0) {
+                    const line = buffer.slice(0, newline).trim();
+                    buffer = buffer.slice(newline + 1);
+                    if (!line) {
+                        continue;
+                    }
+                    const message = JSON.parse(line);
+                    if (message.type === 'progress') {
+                        showProgress(message.data);
+                    } else if (message.type === 'done') {
+                        data = message.data;
+                    }
+                }
+                if (done) {
+                    break;
+                }
+            }
+            if (!data) {
+                throw new Error({{t "check_failed"}});
+            }
+            return data;
+        }
+
+        document.getElementById('harForm').addEventListener('submit', async function(e) {
             e.preventDefault();
             
             const form = e.target;
             const button = form.querySelector('button');
             const loading = document.getElementById('loading');
-            const urls = document.getElementById('urls').value;
             
             button.disabled = true;
             loading.style.display = 'block';
             
-            const formData = new FormData(form);
-            formData.append('urls', urls);
+            try {
+                const response = await fetch('/api/har', {
+                    method: 'POST',
+                    body: new FormData(form)
+                });
+                
+                if (!response.ok) {
+                    throw new Error(await response.text());
+                }
+                
+                const data = await response.json();
+                window.location.href = '/dashboard?results=' + encodeURIComponent(JSON.stringify(data));
+            } catch (error) {
+                alert({{t "error_prefix"}} + error.message);
+            } finally {
+                button.disabled = false;
+                loading.style.display = 'none';
+            }
+        });
+        
+        // チェックを実行して進捗を表示し、完了したら結果ページに移動する
+        async function runCheck(formData, button) {
+            const runProgress = document.getElementById('runProgress');
+            
+            button.disabled = true;
+            showProgress({ total: 0, completed: 0, running: [] });
+            document.getElementById('partialResults').replaceChildren();
+            runProgress.style.display = 'block';
             
             try {
                 const response = await fetch('/api/check', {
                     method: 'POST',
+                    headers: { 'Accept': 'application/x-ndjson' },
                     body: formData
                 });
                 
                 if (!response.ok) {
-                    throw new Error('チェックに失敗しました');
+                    const text = await response.text();
+                    let message = text;
+                    try {
+                        message = JSON.parse(text).errors.map(e => e.message).join('\n');
+                    } catch (_) {}
+                    throw new Error(message || {{t "check_failed"}});
                 }
                 
-                const data = await response.json();
+                const data = await readProgress(response);
                 
                 // 結果ページにリダイレクト
                 window.location.href = '/dashboard?results=' + encodeURIComponent(JSON.stringify(data));
             } catch (error) {
-                alert('エラー: ' + error.message);
+                alert({{t "error_prefix"}} + error.message);
             } finally {
                 button.disabled = false;
-                loading.style.display = 'none';
+                runProgress.style.display = 'none';
             }
+        }
+
+        document.getElementById('checkForm').addEventListener('submit', function(e) {
+            e.preventDefault();
+            
+            const form = e.target;
+            const formData = new FormData(form);
+            formData.append('urls', document.getElementById('urls').value);
+            runCheck(formData, form.querySelector('button'));
+        });
+
+        // 対象の一覧のファイルは、URLリストのフォームの並列度・タイムアウト・リトライ回数でチェックする
+        document.getElementById('targetsForm').addEventListener('submit', function(e) {
+            e.preventDefault();
+            
+            const form = e.target;
+            const formData = new FormData(form);
+            ['concurrency', 'timeout', 'retries'].forEach(function(name) {
+                formData.append(name, document.getElementById(name).value);
+            });
+            runCheck(formData, form.querySelector('button'));
         });
     </script>
 </body>
 </html>`
 
+	t, err := template.New("index").Funcs(pageFuncs(r)).Parse(tmpl)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
-	fmt.Fprint(w, html)
+	t.Execute(w, struct{ Lang string }{lang})
 }
 
 // handleCheck チェック実行（POST）
@@ -264,48 +722,39 @@ func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	urlsText := r.FormValue("urls")
-	urls := parseURLs(urlsText)
+	urls, rejected := urllist.Parse(r.FormValue("urls"))
+	auditAction(r, "check", urls, formOptions(r, "concurrency", "timeout", "retries"))
 
 	if len(urls) == 0 {
-		http.Error(w, "URLが指定されていません", http.StatusBadRequest)
+		http.Error(w, noURLsMessage(rejected), http.StatusBadRequest)
 		return
 	}
-
-	// 設定の更新
-	if concurrency := r.FormValue("concurrency"); concurrency != "" {
-		var c int
-		fmt.Sscanf(concurrency, "%d", &c)
-		if c > 0 {
-			s.config.Concurrency = c
-		}
-	}
-	if timeout := r.FormValue("timeout"); timeout != "" {
-		var t int
-		fmt.Sscanf(timeout, "%d", &t)
-		if t > 0 {
-			s.config.Timeout = time.Duration(t) * time.Second
-			s.config.MaxLatency = s.config.Timeout
-		}
+	metadata, err := formMetadata(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("metaが不正です: %v", err), http.StatusBadRequest)
+		return
 	}
-	if retries := r.FormValue("retries"); retries != "" {
-		var r int
-		fmt.Sscanf(retries, "%d", &r)
-		if r >= 0 {
-			s.config.Retries = r
-		}
+	if !s.checkURLLimit(w, urls) || !s.acquireRun(w) {
+		return
 	}
+	defer s.releaseRun()
 
-	// チェッカーを再作成（設定を反映）
-	s.checker = checker.NewChecker(s.config)
+	// 設定を反映したチェッカーを作成（実行中の設定は変更しない）
+	_, check := s.runChecker(formRunOptions(r))
 
 	// ヘルスチェック実行
-	ctx := context.Background()
+	ctx, span := startRun(r, "web")
+	defer finishRun(span)
+
+	// sitemap:/robots:の対象を個別のページに展開
+	urls = check.ExpandURLs(ctx, urls)
+	if !s.checkURLLimit(w, urls) {
+		return
+	}
 	resultChan := make(chan *checker.CheckResult, len(urls))
-	progressChan := make(chan int, len(urls))
 
 	startTime := time.Now()
-	go s.checker.CheckURLs(ctx, urls, resultChan, progressChan)
+	go check.CheckURLs(ctx, urls, resultChan, nil)
 
 	var results []*checker.CheckResult
 	for result := range resultChan {
@@ -313,14 +762,26 @@ func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
 	}
 	totalDuration := time.Since(startTime)
 
+	// 応答時間の劣化を判定
+	s.markDegraded(results)
+
 	// 統計情報の計算
 	statistics := stats.CalculateStatistics(results, totalDuration)
 
+	// 前回の実行との差分
+	regression := s.compareWithPrevious(results)
+
+	slog.InfoContext(ctx, "check finished", "trigger", "web", "targets", statistics.TotalRequests, "failures", statistics.FailureCount, "duration", totalDuration)
+
 	// 結果を保存
-	historyPath, _ := storage.SaveHistory(results, statistics)
+	historyPath := saveHistory(ctx, span.TraceID, metadata, results, statistics)
+	auditResult(r, span.TraceID, map[string]interface{}{"targets": statistics.TotalRequests, "failures": statistics.FailureCount})
 
 	// ダッシュボードを生成
-	dashboardHTML := dashboard.GenerateDashboard(results, statistics, historyPath)
+	extras := s.dashboardExtras(r, storage.ResultsDir)
+	extras.Regression = regression
+	extras.Rejected = rejected
+	dashboardHTML := dashboard.GenerateDashboard(results, statistics, historyPath, extras)
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
@@ -328,72 +789,294 @@ func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
 }
 
 // handleAPICheck APIエンドポイント（JSON形式で結果を返す）
+// フォーム形式のほか、Content-Type: application/jsonで対象ごとの設定を含めて指定できる
 func (s *Server) handleAPICheck(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	urlsText := r.FormValue("urls")
-	urls := parseURLs(urlsText)
-
-	if len(urls) == 0 {
-		http.Error(w, "URLが指定されていません", http.StatusBadRequest)
+	var targets []config.Target
+	var options runOptions
+	var rejected []urllist.Rejected
+	if isJSONRequest(r) {
+		var errs []fieldError
+		targets, options, errs = s.decodeCheckRequest(w, r)
+		auditAction(r, "check", targetURLs(targets), options.auditOptions())
+		if len(errs) > 0 {
+			writeFieldErrors(w, errs)
+			return
+		}
+	} else {
+		// targetsに対象の一覧（CSV・TSV・xlsx）がアップロードされた場合はurlsの代わりに使う
+		imported, importRejected, uploaded, err := uploadedTargets(r)
+		if uploaded {
+			targets, rejected = imported, importRejected
+		} else {
+			var urls []string
+			urls, rejected = urllist.Parse(r.FormValue("urls"))
+			for _, u := range urls {
+				targets = append(targets, config.Target{Name: u, URL: u})
+			}
+		}
+		options = formRunOptions(r)
+		auditAction(r, "check", targetURLs(targets), formOptions(r, "concurrency", "timeout", "retries", "coalesce"))
+		if err != nil {
+			writeFieldErrors(w, []fieldError{{Field: "targets", Message: fmt.Sprintf("対象の一覧を読み込めませんでした: %v", err)}})
+			return
+		}
+		if len(targets) == 0 {
+			writeRejected(w, rejected)
+			return
+		}
+		metadata, err := formMetadata(r)
+		if err != nil {
+			writeFieldErrors(w, []fieldError{{Field: "meta", Message: fmt.Sprintf("metaが不正です: %v", err)}})
+			return
+		}
+		options.metadata = metadata
+		if options.callbackURL != "" && !validCallbackURL(options.callbackURL) {
+			writeFieldErrors(w, []fieldError{{Field: "callback_url", Message: "callback_urlにはhttpまたはhttpsのURLを指定してください"}})
+			return
+		}
+	}
+	if !s.checkURLLimit(w, targetURLs(targets)) {
 		return
 	}
 
-	// 設定の更新
-	if concurrency := r.FormValue("concurrency"); concurrency != "" {
-		var c int
-		fmt.Sscanf(concurrency, "%d", &c)
-		if c > 0 {
-			s.config.Concurrency = c
+	// 同じ対象と設定のチェックが実行中の場合は、新たに実行せずその結果を返す
+	key := coalesceKey(targets, options)
+	if options.coalesce {
+		if run := s.joinRun(key); run != nil {
+			s.serveCoalesced(w, r, run, options, rejected)
+			return
 		}
 	}
-	if timeout := r.FormValue("timeout"); timeout != "" {
-		var t int
-		fmt.Sscanf(timeout, "%d", &t)
-		if t > 0 {
-			s.config.Timeout = time.Duration(t) * time.Second
-			s.config.MaxLatency = s.config.Timeout
-		}
+	if !s.acquireRun(w) {
+		return
 	}
-	if retries := r.FormValue("retries"); retries != "" {
-		var r int
-		fmt.Sscanf(retries, "%d", &r)
-		if r >= 0 {
-			s.config.Retries = r
+
+	// 設定を反映したチェッカーを作成（実行中の設定は変更しない）
+	cfg, check := s.runChecker(options)
+
+	// ヘルスチェック実行
+	ctx, span := startRun(r, "web")
+
+	// sitemap:/robots:の対象を個別のページに展開
+	targets = check.ExpandTargets(ctx, targets)
+	if !s.checkURLLimit(w, targetURLs(targets)) {
+		finishRun(span)
+		s.releaseRun()
+		return
+	}
+	run := s.registerRun(key, span.TraceID)
+
+	if options.callbackURL != "" {
+		// クライアントを待たせず、実行枠の解放とトレースの終了はチェックの完了後に行う
+		go func() {
+			defer s.releaseRun()
+			defer finishRun(span)
+			outcome := s.runCheck(ctx, cfg, check, span.TraceID, targets, options, rejected, nil)
+			s.completeRun(key, run, outcome)
+			defer run.release(s)
+			if err := s.postCheckCallback(ctx, options, run, outcome.response); err != nil {
+				slog.WarnContext(ctx, "failed to post check callback", "callback_url", options.callbackURL, "error", err)
+			}
+		}()
+		auditResult(r, span.TraceID, map[string]interface{}{"targets": len(targets), "callback": true})
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"run_id":   span.TraceID,
+			"status":   "accepted",
+			"metadata": options.metadata,
+			"rejected": rejected,
+		})
+		return
+	}
+	defer s.releaseRun()
+	defer finishRun(span)
+
+	// 進捗の逐次送信を求められた場合は、チェック中の対象と完了した結果を1行ずつ送信する
+	var progress func(<-chan checker.Progress, func(*checker.CheckResult))
+	stream := wantsProgress(r)
+	if stream {
+		w.Header().Set("Content-Type", progressContentType)
+		w.WriteHeader(http.StatusOK)
+		progress = func(progressChan <-chan checker.Progress, add func(*checker.CheckResult)) {
+			streamProgress(w, progressChan, add)
 		}
 	}
 
-	// チェッカーを再作成
-	s.checker = checker.NewChecker(s.config)
+	outcome := s.runCheck(ctx, cfg, check, span.TraceID, targets, options, rejected, progress)
+	s.completeRun(key, run, outcome)
+	defer run.release(s)
+	auditResult(r, span.TraceID, map[string]interface{}{"targets": outcome.statistics.TotalRequests, "failures": outcome.statistics.FailureCount})
 
-	// ヘルスチェック実行
-	ctx := context.Background()
-	resultChan := make(chan *checker.CheckResult, len(urls))
-	progressChan := make(chan int, len(urls))
+	// JSON形式で返す（結果は集計から1件ずつ書き込む）
+	run.writeResponse(w, r, outcome.response, stream)
+}
+
+// checkOutcome /api/checkの1回の実行の結果
+type checkOutcome struct {
+	aggregator *stats.Aggregator      // 結果の集計（使い終わったらCloseする）
+	statistics *stats.Statistics      // 統計情報
+	response   map[string]interface{} // 応答の内容（結果は集計から書き込む）
+}
+
+// runCheck 対象をチェックして結果を集計し、履歴に保存する
+// cfgとcheckは実行の設定を反映したもの（runCheckerで作成）
+// progressを指定した場合は、完了した結果を進捗から集計する（progressは進捗を処理し、完了した結果をaddで集計に加える）
+func (s *Server) runCheck(ctx context.Context, cfg *config.Config, check *checker.Checker, runID string, targets []config.Target, options runOptions, rejected []urllist.Rejected, progress func(<-chan checker.Progress, func(*checker.CheckResult))) checkOutcome {
+	// チャネルのバッファは上限を設け、受け取りが追いつかない場合はチェックの側を待たせる
+	buffer := min(len(targets), resultChanBuffer)
+	resultChan := make(chan *checker.CheckResult, buffer)
+	var progressChan chan checker.Progress
+	if progress != nil {
+		progressChan = make(chan checker.Progress, 2*buffer)
+	}
+
+	// 結果は受け取りながら集計し、max_buffered_resultsを超えた分は一時ファイルに書き出す
+	aggregator := stats.NewAggregator(cfg.MaxBufferedResults)
+	baselines := s.anomalyBaselines()
+	add := func(result *checker.CheckResult) {
+		addResult(cfg, aggregator, baselines, result)
+	}
 
 	startTime := time.Now()
-	go s.checker.CheckURLs(ctx, urls, resultChan, progressChan)
+	go check.CheckTargets(ctx, targets, resultChan, progressChan)
 
-	var results []*checker.CheckResult
-	for result := range resultChan {
-		results = append(results, result)
+	if progress != nil {
+		// 完了した結果は進捗に含まれるため、進捗から集計する
+		go func() {
+			for range resultChan {
+			}
+		}()
+		progress(progressChan, add)
+	} else {
+		for result := range resultChan {
+			add(result)
+		}
 	}
 	totalDuration := time.Since(startTime)
+	if err := aggregator.Err(); err != nil {
+		slog.WarnContext(ctx, "failed to spill results", "error", err)
+	}
 
 	// 統計情報の計算
-	statistics := stats.CalculateStatistics(results, totalDuration)
+	statistics := aggregator.Statistics(totalDuration)
 
-	// 結果を保存
-	historyPath, _ := storage.SaveHistory(results, statistics)
+	// 前回の実行との差分と履歴の保存（書き出した場合は前回との比較を省略し、ファイルから1件ずつ保存する）
+	var regression *stats.Regression
+	var historyPath string
+	if aggregator.Spilled() {
+		slog.InfoContext(ctx, "results spilled to disk", "targets", statistics.TotalRequests, "limit", cfg.MaxBufferedResults)
+		path, err := storage.SaveAggregatedHistory(runID, options.metadata, aggregator, statistics)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to save results", "error", err)
+		}
+		historyPath = path
+	} else {
+		regression = s.compareWithPrevious(aggregator.Results())
+		historyPath = saveHistory(ctx, runID, options.metadata, aggregator.Results(), statistics)
+	}
+
+	slog.InfoContext(ctx, "check finished", "trigger", "web", "targets", statistics.TotalRequests, "failures", statistics.FailureCount, "duration", totalDuration)
+
+	return checkOutcome{
+		aggregator: aggregator,
+		statistics: statistics,
+		response: map[string]interface{}{
+			"statistics":  statistics,
+			"historyPath": historyPath,
+			"run_id":      runID,
+			"metadata":    options.metadata,
+			"regression":  regression,
+			"rejected":    rejected,
+		},
+	}
+}
+
+// resultChanBuffer /api/checkで結果・進捗のチャネルに持たせる最大のバッファ
+const resultChanBuffer = 256
+
+// addResult 結果の応答時間の劣化を判定して集計に追加
+func addResult(cfg *config.Config, aggregator *stats.Aggregator, baselines map[string]stats.Baseline, result *checker.CheckResult) {
+	stats.MarkDegraded([]*checker.CheckResult{result}, baselines, cfg.AnomalySigma, cfg.AnomalyMinSamples)
+	aggregator.Add(result)
+}
+
+// handleAPIHAR アップロードされたHARをトランザクションチェックに変換して実行
+func (s *Server) handleAPIHAR(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	auditAction(r, "har", nil, nil)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "HARファイルの読み込みに失敗しました", http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("har")
+	if err != nil {
+		http.Error(w, "HARファイルが指定されていません", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "HARファイルの読み込みに失敗しました", http.StatusBadRequest)
+		return
+	}
+	harFile, err := har.Parse(data)
+	if err != nil {
+		http.Error(w, "HARファイルの形式が不正です", http.StatusBadRequest)
+		return
+	}
+	tx, err := har.ToTransaction(r.FormValue("name"), harFile)
+	if err != nil {
+		http.Error(w, "HARにチェック可能なリクエストがありません", http.StatusBadRequest)
+		return
+	}
+
+	if !s.acquireRun(w) {
+		return
+	}
+	defer s.releaseRun()
+
+	// トランザクションチェック実行
+	startTime := time.Now()
+	ctx, span := startRun(r, "har")
+	defer finishRun(span)
+	result := s.currentChecker().CheckTransaction(ctx, tx)
+	results := []*checker.CheckResult{result}
+	s.markDegraded(results)
+	statistics := stats.CalculateStatistics(results, time.Since(startTime))
+
+	// 継続監視できるよう定義を保存
+	transactionPath, err := storage.SaveTransaction(tx, "transactions")
+	if err != nil {
+		slog.WarnContext(ctx, "failed to save transaction", "transaction", tx.Name, "error", err)
+	}
+	regression := s.compareWithPrevious(results)
+	slog.InfoContext(ctx, "check finished", "trigger", "har", "transaction", tx.Name, "success", result.Success)
+	historyPath := saveHistory(ctx, span.TraceID, nil, results, statistics)
+	if e := audit.FromContext(r.Context()); e != nil {
+		e.Targets = []string{tx.Name}
+	}
+	auditResult(r, span.TraceID, map[string]interface{}{"transaction": tx.Name, "success": result.Success})
 
-	// JSON形式で返す
 	response := map[string]interface{}{
-		"results":     results,
-		"statistics":  statistics,
-		"historyPath": historyPath,
+		"results":         results,
+		"statistics":      statistics,
+		"historyPath":     historyPath,
+		"run_id":          span.TraceID,
+		"regression":      regression,
+		"transaction":     tx,
+		"transactionPath": transactionPath,
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -406,6 +1089,9 @@ func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
 	
 	var results []*checker.CheckResult
 	var statistics *stats.Statistics
+	var regression *stats.Regression
+	var rejected []urllist.Rejected
+	historyPath := ""
 	
 	if resultsParam != "" {
 		var data map[string]interface{}
@@ -431,11 +1117,37 @@ func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
 							result.Latency = time.Duration(lat) * time.Millisecond
 						}
 						if err, ok := itemMap["error"].(string); ok {
-							result.Error = err
+							result.Error = checker.ErrorCategory(err)
 						}
 						if errMsg, ok := itemMap["error_message"].(string); ok {
 							result.ErrorMessage = errMsg
 						}
+						if hint, ok := itemMap["hint"].(string); ok {
+							result.Hint = hint
+						}
+						if snippet, ok := itemMap["snippet"].(string); ok {
+							result.Snippet = snippet
+						}
+						if headers, ok := itemMap["response_headers"].(map[string]interface{}); ok {
+							result.ResponseHeaders = make(map[string]string, len(headers))
+							for name, value := range headers {
+								if v, ok := value.(string); ok {
+									result.ResponseHeaders[name] = v
+								}
+							}
+						}
+						if degraded, ok := itemMap["degraded"].(bool); ok {
+							result.Degraded = degraded
+						}
+						if msg, ok := itemMap["degraded_message"].(string); ok {
+							result.DegradedMessage = msg
+						}
+						if flapping, ok := itemMap["flapping"].(bool); ok {
+							result.Flapping = flapping
+						}
+						if dependency, ok := itemMap["dependency_down"].(string); ok {
+							result.DependencyDown = dependency
+						}
 						results = append(results, result)
 					}
 				}
@@ -456,37 +1168,126 @@ func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
 					statistics.SuccessRate = rate
 				}
 			}
+			// 前回との差分（チェック実行時に計算済み）
+			if regressionData, ok := data["regression"]; ok && regressionData != nil {
+				if raw, err := json.Marshal(regressionData); err == nil {
+					json.Unmarshal(raw, &regression)
+				}
+			}
+			// 受け付けなかった入力
+			if rejectedData, ok := data["rejected"]; ok && rejectedData != nil {
+				if raw, err := json.Marshal(rejectedData); err == nil {
+					json.Unmarshal(raw, &rejected)
+				}
+			}
+			// 結果はチェック実行時に保存済み
+			if path, ok := data["historyPath"].(string); ok {
+				historyPath = path
+			}
 		}
+	} else if latest, err := storage.LatestHistoryEntry(storage.ResultsDir); err == nil && latest != nil {
+		// 結果が指定されていない場合は最新の保存済み結果を表示
+		results = latest.Results
+		statistics = latest.Statistics
 	}
-	
-	historyPath := ""
-	if len(results) > 0 {
-		historyPath, _ = storage.SaveHistory(results, statistics)
+	// タグで絞り込んだ場合は絞り込んだ結果で集計し直す
+	filter, err := tagFilter(r)
+	if err != nil {
+		http.Error(w, "tagはkey=valueの形式で指定してください", http.StatusBadRequest)
+		return
+	}
+	if len(filter) > 0 {
+		results = stats.FilterByTags(results, filter)
+		statistics = nil
+	}
+	if statistics == nil {
+		statistics = stats.CalculateStatistics(results, 0)
 	}
 	
-	dashboardHTML := dashboard.GenerateDashboard(results, statistics, historyPath)
+	extras := s.dashboardExtras(r, storage.ResultsDir)
+	extras.Regression = regression
+	extras.Rejected = rejected
+	extras.Live = resultsParam == ""
+	if extras.Live {
+		extras.Scheduler = s.workspace().schedulerState()
+	}
+	if extras.Live && !extras.ReadOnly {
+		extras.TargetIDs = make(map[string]string)
+		for _, t := range s.currentConfig().AllTargets() {
+			extras.TargetIDs[t.URL] = t.ID()
+		}
+	}
+	if extras.Live {
+		extras.Acks = ack.Active(time.Now())
+	}
+	dashboardHTML := dashboard.GenerateDashboard(results, statistics, historyPath, extras)
 	
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	fmt.Fprint(w, dashboardHTML)
 }
 
-// parseURLs URLテキストをパース
-func parseURLs(text string) []string {
-	lines := strings.Split(text, "\n")
-	var urls []string
-	
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		// 空行とコメント行をスキップ
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		// URLのバリデーション（簡単なチェック）
-		if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") {
-			urls = append(urls, line)
-		}
+// startRun Webからの1回の実行のトレースを開始（トレースIDが実行IDになる）
+// リクエストIDを引き継ぐが、クライアントが切断してもチェックは最後まで実行する
+// 実行中の一覧（/api/runs）にも登録するため、終了時はfinishRunを呼び出す
+func startRun(r *http.Request, trigger string) (context.Context, *tracing.Span) {
+	ctx, span := tracing.Start(context.WithoutCancel(r.Context()), "run")
+	span.SetAttribute("healthcheck.trigger", trigger)
+	runs.Start(span.TraceID, trigger, runInitiator(r), "")
+	return ctx, span
+}
+
+// finishRun 実行の完了を登録してトレースを終了
+func finishRun(span *tracing.Span) {
+	runs.Finish(span.TraceID)
+	span.Finish()
+}
+
+// saveHistory 結果を履歴に保存して保存先を返す（失敗した場合は警告を出して空を返す）
+func saveHistory(ctx context.Context, runID string, metadata map[string]string, results []*checker.CheckResult, statistics *stats.Statistics) string {
+	path, err := storage.SaveHistory(runID, metadata, results, statistics)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to save results", "error", err)
 	}
-	
-	return urls
+	return path
+}
+
+// tagFilter クエリパラメータのtag（key=value、複数指定可）からタグの条件を取得
+func tagFilter(r *http.Request) (map[string]string, error) {
+	return config.ParseTagFilter(r.URL.Query()["tag"])
+}
+
+// markDegraded 保存された履歴を基準に応答時間の劣化を判定
+func (s *Server) markDegraded(results []*checker.CheckResult) {
+	cfg := s.currentConfig()
+	stats.MarkDegraded(results, s.anomalyBaselines(), cfg.AnomalySigma, cfg.AnomalyMinSamples)
+}
+
+// anomalyBaselines 保存された履歴から対象ごとの応答時間の基準値を計算（読み込めない場合はnil）
+func (s *Server) anomalyBaselines() map[string]stats.Baseline {
+	history, err := storage.LoadHistoryResults(storage.ResultsDir)
+	if err != nil {
+		return nil
+	}
+	return stats.CalculateBaselines(history)
+}
+
+// withRequestID リクエストごとにIDを発行し、コンテキストとX-Request-IDヘッダーに設定する
+// クライアントがX-Request-IDを指定した場合はその値を引き継ぐ
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+// newRequestID ランダムなリクエストIDを生成
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
 }
@@ -0,0 +1,195 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/storage"
+)
+
+const (
+	// defaultShareTTL 共有リンクの有効期間の省略時の値
+	defaultShareTTL = 24 * time.Hour
+	// maxShareTTL 共有リンクの有効期間の上限
+	maxShareTTL = 30 * 24 * time.Hour
+)
+
+// newShareKey share_secretを設定していない場合に共有リンクの署名に使うランダムな鍵
+func newShareKey() []byte {
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
+
+// shareKey 共有リンクの署名に使う鍵（設定のshare_secret、未設定の場合は起動時に生成した鍵）
+func (s *Server) shareKey() []byte {
+	if secret := s.currentConfig().ShareSecret; secret != "" {
+		return []byte(secret)
+	}
+	return s.randomShareKey
+}
+
+// shareSignature 実行IDと有効期限（プロジェクトの実行の場合はプロジェクト名も）のHMAC-SHA256の署名
+func shareSignature(key []byte, project, runID string, expires int64) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s\n%d", runID, expires)
+	if project != "" {
+		fmt.Fprintf(mac, "\n%s", project)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// handleAPIRunShare 保存された実行のダッシュボードを、ツールにアクセスできない人と共有するリンクを作成（/api/runs/{id}/share）
+func (s *Server) handleAPIRunShare(w http.ResponseWriter, r *http.Request) {
+	s.shareRun(w, r, s.workspace())
+}
+
+// handleProjectRunShare プロジェクトの履歴の実行を共有するリンクを作成（/p/{project}/api/runs/{id}/share）
+func (s *Server) handleProjectRunShare(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	s.shareRun(w, r, ws)
+}
+
+// shareRun 対象・履歴の範囲（全体またはプロジェクト）の履歴から実行を探し、共有するリンクを作成する
+//   - expires_in: 有効期間（例: 2h、省略時は24時間、最大30日）
+//   - format=html: リンクの代わりに、その実行のダッシュボードを単体で開けるHTMLファイルとして返す
+func (s *Server) shareRun(w http.ResponseWriter, r *http.Request, ws *workspace) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	runID := r.PathValue("id")
+	options := formOptions(r, "expires_in", "format")
+	maps.Copy(options, ws.auditOptions())
+	auditAction(r, "share", nil, options)
+
+	ttl := defaultShareTTL
+	if v := strings.TrimSpace(r.FormValue("expires_in")); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 || d > maxShareTTL {
+			http.Error(w, "expires_inには30日以内の正の期間（例: 2h）を指定してください", http.StatusBadRequest)
+			return
+		}
+		ttl = d
+	}
+	format := r.FormValue("format")
+	if format != "" && format != "url" && format != "html" {
+		http.Error(w, "formatにはurlまたはhtmlを指定してください", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := storage.FindHistoryEntry(ws.resultsDir, runID)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+	if entry == nil {
+		http.Error(w, "指定した実行が見つかりません", http.StatusNotFound)
+		return
+	}
+	auditResult(r, runID, nil)
+
+	if format == "html" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="run-%s.html"`, runID))
+		fmt.Fprint(w, sharedDashboard(r, entry))
+		return
+	}
+
+	expires := time.Now().Add(ttl).UTC().Truncate(time.Second)
+	query := url.Values{
+		"expires": {strconv.FormatInt(expires.Unix(), 10)},
+		"sig":     {shareSignature(s.shareKey(), ws.project.Name, runID, expires.Unix())},
+	}
+	if ws.project.Name != "" {
+		query.Set("project", ws.project.Name)
+	}
+	path := "/shared/" + url.PathEscape(runID) + "?" + query.Encode()
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"run_id":     runID,
+		"url":        requestBaseURL(r) + path,
+		"path":       path,
+		"expires_at": expires,
+	})
+}
+
+// handleShared 共有リンクで実行のダッシュボードを表示（/shared/{id}?expires=&sig=、プロジェクトの実行は&project=も、ログインは不要）
+// 署名が一致しない場合と期限切れの場合は、実行の有無にかかわらず同じ応答にする
+func (s *Server) handleShared(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	runID := r.PathValue("id")
+	project := r.URL.Query().Get("project")
+	expires, err := strconv.ParseInt(r.URL.Query().Get("expires"), 10, 64)
+	sig := r.URL.Query().Get("sig")
+	if err != nil || time.Now().Unix() >= expires ||
+		!hmac.Equal([]byte(sig), []byte(shareSignature(s.shareKey(), project, runID, expires))) {
+		http.Error(w, "共有リンクが無効か、有効期限が切れています", http.StatusForbidden)
+		return
+	}
+
+	resultsDir := storage.ResultsDir
+	if project != "" {
+		// 共有した後に削除されたプロジェクトの実行は開けない
+		s.projectsMutex.RLock()
+		ws, ok := s.projects[project]
+		s.projectsMutex.RUnlock()
+		if !ok {
+			http.Error(w, "指定した実行が見つかりません", http.StatusNotFound)
+			return
+		}
+		resultsDir = ws.resultsDir
+	}
+
+	entry, err := storage.FindHistoryEntry(resultsDir, runID)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+	if entry == nil {
+		http.Error(w, "指定した実行が見つかりません", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Robots-Tag", "noindex")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, sharedDashboard(r, entry))
+}
+
+// sharedDashboard 共有する実行のダッシュボード（その実行の結果のみを表示し、アプリ内の操作とリンクは表示しない）
+func sharedDashboard(r *http.Request, entry *storage.HistoryEntry) string {
+	extras := dashboard.Extras{
+		Shared:   true,
+		RunAt:    entry.Timestamp,
+		ReadOnly: true,
+		Language: language(r),
+		Theme:    theme(r),
+	}
+	return dashboard.GenerateDashboard(entry.Results, entry.Statistics, "", extras)
+}
+
+// requestBaseURL リクエストを受けたサーバーのURL（リバースプロキシの場合はX-Forwarded-Protoのスキーム）
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
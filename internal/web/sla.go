@@ -0,0 +1,79 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// handleAPISLA 対象ごとの稼働率とエラーバジェットをJSON形式で返す
+func (s *Server) handleAPISLA(w http.ResponseWriter, r *http.Request) {
+	s.serveSLA(w, r, storage.ResultsDir)
+}
+
+// serveSLA 指定したディレクトリの履歴から計算した稼働率を返す
+func (s *Server) serveSLA(w http.ResponseWriter, r *http.Request, resultsDir string) {
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = "24h"
+	}
+	if _, ok := stats.SLAWindows[window]; !ok {
+		http.Error(w, "windowには24h, 7d, 30dのいずれかを指定してください", http.StatusBadRequest)
+		return
+	}
+
+	slas, err := s.calculateSLA(resultsDir, window)
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"window":     window,
+		"slo_target": s.currentConfig().SLOTarget,
+		"targets":    slas,
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(response)
+}
+
+// calculateSLA 保存された履歴から指定期間の稼働率を計算
+func (s *Server) calculateSLA(resultsDir, window string) ([]*stats.TargetSLA, error) {
+	results, err := storage.LoadHistoryResults(resultsDir)
+	if err != nil {
+		return nil, err
+	}
+	return stats.CalculateSLA(results, stats.SLAWindows[window], s.currentConfig().SLOTarget, time.Now()), nil
+}
+
+// slaWindowParam リクエストから稼働率の集計期間を取得（不正な値の場合は24h）
+func slaWindowParam(r *http.Request) string {
+	window := r.URL.Query().Get("window")
+	if _, ok := stats.SLAWindows[window]; !ok {
+		return "24h"
+	}
+	return window
+}
+
+// dashboardExtras ダッシュボードに表示する履歴ベースの情報を作成
+func (s *Server) dashboardExtras(r *http.Request, resultsDir string) dashboard.Extras {
+	window := slaWindowParam(r)
+	slas, _ := s.calculateSLA(resultsDir, window)
+	extras := dashboard.Extras{
+		SLA:       slas,
+		SLAWindow: window,
+		SLOTarget: s.currentConfig().SLOTarget,
+		Language:  language(r),
+		Theme:     theme(r),
+	}
+	if u, ok := currentUser(r); ok {
+		extras.User = u.Name
+		extras.ReadOnly = !canEdit(r)
+	}
+	return extras
+}
@@ -0,0 +1,153 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"healthcheck/internal/checker"
+	"healthcheck/internal/config"
+	"healthcheck/internal/dashboard"
+	"healthcheck/internal/i18n"
+	"healthcheck/internal/incident"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+)
+
+// statusPageDays ステータスページに表示する日数
+const statusPageDays = 90
+
+// handleStatus 公開ステータスページ表示
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	filter, err := tagFilter(r)
+	if err != nil {
+		http.Error(w, "tagはkey=valueの形式で指定してください", http.StatusBadRequest)
+		return
+	}
+	page, err := s.buildStatusPage(filter, language(r))
+	if err != nil {
+		http.Error(w, "履歴の読み込みに失敗しました", http.StatusInternalServerError)
+		return
+	}
+	page.Theme = theme(r)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, dashboard.GenerateStatusPage(page))
+}
+
+// ExportStatusPage ステータスページを静的HTMLとしてファイルに書き出す
+func (s *Server) ExportStatusPage(path string) error {
+	cfg := s.currentConfig()
+	page, err := s.buildStatusPage(nil, cfg.Language)
+	if err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+	page.Static = true
+	page.Theme = pageTheme(cfg.Theme)
+	if err := os.WriteFile(path, []byte(dashboard.GenerateStatusPage(page)), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// buildStatusPage 履歴から対象をサービスごとにまとめたステータスページの内容を作成
+// タグの条件を指定した場合は、タグが一致する対象のみを表示する
+func (s *Server) buildStatusPage(filter map[string]string, lang string) (dashboard.StatusPage, error) {
+	entries, err := storage.LoadHistoryEntries(storage.ResultsDir)
+	if err != nil {
+		return dashboard.StatusPage{}, err
+	}
+
+	now := time.Now()
+	var results []*checker.CheckResult
+	latest := make(map[string]*checker.CheckResult)
+	for _, entry := range entries {
+		for _, r := range stats.FilterByTags(entry.Results, filter) {
+			results = append(results, r)
+			latest[r.URL] = r
+		}
+	}
+
+	// 設定された対象がない場合は履歴に含まれるURLを表示
+	var targets []config.Target
+	for _, t := range s.currentConfig().AllTargets() {
+		if config.MatchTags(t.Tags, filter) {
+			targets = append(targets, t)
+		}
+	}
+	if len(targets) == 0 {
+		for url := range latest {
+			targets = append(targets, config.Target{Name: url, URL: url})
+		}
+		sort.Slice(targets, func(i, j int) bool {
+			return targets[i].URL < targets[j].URL
+		})
+	}
+
+	regions := stats.LatestByRegion(results)
+
+	var services []dashboard.ServiceStatus
+	index := make(map[string]int)
+	for _, t := range targets {
+		name := t.Service
+		if name == "" {
+			name = i18n.T(lang, "status_default_service")
+		}
+		i, exists := index[name]
+		if !exists {
+			i = len(services)
+			index[name] = i
+			services = append(services, dashboard.ServiceStatus{Name: name})
+		}
+
+		state := targetState(latest[t.URL], regions[t.URL])
+		days := stats.CalculateDailyUptime(results, t.URL, statusPageDays, i18n.In(now))
+		services[i].Targets = append(services[i].Targets, dashboard.TargetStatus{
+			Name:   t.Name,
+			State:  state,
+			Uptime: stats.OverallUptime(days),
+			Days:   days,
+		})
+	}
+
+	// タグで絞り込んだ場合は表示する対象のインシデントのみ
+	shown := make(map[string]bool)
+	for _, t := range targets {
+		shown[t.URL] = true
+	}
+	var incidents []*incident.Incident
+	for _, i := range incident.Active(incident.Detect(entries)) {
+		if len(filter) == 0 || shown[i.URL] {
+			incidents = append(incidents, i)
+		}
+	}
+
+	return dashboard.StatusPage{
+		Title:       i18n.T(lang, "status_title"),
+		GeneratedAt: i18n.FormatTime(now),
+		Services:    services,
+		Incidents:   incidents,
+		Language:    lang,
+	}, nil
+}
+
+// targetState 対象の最新のチェック結果から状態を判定（up / down / degraded / flapping、結果がない場合はunknown）
+func targetState(r *checker.CheckResult, region *stats.RegionStatus) string {
+	switch {
+	case r == nil:
+		return "unknown"
+	case region != nil && region.State == "partial":
+		// 一部の地域からのみ失敗している場合は部分的な障害として扱う
+		return "degraded"
+	case r.Flapping:
+		return "flapping"
+	case !r.Success:
+		return "down"
+	case r.Degraded:
+		return "degraded"
+	}
+	return "up"
+}
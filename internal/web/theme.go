@@ -0,0 +1,56 @@
+package web
+
+import (
+	"context"
+	"net/http"
+
+	"healthcheck/internal/config"
+	"healthcheck/internal/dashboard"
+)
+
+// themeCookie テーマの切り替えで選択した表示モードを保存するクッキー
+const themeCookie = "healthcheck_theme"
+
+// themeKey リクエストのコンテキストにテーマを保持するキー
+type themeKey struct{}
+
+// withTheme リクエストのテーマを決めてコンテキストに設定する
+// 表示モードの優先順位: クエリパラメータのtheme（クッキーに保存する、autoの場合はクッキーを削除）> クッキー > 設定ファイルのtheme.mode > OSの設定
+func (s *Server) withTheme(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t := pageTheme(s.currentConfig().Theme)
+		mode := r.URL.Query().Get("theme")
+		switch mode {
+		case "light", "dark":
+			http.SetCookie(w, &http.Cookie{
+				Name:     themeCookie,
+				Value:    mode,
+				Path:     "/",
+				MaxAge:   365 * 24 * 60 * 60,
+				SameSite: http.SameSiteLaxMode,
+			})
+			t.Mode = mode
+		case "auto":
+			http.SetCookie(w, &http.Cookie{Name: themeCookie, Path: "/", MaxAge: -1})
+			t.Mode = ""
+		default:
+			if c, err := r.Cookie(themeCookie); err == nil && (c.Value == "light" || c.Value == "dark") {
+				t.Mode = c.Value
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), themeKey{}, t)))
+	})
+}
+
+// theme リクエストのテーマ
+func theme(r *http.Request) dashboard.Theme {
+	if t, ok := r.Context().Value(themeKey{}).(dashboard.Theme); ok {
+		return t
+	}
+	return dashboard.Theme{}
+}
+
+// pageTheme 設定ファイルのテーマをページのテーマに変換
+func pageTheme(t config.Theme) dashboard.Theme {
+	return dashboard.Theme{Mode: t.Mode, Accent: t.AccentColor, Logo: t.LogoURL, Title: t.Title}
+}
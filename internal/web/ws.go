@@ -0,0 +1,216 @@
+package web
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcheck/internal/events"
+)
+
+// WebSocketのハンドシェイクで使う固定のGUID（RFC 6455）
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocketのオペコード
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// wsPingInterval 接続を維持するためのPingの間隔
+const wsPingInterval = 30 * time.Second
+
+// handleWebSocket 結果・定期チェックの状態・アラートのイベントをWebSocketで配信（プロジェクトのイベントは除く）
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	serveEvents(w, r, "")
+}
+
+// serveEvents 指定したプロジェクト（空の場合は全体）のイベントをWebSocketで配信
+func serveEvents(w http.ResponseWriter, r *http.Request, project string) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "WebSocketで接続してください", http.StatusBadRequest)
+		return
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(w, "WebSocketのバージョンが不正です", http.StatusBadRequest)
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocketに対応していません", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	hash := sha1.Sum([]byte(key + websocketGUID))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	rw.WriteString("Upgrade: websocket\r\n")
+	rw.WriteString("Connection: Upgrade\r\n")
+	rw.WriteString("Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(hash[:]) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		return
+	}
+
+	ws := &wsConn{conn: conn}
+	sub := events.Subscribe()
+	defer events.Unsubscribe(sub)
+
+	// クライアントからのフレームを読み、切断されたら配信を終了する
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		ws.readLoop(rw.Reader)
+	}()
+
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+			if err := ws.writeFrame(opPing, nil); err != nil {
+				return
+			}
+		case e, ok := <-sub:
+			if !ok {
+				return
+			}
+			if e.Project != project {
+				continue
+			}
+			payload, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			if err := ws.writeFrame(opText, payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// headerContains カンマ区切りのヘッダーの値にtokenが含まれるか（大文字小文字を区別しない）
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wsConn サーバー側のWebSocket接続（書き込みは排他制御する）
+type wsConn struct {
+	conn  net.Conn
+	mutex sync.Mutex
+}
+
+// writeFrame 1つのフレームを送信（サーバーからのフレームはマスクしない）
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	header := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(n))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(n))
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// readLoop クライアントからのフレームを読み、Pingへの応答と切断を処理する
+// 配信専用のため、テキストなどのデータフレームは読み捨てる
+func (c *wsConn) readLoop(r *bufio.Reader) {
+	for {
+		opcode, payload, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case opClose:
+			c.writeFrame(opClose, payload)
+			return
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readFrame クライアントからの1つのフレームを読む（クライアントのフレームは必ずマスクされる）
+func readFrame(r *bufio.Reader) (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := head[0] & 0x0F
+	if head[1]&0x80 == 0 {
+		return 0, nil, errors.New("unmasked client frame")
+	}
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	// 配信専用のため大きなフレームは受け付けない
+	if length > 64<<10 {
+		return 0, nil, errors.New("client frame too large")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
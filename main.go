@@ -1,29 +1,375 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
+	"net"
 	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+	_ "time/tzdata" // display_timezoneをタイムゾーンのデータがない環境（コンテナなど）でも使えるようにする
 
+	"healthcheck/internal/account"
+	"healthcheck/internal/checker"
+	"healthcheck/internal/cli"
 	"healthcheck/internal/config"
+	"healthcheck/internal/demo"
+	"healthcheck/internal/i18n"
+	"healthcheck/internal/importer"
+	"healthcheck/internal/logging"
+	"healthcheck/internal/stats"
+	"healthcheck/internal/storage"
+	"healthcheck/internal/tracing"
 	"healthcheck/internal/web"
 )
 
 func main() {
 	var port string
+	var listen string
+	var configPath string
+	var exportStatus string
+	var demoMode bool
+	var importPath, importFormat, importName string
+	var targetsFile string
+	var logFormat string
+	var verbose bool
+	var runMode bool
+	var cliOpts cli.Options
+	var watch time.Duration
+	var noColor bool
+	var reloadInterval time.Duration
+	var hashPassword bool
+	var metadata []string
+	var opts overrides
 	flag.StringVar(&port, "port", "8080", "サーバーのポート番号")
 	flag.StringVar(&port, "p", "8080", "サーバーのポート番号（短縮形）")
+	flag.StringVar(&listen, "listen", "", "待ち受けるアドレス（例: 0.0.0.0:8080、127.0.0.1:9000、指定時は-portより優先）")
+	flag.StringVar(&configPath, "config", "", "設定ファイル（JSON）のパス")
+	flag.StringVar(&exportStatus, "export-status", "", "ステータスページを静的HTMLとして書き出すパス（書き出して終了）")
+	flag.BoolVar(&demoMode, "demo", false, "サンプルの対象と合成した履歴でダッシュボードを確認するデモモード")
+	flag.StringVar(&importPath, "import", "", "HAR・curlコマンド・Postmanコレクションのファイルをトランザクションとして登録（登録して終了）")
+	flag.StringVar(&importFormat, "import-format", "", "取り込むファイルの形式（har / curl / postman、省略時は自動判定）")
+	flag.StringVar(&importName, "import-name", "", "取り込むトランザクションの名前")
+	flag.StringVar(&targetsFile, "targets-file", "", "CSV・TSV・Excel（xlsx）の対象の一覧（列: URL・名前・期待するステータス・タグ・タイムアウト、設定ファイルの対象に追加）")
+	flag.StringVar(&logFormat, "log-format", "", "ログの形式（text / json、設定ファイルより優先）")
+	flag.BoolVar(&verbose, "verbose", false, "DEBUGレベルの詳細ログも出力")
+	flag.BoolVar(&runMode, "run", false, "引数のURL（省略時は設定ファイルの対象）を1回チェックして結果を表示して終了")
+	flag.Float64Var(&cliOpts.MinSuccessRate, "fail-if-success-rate-below", 0, "成功率（%）がこの値を下回った場合に終了コード3で終了")
+	flag.DurationVar(&cliOpts.MaxP95, "fail-if-p95-above", 0, "応答時間のp95がこの値（例: 500ms）を超えた場合に終了コード4で終了")
+	flag.DurationVar(&watch, "watch", 0, "指定した間隔（例: 30s）でチェックを繰り返し、端末の表を更新し続ける")
+	flag.BoolVar(&noColor, "no-color", false, "端末の表示に色を付けない")
+	flag.DurationVar(&reloadInterval, "reload-interval", 0, "設定ファイルの更新を確認する間隔（例: 10s、更新されていれば再読み込み）")
+	flag.DurationVar(&opts.timeout, "timeout", 0, "リクエストのタイムアウト（例: 10s、設定ファイルより優先）")
+	flag.IntVar(&opts.concurrency, "concurrency", 0, "同時にチェックする数（設定ファイルより優先）")
+	flag.IntVar(&opts.retries, "retries", 0, "失敗時のリトライ回数（設定ファイルより優先）")
+	flag.IntVar(&opts.domainRate, "domain-rate", 0, "同一ドメインへの1秒あたりの最大リクエスト数（設定ファイルより優先）")
+	flag.IntVar(&opts.globalRate, "global-rate", 0, "全体の1秒あたりの最大リクエスト数（設定ファイルより優先）")
+	flag.BoolVar(&opts.insecure, "insecure", false, "SSL証明書の検証をスキップ")
+	flag.BoolVar(&opts.debugEndpoints, "debug-endpoints", false, "/debug/pprof と /debug/vars で診断情報を公開する（adminの役割が必要）")
+	flag.StringVar(&opts.resultsDir, "results-dir", "", "履歴を保存するディレクトリ（設定ファイルより優先）")
+	flag.BoolVar(&hashPassword, "hash-password", false, "設定ファイルのusersに指定するパスワードのハッシュを生成して終了（パスワードは標準入力から読み込む）")
+	flag.Func("meta", "JSON形式の結果に含める実行の情報（key=value、複数指定可。例: -meta deploy_sha=abc123 -meta environment=staging）", func(v string) error {
+		metadata = append(metadata, v)
+		return nil
+	})
+	flag.StringVar(&opts.output, "output", "", "コマンドラインでの結果の表示形式（"+strings.Join(config.OutputFormats, " / ")+"）")
+	flag.StringVar(&opts.duplicates, "duplicates", "", "同じURLが複数回指定された場合の扱い（each: 出現ごとにチェック / dedupe: 1回だけチェックして結果を共有）")
 	flag.Parse()
 
-	cfg := config.DefaultConfig()
+	opts.set = make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { opts.set[f.Name] = true })
+	// 設定ファイルを読み込むまでは環境変数の言語でメッセージを表示する
+	lang := i18n.FromEnv()
+	addr := ":" + port
+	if listen != "" {
+		addr = listen
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T(lang, "main_option_error", i18n.T(lang, "main_invalid_listen", addr)))
+		os.Exit(2)
+	}
+	if err := opts.validate(lang); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T(lang, "main_option_error", err))
+		os.Exit(2)
+	}
+	if m, err := config.ParseMetadata(metadata); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T(lang, "main_option_error", err))
+		os.Exit(2)
+	} else if len(m) > 0 {
+		cliOpts.Metadata = m
+	}
+
+	if hashPassword {
+		if err := runHashPassword(); err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T(lang, "main_hash_error", err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	if importPath != "" {
+		if err := runImport(lang, importPath, importFormat, importName); err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T(lang, "main_import_error", err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	// 起動時と再読み込み時で同じ検証とコマンドラインの指定の反映を行う
+	loadConfig := func() (*config.Config, error) {
+		cfg := config.DefaultConfig()
+		if configPath != "" {
+			loaded, err := config.Load(configPath)
+			if err == nil {
+				err = checker.ValidateRules(loaded.Targets)
+			}
+			if err == nil {
+				err = checker.ValidateSelectors(loaded.Targets)
+			}
+			if err == nil {
+				for _, p := range loaded.Projects {
+					err = checker.ValidateRules(p.Targets)
+					if err == nil {
+						err = checker.ValidateSelectors(p.Targets)
+					}
+					if err != nil {
+						err = fmt.Errorf("project %q: %w", p.Name, err)
+						break
+					}
+				}
+			}
+			if err == nil {
+				for _, h := range loaded.Hooks {
+					err = checker.ValidateRules(h.Targets)
+					if err == nil {
+						err = checker.ValidateSelectors(h.Targets)
+					}
+					if err != nil {
+						err = fmt.Errorf("hook %q: %w", h.Name, err)
+						break
+					}
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+			cfg = loaded
+		}
+		if targetsFile != "" {
+			targets, err := loadTargetsFile(targetsFile)
+			if err != nil {
+				return nil, err
+			}
+			cfg.Targets = append(cfg.Targets, targets...)
+		}
+		if logFormat != "" {
+			cfg.LogFormat = logFormat
+		}
+		if verbose {
+			cfg.Verbose = true
+		}
+		if noColor {
+			cfg.NoColor = true
+		}
+		opts.apply(cfg)
+		return cfg, nil
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T(lang, "main_config_error", err))
+		os.Exit(1)
+	}
+	lang = cli.Language(cfg)
+	if err := logging.Setup(cfg.LogFormat, cfg.Verbose); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T(lang, "main_logging_error", err))
+		os.Exit(1)
+	}
+	storage.SetHistoryLimit(cfg.HistoryLimit)
+	stats.SetHistogramBuckets(cfg.HistogramBuckets)
+	i18n.SetLocation(cfg.DisplayLocation)
+	storage.ResultsDir = cfg.ResultsDir
+	tracing.Setup(cfg.OTLPEndpoint, cfg.OTLPHeaders, cfg.ServiceName)
+
+	// URLが引数で指定された場合もCLIとして1回だけチェックする
+	if runMode || watch > 0 || flag.NArg() > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		cliOpts.URLs = flag.Args()
+		var code int
+		if watch > 0 {
+			code = cli.Watch(ctx, cfg, cliOpts.URLs, cliOpts.Metadata, watch, os.Stdout)
+		} else {
+			code = cli.Run(ctx, cfg, cliOpts, os.Stdout)
+		}
+		stop()
+		tracing.Shutdown()
+		os.Exit(code)
+	}
+	if demoMode {
+		dir, err := demo.Setup(cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T(lang, "main_demo_error", err))
+			os.Exit(1)
+		}
+		// デモでは実際のチェックを定期実行しない
+		cfg.Interval = 0
+		slog.Info("demo mode: generated sample history", "dir", dir)
+	}
 	server := web.NewServer(cfg)
 
-	fmt.Println("=== Health Check Tool ===")
-	fmt.Println("ブラウザで http://localhost:" + port + " を開いてください")
-	fmt.Println()
+	if exportStatus != "" {
+		if err := server.ExportStatusPage(exportStatus); err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T(lang, "main_export_status_error", err))
+			os.Exit(1)
+		}
+		fmt.Println(i18n.T(lang, "main_export_status_done", exportStatus))
+		return
+	}
+
+	if configPath != "" && !demoMode {
+		server.EnableReload(configPath, loadConfig, reloadInterval)
+	}
+	if cfg.Interval > 0 {
+		slog.Info("scheduled checks enabled", "targets", len(cfg.Targets), "interval", cfg.Interval)
+	}
 
-	if err := server.Start(port); err != nil {
-		fmt.Fprintf(os.Stderr, "サーバー起動エラー: %v\n", err)
+	if err := server.Start(addr); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T(lang, "main_server_error", err))
 		os.Exit(1)
 	}
 }
+
+// overrides コマンドラインで指定された設定（明示的に指定されたものだけを設定ファイルより優先する）
+type overrides struct {
+	timeout        time.Duration
+	concurrency    int
+	retries        int
+	domainRate     int
+	globalRate     int
+	insecure       bool
+	debugEndpoints bool
+	resultsDir     string
+	output         string
+	duplicates     string
+	set            map[string]bool // 指定されたフラグの名前
+}
+
+// validate 指定された値を検証し、誤りがあればフラグの名前を含むエラーを返す（メッセージは指定した言語）
+func (o *overrides) validate(lang string) error {
+	switch {
+	case o.set["timeout"] && o.timeout <= 0:
+		return errors.New(i18n.T(lang, "main_invalid_timeout", o.timeout))
+	case o.set["concurrency"] && o.concurrency < 1:
+		return errors.New(i18n.T(lang, "main_invalid_concurrency", o.concurrency))
+	case o.set["retries"] && o.retries < 0:
+		return errors.New(i18n.T(lang, "main_invalid_retries", o.retries))
+	case o.set["domain-rate"] && o.domainRate < 1:
+		return errors.New(i18n.T(lang, "main_invalid_domain_rate", o.domainRate))
+	case o.set["global-rate"] && o.globalRate < 1:
+		return errors.New(i18n.T(lang, "main_invalid_global_rate", o.globalRate))
+	case o.set["results-dir"] && strings.TrimSpace(o.resultsDir) == "":
+		return errors.New(i18n.T(lang, "main_invalid_results_dir"))
+	case o.set["output"] && !slices.Contains(config.OutputFormats, o.output):
+		return errors.New(i18n.T(lang, "main_invalid_output", strings.Join(config.OutputFormats, " / "), o.output))
+	case o.set["duplicates"] && !slices.Contains(config.DuplicateModes, o.duplicates):
+		return errors.New(i18n.T(lang, "main_invalid_duplicates", strings.Join(config.DuplicateModes, " / "), o.duplicates))
+	}
+	return nil
+}
+
+// apply 指定されたフラグの値を設定に反映
+func (o *overrides) apply(cfg *config.Config) {
+	if o.set["timeout"] {
+		cfg.Timeout = o.timeout
+		cfg.MaxLatency = o.timeout
+	}
+	if o.set["concurrency"] {
+		cfg.Concurrency = o.concurrency
+	}
+	if o.set["retries"] {
+		cfg.Retries = o.retries
+	}
+	if o.set["domain-rate"] {
+		cfg.DomainRate = o.domainRate
+	}
+	if o.set["global-rate"] {
+		cfg.GlobalRate = o.globalRate
+	}
+	if o.set["insecure"] {
+		cfg.Insecure = o.insecure
+	}
+	if o.set["debug-endpoints"] {
+		cfg.DebugEndpoints = o.debugEndpoints
+	}
+	if o.set["results-dir"] {
+		cfg.ResultsDir = o.resultsDir
+	}
+	if o.set["output"] {
+		cfg.OutputFormat = o.output
+	}
+	if o.set["duplicates"] {
+		cfg.Duplicates = o.duplicates
+	}
+}
+
+// runHashPassword 標準入力の1行目をパスワードとしてハッシュを生成し、標準出力に書き出す
+func runHashPassword() error {
+	scanner := bufio.NewScanner(os.Stdin)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		return errors.New("no password given on stdin")
+	}
+	password := strings.TrimRight(scanner.Text(), "\r")
+	if password == "" {
+		return errors.New("password must not be empty")
+	}
+	hash, err := account.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	fmt.Println(hash)
+	return nil
+}
+
+// loadTargetsFile 対象の一覧のファイルを読み込む（不正な行は警告をログに出力して読み飛ばす）
+func loadTargetsFile(path string) ([]config.Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	targets, rejected, err := importer.ImportTargets(data, "", path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, r := range rejected {
+		slog.Warn("skipped row in targets file", "path", path, "line", r.Line, "text", r.Text, "reason", r.Reason)
+	}
+	return targets, nil
+}
+
+// runImport ファイルを取り込んでトランザクションとして保存
+func runImport(lang, path, format, name string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	transactions, err := importer.Import(data, format, name)
+	if err != nil {
+		return err
+	}
+	for _, tx := range transactions {
+		saved, err := storage.SaveTransaction(tx, "transactions")
+		if err != nil {
+			return err
+		}
+		fmt.Println(i18n.T(lang, "main_import_saved", tx.Name, len(tx.Steps), saved))
+	}
+	return nil
+}